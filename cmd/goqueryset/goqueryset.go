@@ -2,19 +2,230 @@ package main
 
 import (
 	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
 	"log"
+	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/jirfag/go-queryset/queryset"
+	"github.com/jirfag/go-queryset/queryset/dialect"
 )
 
+const defaultOutFile = "autogenerated_{in}"
+
+// version of goqueryset: it's set by -ldflags "-X main.version=v1.2.3"
+var version = "devel"
+
+const usage = `goqueryset generates querysets of GORM models.
+
+Usage:
+
+	goqueryset [gen] [flags]   generate querysets (gen is the default command)
+	goqueryset check [flags]   check that generated querysets are up to date, the same as gen -check
+	goqueryset graph [flags]   write graph of models and their relations
+	goqueryset version         print version
+
+Run goqueryset <command> -h for flags of command.
+`
+
 func main() {
-	inFile := flag.String("in", "models.go", "path to input file")
-	outFile := flag.String("out", "autogenerated_{in}", "path to output file")
-	flag.Parse()
+	cmd, args := "gen", os.Args[1:]
+	if len(args) != 0 && !strings.HasPrefix(args[0], "-") {
+		cmd, args = args[0], args[1:]
+	}
+
+	switch cmd {
+	case "gen", "check":
+		gen(cmd, args)
+	case "graph":
+		graph(args)
+	case "version":
+		fmt.Println("goqueryset", version)
+	case "help":
+		fmt.Print(usage)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", cmd, usage)
+		os.Exit(2)
+	}
+}
+
+// gen runs gen and check verbs: check verb doesn't write out files, it fails
+// if they are out of date and checks raw conditions of Where
+func gen(cmd string, args []string) {
+	fs := flag.NewFlagSet(cmd, flag.ExitOnError)
+	inFile := fs.String("in", "models.go", "path to input file, to directory of package or glob pattern "+
+		"of files of package, e.g. models/*_model.go: all (matching) files of package are processed then")
+	pkgDir := fs.String("pkg", "", "path to directory of package, the same as -in with directory")
+	outFile := fs.String("out", defaultOutFile, "path to output file; for package "+
+		"querysets are generated into {file}{suffix} next to every file by default")
+	outPkg := fs.String("out-pkg", "", "directory of package of generated querysets, e.g. models/queries: "+
+		"it imports package of structs, object methods (Create, Update etc) aren't generated; it needs Go 1.9")
+	exclude := fs.String("exclude", "", "comma-separated glob patterns of names of files of package, "+
+		"which structs are skipped, e.g. legacy_*.go")
+	dialectName := fs.String("dialect", "", "target SQL dialect: "+
+		strings.Join(dialect.Names(), ", ")+"; generic SQL by default")
+	debugTag := fs.String("debug-tag", "", "build tag of debug methods variant, e.g. !prod: "+
+		"debug methods are generated into {out}_debug.go and their no-op stubs into {out}_nodebug.go")
+	filterPrefix := fs.String("filter-prefix", "", "prefix of names of fields filters, e.g. Filter for FilterNameEq; "+
+		"struct's prefix option overrides it")
+	allStructs := fs.Bool("all-structs", false, "generate querysets for all structs except ones with "+
+		"gen:qs skip line in doc, by default they are generated only for ones with gen:qs line")
+	checkWhere := fs.Bool("check-where", cmd == "check", "check, that raw conditions of Where in files of package, "+
+		"e.g. NewUserQuerySet(db).Where(\"name = ?\", name), reference only columns of structs")
+	templatesDir := fs.String("templates", "", "directory of user templates (*.tmpl) defining templates "+
+		"\"struct\" (executed for every struct) and \"package\" (executed once) to extend generated code")
+	tenantField := fs.String("tenant-field", "", "name of field of tenant, e.g. TenantID: constructors of "+
+		"querysets of structs with this field need tenant and filter rows by it")
+	configFile := fs.String("config", "", "path to YAML (or JSON if it's *.json) config file of generation; "+
+		strings.Join(queryset.ConfigFileNames, ", ")+" in directory of input files is loaded by default "+
+		"if it exists, flags override it")
+	check := fs.Bool("check", cmd == "check", "don't write out files: fail with diff if they are out of date, "+
+		"e.g. in CI")
+	jobs := fs.Int("jobs", 0, "max number of structs and out files generated concurrently, number of CPUs by default")
+	buildTag := fs.String("build-tag", "", "build constraint expression of all generated files, "+
+		"e.g. !codeanalysis; it's combined with -debug-tag")
+	headerFile := fs.String("header-file", "", "path to file with header of generated files (e.g. license) "+
+		"replacing default one, it must have \"// Code generated ... DO NOT EDIT.\" line")
+	suffix := fs.String("suffix", queryset.DefaultFileSuffix, "suffix of names of out files of package, "+
+		"e.g. _queryset.go for models_queryset.go; if it's set for a file, it replaces default -out")
+	strict := fs.Bool("strict", false, "make Update, Delete and SoftDelete return ErrNoRowsAffected "+
+		"if no rows were affected; their Num variants (e.g. DeleteNum) return number of affected rows")
+	force := fs.Bool("force", false, "regenerate querysets even if neither inputs (package, config, templates, "+
+		"generator) nor out files were changed since the previous generation")
+	if err := fs.Parse(args); err != nil {
+		log.Fatalf("can't parse args: %s", err)
+	}
 
-	*outFile = strings.Replace(*outFile, "{in}", *inFile, 1)
-	if err := queryset.GenerateQuerySets(*inFile, *outFile); err != nil {
+	in := *inFile
+	if *pkgDir != "" {
+		in = *pkgDir
+	}
+	var include []string
+	if strings.ContainsAny(in, "*?[") {
+		include = []string{filepath.Base(in)}
+		in = filepath.Dir(in)
+	}
+
+	cfg := loadConfig(*configFile, in)
+	cfg.Check = *check
+	cfg.Force = *force
+	cfg.Jobs = *jobs
+	if include != nil {
+		cfg.Include = include
+	}
+	fs.Visit(func(f *flag.Flag) { // flags override config file
+		switch f.Name {
+		case "dialect":
+			cfg.Dialect = *dialectName
+		case "debug-tag":
+			cfg.DebugBuildTag = *debugTag
+		case "filter-prefix":
+			cfg.FilterPrefix = *filterPrefix
+		case "all-structs":
+			cfg.AllStructs = *allStructs
+		case "templates":
+			cfg.TemplatesDir = *templatesDir
+		case "tenant-field":
+			cfg.TenantField = *tenantField
+		case "exclude":
+			cfg.Exclude = strings.Split(*exclude, ",")
+		case "out-pkg":
+			cfg.OutPkg = *outPkg
+		case "build-tag":
+			cfg.BuildTag = *buildTag
+		case "header-file":
+			cfg.Header = readHeaderFile(*headerFile)
+		case "suffix":
+			cfg.FileSuffix = *suffix
+		case "strict":
+			cfg.Strict = *strict
+		}
+	})
+	cfg.CheckWhere = cfg.CheckWhere || *checkWhere
+
+	if fi, err := os.Stat(in); err == nil && fi.IsDir() {
+		if *outFile == defaultOutFile {
+			*outFile = ""
+		}
+		if err = queryset.GenerateQuerySetsForPackage(in, *outFile, cfg); err != nil {
+			log.Fatalf("can't generate query sets: %s", err)
+		}
+		return
+	}
+
+	if *outFile == defaultOutFile && cfg.FileSuffix != "" {
+		*outFile = strings.TrimSuffix(in, ".go") + cfg.FileSuffix
+	}
+	*outFile = strings.Replace(*outFile, "{in}", in, 1)
+	if err := queryset.GenerateQuerySetsWithConfig(in, *outFile, cfg); err != nil {
 		log.Fatalf("can't generate query sets: %s", err)
 	}
 }
+
+// readHeaderFile returns content of file with header of generated files
+func readHeaderFile(path string) string {
+	hdr, err := ioutil.ReadFile(path)
+	if err != nil {
+		log.Fatalf("can't read header file: %s", err)
+	}
+	return string(hdr)
+}
+
+// loadConfig loads config file at path or, if path is empty, default config
+// file in directory of input in if it exists
+func loadConfig(path, in string) queryset.Config {
+	if path == "" {
+		dir := in
+		if fi, err := os.Stat(in); err != nil || !fi.IsDir() {
+			dir = filepath.Dir(in)
+		}
+		path = ""
+		for _, name := range queryset.ConfigFileNames {
+			if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+				path = filepath.Join(dir, name)
+				break
+			}
+		}
+		if path == "" {
+			return queryset.Config{}
+		}
+	}
+
+	cfg, err := queryset.LoadConfig(path)
+	if err != nil {
+		log.Fatalf("can't load config: %s", err)
+	}
+	return cfg
+}
+
+// graph runs graph verb: goqueryset graph -in models.go -format dot | dot -Tsvg
+func graph(args []string) {
+	fs := flag.NewFlagSet("graph", flag.ExitOnError)
+	inFile := fs.String("in", "models.go", "path to input file")
+	outFile := fs.String("out", "-", "path to output file, - is stdout")
+	format := fs.String("format", "dot", "format of graph: "+strings.Join(queryset.GraphFormats(), ", "))
+	if err := fs.Parse(args); err != nil {
+		log.Fatalf("can't parse args: %s", err)
+	}
+
+	var w io.Writer = os.Stdout
+	if *outFile != "-" {
+		f, err := os.Create(*outFile)
+		if err != nil {
+			log.Fatalf("can't create out file: %s", err)
+		}
+		defer func() {
+			if err = f.Close(); err != nil {
+				fmt.Fprintf(os.Stderr, "can't close out file: %s\n", err)
+			}
+		}()
+		w = f
+	}
+
+	if err := queryset.WriteModelsGraph(*inFile, w, *format); err != nil {
+		log.Fatalf("can't write models graph: %s", err)
+	}
+}
@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"bytes"
 	"fmt"
 	"go/ast"
 	"go/parser"
@@ -9,6 +10,7 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"regexp"
 	"strings"
 
 	"golang.org/x/tools/go/loader"
@@ -41,14 +43,19 @@ type ParsedStruct struct {
 	TypeName string
 	Fields   []StructField
 	Doc      *ast.CommentGroup // line comments; or nil
+	File     string            // path of file with struct declaration
+	Line     int               // line of struct declaration in file
 }
 
 func fileNameToPkgName(filePath, absFilePath string) string {
-	dir := filepath.Dir(absFilePath)
+	return dirToPkgName(filepath.Dir(filePath), filepath.Dir(absFilePath))
+}
+
+func dirToPkgName(dirPath, dir string) string {
 	gopath := os.Getenv("GOPATH")
 	if !strings.HasPrefix(dir, gopath) {
 		// not in GOPATH
-		return "./" + filepath.Dir(filePath)
+		return "./" + dirPath
 	}
 
 	r := strings.TrimPrefix(dir, gopath)
@@ -135,6 +142,95 @@ func GetStructsInFile(filePath string) (*loader.PackageInfo, ParsedStructs, erro
 			filePath, packageFullName)
 	}
 
+	structFiles := map[string]string{}
+	for name := range neededStructs {
+		structFiles[name] = filePath
+	}
+
+	return pkgInfo, parseStructs(lprog.Fset, pkgInfo, neededStructs, structFiles), nil
+}
+
+// GetStructsInDir lists all structures in all files of package in directory
+// dirPath (except tests and generated files) and returns them with all fields
+// and their files
+func GetStructsInDir(dirPath string) (*loader.PackageInfo, ParsedStructs, error) {
+	absDirPath, err := filepath.Abs(dirPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("can't get abs path for %s", dirPath)
+	}
+
+	packageFullName := dirToPkgName(filepath.Clean(dirPath), absDirPath)
+	lprog, err := loadProgramFromPackage(packageFullName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pkgInfo := lprog.Package(packageFullName)
+	if pkgInfo == nil {
+		return nil, nil, fmt.Errorf("can't load types for directory %s in package %q",
+			dirPath, packageFullName)
+	}
+
+	// files of package are already parsed by loader: only files matching
+	// build constraints are taken
+	neededStructs := structNamesInfo{}
+	structFiles := map[string]string{}
+	for _, f := range pkgInfo.Files {
+		if isGeneratedFile(f) {
+			continue
+		}
+
+		filePath := filepath.Join(dirPath, filepath.Base(lprog.Fset.File(f.Pos()).Name()))
+		v := structNamesVisitor{
+			names: structNamesInfo{},
+		}
+		ast.Walk(&v, f)
+		for name, decl := range v.names {
+			neededStructs[name] = decl
+			structFiles[name] = filePath
+		}
+	}
+
+	return pkgInfo, parseStructs(lprog.Fset, pkgInfo, neededStructs, structFiles), nil
+}
+
+var generatedCodeRe = regexp.MustCompile(`^// Code generated .* DO NOT EDIT\.$`)
+
+// IsGeneratedCode returns true if source code has standard comment of
+// generated code before package clause
+func IsGeneratedCode(code []byte) bool {
+	if i := bytes.Index(code, []byte("\npackage ")); i != -1 {
+		code = code[:i]
+	}
+	for _, l := range bytes.Split(code, []byte("\n")) {
+		if generatedCodeRe.Match(bytes.TrimSuffix(l, []byte("\r"))) {
+			return true
+		}
+	}
+	return false
+}
+
+// isGeneratedFile returns true if file has standard comment of generated code
+// before package clause
+func isGeneratedFile(f *ast.File) bool {
+	for _, g := range f.Comments {
+		if g.Pos() > f.Package {
+			break
+		}
+		for _, c := range g.List {
+			if generatedCodeRe.MatchString(c.Text) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// parseStructs parses needed structs of package, structFiles maps their
+// names to paths of their files
+func parseStructs(fset *token.FileSet, pkgInfo *loader.PackageInfo, neededStructs structNamesInfo,
+	structFiles map[string]string) ParsedStructs {
+
 	ret := ParsedStructs{}
 
 	scope := pkgInfo.Pkg.Scope()
@@ -151,14 +247,63 @@ func GetStructsInFile(filePath string) (*loader.PackageInfo, ParsedStructs, erro
 		parsedStruct := parseStruct(s, neededStructs[name])
 		if parsedStruct != nil {
 			parsedStruct.TypeName = name
+			parsedStruct.File = structFiles[name]
+			parsedStruct.Line = fset.Position(obj.Pos()).Line
 			ret[name] = *parsedStruct
 		}
 	}
 
-	return pkgInfo, ret, nil
+	return ret
+}
+
+// getEmbeddedStruct returns struct, which fields are promoted from field f:
+// anonymous struct field (or pointer to it) or field with gorm "embedded" tag
+// setting. time.Time is a column, not embedded struct.
+func getEmbeddedStruct(f *types.Var, tag reflect.StructTag) *types.Struct {
+	if !f.Anonymous() && !hasGormTagSetting(tag, "EMBEDDED") {
+		return nil
+	}
+
+	t := f.Type()
+	for {
+		p, ok := t.(*types.Pointer)
+		if !ok {
+			break
+		}
+		t = p.Elem()
+	}
+
+	if n, ok := t.(*types.Named); ok && n.Obj().Pkg() != nil &&
+		n.Obj().Pkg().Path() == "time" && n.Obj().Name() == "Time" {
+		return nil
+	}
+
+	s, _ := t.Underlying().(*types.Struct)
+	return s
+}
+
+// hasGormTagSetting returns true if gorm tag has setting, e.g. EMBEDDED
+func hasGormTagSetting(tag reflect.StructTag, setting string) bool {
+	for _, kv := range strings.Split(tag.Get("gorm"), ";") {
+		k := strings.SplitN(kv, ":", 2)[0]
+		if strings.ToUpper(strings.TrimSpace(k)) == setting {
+			return true
+		}
+	}
+	return false
 }
 
 func parseStruct(s *types.Struct, decl *ast.GenDecl) *ParsedStruct {
+	// promoted field is shadowed by field of struct or by field promoted
+	// from preceding embedded struct with the same name
+	names := map[string]bool{}
+	for i := 0; i < s.NumFields(); i++ {
+		f := s.Field(i)
+		if getEmbeddedStruct(f, reflect.StructTag(s.Tag(i))) == nil {
+			names[f.Name()] = true
+		}
+	}
+
 	var fields []StructField
 	for i := 0; i < s.NumFields(); i++ {
 		f := s.Field(i)
@@ -167,14 +312,19 @@ func parseStruct(s *types.Struct, decl *ast.GenDecl) *ParsedStruct {
 			continue
 		}
 
-		if f.Anonymous() {
-			e := f.Type().Underlying().(*types.Struct)
+		tag := reflect.StructTag(s.Tag(i))
+		if e := getEmbeddedStruct(f, tag); e != nil {
 			pe := parseStruct(e, nil)
 			if pe == nil {
 				continue
 			}
 
-			fields = append(fields, pe.Fields...)
+			for _, pf := range pe.Fields {
+				if !names[pf.name] {
+					names[pf.name] = true
+					fields = append(fields, pf)
+				}
+			}
 			continue
 		}
 
@@ -185,7 +335,7 @@ func parseStruct(s *types.Struct, decl *ast.GenDecl) *ParsedStruct {
 		sf := StructField{
 			name: f.Name(),
 			typ:  f.Type(),
-			tag:  reflect.StructTag(s.Tag(i)),
+			tag:  tag,
 		}
 
 		fields = append(fields, sf)
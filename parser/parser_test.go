@@ -9,6 +9,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"strings"
 	"testing"
 
@@ -237,6 +238,45 @@ func TestGetStructsInFile(t *testing.T) {
 				}`,
 			expectedStructFields: []string{"ID", "CreatedAt", "UpdatedAt", "DeletedAt", "F"},
 		},
+		{ // test pointer embedding and embedding by tag
+			code: `package p
+				import "github.com/jinzhu/gorm"
+				type Author struct {
+					AuthorName string
+				}
+				type T struct {
+					*gorm.Model
+					Author Author ` + "`gorm:\"embedded\"`" + `
+					F int
+				}`,
+			expectedStructFields: []string{"ID", "CreatedAt", "UpdatedAt", "DeletedAt", "AuthorName", "F"},
+			expectedStructsCount: 2,
+		},
+		{ // test shadowing of promoted fields
+			code: `package p
+				import "github.com/jinzhu/gorm"
+				type Base struct {
+					ID        string
+					CreatedAt int
+				}
+				type T struct {
+					gorm.Model
+					Base
+					ID string
+				}`,
+			expectedStructFields: []string{"ID", "CreatedAt", "UpdatedAt", "DeletedAt"},
+			expectedStructsCount: 2,
+		},
+		{ // test embedding of non-struct types and time
+			code: `package p
+				import "time"
+				type Status string
+				type T struct {
+					time.Time
+					Status
+				}`,
+			expectedStructFields: []string{"Time", "Status"},
+		},
 		{
 			code: `package p
 			type MyType int`,
@@ -299,6 +339,10 @@ func testStructFields(t *testing.T, tc structFieldsCase) {
 		fieldNames = append(fieldNames, field.name)
 	}
 	assert.Len(t, fieldNames, len(tc.expectedStructFields))
+	expectedFieldNames := append([]string{}, tc.expectedStructFields...)
+	sort.Strings(expectedFieldNames)
+	sort.Strings(fieldNames)
+	assert.Equal(t, expectedFieldNames, fieldNames)
 
 	if tc.expectedDoc != nil {
 		docLines := []string{}
@@ -309,3 +353,35 @@ func testStructFields(t *testing.T, tc structFieldsCase) {
 		assert.Equal(t, tc.expectedDoc, docLines)
 	}
 }
+
+func TestGetStructsInDir(t *testing.T) {
+	f := getTmpFileForCode(`package p
+		type T1 struct {
+			F int
+		}`)
+	defer removeTempFileAndDir(f)
+
+	dir := filepath.Dir(f.Name())
+	secondFile := filepath.Join(dir, "second.go")
+	err := ioutil.WriteFile(secondFile, []byte(`package p
+		type T2 struct {
+			T1 T1
+		}`), 0600)
+	assert.Nil(t, err)
+	err = ioutil.WriteFile(filepath.Join(dir, "second_test.go"), []byte(`package p
+		type T3 struct {}`), 0600)
+	assert.Nil(t, err)
+	err = ioutil.WriteFile(filepath.Join(dir, "generated.go"), []byte(`// Code generated by go-queryset. DO NOT EDIT.
+
+		package p
+		type T4 struct {}`), 0600)
+	assert.Nil(t, err)
+
+	pkg, structs, err := GetStructsInDir(dir)
+	assert.Nil(t, err)
+	assert.NotNil(t, pkg)
+	assert.Len(t, structs, 2)
+	assert.Equal(t, f.Name(), structs["T1"].File)
+	assert.Equal(t, secondFile, structs["T2"].File)
+	assert.Len(t, structs["T2"].Fields, 1)
+}
@@ -2,36 +2,334 @@ package queryset
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"log"
 	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
 
 	"github.com/jirfag/go-queryset/parser"
 	"golang.org/x/tools/go/loader"
 	"golang.org/x/tools/imports"
 )
 
+// Config is a config of querysets generation
+type Config struct {
+	// Dialect is a name of target SQL dialect: mysql, postgres etc.
+	// Generic SQL is generated if it's empty.
+	Dialect string
+
+	// DebugBuildTag is a build tag of debug variant (e.g. !prod): debug
+	// methods (Debug, DryRun, N+1 queries detection) are generated into
+	// {out}_debug.go file with this tag and their no-op stubs are generated
+	// into {out}_nodebug.go file with negated tag. Debug methods
+	// aren't generated if it's empty.
+	DebugBuildTag string
+
+	// FilterPrefix is a default prefix of names of fields filters, e.g.
+	// Filter for FilterNameEq. Struct's "prefix" option overrides it.
+	FilterPrefix string
+
+	// AllStructs inverts default of generation: querysets are generated for
+	// all structs except ones with "gen:qs skip" line in doc, not only for
+	// ones with "gen:qs" line.
+	AllStructs bool
+
+	// CheckWhere enables checks of raw conditions of Where: columns referenced
+	// by string literals passed to Where of querysets constructed in the same
+	// chain of calls in files of package must exist in structs.
+	CheckWhere bool
+
+	// TemplatesDir is a directory of user templates (*.tmpl files) extending
+	// generated code without forking: template "struct" defined by them is
+	// executed for config of every struct, e.g. to generate methods of audit
+	// columns, template "package" is executed once per package.
+	TemplatesDir string
+
+	// TenantField is a name of field of tenant, e.g. TenantID: constructors
+	// of querysets of structs with this field need tenant and filter rows by
+	// it, so rows of other tenants can't leak.
+	TenantField string
+
+	// Include and Exclude are glob patterns of names of files of package
+	// (e.g. *_model.go): querysets are generated only for structs of files
+	// matching any of Include patterns (all files if it's empty) and not
+	// matching any of Exclude patterns.
+	Include []string
+	Exclude []string
+
+	// Check makes generation fail if out files aren't up to date instead of
+	// writing them, e.g. to check generated code in CI.
+	Check bool
+
+	// Force disables skipping of generation if neither sources of package,
+	// config, user templates and generator nor out files were changed since
+	// the previous generation
+	Force bool
+
+	// Strict makes Update, Delete and SoftDelete of querysets, updaters and
+	// objects of all structs return ErrNoRowsAffected if no rows were
+	// affected like "strict" option of struct does. Their Num variants
+	// (e.g. DeleteNum) return number of affected rows.
+	Strict bool
+
+	// Models are configs of querysets of structs by names of structs set in
+	// config file, see LoadConfig
+	Models map[string]ModelConfig
+
+	// OutPkg is a directory of package of generated querysets, e.g.
+	// models/queries: querysets are generated into it instead of package of
+	// structs, which is imported. Only exported fields and types of structs
+	// are used and object methods (Create, Update etc) aren't generated: Go
+	// has no methods of types of other packages. Models are aliased there
+	// (type User = models.User), so generated package needs Go 1.9.
+	OutPkg string
+
+	// BuildTag is a build constraint expression of all generated files, e.g.
+	// !codeanalysis: it's combined with tags of debug variants
+	BuildTag string
+
+	// Header is a header of generated files (e.g. license) replacing default
+	// one: it must have "// Code generated ... DO NOT EDIT." line, so
+	// generated files are recognized by tools and aren't parsed as models
+	Header string
+
+	// FileSuffix is a suffix of names of out files generated next to files
+	// of package, e.g. _queryset.go for models_queryset.go: it's
+	// DefaultFileSuffix if it's empty
+	FileSuffix string
+
+	// Jobs is a max number of structs and out files generated concurrently,
+	// it's a number of CPUs if it isn't positive
+	Jobs int
+}
+
+// outOfDateError is returned in Check mode if generated code differs from
+// code in out file, Diff is their unified diff
+type outOfDateError struct {
+	Diff string
+}
+
+func (e outOfDateError) Error() string {
+	return "out file is out of date, regenerate it:\n" + e.Diff
+}
+
+var buildTagRe = regexp.MustCompile(`^!?[\w.]+$`)
+
+// negateBuildTag returns negation of build tag: prod -> !prod, !prod -> prod
+func negateBuildTag(tag string) (string, error) {
+	if !buildTagRe.MatchString(tag) {
+		return "", fmt.Errorf("invalid build tag %q: only one tag or its negation is supported", tag)
+	}
+
+	if strings.HasPrefix(tag, "!") {
+		return tag[1:], nil
+	}
+	return "!" + tag, nil
+}
+
+// debugVariantPath returns path of debug variant file of outFilePath
+func debugVariantPath(outFilePath, suffix string) string {
+	return strings.TrimSuffix(outFilePath, ".go") + suffix + ".go"
+}
+
 // GenerateQuerySets generates output file with querysets
 func GenerateQuerySets(inFilePath, outFilePath string) error {
+	return GenerateQuerySetsWithConfig(inFilePath, outFilePath, Config{})
+}
+
+// GenerateQuerySetsWithConfig generates output file with querysets using config
+func GenerateQuerySetsWithConfig(inFilePath, outFilePath string, cfg Config) error {
+	outFilePath = getOutFilePath(outFilePath, cfg)
+	cache := newGenerationCache(inFilePath, outFilePath, cfg)
+	if cache.isUpToDate() {
+		log.Printf("querysets of %s are up to date, generation is skipped", inFilePath)
+		return nil
+	}
+
 	pkgInfo, structs, err := parser.GetStructsInFile(inFilePath)
 	if err != nil {
 		return fmt.Errorf("can't parse file %s to get structs: %s", inFilePath, err)
 	}
 
-	var r io.Reader
-	r, err = GenerateQuerySetsForStructs(pkgInfo, structs)
+	configs, err := getQuerySetConfigs(pkgInfo, structs, cfg)
 	if err != nil {
 		return fmt.Errorf("can't generate query sets: %s", err)
 	}
 
-	if r == nil {
+	ok, err := generateQuerySetsFile(pkgInfo, structs, configs, outFilePath, cfg, querySetsPart{PackageFuncs: true})
+	if err != nil {
+		return err
+	}
+
+	if !ok {
 		return fmt.Errorf("no structs to generate query set in %s", inFilePath)
 	}
 
-	if err = writeQuerySetsToOutput(r, pkgInfo, outFilePath); err != nil {
-		return fmt.Errorf("can't save query sets to out file %s: %s", outFilePath, err)
+	cache.save(generatedFilePaths(outFilePath, cfg))
+	return nil
+}
+
+// GenerateQuerySetsForPackage generates querysets of structs of all files of
+// package in directory dir using config: into outFilePath if it isn't empty,
+// otherwise into {file}{suffix} (suffix is Config.FileSuffix or
+// DefaultFileSuffix) next to every file with such structs.
+// Package level funcs are generated only into the first of these files.
+func GenerateQuerySetsForPackage(dir, outFilePath string, cfg Config) error {
+	if cfg.FileSuffix != "" && (!strings.HasSuffix(cfg.FileSuffix, ".go") || cfg.FileSuffix == ".go") {
+		return fmt.Errorf("invalid suffix %q of out files: it must end with .go, e.g. _queryset.go", cfg.FileSuffix)
+	}
+
+	cache := newGenerationCache(dir, outFilePath, cfg)
+	if cache.isUpToDate() {
+		log.Printf("querysets of package in %s are up to date, generation is skipped", dir)
+		return nil
+	}
+
+	pkgInfo, structs, err := parser.GetStructsInDir(dir)
+	if err != nil {
+		return fmt.Errorf("can't parse package in %s to get structs: %s", dir, err)
+	}
+
+	if structs, err = filterStructsByFiles(structs, cfg.Include, cfg.Exclude); err != nil {
+		return err
+	}
+
+	parts := []querySetsPart{{PackageFuncs: true}}
+	outFilePaths := []string{getOutFilePath(outFilePath, cfg)}
+	if outFilePath == "" {
+		parts, outFilePaths = nil, nil
+		for _, file := range getFilesWithQuerySets(structs, cfg) {
+			parts = append(parts, querySetsPart{
+				File:         file,
+				PackageFuncs: len(parts) == 0,
+			})
+			outFilePaths = append(outFilePaths, getOutFilePath(getFileOutFilePath(file, cfg), cfg))
+		}
+	}
+
+	configs, err := getQuerySetConfigs(pkgInfo, structs, cfg)
+	if err != nil {
+		return fmt.Errorf("can't generate query sets: %s", err)
+	}
+
+	// out files are generated concurrently from configs of all structs
+	generatedParts := make([]bool, len(parts))
+	err = runParallel(cfg.Jobs, len(parts), func(i int) error {
+		ok, err := generateQuerySetsFile(pkgInfo, structs, configs, outFilePaths[i], cfg, parts[i])
+		if err != nil {
+			return positionError(parts[i].File, 0, err)
+		}
+		generatedParts[i] = ok
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	var generated []string
+	for i, ok := range generatedParts {
+		if ok {
+			generated = append(generated, generatedFilePaths(outFilePaths[i], cfg)...)
+		}
+	}
+
+	if len(generated) == 0 {
+		return fmt.Errorf("no structs to generate query set in %s", dir)
+	}
+
+	cache.save(generated)
+	return nil
+}
+
+// generatedFilePaths returns paths of files generated into outFilePath:
+// it and files of debug variants
+func generatedFilePaths(outFilePath string, cfg Config) []string {
+	if cfg.DebugBuildTag == "" {
+		return []string{outFilePath}
+	}
+	return []string{outFilePath, debugVariantPath(outFilePath, "_debug"), debugVariantPath(outFilePath, "_nodebug")}
+}
+
+// filterStructsByFiles returns structs of files, which names match any of
+// include patterns (if they are set) and don't match exclude patterns
+func filterStructsByFiles(structs parser.ParsedStructs, include, exclude []string) (parser.ParsedStructs, error) {
+	matchesAny := func(patterns []string, file string) (bool, error) {
+		for _, p := range patterns {
+			ok, err := filepath.Match(p, filepath.Base(file))
+			if err != nil {
+				return false, fmt.Errorf("invalid pattern %q of files: %s", p, err)
+			}
+			if ok {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+
+	ret := parser.ParsedStructs{}
+	for name, s := range structs {
+		included, err := matchesAny(include, s.File)
+		if err != nil {
+			return nil, err
+		}
+		excluded, err := matchesAny(exclude, s.File)
+		if err != nil {
+			return nil, err
+		}
+
+		if (len(include) == 0 || included) && !excluded {
+			ret[name] = s
+		}
+	}
+	return ret, nil
+}
+
+// getFilesWithQuerySets returns sorted paths of files with structs to
+// generate querysets
+func getFilesWithQuerySets(structs parser.ParsedStructs, cfg Config) []string {
+	files := map[string]bool{}
+	for _, s := range structs {
+		if _, ok := getStructQuerySetOptions(s, cfg); ok {
+			files[s.File] = true
+		}
+	}
+
+	var ret []string
+	for file := range files {
+		ret = append(ret, file)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// generateQuerySetsFile generates part of querysets into outFilePath, it
+// returns false if there are no structs to generate querysets in part
+func generateQuerySetsFile(pkgInfo *loader.PackageInfo, structs parser.ParsedStructs,
+	configs querySetStructConfigSlice, outFilePath string, cfg Config, part querySetsPart) (bool, error) {
+
+	r, err := generateQuerySetsPart(configs, structs, cfg, part)
+	if err != nil {
+		return false, fmt.Errorf("can't generate query sets: %s", err)
+	}
+
+	if r == nil {
+		return false, nil
+	}
+
+	if err = writeQuerySetsToOutput(r, pkgInfo, outFilePath, "", cfg); err != nil {
+		return false, fmt.Errorf("can't save query sets to out file %s: %s", outFilePath, err)
+	}
+
+	if cfg.DebugBuildTag != "" {
+		if err = generateDebugVariants(pkgInfo, structs, configs, outFilePath, cfg, part); err != nil {
+			return false, err
+		}
 	}
 
 	var absOutPath string
@@ -40,24 +338,219 @@ func GenerateQuerySets(inFilePath, outFilePath string) error {
 		absOutPath = outFilePath
 	}
 
-	log.Printf("successfully wrote querysets to %s", absOutPath)
+	if cfg.Check {
+		log.Printf("querysets in %s are up to date", absOutPath)
+	} else {
+		log.Printf("successfully wrote querysets to %s", absOutPath)
+	}
+	return true, nil
+}
+
+func generateDebugVariants(pkgInfo *loader.PackageInfo, structs parser.ParsedStructs,
+	configs querySetStructConfigSlice, outFilePath string, cfg Config, part querySetsPart) error {
+
+	noDebugTag, err := negateBuildTag(cfg.DebugBuildTag)
+	if err != nil {
+		return err
+	}
+
+	debug, noDebug, err := generateDebugVariantsPart(configs, structs, cfg, part)
+	if err != nil {
+		return fmt.Errorf("can't generate debug methods: %s", err)
+	}
+
+	variants := []struct {
+		r        io.Reader
+		suffix   string
+		buildTag string
+	}{
+		{debug, "_debug", cfg.DebugBuildTag},
+		{noDebug, "_nodebug", noDebugTag},
+	}
+	for _, v := range variants {
+		outFile := debugVariantPath(outFilePath, v.suffix)
+		if err = writeQuerySetsToOutput(v.r, pkgInfo, outFile, v.buildTag, cfg); err != nil {
+			return fmt.Errorf("can't save debug methods to out file %s: %s", outFile, err)
+		}
+	}
+
 	return nil
 }
 
-func writeQuerySetsToOutput(r io.Reader, pkgInfo *loader.PackageInfo, outFile string) error {
+// getOutPackage returns name of package of generated querysets and import
+// of package of structs if it's another package (Config.OutPkg)
+func getOutPackage(pkgInfo *loader.PackageInfo, cfg Config) (name, modelImport string, err error) {
+	if cfg.OutPkg == "" {
+		return pkgInfo.Pkg.Name(), "", nil
+	}
+
+	absOutPkg, err := filepath.Abs(cfg.OutPkg)
+	if err != nil {
+		return "", "", fmt.Errorf("can't get abs path of out package %s: %s", cfg.OutPkg, err)
+	}
+	name = strings.Replace(filepath.Base(absOutPkg), "-", "_", -1)
+	if !isIdentifier(name) {
+		return "", "", fmt.Errorf("invalid name %q of out package %s", name, cfg.OutPkg)
+	}
+	if name == pkgInfo.Pkg.Name() {
+		return "", "", fmt.Errorf("out package %s has the same name as package of structs", cfg.OutPkg)
+	}
+
+	path := pkgInfo.Pkg.Path()
+	if strings.HasPrefix(path, ".") {
+		return "", "", fmt.Errorf("package of structs isn't in GOPATH: it can't be imported by out package %s",
+			cfg.OutPkg)
+	}
+	if pkgInfo.Pkg.Name() != filepath.Base(path) {
+		return name, fmt.Sprintf("\n\t%s %q", pkgInfo.Pkg.Name(), path), nil
+	}
+	return name, fmt.Sprintf("\n\t%q", path), nil
+}
+
+// getOutFilePath returns path of out file in out package (Config.OutPkg)
+// if it's set
+func getOutFilePath(outFilePath string, cfg Config) string {
+	if cfg.OutPkg == "" {
+		return outFilePath
+	}
+	return filepath.Join(cfg.OutPkg, filepath.Base(outFilePath))
+}
+
+// generatedHdr marks generated files: their structs aren't taken by
+// generation for package
+const generatedHdr = "// Code generated by go-queryset. DO NOT EDIT.\n\n"
+
+// DefaultFileSuffix is a suffix of names of out files generated next to
+// files of package if Config.FileSuffix is empty
+const DefaultFileSuffix = "_queryset.go"
+
+// getFileOutFilePath returns path of out file of querysets of structs of
+// file of package
+func getFileOutFilePath(file string, cfg Config) string {
+	suffix := cfg.FileSuffix
+	if suffix == "" {
+		suffix = DefaultFileSuffix
+	}
+	return strings.TrimSuffix(file, ".go") + suffix
+}
+
+// getGeneratedHeader returns header of generated files: custom one or
+// default one
+func getGeneratedHeader(cfg Config) (string, error) {
+	if cfg.Header == "" {
+		return generatedHdr, nil
+	}
+
+	hdr := strings.TrimSpace(cfg.Header) + "\n\n"
+	if !parser.IsGeneratedCode([]byte(hdr)) {
+		return "", errors.New("header of generated files has no \"// Code generated ... DO NOT EDIT.\" line")
+	}
+	for _, l := range strings.Split(strings.TrimSpace(hdr), "\n") {
+		if l != "" && !strings.HasPrefix(l, "//") {
+			return "", fmt.Errorf("line %q of header of generated files isn't a comment", l)
+		}
+	}
+	return hdr, nil
+}
+
+// getBuildConstraints returns build constraints lines of generated file
+// satisfying all build constraint expressions tags: an expression is an
+// OR (||) of ANDs (&&) of tags or their negations, parentheses aren't
+// supported. Both //go:build and // +build lines are written by hand,
+// so go/build/constraint of Go 1.16 isn't needed
+func getBuildConstraints(tags ...string) (string, error) {
+	var goBuild []string
+	options := [][]string{nil} // OR of ANDs of all expressions
+	for _, tag := range tags {
+		if tag == "" {
+			continue
+		}
+
+		ors := strings.Split(tag, "||")
+		var tagOptions [][]string
+		for _, or := range ors {
+			var terms []string
+			for _, term := range strings.Split(or, "&&") {
+				term = strings.TrimSpace(term)
+				if !buildTagRe.MatchString(term) {
+					return "", fmt.Errorf("invalid build tag %q: term %q isn't a tag or its negation", tag, term)
+				}
+				terms = append(terms, term)
+			}
+			tagOptions = append(tagOptions, terms)
+		}
+
+		x := joinBuildTerms(tagOptions, " && ", " || ")
+		if len(tagOptions) > 1 {
+			x = "(" + x + ")"
+		}
+		goBuild = append(goBuild, x)
+
+		var product [][]string
+		for _, o := range options {
+			for _, to := range tagOptions {
+				product = append(product, append(append([]string{}, o...), to...))
+			}
+		}
+		options = product
+	}
+	if len(goBuild) == 0 {
+		return "", nil
+	}
+
+	x := strings.Join(goBuild, " && ")
+	if len(goBuild) == 1 {
+		x = strings.TrimSuffix(strings.TrimPrefix(x, "("), ")")
+	}
+	return "//go:build " + x + "\n// +build " + joinBuildTerms(options, ",", " ") + "\n\n", nil
+}
+
+// joinBuildTerms joins OR of ANDs of build tags with separators
+func joinBuildTerms(options [][]string, and, or string) string {
+	var parts []string
+	for _, o := range options {
+		parts = append(parts, strings.Join(o, and))
+	}
+	return strings.Join(parts, or)
+}
+
+func writeQuerySetsToOutput(r io.Reader, pkgInfo *loader.PackageInfo, outFile, buildTag string, cfg Config) error {
 	const hdrTmpl = `package %s
 
 import (
+	"context"
+	"database/sql"
+	"encoding/json"
 	"fmt"
+	"hash/fnv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
+	"unicode/utf8"
 
-	"github.com/jinzhu/gorm"
+	"github.com/jinzhu/gorm"%s
 )
 `
 
+	hdr, err := getGeneratedHeader(cfg)
+	if err != nil {
+		return err
+	}
+	constraints, err := getBuildConstraints(cfg.BuildTag, buildTag)
+	if err != nil {
+		return err
+	}
+
 	var buf bytes.Buffer
-	pkgName := fmt.Sprintf(hdrTmpl, pkgInfo.Pkg.Name())
-	if _, err := buf.WriteString(pkgName); err != nil {
+	if _, err = buf.WriteString(hdr + constraints); err != nil {
+		return fmt.Errorf("can't write generated code header into buf: %s", err)
+	}
+	pkgName, modelImport, err := getOutPackage(pkgInfo, cfg)
+	if err != nil {
+		return err
+	}
+	if _, err = fmt.Fprintf(&buf, hdrTmpl, pkgName, modelImport); err != nil {
 		return fmt.Errorf("can't write hdr string into buf: %s", err)
 	}
 	if _, err := io.Copy(&buf, r); err != nil {
@@ -69,6 +562,22 @@ import (
 		return fmt.Errorf("can't format generated file: %s", err)
 	}
 
+	// unchanged file isn't rewritten: its modification time is kept for
+	// build tools
+	prev, err := ioutil.ReadFile(outFile)
+	if err == nil && bytes.Equal(prev, formattedRes) {
+		return nil
+	}
+	if cfg.Check {
+		return outOfDateError{Diff: unifiedDiff(outFile, prev, formattedRes)}
+	}
+
+	if cfg.OutPkg != "" {
+		if err = os.MkdirAll(filepath.Dir(outFile), 0750); err != nil {
+			return fmt.Errorf("can't create directory of out package: %s", err)
+		}
+	}
+
 	var outF *os.File
 	outF, err = os.OpenFile(outFile, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0640)
 	if err != nil {
@@ -0,0 +1,228 @@
+package queryset
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"go/build"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jirfag/go-queryset/parser"
+)
+
+// generationCache skips generation of querysets if neither its inputs
+// (sources of package, config, user templates and generator) nor its out
+// files were changed since the previous generation. Entries are kept in
+// temporary directory: losing them only makes generation slower.
+type generationCache struct {
+	path   string // path of entry
+	inputs string // hash of inputs
+}
+
+// generationCacheEntry is an entry of cache saved after generation
+type generationCacheEntry struct {
+	Inputs  string            `json:"inputs"`
+	Outputs map[string]string `json:"outputs"` // hashes of out files by paths
+}
+
+// newGenerationCache returns cache of generation of querysets of file or
+// package in directory in into out file (or next to files of package if it's
+// empty). Nil is returned if inputs can't be hashed: generation isn't cached
+// then.
+func newGenerationCache(in, outFilePath string, cfg Config) *generationCache {
+	if cfg.Check || cfg.Force {
+		return nil
+	}
+
+	absIn, err := filepath.Abs(in)
+	if err != nil {
+		return nil
+	}
+	absOut := ""
+	if outFilePath != "" {
+		if absOut, err = filepath.Abs(outFilePath); err != nil {
+			return nil
+		}
+	}
+
+	dir := absIn
+	if fi, err := os.Stat(absIn); err != nil || !fi.IsDir() {
+		dir = filepath.Dir(absIn) // structs of file are parsed with types of its package
+	}
+	inputs, err := hashGenerationInputs(dir, cfg)
+	if err != nil {
+		return nil
+	}
+
+	key := sha256.Sum256([]byte(absIn + "\x00" + absOut))
+	return &generationCache{
+		path:   filepath.Join(os.TempDir(), "go-queryset", hex.EncodeToString(key[:])+".json"),
+		inputs: inputs,
+	}
+}
+
+// hashGenerationInputs returns hash of inputs of generation: Go files of
+// package and of packages imported by it (types of fields can be declared
+// there) except generated files, config, user templates and executable of
+// generator, which templates and methods are compiled into
+func hashGenerationInputs(dir string, cfg Config) (string, error) {
+	h := sha256.New()
+	cfgJSON, err := json.Marshal(cfg)
+	if err != nil {
+		return "", err
+	}
+	h.Write(cfgJSON)
+
+	goFiles, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return "", err
+	}
+	var templates []string
+	if cfg.TemplatesDir != "" {
+		if templates, err = filepath.Glob(filepath.Join(cfg.TemplatesDir, "*.tmpl")); err != nil {
+			return "", err
+		}
+	}
+	exe, err := exec.LookPath(os.Args[0])
+	if err != nil {
+		return "", err
+	}
+	importedFiles, err := getImportedFiles(dir)
+	if err != nil {
+		return "", err
+	}
+
+	files := append(append(append(goFiles, templates...), importedFiles...), exe)
+	sort.Strings(files)
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return "", err
+		}
+		if parser.IsGeneratedCode(data) {
+			continue
+		}
+		fmt.Fprintf(h, "%s\x00%d\x00", file, len(data))
+		h.Write(data)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// getImportedFiles returns Go files of packages imported by package in dir
+// directly or indirectly except packages of standard library
+func getImportedFiles(dir string) ([]string, error) {
+	dir, err := filepath.Abs(dir) // vendored packages are found only from absolute dir
+	if err != nil {
+		return nil, err
+	}
+	pkg, err := build.ImportDir(dir, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	visited := map[string]bool{}
+	var visit func(imports []string, srcDir string) error
+	visit = func(imports []string, srcDir string) error {
+		for _, path := range imports {
+			p, err := build.Import(path, srcDir, 0)
+			if err != nil {
+				return err
+			}
+			if p.Goroot || visited[p.Dir] {
+				continue
+			}
+			visited[p.Dir] = true
+
+			for _, f := range append(p.GoFiles, p.CgoFiles...) {
+				files = append(files, filepath.Join(p.Dir, f))
+			}
+			if err = visit(p.Imports, p.Dir); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err = visit(pkg.Imports, dir); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// hashFile returns hash of content of file
+func hashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err = io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// isUpToDate returns true if inputs weren't changed since generation into
+// out files saved in cache and out files weren't changed too
+func (c *generationCache) isUpToDate() bool {
+	if c == nil {
+		return false
+	}
+
+	data, err := ioutil.ReadFile(c.path)
+	if err != nil {
+		return false
+	}
+	var e generationCacheEntry
+	if err = json.Unmarshal(data, &e); err != nil || e.Inputs != c.inputs || len(e.Outputs) == 0 {
+		return false
+	}
+
+	for path, hash := range e.Outputs {
+		if h, err := hashFile(path); err != nil || h != hash {
+			return false
+		}
+	}
+	return true
+}
+
+// save saves inputs and out files of generation into cache: errors are
+// ignored, generation isn't cached then
+func (c *generationCache) save(outFilePaths []string) {
+	if c == nil {
+		return
+	}
+
+	e := generationCacheEntry{Inputs: c.inputs, Outputs: map[string]string{}}
+	for _, path := range outFilePaths {
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			return
+		}
+		if e.Outputs[absPath], err = hashFile(absPath); err != nil {
+			return
+		}
+	}
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	if err = os.MkdirAll(filepath.Dir(c.path), 0700); err != nil {
+		return
+	}
+	_ = ioutil.WriteFile(c.path, data, 0600)
+}
@@ -0,0 +1,240 @@
+package queryset
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+
+	"github.com/jirfag/go-queryset/parser"
+	"github.com/jirfag/go-queryset/queryset/methods"
+	"gopkg.in/yaml.v2"
+)
+
+// ConfigFileName is a name of config file of generation looked up in
+// directory of input files
+const ConfigFileName = "queryset.yaml"
+
+// ConfigFileNames are names of config files looked up in directory of input
+// files in order of priority: JSON config files are supported too
+var ConfigFileNames = []string{ConfigFileName, "queryset.yml", "queryset.json"}
+
+// ModelConfig is a config of generation of queryset of model (struct) set
+// in config file instead of doc of struct
+type ModelConfig struct {
+	// Options are options of "gen:qs" line, e.g. "cache name=Users": struct
+	// listed in config file needs queryset even if its doc has no such line.
+	// Options set in doc override them.
+	Options string `json:"options" yaml:"options"`
+
+	// Methods are glob patterns of names of queryset methods to generate,
+	// e.g. ["All", "One", "*Eq", "OrderBy*"]: all methods are generated if
+	// it's empty. Methods used by generated code are always generated.
+	Methods []string `json:"methods" yaml:"methods"`
+}
+
+// configFile is a YAML (or JSON) config file of generation: its settings are
+// the same as flags of goqueryset
+type configFile struct {
+	Dialect      string                 `json:"dialect" yaml:"dialect"`
+	Backend      string                 `json:"backend" yaml:"backend"`
+	DebugTag     string                 `json:"debug_tag" yaml:"debug_tag"`
+	FilterPrefix string                 `json:"filter_prefix" yaml:"filter_prefix"`
+	AllStructs   bool                   `json:"all_structs" yaml:"all_structs"`
+	CheckWhere   bool                   `json:"check_where" yaml:"check_where"`
+	Templates    string                 `json:"templates" yaml:"templates"`
+	TenantField  string                 `json:"tenant_field" yaml:"tenant_field"`
+	OutPkg       string                 `json:"out_pkg" yaml:"out_pkg"`
+	BuildTag     string                 `json:"build_tag" yaml:"build_tag"`
+	HeaderFile   string                 `json:"header_file" yaml:"header_file"`
+	FileSuffix   string                 `json:"file_suffix" yaml:"file_suffix"`
+	Strict       bool                   `json:"strict" yaml:"strict"`
+	Include      []string               `json:"include" yaml:"include"`
+	Exclude      []string               `json:"exclude" yaml:"exclude"`
+	Models       map[string]ModelConfig `json:"models" yaml:"models"`
+}
+
+// backends are names of supported backends: generated querysets call methods
+// of *gorm.DB of jinzhu/gorm (gorm v1)
+var backends = map[string]bool{"": true, "gorm": true, "gorm1": true}
+
+// LoadConfig loads config of generation from YAML config file or from JSON
+// one if its extension is .json: directories of templates and of out package
+// and header file are relative to directory of file
+func LoadConfig(path string) (Config, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return Config{}, fmt.Errorf("can't read config file: %s", err)
+	}
+
+	var f configFile
+	if filepath.Ext(path) == ".json" {
+		err = json.Unmarshal(data, &f)
+	} else {
+		err = yaml.Unmarshal(data, &f)
+	}
+	if err != nil {
+		return Config{}, fmt.Errorf("can't parse config file %s: %s", path, err)
+	}
+
+	if !backends[f.Backend] {
+		return Config{}, fmt.Errorf("backend %q of config file %s isn't supported: querysets are "+
+			"generated only for gorm (jinzhu/gorm, gorm v1), not for gorm v2 or sqlx", f.Backend, path)
+	}
+	for name, m := range f.Models {
+		for _, p := range m.Methods {
+			if _, err = filepath.Match(p, ""); err != nil {
+				return Config{}, fmt.Errorf("invalid pattern %q of methods of %s: %s", p, name, err)
+			}
+		}
+	}
+
+	if f.Templates != "" && !filepath.IsAbs(f.Templates) {
+		f.Templates = filepath.Join(filepath.Dir(path), f.Templates)
+	}
+	if f.OutPkg != "" && !filepath.IsAbs(f.OutPkg) {
+		f.OutPkg = filepath.Join(filepath.Dir(path), f.OutPkg)
+	}
+	var header []byte
+	if f.HeaderFile != "" {
+		if !filepath.IsAbs(f.HeaderFile) {
+			f.HeaderFile = filepath.Join(filepath.Dir(path), f.HeaderFile)
+		}
+		if header, err = ioutil.ReadFile(f.HeaderFile); err != nil {
+			return Config{}, fmt.Errorf("can't read header file of config file %s: %s", path, err)
+		}
+	}
+	return Config{
+		Dialect:       f.Dialect,
+		DebugBuildTag: f.DebugTag,
+		FilterPrefix:  f.FilterPrefix,
+		AllStructs:    f.AllStructs,
+		CheckWhere:    f.CheckWhere,
+		TemplatesDir:  f.Templates,
+		TenantField:   f.TenantField,
+		OutPkg:        f.OutPkg,
+		BuildTag:      f.BuildTag,
+		Header:        string(header),
+		FileSuffix:    f.FileSuffix,
+		Strict:        f.Strict,
+		Include:       f.Include,
+		Exclude:       f.Exclude,
+		Models:        f.Models,
+	}, nil
+}
+
+// getStructQuerySetOptions returns options of struct set by its doc and by
+// config and false if struct doesn't need queryset. Struct listed in config
+// needs queryset unless its doc has "gen:qs skip" line.
+func getStructQuerySetOptions(s parser.ParsedStruct, cfg Config) (structOptions, bool) {
+	m, ok := cfg.Models[s.TypeName]
+	if !ok {
+		return getQuerySetOptions(s.Doc, cfg.AllStructs)
+	}
+
+	opts, ok := getQuerySetOptions(s.Doc, true)
+	if !ok {
+		return nil, false
+	}
+
+	cfgOpts, _ := parseGenQsComment("gen:qs " + m.Options)
+	for k, v := range cfgOpts {
+		if _, ok := opts[k]; !ok {
+			opts[k] = v
+		}
+	}
+	return opts, true
+}
+
+var calledMethodRe = regexp.MustCompile(`\.(\w+)\(`)
+
+// getTemplateCalls returns names of methods called by code generated by
+// templates for queryset c: it's generated without methods of queryset, so
+// only calls of code of templates (depending on options) are returned
+func getTemplateCalls(c querySetStructConfig, cfg Config) (map[string]bool, error) {
+	tmpl, err := getQuerySetsTemplate(cfg.TemplatesDir)
+	if err != nil {
+		return nil, err
+	}
+
+	c.Methods = nil
+	var b bytes.Buffer
+	err = tmpl.Execute(&b, struct {
+		Configs      querySetStructConfigSlice
+		PackageFuncs bool
+	}{
+		Configs: querySetStructConfigSlice{c},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("can't generate queryset of %s to get methods called by it: %s", c.StructName, err)
+	}
+	if cfg.DebugBuildTag != "" {
+		data := struct {
+			Configs              querySetStructConfigSlice
+			DebugTag, NoDebugTag string
+		}{
+			Configs: querySetStructConfigSlice{c},
+		}
+		if err = debugTmpl.Execute(&b, data); err != nil {
+			return nil, fmt.Errorf("can't generate debug methods of %s to get methods called by them: %s",
+				c.StructName, err)
+		}
+	}
+
+	called := map[string]bool{}
+	for _, m := range calledMethodRe.FindAllStringSubmatch(b.String(), -1) {
+		called[m[1]] = true
+	}
+	return called, nil
+}
+
+// filterQuerySetMethods returns methods without methods of queryset qsTypeName
+// not matching any of patterns. Methods called by returned methods or by
+// templates (see getTemplateCalls) are kept.
+func filterQuerySetMethods(ms []methods.Method, qsTypeName string, patterns []string,
+	templateCalls map[string]bool) []methods.Method {
+
+	if len(patterns) == 0 {
+		return ms
+	}
+
+	receiver := "qs " + qsTypeName
+	keep := make([]bool, len(ms))
+	called := map[string]bool{}
+	for name := range templateCalls {
+		called[name] = true
+	}
+	for changed := true; changed; {
+		changed = false
+		for i, m := range ms {
+			if keep[i] {
+				continue
+			}
+
+			keep[i] = m.GetReceiverDeclaration() != receiver || called[m.GetMethodName()]
+			for _, p := range patterns {
+				if ok, _ := filepath.Match(p, m.GetMethodName()); ok {
+					keep[i] = true
+				}
+			}
+			if !keep[i] {
+				continue
+			}
+
+			changed = true
+			for _, c := range calledMethodRe.FindAllStringSubmatch(m.GetBody(), -1) {
+				called[c[1]] = true
+			}
+		}
+	}
+
+	var ret []methods.Method
+	for i, m := range ms {
+		if keep[i] {
+			ret = append(ret, m)
+		}
+	}
+	return ret
+}
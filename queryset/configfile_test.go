@@ -0,0 +1,132 @@
+package queryset
+
+import (
+	"go/ast"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jirfag/go-queryset/parser"
+	"github.com/jirfag/go-queryset/queryset/dialect"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeConfigFile(t *testing.T, content string) string {
+	dir, err := ioutil.TempDir("", "config")
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	path := filepath.Join(dir, ConfigFileName)
+	assert.Nil(t, ioutil.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func TestLoadConfig(t *testing.T) {
+	path := writeConfigFile(t, `
+dialect: postgres
+backend: gorm
+templates: templates
+exclude: ["legacy_*.go"]
+models:
+  User:
+    options: cache name=Users
+    methods:
+      - All
+      - "*Eq"
+`)
+	defer os.RemoveAll(filepath.Dir(path))
+
+	expected := Config{
+		Dialect:      "postgres",
+		TemplatesDir: filepath.Join(filepath.Dir(path), "templates"),
+		Exclude:      []string{"legacy_*.go"},
+		Models:       map[string]ModelConfig{"User": {Options: "cache name=Users", Methods: []string{"All", "*Eq"}}},
+	}
+	cfg, err := LoadConfig(path)
+	assert.Nil(t, err)
+	assert.Equal(t, expected, cfg)
+
+	jsonPath := filepath.Join(filepath.Dir(path), "queryset.json")
+	assert.Nil(t, ioutil.WriteFile(jsonPath, []byte(`{
+		"dialect": "postgres",
+		"backend": "gorm",
+		"templates": "templates",
+		"exclude": ["legacy_*.go"],
+		"models": {"User": {"options": "cache name=Users", "methods": ["All", "*Eq"]}}
+	}`), 0600))
+	cfg, err = LoadConfig(jsonPath)
+	assert.Nil(t, err)
+	assert.Equal(t, expected, cfg)
+
+	for _, backend := range []string{"sqlx", "gorm2"} {
+		assert.Nil(t, ioutil.WriteFile(path, []byte("backend: "+backend), 0600))
+		_, err = LoadConfig(path)
+		if assert.NotNil(t, err) {
+			assert.Contains(t, err.Error(), `backend "`+backend+`"`)
+		}
+	}
+}
+
+func TestStructQuerySetOptionsOfConfig(t *testing.T) {
+	doc := func(line string) *ast.CommentGroup {
+		return &ast.CommentGroup{List: []*ast.Comment{{Text: line}}}
+	}
+	cfg := Config{Models: map[string]ModelConfig{
+		"User": {Options: "cache name=Users"},
+		"Post": {Options: "cache"},
+	}}
+
+	opts, ok := getStructQuerySetOptions(parser.ParsedStruct{TypeName: "User"}, cfg)
+	assert.True(t, ok)
+	assert.Equal(t, structOptions{"cache": "", "name": "Users"}, opts)
+
+	opts, ok = getStructQuerySetOptions(parser.ParsedStruct{TypeName: "User", Doc: doc("// gen:qs name=People")}, cfg)
+	assert.True(t, ok)
+	assert.Equal(t, structOptions{"cache": "", "name": "People"}, opts)
+
+	_, ok = getStructQuerySetOptions(parser.ParsedStruct{TypeName: "Post", Doc: doc("// gen:qs skip")}, cfg)
+	assert.False(t, ok)
+	_, ok = getStructQuerySetOptions(parser.ParsedStruct{TypeName: "Blog"}, cfg)
+	assert.False(t, ok)
+}
+
+func TestFilterQuerySetMethods(t *testing.T) {
+	pkgInfo, structs, err := parser.GetStructsInFile("test/models.go")
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	cfg := testConfig
+	cfg.Models = map[string]ModelConfig{
+		"Blog": {Methods: []string{"NameEq", "OrderAscBy*"}},
+		"User": {Methods: []string{"All"}},
+	}
+	d, _ := dialect.Get(cfg.Dialect)
+	configs, err := generateQuerySetConfigs(pkgInfo, structs, d, cfg)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	names := map[string]map[string]bool{}
+	for _, c := range configs {
+		names[c.StructName] = map[string]bool{}
+		for _, m := range c.Methods {
+			if m.GetReceiverDeclaration() == "qs "+c.Name {
+				names[c.StructName][m.GetMethodName()] = true
+			}
+		}
+	}
+	assert.True(t, names["Blog"]["NameEq"])
+	assert.True(t, names["Blog"]["OrderAscByID"])
+	assert.True(t, names["Blog"]["All"], "methods called by template are kept")
+	assert.False(t, names["Blog"]["NameNe"])
+	assert.False(t, names["Blog"]["OrderDescByID"])
+
+	// cache, batches and other code of options of User calls them
+	for _, name := range []string{"IDEq", "OrderAscByID", "DeleteNum"} {
+		assert.True(t, names["User"][name], name)
+	}
+	assert.False(t, names["User"]["NameNe"])
+}
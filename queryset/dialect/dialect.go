@@ -0,0 +1,801 @@
+// Package dialect describes SQL spellings of databases for generated code
+package dialect
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+)
+
+// Dialect describes SQL spellings specific for database
+type Dialect interface {
+	// Name returns name of dialect like in gorm: mysql, postgres etc.
+	// It's empty for generic SQL dialect.
+	Name() string
+
+	// UpsertClause returns format of upsert clause to append to INSERT:
+	// %[1]s is a comma-separated list of conflict columns, %[2]s is
+	// a comma-separated list of updates and %[3]s is " WHERE predicate"
+	// of partial unique index or empty string. Empty string is returned
+	// if upsert isn't supported by dialect.
+	UpsertClause() string
+
+	// UpsertNothingClause returns format of upsert clause leaving conflicting
+	// row as is: it's used instead of UpsertClause if there are no updates.
+	// Arguments are ones of UpsertClause, %[4]s is a quoted primary key.
+	UpsertNothingClause() string
+
+	// UpsertUpdate returns format of update of column %[1]s to the value
+	// it was tried to be inserted with
+	UpsertUpdate() string
+
+	// UpsertMerge returns format of MERGE statement used for upserts instead
+	// of INSERT with UpsertClause: %[1]s is a quoted table, %[2]s is
+	// a comma-separated list of columns, %[3]s is a list of placeholders,
+	// %[4]s is a condition of conflict, %[5]s is "WHEN MATCHED THEN UPDATE
+	// SET updates " clause or empty string if there is nothing to update,
+	// %[6]s is " WHERE predicate" of filtered unique index or empty string
+	// and %[7]s is a list of inserted values. Merged table is aliased as "target" and
+	// inserted row as "source". Empty string is returned if dialect upserts
+	// by INSERT.
+	UpsertMerge() string
+
+	// InsertIgnoreClause returns format of clause appended to INSERT to skip
+	// row conflicting with existing one on comma-separated list of unique
+	// columns %[1]s, %[2]s is a quoted primary key. Empty string is returned
+	// if there is no such clause: row is inserted by InsertWhereNotExists then.
+	InsertIgnoreClause() string
+
+	// InsertWhereNotExists returns format of INSERT into table %[1]s of
+	// comma-separated list of columns %[2]s with list of placeholders %[3]s
+	// if there is no row matching condition %[4]s. Empty string is returned
+	// if such conditional INSERT isn't supported by dialect.
+	InsertWhereNotExists() string
+
+	// InsertOr returns format of INSERT resolving conflict on primary key by
+	// %[4]s, "UPDATE" or "IGNORE", instead of clause: %[1]s is a quoted table,
+	// %[2]s is a comma-separated list of columns and %[3]s is a list of
+	// placeholders. Empty string is returned if dialect resolves conflicts by
+	// clauses or MERGE.
+	InsertOr() string
+
+	// UpdateFromValues returns format of UPDATE of rows of table %[1]s by
+	// quoted primary key %[5]s from VALUES table: %[2]s is a comma-separated
+	// list of quoted columns starting with primary key, %[3]s is a list of
+	// rows of placeholders and %[4]s is a list of updates. Source row is
+	// aliased as "source". Empty string is returned if rows are updated by
+	// CASE expressions on primary key.
+	UpdateFromValues() string
+
+	// Quote quotes identifier (column or table name)
+	Quote(name string) string
+
+	// ILike returns format of case-insensitive LIKE condition on
+	// already quoted column %[1]s with one placeholder for pattern
+	ILike() string
+
+	// RegexpMatch returns format of condition on already quoted column %[1]s:
+	// it matches regular expression passed as placeholder. Empty string is
+	// returned if regular expressions aren't supported by dialect.
+	RegexpMatch() string
+
+	// JSONPathEq returns format of condition on already quoted JSON column
+	// %[1]s: text value at path (first placeholder) equals to second
+	// placeholder. Empty string is returned if JSON isn't supported by dialect.
+	JSONPathEq() string
+
+	// JSONPath returns format of Go expression converting dot-separated
+	// keys %[1]s (e.g. "address.city") into path argument of JSONPathEq
+	JSONPath() string
+
+	// JSONContains returns format of condition on already quoted JSON column
+	// %[1]s: it contains JSON document passed as placeholder. Empty string
+	// is returned if it isn't supported by dialect.
+	JSONContains() string
+
+	// JSONHasKey returns format of condition on already quoted JSON column
+	// %[1]s: it has top-level key passed as placeholder. Empty string is
+	// returned if it isn't supported by dialect.
+	JSONHasKey() string
+
+	// ArrayContains, ArrayOverlaps and ArrayLengthEq return formats of
+	// conditions on already quoted array column %[1]s: it contains all
+	// elements of array placeholder, it has common elements with array
+	// placeholder and its length equals to placeholder. Empty strings are
+	// returned if array columns aren't supported by dialect.
+	ArrayContains() string
+	ArrayOverlaps() string
+	ArrayLengthEq() string
+
+	// FullTextMatch returns format of condition on text of comma-separated
+	// list of already quoted columns %[1]s: it matches query in natural
+	// language passed as placeholder. Empty string is returned if full-text
+	// search isn't supported by dialect.
+	FullTextMatch() string
+
+	// FullTextMatchConfig returns format like FullTextMatch, but text search
+	// configuration (e.g. german) is passed as placeholder twice before query.
+	// Empty string is returned if configurations aren't supported by dialect.
+	FullTextMatchConfig() string
+
+	// WeightedRandomKey returns format of random sort key of row with weight
+	// column %[1]s: ascending order by key is a weighted random order, where
+	// probability of row to be the first is proportional to its positive weight.
+	// Empty string is returned if there are no logarithm or random functions.
+	WeightedRandomKey() string
+
+	// GeoDistance returns format of great-circle distance in meters between
+	// point of latitude column %[1]s and longitude column %[2]s and bound
+	// point: its latitude is bound twice, then longitude is bound. Empty
+	// string is returned if there are no trigonometric functions.
+	GeoDistance() string
+
+	// DuplicateKeyError returns regexp of error messages of driver about
+	// violations of unique indexes: its first group is a name of index,
+	// constraint or column. Empty string is returned if messages are unknown.
+	DuplicateKeyError() string
+
+	// CountFilter returns format of aggregate counting rows matching
+	// condition %[1]s
+	CountFilter() string
+
+	// Collate returns format of expression of already quoted column %[1]s in
+	// collation %[2]s. Empty string is returned if collations of expressions
+	// aren't supported by dialect.
+	Collate() string
+
+	// ForUpdate returns clause of SELECT locking selected rows for update.
+	// Empty string is returned if row-level locking isn't supported.
+	ForUpdate() string
+
+	// ForShare returns clause of SELECT locking selected rows in shared mode.
+	// Empty string is returned if it isn't supported.
+	ForShare() string
+
+	// ForUpdateSkipLocked returns clause of SELECT locking selected rows for
+	// update and skipping rows already locked by other transactions. Empty
+	// string is returned if it isn't supported.
+	ForUpdateSkipLocked() string
+
+	// AutoIncrement returns false if database doesn't generate numeric primary
+	// keys without default value (sequence) of column
+	AutoIncrement() bool
+
+	// NextSequenceValue returns format of query selecting next value of
+	// sequence %[1]s. Empty string is returned if there are no sequences.
+	NextSequenceValue() string
+
+	// SetIsolation returns format of statement setting isolation level %[1]s
+	// (e.g. SERIALIZABLE) of transaction: it's the first statement of
+	// transaction. Empty string is returned if level can't be set so.
+	SetIsolation() string
+
+	// PrepareTransaction returns format of statement preparing current
+	// transaction for two-phase commit by global identifier %[1]s (quoted
+	// literal). Empty string is returned if two-phase commit isn't supported.
+	PrepareTransaction() string
+
+	// CommitPrepared and RollbackPrepared return formats of statements
+	// finishing prepared transaction with global identifier %[1]s
+	CommitPrepared() string
+	RollbackPrepared() string
+
+	// SetConstraints returns format of statement setting mode %[1]s (DEFERRED
+	// or IMMEDIATE) of all deferrable constraints in current transaction.
+	// Empty string is returned if constraints can't be deferred.
+	SetConstraints() string
+
+	// CallProcedure returns format of statement calling stored procedure
+	// (or table function) %[1]s with placeholders %[2]s and selecting
+	// returned rows. Empty string is returned if there are no procedures.
+	CallProcedure() string
+
+	// MaxIdentifierLen returns limit of identifier length: longer identifiers
+	// are truncated by Quote. Zero is returned if there is no limit.
+	MaxIdentifierLen() int
+
+	// MaxBindVars returns limit of number of bind variables of one statement:
+	// batch statements are split into chunks to not exceed it
+	MaxBindVars() int
+
+	// AsOfSystemTime returns format of clause appended to table in FROM to read
+	// snapshot of table at UTC time %[1]s formatted by TimestampLayout. Empty
+	// string is returned if historical reads aren't supported by SQL.
+	AsOfSystemTime() string
+
+	// Explain returns format of statement selecting plan of select query %[1]s
+	// without executing the query. Empty string is returned if plan can't be
+	// selected by one statement.
+	Explain() string
+
+	// UnionSelect returns format of operand of UNION: select query %[1]s.
+	// Operands keep their own ORDER BY and LIMIT.
+	UnionSelect() string
+
+	// LikeWildcards returns special characters of LIKE patterns besides
+	// % and _, they must be escaped to be matched literally
+	LikeWildcards() string
+
+	// Returning returns format of clause appended to INSERT to return column
+	// %[1]s of inserted or upserted row. Empty string is returned if INSERT
+	// can't return rows.
+	Returning() string
+
+	// OffsetNeedsLimit is true if OFFSET is invalid without LIMIT
+	OffsetNeedsLimit() bool
+
+	// PagingOrder returns ORDER BY expression of queries with LIMIT or OFFSET
+	// if they must be ordered: it's constant, so it doesn't change order of
+	// ordered query. Empty string is returned if such queries may be unordered.
+	PagingOrder() string
+
+	// OrderedSubquery returns clause appended to subquery with ORDER BY, but
+	// without LIMIT and OFFSET, if dialect allows ORDER BY in subqueries only
+	// with them. Empty string is returned if ORDER BY is valid as is.
+	OrderedSubquery() string
+}
+
+// TimestampLayout is a layout of time in SQL timestamp literals
+const TimestampLayout = "2006-01-02 15:04:05.999999"
+
+// TruncateIdentifier truncates name longer than maxLen deterministically:
+// its prefix is kept and the rest is replaced by hash of the whole name
+func TruncateIdentifier(name string, maxLen int) string {
+	if len(name) <= maxLen {
+		return name
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	suffix := fmt.Sprintf("_%08x", h.Sum32())
+	return name[:maxLen-len(suffix)] + suffix
+}
+
+// generic is a dialect with standard SQL only
+type generic struct{}
+
+func (d generic) Name() string         { return "" }
+func (d generic) UpsertClause() string { return "" }
+func (d generic) UpsertUpdate() string { return "" }
+func (d generic) UpsertMerge() string  { return "" }
+
+func (d generic) UpsertNothingClause() string { return "" }
+
+// UpdateFromValues is empty: UPDATE FROM isn't standard
+func (d generic) UpdateFromValues() string { return "" }
+
+// InsertIgnoreClause is empty: conflicts are skipped by non-standard clauses
+func (d generic) InsertIgnoreClause() string { return "" }
+
+func (d generic) InsertOr() string { return "" }
+
+func (d generic) InsertWhereNotExists() string {
+	return "INSERT INTO %[1]s (%[2]s) SELECT %[3]s WHERE NOT EXISTS (SELECT 1 FROM %[1]s WHERE %[4]s)"
+}
+
+// Quote returns name as is: there is no standard quoting supported by all databases
+func (d generic) Quote(name string) string { return name }
+func (d generic) ILike() string            { return "LOWER(%[1]s) LIKE LOWER(?)" }
+func (d generic) RegexpMatch() string      { return "" }
+func (d generic) JSONPathEq() string       { return "" }
+func (d generic) JSONPath() string         { return "" }
+func (d generic) JSONContains() string     { return "" }
+func (d generic) JSONHasKey() string       { return "" }
+func (d generic) ArrayContains() string    { return "" }
+func (d generic) ArrayOverlaps() string    { return "" }
+func (d generic) ArrayLengthEq() string    { return "" }
+func (d generic) FullTextMatch() string    { return "" }
+func (d generic) ForUpdate() string        { return "FOR UPDATE" }
+func (d generic) ForShare() string         { return "" }
+func (d generic) AutoIncrement() bool      { return true }
+func (d generic) MaxIdentifierLen() int    { return 0 }
+func (d generic) CallProcedure() string    { return "CALL %[1]s(%[2]s)" }
+func (d generic) SetIsolation() string     { return "SET TRANSACTION ISOLATION LEVEL %[1]s" }
+
+// FullTextMatchConfig is empty: only postgres has text search configurations
+func (d generic) FullTextMatchConfig() string { return "" }
+
+// MaxBindVars is a default limit of sqlite before 3.32: it's the least limit
+// of supported databases
+func (d generic) MaxBindVars() int { return 999 }
+
+// NextSequenceValue is empty: sequences aren't supported by all databases
+func (d generic) NextSequenceValue() string { return "" }
+
+// Collate is a standard COLLATE clause
+func (d generic) Collate() string { return "%[1]s COLLATE %[2]s" }
+
+// WeightedRandomKey is empty: random functions aren't standard
+func (d generic) WeightedRandomKey() string { return "" }
+
+// DuplicateKeyError is empty: messages of drivers aren't standard
+func (d generic) DuplicateKeyError() string { return "" }
+
+// GeoDistance is a haversine formula on sphere of mean radius of Earth,
+// degrees are converted to radians by multiplication: not all DBs have RADIANS
+func (d generic) GeoDistance() string {
+	return "12742000 * ASIN(SQRT(POWER(SIN((%[1]s - ?) * 0.008726646259971648), 2) + " +
+		"COS(%[1]s * 0.017453292519943295) * COS(? * 0.017453292519943295) * " +
+		"POWER(SIN((%[2]s - ?) * 0.008726646259971648), 2)))"
+}
+
+// CountFilter counts by CASE: FILTER clause isn't supported by all DBs
+func (d generic) CountFilter() string { return "COUNT(CASE WHEN %[1]s THEN 1 END)" }
+
+// ForUpdateSkipLocked is empty: SKIP LOCKED isn't standard
+func (d generic) ForUpdateSkipLocked() string { return "" }
+
+// two-phase commit isn't standard: XA transactions of mysql must be started
+// by XA START, not by GORM's BEGIN
+func (d generic) PrepareTransaction() string { return "" }
+func (d generic) CommitPrepared() string     { return "" }
+func (d generic) RollbackPrepared() string   { return "" }
+
+// SetConstraints is empty: mysql and mssql can't defer constraints
+func (d generic) SetConstraints() string { return "" }
+
+// AsOfSystemTime is empty: historical reads aren't standard
+func (d generic) AsOfSystemTime() string { return "" }
+
+func (d generic) Explain() string { return "EXPLAIN %[1]s" }
+
+// UnionSelect parenthesizes query: ORDER BY and LIMIT of unparenthesized
+// operand would be applied to the whole UNION
+func (d generic) UnionSelect() string { return "(%[1]s)" }
+
+func (d generic) LikeWildcards() string { return "" }
+
+// Returning is empty: RETURNING isn't standard
+func (d generic) Returning() string { return "" }
+
+func (d generic) OffsetNeedsLimit() bool  { return false }
+func (d generic) PagingOrder() string     { return "" }
+func (d generic) OrderedSubquery() string { return "" }
+
+// mysql has no partial indexes: conflict target isn't set in upserts at all
+type mysql struct {
+	generic
+}
+
+func (d mysql) Name() string             { return "mysql" }
+func (d mysql) MaxBindVars() int         { return 65535 }
+func (d mysql) UpsertClause() string     { return "ON DUPLICATE KEY UPDATE %[2]s" }
+func (d mysql) UpsertUpdate() string     { return "%[1]s = VALUES(%[1]s)" }
+func (d mysql) Quote(name string) string { return "`" + name + "`" }
+func (d mysql) JSONPathEq() string       { return "JSON_UNQUOTE(JSON_EXTRACT(%[1]s, ?)) = ?" }
+func (d mysql) JSONPath() string         { return `"$." + %[1]s` }
+func (d mysql) JSONContains() string     { return "JSON_CONTAINS(%[1]s, ?)" }
+func (d mysql) RegexpMatch() string      { return "%[1]s REGEXP ?" }
+
+// UpsertNothingClause updates primary key to itself: mysql has no DO NOTHING,
+// INSERT IGNORE would ignore other errors too
+func (d mysql) UpsertNothingClause() string { return "ON DUPLICATE KEY UPDATE %[4]s = %[4]s" }
+
+// FullTextMatch needs FULLTEXT index on the same list of columns
+func (d mysql) FullTextMatch() string { return "MATCH (%[1]s) AGAINST (? IN NATURAL LANGUAGE MODE)" }
+
+// WeightedRandomKey is -ln(u)/weight for uniform u in (0, 1]
+func (d mysql) WeightedRandomKey() string { return "-LN(1 - RAND()) / %[1]s" }
+
+// DuplicateKeyError matches key names prefixed by table name by MySQL 8 too
+func (d mysql) DuplicateKeyError() string { return `Duplicate entry '.*' for key '(?:\w+\.)?(\w+)'` }
+
+// ForShare uses syntax supported by MySQL before 8.0 too
+func (d mysql) ForShare() string { return "LOCK IN SHARE MODE" }
+
+// ForUpdateSkipLocked is supported since MySQL 8.0
+func (d mysql) ForUpdateSkipLocked() string { return "FOR UPDATE SKIP LOCKED" }
+
+// SetIsolation is empty: MySQL sets isolation only before transaction starts,
+// but GORM begins transactions without options
+func (d mysql) SetIsolation() string { return "" }
+
+// InsertIgnoreClause updates primary key to itself like UpsertNothingClause:
+// conflict on any unique index skips row, but other errors aren't ignored
+// unlike by INSERT IGNORE. Rows affected are 0 for skipped row unless
+// clientFoundRows is set.
+func (d mysql) InsertIgnoreClause() string { return "ON DUPLICATE KEY UPDATE %[2]s = %[2]s" }
+
+// OffsetNeedsLimit is true: mysql has OFFSET only in LIMIT clause
+func (d mysql) OffsetNeedsLimit() bool { return true }
+
+// InsertWhereNotExists selects from DUAL: WHERE without FROM is supported
+// only since MySQL 8.0
+func (d mysql) InsertWhereNotExists() string {
+	return "INSERT INTO %[1]s (%[2]s) SELECT %[3]s FROM DUAL WHERE NOT EXISTS (SELECT 1 FROM %[1]s WHERE %[4]s)"
+}
+
+type postgres struct {
+	generic
+}
+
+func (d postgres) Name() string             { return "postgres" }
+func (d postgres) MaxBindVars() int         { return 65535 }
+func (d postgres) UpsertClause() string     { return "ON CONFLICT (%[1]s)%[3]s DO UPDATE SET %[2]s" }
+func (d postgres) UpsertUpdate() string     { return "%[1]s = EXCLUDED.%[1]s" }
+func (d postgres) Quote(name string) string { return `"` + name + `"` }
+func (d postgres) ILike() string            { return "%[1]s ILIKE ?" }
+func (d postgres) RegexpMatch() string      { return "%[1]s ~ ?" }
+
+func (d postgres) UpsertNothingClause() string { return "ON CONFLICT (%[1]s)%[3]s DO NOTHING" }
+
+// JSONPathEq uses #>> operator, which is supported by both json and jsonb columns
+func (d postgres) JSONPathEq() string { return "%[1]s #>> ? = ?" }
+func (d postgres) JSONPath() string   { return `"{" + strings.Replace(%[1]s, ".", ",", -1) + "}"` }
+
+// JSONContains casts column to jsonb: json columns have no containment operator
+func (d postgres) JSONContains() string { return "%[1]s::jsonb @> ?::jsonb" }
+func (d postgres) ForShare() string     { return "FOR SHARE" }
+
+// JSONHasKey uses -> operator instead of ? operator: question mark is
+// a placeholder for GORM. Value of present key isn't NULL even for JSON null.
+func (d postgres) JSONHasKey() string { return "%[1]s -> ? IS NOT NULL" }
+
+func (d postgres) ArrayContains() string { return "%[1]s @> ?" }
+func (d postgres) ArrayOverlaps() string { return "%[1]s && ?" }
+
+// ArrayLengthEq coalesces array_length: it's NULL for empty arrays
+func (d postgres) ArrayLengthEq() string { return "COALESCE(array_length(%[1]s, 1), 0) = ?" }
+
+// FullTextMatch concatenates columns by concat_ws: it skips NULL columns
+func (d postgres) FullTextMatch() string {
+	return "to_tsvector(concat_ws(' ', %[1]s)) @@ plainto_tsquery(?)"
+}
+
+func (d postgres) FullTextMatchConfig() string {
+	return "to_tsvector(?::regconfig, concat_ws(' ', %[1]s)) @@ plainto_tsquery(?::regconfig, ?)"
+}
+
+func (d postgres) WeightedRandomKey() string { return "-LN(1 - RANDOM()) / %[1]s" }
+
+func (d postgres) DuplicateKeyError() string {
+	return `duplicate key value violates unique constraint "(\w+)"`
+}
+
+// CountFilter uses FILTER clause, it's supported by sqlite since 3.30 too
+func (d postgres) CountFilter() string { return "COUNT(*) FILTER (WHERE %[1]s)" }
+
+// Collate quotes collation: names like de-DE-x-icu aren't identifiers
+func (d postgres) Collate() string { return `%[1]s COLLATE "%[2]s"` }
+
+func (d postgres) ForUpdateSkipLocked() string { return "FOR UPDATE SKIP LOCKED" }
+
+// CallProcedure selects from function: procedures of postgres don't return rows
+func (d postgres) CallProcedure() string { return "SELECT * FROM %[1]s(%[2]s)" }
+
+func (d postgres) NextSequenceValue() string { return "SELECT nextval('%[1]s')" }
+
+func (d postgres) PrepareTransaction() string { return "PREPARE TRANSACTION %[1]s" }
+func (d postgres) CommitPrepared() string     { return "COMMIT PREPARED %[1]s" }
+func (d postgres) RollbackPrepared() string   { return "ROLLBACK PREPARED %[1]s" }
+
+func (d postgres) SetConstraints() string { return "SET CONSTRAINTS ALL %[1]s" }
+func (d postgres) Explain() string        { return "EXPLAIN (ANALYZE off) %[1]s" }
+
+// InsertIgnoreClause needs unique index on columns: unlike WHERE NOT EXISTS
+// it isn't racy and doesn't abort transaction on concurrent insert
+func (d postgres) InsertIgnoreClause() string { return "ON CONFLICT (%[1]s) DO NOTHING" }
+
+func (d postgres) Returning() string { return "RETURNING %[1]s" }
+
+// UpdateFromValues unions VALUES with empty SELECT from table: placeholders
+// get types of columns instead of text
+func (d postgres) UpdateFromValues() string {
+	return `UPDATE %[1]s SET %[4]s FROM ((SELECT %[2]s FROM %[1]s LIMIT 0) UNION ALL VALUES %[3]s) AS "source" ` +
+		`WHERE %[1]s.%[5]s = "source".%[5]s`
+}
+
+// cockroachdb is a postgres wire-compatible dialect of CockroachDB: it has no
+// LISTEN/NOTIFY, prepared transactions and deferrable constraints, but it reads
+// historical snapshots by AS OF SYSTEM TIME
+type cockroachdb struct {
+	postgres
+}
+
+func (d cockroachdb) Name() string           { return "cockroachdb" }
+func (d cockroachdb) AsOfSystemTime() string { return "AS OF SYSTEM TIME '%[1]s'" }
+
+func (d cockroachdb) PrepareTransaction() string { return "" }
+func (d cockroachdb) CommitPrepared() string     { return "" }
+func (d cockroachdb) RollbackPrepared() string   { return "" }
+func (d cockroachdb) SetConstraints() string     { return "" }
+
+// Explain has no options: EXPLAIN of CockroachDB doesn't execute query anyway
+func (d cockroachdb) Explain() string { return generic{}.Explain() }
+
+// sqlite3 supports upserts like postgres since version 3.24
+type sqlite3 struct {
+	postgres
+}
+
+func (d sqlite3) Name() string { return "sqlite3" }
+
+// ILike uses LIKE: it's case-insensitive for ASCII characters in sqlite
+func (d sqlite3) ILike() string { return "%[1]s LIKE ?" }
+
+func (d sqlite3) MaxBindVars() int { return generic{}.MaxBindVars() }
+
+// RegexpMatch is empty: REGEXP operator of sqlite fails without function
+// registered by application
+func (d sqlite3) RegexpMatch() string { return "" }
+
+// JSONPathEq uses json_extract of JSON1 extension, it has no containment function
+func (d sqlite3) JSONPathEq() string   { return "json_extract(%[1]s, ?) = ?" }
+func (d sqlite3) JSONPath() string     { return mysql{}.JSONPath() }
+func (d sqlite3) JSONContains() string { return "" }
+func (d sqlite3) JSONHasKey() string   { return "" }
+
+// arrays are empty: sqlite has no array columns
+func (d sqlite3) ArrayContains() string { return "" }
+func (d sqlite3) ArrayOverlaps() string { return "" }
+func (d sqlite3) ArrayLengthEq() string { return "" }
+
+// FullTextMatch is empty: full-text search of sqlite needs FTS virtual tables
+func (d sqlite3) FullTextMatch() string       { return "" }
+func (d sqlite3) FullTextMatchConfig() string { return "" }
+
+// NextSequenceValue is empty: sqlite has no sequences
+func (d sqlite3) NextSequenceValue() string { return "" }
+
+// WeightedRandomKey is empty: math functions of sqlite are optional
+func (d sqlite3) WeightedRandomKey() string { return "" }
+func (d sqlite3) GeoDistance() string       { return "" }
+
+// DuplicateKeyError matches column: sqlite reports columns, not indexes
+func (d sqlite3) DuplicateKeyError() string { return `UNIQUE constraint failed: \w+\.(\w+)` }
+
+// ForUpdate is empty: sqlite locks the whole database, there are no row locks
+func (d sqlite3) ForUpdate() string           { return "" }
+func (d sqlite3) ForShare() string            { return "" }
+func (d sqlite3) ForUpdateSkipLocked() string { return "" }
+
+// CallProcedure is empty: sqlite has no stored procedures
+func (d sqlite3) CallProcedure() string { return "" }
+
+// SetIsolation is empty: transactions of sqlite are always serializable
+func (d sqlite3) SetIsolation() string { return "" }
+
+// two-phase commit is empty: sqlite has no prepared transactions
+func (d sqlite3) PrepareTransaction() string { return "" }
+func (d sqlite3) CommitPrepared() string     { return "" }
+func (d sqlite3) RollbackPrepared() string   { return "" }
+
+// SetConstraints is empty: sqlite defers only foreign keys by pragma
+func (d sqlite3) SetConstraints() string { return "" }
+
+// UpdateFromValues is empty: UPDATE FROM is supported only since sqlite 3.33
+func (d sqlite3) UpdateFromValues() string { return "" }
+
+// Explain selects readable plan: plain EXPLAIN of sqlite lists opcodes of VM
+func (d sqlite3) Explain() string { return "EXPLAIN QUERY PLAN %[1]s" }
+
+// UnionSelect selects from subquery: sqlite doesn't allow parenthesized
+// operands of compound SELECT
+func (d sqlite3) UnionSelect() string { return "SELECT * FROM (%[1]s)" }
+
+// Returning is supported since sqlite 3.35
+func (d sqlite3) Returning() string { return postgres{}.Returning() }
+
+// OffsetNeedsLimit is true: sqlite has OFFSET only after LIMIT
+func (d sqlite3) OffsetNeedsLimit() bool { return true }
+
+// spanner is a GoogleSQL dialect of Cloud Spanner: it has no auto-increment
+// and upserts are spelled by INSERT OR UPDATE prefix instead of clause
+type spanner struct {
+	mysql
+}
+
+func (d spanner) Name() string         { return "spanner" }
+func (d spanner) UpsertClause() string { return "" }
+func (d spanner) UpsertUpdate() string { return "" }
+func (d spanner) ILike() string        { return generic{}.ILike() }
+func (d spanner) RegexpMatch() string  { return "REGEXP_CONTAINS(%[1]s, ?)" }
+
+func (d spanner) UpsertNothingClause() string { return "" }
+
+// JSONPathEq uses JSON_VALUE: it returns scalar value as string
+func (d spanner) JSONPathEq() string   { return "JSON_VALUE(%[1]s, ?) = ?" }
+func (d spanner) JSONContains() string { return "" }
+func (d spanner) ForShare() string     { return "" }
+func (d spanner) AutoIncrement() bool  { return false }
+
+// FullTextMatch is empty: full-text search of Spanner needs token columns
+func (d spanner) FullTextMatch() string { return "" }
+
+// WeightedRandomKey is empty: Spanner has no random function
+func (d spanner) WeightedRandomKey() string { return "" }
+
+func (d spanner) DuplicateKeyError() string { return `Unique index violation on index (\w+)` }
+
+// Collate is empty: Spanner collates strings by COLLATE function, not clause
+func (d spanner) Collate() string { return "" }
+
+// ForUpdateSkipLocked is empty: Spanner has no row locks to skip
+func (d spanner) ForUpdateSkipLocked() string { return "" }
+
+// AsOfSystemTime is empty: stale reads of Spanner are set by options of
+// read-only transactions of client, not by SQL
+func (d spanner) AsOfSystemTime() string { return "" }
+
+// CallProcedure is empty: Spanner has no stored procedures
+func (d spanner) CallProcedure() string { return "" }
+
+// SetIsolation is empty: transactions of Spanner are always serializable
+func (d spanner) SetIsolation() string { return "" }
+
+// Explain is empty: plans of Spanner are returned by query mode of client
+func (d spanner) Explain() string { return "" }
+
+// InsertWhereNotExists is empty: Spanner has no DUAL table
+func (d spanner) InsertWhereNotExists() string { return "" }
+
+// InsertIgnoreClause is empty: Spanner has no ON DUPLICATE KEY UPDATE,
+// rows are inserted by INSERT OR IGNORE
+func (d spanner) InsertIgnoreClause() string { return "" }
+
+// MaxBindVars is a limit of parameters of Spanner statement
+func (d spanner) MaxBindVars() int { return 950 }
+
+// InsertOr spells upserts of Spanner: it detects conflicts only by primary key
+func (d spanner) InsertOr() string { return "INSERT OR %[4]s INTO %[1]s (%[2]s) VALUES (%[3]s)" }
+
+// mssql is a T-SQL dialect of Microsoft SQL Server: upserts are MERGE
+// statements, LIMIT and OFFSET are spelled as OFFSET FETCH by GORM dialect
+type mssql struct {
+	generic
+}
+
+func (d mssql) Name() string             { return "mssql" }
+func (d mssql) MaxBindVars() int         { return 2100 }
+func (d mssql) UpsertUpdate() string     { return "%[1]s = [source].%[1]s" }
+func (d mssql) Quote(name string) string { return "[" + name + "]" }
+
+// UpsertMerge merges into CTE of rows matching predicate of filtered index:
+// columns of predicate aren't ambiguous with columns of source. HOLDLOCK
+// prevents concurrent upserts from inserting the same row twice.
+func (d mssql) UpsertMerge() string {
+	return "WITH [target] AS (SELECT * FROM %[1]s%[6]s) " +
+		"MERGE INTO [target] WITH (HOLDLOCK) USING (VALUES (%[3]s)) AS [source] (%[2]s) ON %[4]s " +
+		"%[5]sWHEN NOT MATCHED THEN INSERT (%[2]s) VALUES (%[7]s);"
+}
+
+// JSONPathEq uses JSON_VALUE: it returns scalar value as string
+func (d mssql) JSONPathEq() string { return "JSON_VALUE(%[1]s, ?) = ?" }
+func (d mssql) JSONPath() string   { return mysql{}.JSONPath() }
+
+// RegexpMatch is empty: SQL Server has no regular expressions before 2025
+func (d mssql) RegexpMatch() string { return "" }
+
+// FullTextMatch needs full-text index on columns
+func (d mssql) FullTextMatch() string { return "FREETEXT((%[1]s), ?)" }
+
+// WeightedRandomKey seeds RAND by NEWID: RAND() is evaluated once per query
+func (d mssql) WeightedRandomKey() string { return "-LOG(1 - RAND(CHECKSUM(NEWID()))) / %[1]s" }
+
+// DuplicateKeyError matches unique indexes and UNIQUE constraints
+func (d mssql) DuplicateKeyError() string { return `(?:unique index|UNIQUE KEY constraint) '(\w+)'` }
+
+// ForUpdate is empty: rows are locked by table hints like UPDLOCK, not by clause
+func (d mssql) ForUpdate() string     { return "" }
+func (d mssql) CallProcedure() string { return "EXEC %[1]s %[2]s" }
+
+func (d mssql) NextSequenceValue() string { return "SELECT NEXT VALUE FOR %[1]s" }
+
+// Explain is empty: plans are returned after SET SHOWPLAN_TEXT ON in own batch
+func (d mssql) Explain() string { return "" }
+
+// PagingOrder is needed by OFFSET FETCH: GORM dialect spells LIMIT and
+// OFFSET by it and it's valid only in ordered query
+func (d mssql) PagingOrder() string { return "(SELECT NULL)" }
+
+// OrderedSubquery is OFFSET 0 ROWS: T-SQL allows ORDER BY in subqueries only
+// with TOP or OFFSET
+func (d mssql) OrderedSubquery() string { return " OFFSET 0 ROWS" }
+
+// UnionSelect selects from derived table: T-SQL doesn't allow ORDER BY in
+// parenthesized operands of UNION
+func (d mssql) UnionSelect() string { return "SELECT * FROM (%[1]s) AS [union_operand]" }
+
+// LikeWildcards has [: it starts character ranges like [a-f] in T-SQL
+func (d mssql) LikeWildcards() string { return "[" }
+
+// InsertWhereNotExists locks checked range by UPDLOCK and HOLDLOCK like
+// upserts: concurrent inserts of the same row wait instead of violating
+// unique index
+func (d mssql) InsertWhereNotExists() string {
+	return "INSERT INTO %[1]s (%[2]s) SELECT %[3]s " +
+		"WHERE NOT EXISTS (SELECT 1 FROM %[1]s WITH (UPDLOCK, HOLDLOCK) WHERE %[4]s)"
+}
+
+// oracleMaxIdentifierLen is a limit of identifier length before Oracle 12.2
+const oracleMaxIdentifierLen = 30
+
+// oracle is a dialect of Oracle Database: numeric primary keys are filled by
+// sequences, upserts are MERGE statements. LIMIT and OFFSET are spelled as
+// OFFSET FETCH by GORM dialect of gormoracle package.
+type oracle struct {
+	generic
+}
+
+func (d oracle) Name() string         { return "oracle" }
+func (d oracle) UpsertUpdate() string { return `%[1]s = "source".%[1]s` }
+
+// UpsertMerge selects inserted row from dual: Oracle has no VALUES table
+// constructor and no AS before table aliases. It merges into inline view of
+// rows matching predicate of unique index: Oracle has no partial indexes, but
+// unique function-based indexes emulate them.
+func (d oracle) UpsertMerge() string {
+	return `MERGE INTO (SELECT * FROM %[1]s%[6]s) "target" USING (WITH "row" (%[2]s) AS (SELECT %[3]s FROM dual) ` +
+		`SELECT * FROM "row") "source" ON (%[4]s) ` +
+		`%[5]sWHEN NOT MATCHED THEN INSERT (%[2]s) VALUES (%[7]s)`
+}
+
+// Quote truncates names longer than 30 characters: generated identifiers like
+// aliases of joins must fit into the limit too
+func (d oracle) Quote(name string) string {
+	return `"` + TruncateIdentifier(name, d.MaxIdentifierLen()) + `"`
+}
+
+func (d oracle) MaxIdentifierLen() int { return oracleMaxIdentifierLen }
+func (d oracle) MaxBindVars() int      { return 65535 }
+func (d oracle) AutoIncrement() bool   { return false }
+func (d oracle) RegexpMatch() string   { return "REGEXP_LIKE(%[1]s, ?)" }
+
+func (d oracle) NextSequenceValue() string { return "SELECT %[1]s.NEXTVAL FROM dual" }
+
+// JSONPathEq is empty: path of JSON_VALUE must be a literal, not a bind variable
+func (d oracle) JSONPathEq() string { return "" }
+
+// CallProcedure selects from pipelined table function: procedures of oracle
+// return rows only by cursors
+func (d oracle) CallProcedure() string { return "SELECT * FROM TABLE(%[1]s(%[2]s))" }
+
+func (d oracle) SetConstraints() string { return postgres{}.SetConstraints() }
+
+// InsertWhereNotExists selects from dual: Oracle has no SELECT without FROM
+func (d oracle) InsertWhereNotExists() string { return mysql{}.InsertWhereNotExists() }
+
+func (d oracle) ForUpdateSkipLocked() string { return postgres{}.ForUpdateSkipLocked() }
+func (d oracle) WeightedRandomKey() string   { return "-LN(1 - DBMS_RANDOM.VALUE) / %[1]s" }
+func (d oracle) DuplicateKeyError() string {
+	return `ORA-00001: unique constraint \((?:\w+\.)?(\w+)\) violated`
+}
+
+// Explain is empty: EXPLAIN PLAN FOR writes plan into PLAN_TABLE, it's
+// selected by another statement
+func (d oracle) Explain() string { return "" }
+
+var dialects = map[string]Dialect{
+	"":            generic{},
+	"cockroachdb": cockroachdb{},
+	"mssql":       mssql{},
+	"mysql":       mysql{},
+	"oracle":      oracle{},
+	"postgres":    postgres{},
+	"sqlite3":     sqlite3{},
+	"spanner":     spanner{},
+}
+
+// Get returns dialect by name. Empty name is for generic SQL dialect.
+func Get(name string) (Dialect, error) {
+	d := dialects[name]
+	if d == nil {
+		return nil, fmt.Errorf("unknown dialect %q, supported dialects: %s",
+			name, strings.Join(Names(), ", "))
+	}
+
+	return d, nil
+}
+
+// Names returns sorted names of all supported not generic dialects
+func Names() []string {
+	var names []string
+	for name := range dialects {
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,500 @@
+package dialect
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGet(t *testing.T) {
+	for _, name := range append(Names(), "") {
+		d, err := Get(name)
+		assert.Nil(t, err)
+		assert.Equal(t, name, d.Name())
+	}
+
+	_, err := Get("unknown")
+	assert.NotNil(t, err)
+}
+
+func TestUpsertSupport(t *testing.T) {
+	for _, name := range Names() {
+		d, _ := Get(name)
+		switch name {
+		case "spanner":
+			assert.Empty(t, d.UpsertClause(), name)
+			assert.Equal(t, "INSERT OR UPDATE INTO t (a,b) VALUES (?,?)",
+				fmt.Sprintf(d.InsertOr(), "t", "a,b", "?,?", "UPDATE"), name)
+			continue
+		case "mssql", "oracle":
+			assert.Empty(t, d.UpsertClause(), name)
+			assert.Contains(t, d.UpsertMerge(), "MERGE INTO", name)
+		default:
+			assert.NotEmpty(t, d.UpsertClause(), name)
+			assert.NotEmpty(t, d.UpsertNothingClause(), name)
+			assert.Empty(t, d.UpsertMerge(), name)
+		}
+		assert.Empty(t, d.InsertOr(), name)
+		assert.NotEmpty(t, d.UpsertUpdate(), name)
+	}
+
+	d, _ := Get("")
+	assert.Empty(t, d.UpsertClause())
+	assert.Empty(t, d.UpsertMerge())
+}
+
+func TestUpsertMergeWithoutUpdates(t *testing.T) {
+	for _, name := range []string{"mssql", "oracle"} {
+		d, _ := Get(name)
+		merge := fmt.Sprintf(d.UpsertMerge(), "t", "a, b", "?, ?", "c", "", "", "v")
+		assert.NotContains(t, merge, "WHEN MATCHED", name)
+		assert.Contains(t, merge, "WHEN NOT MATCHED THEN INSERT", name)
+	}
+}
+
+func TestUpsertMergeOfPartialIndex(t *testing.T) {
+	d, _ := Get("oracle")
+	merge := fmt.Sprintf(d.UpsertMerge(), `"orders"`, `"number"`, "?", `"target"."number" = "source"."number"`, "",
+		` WHERE "deleted_at" IS NULL`, `"source"."number"`)
+	assert.Contains(t, merge, `MERGE INTO (SELECT * FROM "orders" WHERE "deleted_at" IS NULL) "target"`)
+}
+
+func TestNextSequenceValue(t *testing.T) {
+	expected := map[string]string{
+		"":            "",
+		"cockroachdb": "SELECT nextval('orders_id_seq')",
+		"postgres":    "SELECT nextval('orders_id_seq')",
+		"sqlite3":     "",
+		"mysql":       "",
+		"spanner":     "",
+		"mssql":       "SELECT NEXT VALUE FOR orders_id_seq",
+		"oracle":      "SELECT orders_id_seq.NEXTVAL FROM dual",
+	}
+	for name, query := range expected {
+		d, _ := Get(name)
+		if query == "" {
+			assert.Empty(t, d.NextSequenceValue(), name)
+			continue
+		}
+		assert.Equal(t, query, fmt.Sprintf(d.NextSequenceValue(), "orders_id_seq"), name)
+	}
+}
+
+func TestInsertIfNotExistsSupport(t *testing.T) {
+	for _, name := range append(Names(), "") {
+		d, _ := Get(name)
+		switch name {
+		case "cockroachdb", "postgres", "sqlite3":
+			assert.Equal(t, "ON CONFLICT (email) DO NOTHING", fmt.Sprintf(d.InsertIgnoreClause(), "email", "id"), name)
+		case "mysql":
+			assert.Equal(t, "ON DUPLICATE KEY UPDATE id = id", fmt.Sprintf(d.InsertIgnoreClause(), "email", "id"))
+		case "spanner":
+			assert.Empty(t, d.InsertIgnoreClause(), name)
+			assert.Empty(t, d.InsertWhereNotExists(), name)
+			assert.Equal(t, "INSERT OR IGNORE INTO t (a) VALUES (?)",
+				fmt.Sprintf(d.InsertOr(), "t", "a", "?", "IGNORE"), name)
+		default:
+			assert.Empty(t, d.InsertIgnoreClause(), name)
+			assert.Contains(t, d.InsertWhereNotExists(), "WHERE NOT EXISTS", name)
+		}
+	}
+
+	d, _ := Get("mysql")
+	assert.Equal(t, "INSERT INTO users (email,name) SELECT ?,? FROM DUAL WHERE NOT EXISTS "+
+		"(SELECT 1 FROM users WHERE email = ?)",
+		fmt.Sprintf(d.InsertWhereNotExists(), "users", "email,name", "?,?", "email = ?"))
+}
+
+func TestPaging(t *testing.T) {
+	for _, name := range append(Names(), "") {
+		d, _ := Get(name)
+		switch name {
+		case "mysql", "sqlite3", "spanner":
+			assert.True(t, d.OffsetNeedsLimit(), name)
+		default:
+			assert.False(t, d.OffsetNeedsLimit(), name)
+		}
+		if name == "mssql" {
+			assert.Equal(t, "(SELECT NULL)", d.PagingOrder())
+		} else {
+			assert.Empty(t, d.PagingOrder(), name)
+		}
+	}
+}
+
+func TestReturning(t *testing.T) {
+	for _, name := range append(Names(), "") {
+		d, _ := Get(name)
+		switch name {
+		case "postgres", "cockroachdb", "sqlite3":
+			assert.Equal(t, `RETURNING "id"`, fmt.Sprintf(d.Returning(), d.Quote("id")), name)
+		default:
+			assert.Empty(t, d.Returning(), name)
+		}
+	}
+}
+
+func TestUnionSelect(t *testing.T) {
+	for _, name := range append(Names(), "") {
+		d, _ := Get(name)
+		q := "SELECT * FROM users ORDER BY id LIMIT 1"
+		switch name {
+		case "sqlite3":
+			assert.Equal(t, "SELECT * FROM ("+q+")", fmt.Sprintf(d.UnionSelect(), q), name)
+		case "mssql":
+			assert.Equal(t, "SELECT * FROM ("+q+") AS [union_operand]", fmt.Sprintf(d.UnionSelect(), q), name)
+			assert.Equal(t, " OFFSET 0 ROWS", d.OrderedSubquery(), name)
+		default:
+			assert.Equal(t, "("+q+")", fmt.Sprintf(d.UnionSelect(), q), name)
+			assert.Equal(t, "", d.OrderedSubquery(), name)
+		}
+	}
+}
+
+func TestRegexpMatchSupport(t *testing.T) {
+	for _, name := range append(Names(), "") {
+		d, _ := Get(name)
+		switch name {
+		case "", "mssql", "sqlite3":
+			assert.Empty(t, d.RegexpMatch(), name)
+		default:
+			assert.Contains(t, fmt.Sprintf(d.RegexpMatch(), "name"), "name", name)
+		}
+	}
+}
+
+func TestLikeWildcards(t *testing.T) {
+	for _, name := range append(Names(), "") {
+		d, _ := Get(name)
+		if name == "mssql" {
+			assert.Equal(t, "[", d.LikeWildcards(), name)
+			continue
+		}
+		assert.Empty(t, d.LikeWildcards(), name)
+	}
+}
+
+func TestUpdateFromValues(t *testing.T) {
+	for _, name := range Names() {
+		d, _ := Get(name)
+		switch name {
+		case "cockroachdb", "postgres":
+			assert.Contains(t, d.UpdateFromValues(), "UNION ALL VALUES %[3]s", name)
+		default:
+			assert.Empty(t, d.UpdateFromValues(), name)
+		}
+	}
+}
+
+func TestQuote(t *testing.T) {
+	expected := map[string]string{
+		"":            "email",
+		"cockroachdb": `"email"`,
+		"mssql":       "[email]",
+		"mysql":       "`email`",
+		"oracle":      `"email"`,
+		"postgres":    `"email"`,
+		"sqlite3":     `"email"`,
+		"spanner":     "`email`",
+	}
+	for name, quoted := range expected {
+		d, _ := Get(name)
+		assert.Equal(t, quoted, d.Quote("email"), name)
+		assert.Contains(t, d.ILike(), "%[1]s", name)
+	}
+}
+
+func TestJSONSupport(t *testing.T) {
+	for _, name := range Names() {
+		if name == "oracle" {
+			continue
+		}
+		d, _ := Get(name)
+		assert.Contains(t, d.JSONPathEq(), "%[1]s", name)
+		assert.Contains(t, d.JSONPath(), "%[1]s", name)
+	}
+
+	d, _ := Get("")
+	assert.Empty(t, d.JSONPathEq())
+	assert.Empty(t, d.JSONContains())
+
+	for _, name := range []string{"", "mssql", "mysql", "sqlite3"} {
+		d, _ = Get(name)
+		assert.Empty(t, d.JSONHasKey(), name)
+	}
+	for _, name := range []string{"cockroachdb", "postgres"} {
+		d, _ = Get(name)
+		assert.Equal(t, "%[1]s -> ? IS NOT NULL", d.JSONHasKey(), name)
+	}
+}
+
+func TestFullTextMatch(t *testing.T) {
+	for _, name := range Names() {
+		d, _ := Get(name)
+		switch name {
+		case "oracle", "spanner", "sqlite3":
+			assert.Empty(t, d.FullTextMatch(), name)
+		default:
+			assert.Contains(t, d.FullTextMatch(), "%[1]s", name)
+		}
+	}
+
+	d, _ := Get("")
+	assert.Empty(t, d.FullTextMatch())
+}
+
+func TestLocaleSupport(t *testing.T) {
+	for _, name := range Names() {
+		d, _ := Get(name)
+		if name == "spanner" {
+			assert.Empty(t, d.Collate(), name)
+		} else {
+			assert.Contains(t, d.Collate(), "COLLATE", name)
+		}
+
+		if name == "postgres" || name == "cockroachdb" {
+			assert.Contains(t, d.FullTextMatchConfig(), "?::regconfig", name)
+		} else {
+			assert.Empty(t, d.FullTextMatchConfig(), name)
+		}
+	}
+
+	d, _ := Get("postgres")
+	assert.Equal(t, `"name" COLLATE "de-DE-x-icu"`, fmt.Sprintf(d.Collate(), d.Quote("name"), "de-DE-x-icu"))
+}
+
+func TestWeightedRandomKey(t *testing.T) {
+	for _, name := range Names() {
+		d, _ := Get(name)
+		switch name {
+		case "spanner", "sqlite3":
+			assert.Empty(t, d.WeightedRandomKey(), name)
+		default:
+			assert.Contains(t, d.WeightedRandomKey(), "/ %[1]s", name)
+		}
+	}
+
+	d, _ := Get("")
+	assert.Empty(t, d.WeightedRandomKey())
+}
+
+func TestGeoDistance(t *testing.T) {
+	for _, name := range Names() {
+		d, _ := Get(name)
+		if name == "sqlite3" {
+			assert.Empty(t, d.GeoDistance(), name)
+			continue
+		}
+		assert.Equal(t, 3, strings.Count(d.GeoDistance(), "?"), name)
+	}
+
+	d, _ := Get("mysql")
+	assert.Contains(t, fmt.Sprintf(d.GeoDistance(), d.Quote("lat"), d.Quote("lng")),
+		"SIN((`lng` - ?) * 0.008726646259971648)")
+}
+
+func TestDuplicateKeyError(t *testing.T) {
+	messages := map[string]string{
+		"mysql":       "Error 1062: Duplicate entry 'a@b.c' for key 'users.idx_users_email'",
+		"postgres":    `pq: duplicate key value violates unique constraint "idx_users_email"`,
+		"cockroachdb": `pq: duplicate key value violates unique constraint "idx_users_email"`,
+		"sqlite3":     "UNIQUE constraint failed: users.email",
+		"spanner":     "Unique index violation on index idx_users_email at index key [a@b.c]",
+		"mssql":       "Cannot insert duplicate key row in object 'dbo.users' with unique index 'idx_users_email'.",
+		"oracle":      "ORA-00001: unique constraint (APP.IDX_USERS_EMAIL) violated",
+	}
+	for _, name := range Names() {
+		d, _ := Get(name)
+		m := regexp.MustCompile(d.DuplicateKeyError()).FindStringSubmatch(messages[name])
+		if assert.Len(t, m, 2, name) {
+			assert.Contains(t, []string{"idx_users_email", "IDX_USERS_EMAIL", "email"}, m[1], name)
+		}
+	}
+
+	d, _ := Get("")
+	assert.Empty(t, d.DuplicateKeyError())
+}
+
+func TestCountFilter(t *testing.T) {
+	d, _ := Get("postgres")
+	assert.Equal(t, `COUNT(*) FILTER (WHERE "kind" = ?)`, fmt.Sprintf(d.CountFilter(), d.Quote("kind")+" = ?"))
+	d, _ = Get("mysql")
+	assert.Equal(t, "COUNT(CASE WHEN `kind` = ? THEN 1 END)", fmt.Sprintf(d.CountFilter(), d.Quote("kind")+" = ?"))
+}
+
+func TestArraySupport(t *testing.T) {
+	for _, name := range Names() {
+		d, _ := Get(name)
+		if name == "cockroachdb" || name == "postgres" {
+			assert.Equal(t, "%[1]s @> ?", d.ArrayContains(), name)
+			assert.Equal(t, "%[1]s && ?", d.ArrayOverlaps(), name)
+			assert.Contains(t, d.ArrayLengthEq(), "array_length(%[1]s, 1)", name)
+			continue
+		}
+		assert.Empty(t, d.ArrayContains(), name)
+		assert.Empty(t, d.ArrayOverlaps(), name)
+		assert.Empty(t, d.ArrayLengthEq(), name)
+	}
+}
+
+func TestRowLocking(t *testing.T) {
+	for _, name := range []string{"mysql", "postgres"} {
+		d, _ := Get(name)
+		assert.Equal(t, "FOR UPDATE", d.ForUpdate(), name)
+		assert.NotEmpty(t, d.ForShare(), name)
+	}
+
+	d, _ := Get("sqlite3")
+	assert.Empty(t, d.ForUpdate())
+	assert.Empty(t, d.ForShare())
+}
+
+func TestSpanner(t *testing.T) {
+	d, _ := Get("spanner")
+	assert.False(t, d.AutoIncrement())
+	assert.Equal(t, "FOR UPDATE", d.ForUpdate())
+	assert.Equal(t, "JSON_VALUE(%[1]s, ?) = ?", d.JSONPathEq())
+
+	for _, name := range []string{"", "mssql", "mysql", "postgres", "sqlite3"} {
+		d, _ = Get(name)
+		assert.True(t, d.AutoIncrement(), name)
+	}
+}
+
+func TestMSSQL(t *testing.T) {
+	d, _ := Get("mssql")
+	assert.Empty(t, d.ForUpdate())
+	assert.Empty(t, d.ForShare())
+	assert.Empty(t, d.JSONContains())
+	assert.Equal(t, "JSON_VALUE(%[1]s, ?) = ?", d.JSONPathEq())
+	assert.Equal(t, "LOWER(%[1]s) LIKE LOWER(?)", d.ILike())
+}
+
+func TestOracle(t *testing.T) {
+	d, _ := Get("oracle")
+	assert.False(t, d.AutoIncrement())
+	assert.Empty(t, d.JSONPathEq())
+	assert.Equal(t, "FOR UPDATE", d.ForUpdate())
+
+	long := "join_order_items_with_long_relation_name_key"
+	quoted := d.Quote(long)
+	assert.Len(t, quoted, oracleMaxIdentifierLen+2)
+	assert.Equal(t, quoted, d.Quote(long))
+	assert.NotEqual(t, quoted, d.Quote(long+"2"))
+	assert.Equal(t, `"`+long[:21], quoted[:22])
+	assert.Equal(t, `"email"`, d.Quote("email"))
+
+	for _, name := range []string{"", "mssql", "mysql", "postgres", "sqlite3", "spanner"} {
+		d, _ = Get(name)
+		assert.Zero(t, d.MaxIdentifierLen(), name)
+	}
+}
+
+func TestCallProcedure(t *testing.T) {
+	expected := map[string]string{
+		"":         "CALL %[1]s(%[2]s)",
+		"mysql":    "CALL %[1]s(%[2]s)",
+		"postgres": "SELECT * FROM %[1]s(%[2]s)",
+		"sqlite3":  "",
+		"spanner":  "",
+		"mssql":    "EXEC %[1]s %[2]s",
+		"oracle":   "SELECT * FROM TABLE(%[1]s(%[2]s))",
+	}
+	for name, call := range expected {
+		d, _ := Get(name)
+		assert.Equal(t, call, d.CallProcedure(), name)
+	}
+}
+
+func TestSetIsolation(t *testing.T) {
+	for _, name := range []string{"", "postgres", "mssql", "oracle"} {
+		d, _ := Get(name)
+		assert.Equal(t, "SET TRANSACTION ISOLATION LEVEL %[1]s", d.SetIsolation(), name)
+	}
+	for _, name := range []string{"mysql", "sqlite3", "spanner"} {
+		d, _ := Get(name)
+		assert.Empty(t, d.SetIsolation(), name)
+	}
+}
+
+func TestTwoPhaseCommit(t *testing.T) {
+	d, _ := Get("postgres")
+	assert.Equal(t, "PREPARE TRANSACTION %[1]s", d.PrepareTransaction())
+	assert.Equal(t, "COMMIT PREPARED %[1]s", d.CommitPrepared())
+	assert.Equal(t, "ROLLBACK PREPARED %[1]s", d.RollbackPrepared())
+
+	for _, name := range []string{"", "cockroachdb", "mysql", "sqlite3", "spanner", "mssql", "oracle"} {
+		d, _ := Get(name)
+		assert.Empty(t, d.PrepareTransaction(), name)
+	}
+}
+
+func TestSetConstraints(t *testing.T) {
+	for _, name := range []string{"postgres", "oracle"} {
+		d, _ := Get(name)
+		assert.Equal(t, "SET CONSTRAINTS ALL %[1]s", d.SetConstraints(), name)
+	}
+
+	for _, name := range []string{"", "cockroachdb", "mysql", "sqlite3", "spanner", "mssql"} {
+		d, _ := Get(name)
+		assert.Empty(t, d.SetConstraints(), name)
+	}
+}
+
+func TestForUpdateSkipLocked(t *testing.T) {
+	for _, name := range []string{"mysql", "postgres", "oracle"} {
+		d, _ := Get(name)
+		assert.Equal(t, "FOR UPDATE SKIP LOCKED", d.ForUpdateSkipLocked(), name)
+	}
+
+	for _, name := range []string{"", "sqlite3", "spanner", "mssql"} {
+		d, _ := Get(name)
+		assert.Empty(t, d.ForUpdateSkipLocked(), name)
+	}
+}
+
+func TestAsOfSystemTime(t *testing.T) {
+	d, _ := Get("cockroachdb")
+	assert.Equal(t, "AS OF SYSTEM TIME '%[1]s'", d.AsOfSystemTime())
+	assert.Equal(t, "FOR UPDATE SKIP LOCKED", d.ForUpdateSkipLocked())
+
+	for _, name := range []string{"", "mysql", "postgres", "sqlite3", "spanner", "mssql", "oracle"} {
+		d, _ = Get(name)
+		assert.Empty(t, d.AsOfSystemTime(), name)
+	}
+}
+
+func TestExplain(t *testing.T) {
+	for name, explain := range map[string]string{
+		"":            "EXPLAIN %[1]s",
+		"mysql":       "EXPLAIN %[1]s",
+		"cockroachdb": "EXPLAIN %[1]s",
+		"postgres":    "EXPLAIN (ANALYZE off) %[1]s",
+		"sqlite3":     "EXPLAIN QUERY PLAN %[1]s",
+	} {
+		d, _ := Get(name)
+		assert.Equal(t, explain, d.Explain(), name)
+	}
+
+	for _, name := range []string{"spanner", "mssql", "oracle"} {
+		d, _ := Get(name)
+		assert.Empty(t, d.Explain(), name)
+	}
+}
+
+func TestMaxBindVars(t *testing.T) {
+	for name, max := range map[string]int{
+		"":            999,
+		"cockroachdb": 65535,
+		"mssql":       2100,
+		"mysql":       65535,
+		"sqlite3":     999,
+		"spanner":     950,
+	} {
+		d, _ := Get(name)
+		assert.Equal(t, max, d.MaxBindVars(), name)
+	}
+}
@@ -0,0 +1,150 @@
+// Package gormoracle registers "oracle" dialect of GORM v1: querysets
+// generated for oracle dialect need it to bind variables as :1 and to limit
+// rows by OFFSET FETCH of Oracle 12c. GORM v1 has no Oracle dialect, so it's
+// registered by blank import along with database driver:
+//
+//	import _ "github.com/jirfag/go-queryset/queryset/dialect/gormoracle"
+//
+//	db, err := gorm.Open("oracle", sqlDB)
+//
+// Oracle before 12c limits rows only by ROWNUM in subquery: GORM v1 appends
+// limit to query, so such versions aren't supported.
+package gormoracle
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+func init() {
+	gorm.RegisterDialect("oracle", &oracle{})
+}
+
+type oracle struct {
+	db *sql.DB
+}
+
+func (oracle) GetName() string {
+	return "oracle"
+}
+
+func (s *oracle) SetDB(db *sql.DB) {
+	s.db = db
+}
+
+func (oracle) BindVar(i int) string {
+	return fmt.Sprintf(":%d", i)
+}
+
+func (oracle) Quote(key string) string {
+	return fmt.Sprintf(`"%s"`, key)
+}
+
+// DataTypeOf doesn't make numeric primary keys identity columns: they are
+// filled by sequences of "sequence" option of querysets
+func (oracle) DataTypeOf(field *gorm.StructField) string {
+	var dataValue, sqlType, size, additionalType = gorm.ParseFieldStructForDialect(field)
+
+	if sqlType == "" {
+		switch dataValue.Kind() {
+		case reflect.Bool:
+			sqlType = "NUMBER(1)"
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uintptr:
+			sqlType = "NUMBER(10)"
+		case reflect.Int64, reflect.Uint64:
+			sqlType = "NUMBER(19)"
+		case reflect.Float32, reflect.Float64:
+			sqlType = "BINARY_DOUBLE"
+		case reflect.String:
+			if size > 0 && size <= 4000 {
+				sqlType = fmt.Sprintf("VARCHAR2(%d)", size)
+			} else {
+				sqlType = "CLOB"
+			}
+		case reflect.Struct:
+			if _, ok := dataValue.Interface().(time.Time); ok {
+				sqlType = "TIMESTAMP WITH TIME ZONE"
+			}
+		default:
+			if _, ok := dataValue.Interface().([]byte); ok {
+				if size > 0 && size <= 2000 {
+					sqlType = fmt.Sprintf("RAW(%d)", size)
+				} else {
+					sqlType = "BLOB"
+				}
+			}
+		}
+	}
+
+	if sqlType == "" {
+		panic(fmt.Sprintf("invalid sql type %s (%s) for oracle", dataValue.Type().Name(), dataValue.Kind().String()))
+	}
+
+	if strings.TrimSpace(additionalType) == "" {
+		return sqlType
+	}
+	return fmt.Sprintf("%v %v", sqlType, additionalType)
+}
+
+func (s oracle) HasIndex(tableName string, indexName string) bool {
+	var count int
+	s.db.QueryRow("SELECT count(*) FROM user_indexes WHERE table_name = :1 AND index_name = :2",
+		tableName, indexName).Scan(&count)
+	return count > 0
+}
+
+func (s oracle) RemoveIndex(tableName string, indexName string) error {
+	_, err := s.db.Exec(fmt.Sprintf("DROP INDEX %v", s.Quote(indexName)))
+	return err
+}
+
+func (s oracle) HasForeignKey(tableName string, foreignKeyName string) bool {
+	var count int
+	s.db.QueryRow("SELECT count(*) FROM user_constraints WHERE constraint_type = 'R' AND table_name = :1 "+
+		"AND constraint_name = :2", tableName, foreignKeyName).Scan(&count)
+	return count > 0
+}
+
+func (s oracle) HasTable(tableName string) bool {
+	var count int
+	s.db.QueryRow("SELECT count(*) FROM user_tables WHERE table_name = :1", tableName).Scan(&count)
+	return count > 0
+}
+
+func (s oracle) HasColumn(tableName string, columnName string) bool {
+	var count int
+	s.db.QueryRow("SELECT count(*) FROM user_tab_columns WHERE table_name = :1 AND column_name = :2",
+		tableName, columnName).Scan(&count)
+	return count > 0
+}
+
+// LimitAndOffsetSQL spells limit and offset by OFFSET FETCH of Oracle 12c
+func (oracle) LimitAndOffsetSQL(limit, offset int) (sql string) {
+	if limit > 0 || offset > 0 {
+		if offset < 0 {
+			offset = 0
+		}
+
+		sql += fmt.Sprintf(" OFFSET %d ROWS", offset)
+
+		if limit >= 0 {
+			sql += fmt.Sprintf(" FETCH NEXT %d ROWS ONLY", limit)
+		}
+	}
+	return
+}
+
+func (oracle) SelectFromDummyTable() string {
+	return "FROM DUAL"
+}
+
+// LastInsertIDReturningSuffix is empty: RETURNING INTO of Oracle needs out
+// bind variables, primary keys are set by sequences before inserts instead
+func (oracle) LastInsertIDReturningSuffix(tableName, columnName string) string {
+	return ""
+}
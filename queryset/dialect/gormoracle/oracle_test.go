@@ -0,0 +1,21 @@
+package gormoracle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimitAndOffsetSQL(t *testing.T) {
+	d := oracle{}
+	assert.Equal(t, "", d.LimitAndOffsetSQL(-1, -1))
+	assert.Equal(t, " OFFSET 0 ROWS FETCH NEXT 10 ROWS ONLY", d.LimitAndOffsetSQL(10, -1))
+	assert.Equal(t, " OFFSET 20 ROWS FETCH NEXT 10 ROWS ONLY", d.LimitAndOffsetSQL(10, 20))
+	assert.Equal(t, " OFFSET 20 ROWS", d.LimitAndOffsetSQL(-1, 20))
+}
+
+func TestBindVar(t *testing.T) {
+	d := oracle{}
+	assert.Equal(t, ":1", d.BindVar(1))
+	assert.Equal(t, ":12", d.BindVar(12))
+}
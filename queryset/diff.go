@@ -0,0 +1,196 @@
+package queryset
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// diffContextLines is a number of unchanged lines around changes in hunks
+const diffContextLines = 3
+
+// maxDiffEdits is a max number of edits found by diffLines: if files differ
+// more, all differing lines are reported as changed
+const maxDiffEdits = 2000
+
+// diffOp is an operation of diff: ' ' (line is kept), '-' or '+'
+type diffOp struct {
+	kind byte
+	line string
+}
+
+// unifiedDiff returns diff of content of file in unified format, it's empty
+// if content wasn't changed
+func unifiedDiff(path string, prev, cur []byte) string {
+	if bytes.Equal(prev, cur) {
+		return ""
+	}
+
+	ops := diffLines(splitLines(prev), splitLines(cur))
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "--- %s\n+++ %s (generated)\n", path, path)
+
+	prevLine, curLine := 1, 1 // numbers of lines of ops[i]
+	for i := 0; i < len(ops); {
+		if ops[i].kind == ' ' {
+			prevLine, curLine, i = prevLine+1, curLine+1, i+1
+			continue
+		}
+
+		// hunk is extended while changes are separated by less than
+		// 2*diffContextLines unchanged lines
+		start := i - diffContextLines
+		if start < 0 {
+			start = 0
+		}
+		end := i
+		for j := i; j < len(ops) && j-end <= 2*diffContextLines; j++ {
+			if ops[j].kind != ' ' {
+				end = j + 1
+			}
+		}
+		end += diffContextLines
+		if end > len(ops) {
+			end = len(ops)
+		}
+
+		hunkPrevLine, hunkCurLine := prevLine-(i-start), curLine-(i-start)
+		var prevCount, curCount int
+		var lines bytes.Buffer
+		for _, op := range ops[start:end] {
+			if op.kind != '+' {
+				prevCount++
+			}
+			if op.kind != '-' {
+				curCount++
+			}
+			fmt.Fprintf(&lines, "%c%s\n", op.kind, op.line)
+		}
+		// empty range starts at the line before it
+		if prevCount == 0 {
+			hunkPrevLine--
+		}
+		if curCount == 0 {
+			hunkCurLine--
+		}
+		fmt.Fprintf(&b, "@@ -%d,%d +%d,%d @@\n%s", hunkPrevLine, prevCount, hunkCurLine, curCount, lines.String())
+
+		for _, op := range ops[i:end] {
+			if op.kind != '+' {
+				prevLine++
+			}
+			if op.kind != '-' {
+				curLine++
+			}
+		}
+		i = end
+	}
+	return b.String()
+}
+
+func splitLines(data []byte) []string {
+	if len(data) == 0 {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+}
+
+// diffLines returns operations transforming lines a into lines b found by
+// Myers algorithm
+func diffLines(a, b []string) []diffOp {
+	// common prefix and suffix are kept: generated files are long and
+	// usually differ in few places
+	var ops []diffOp
+	for len(a) != 0 && len(b) != 0 && a[0] == b[0] {
+		ops = append(ops, diffOp{' ', a[0]})
+		a, b = a[1:], b[1:]
+	}
+	suffix := 0
+	for suffix < len(a) && suffix < len(b) && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	ops = append(ops, diffMiddle(a[:len(a)-suffix], b[:len(b)-suffix])...)
+	for _, l := range a[len(a)-suffix:] {
+		ops = append(ops, diffOp{' ', l})
+	}
+	return ops
+}
+
+// diffMiddle returns shortest edit script of a into b, if it's longer than
+// maxDiffEdits all lines of a are removed and all lines of b are added
+func diffMiddle(a, b []string) []diffOp {
+	n, m := len(a), len(b)
+	max := n + m
+	if max > maxDiffEdits {
+		max = maxDiffEdits
+	}
+
+	// v[offset+k] is the furthest x on diagonal k, trace[d] keeps its
+	// diagonals -d..d before d-th step
+	offset := max + 1
+	v := make([]int, 2*offset+1)
+	var trace [][]int
+	for d := 0; d <= max; d++ {
+		trace = append(trace, append([]int(nil), v[offset-d:offset+d+1]...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x, y = x+1, y+1
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return backtrackDiff(a, b, trace)
+			}
+		}
+	}
+
+	var ops []diffOp
+	for _, l := range a {
+		ops = append(ops, diffOp{'-', l})
+	}
+	for _, l := range b {
+		ops = append(ops, diffOp{'+', l})
+	}
+	return ops
+}
+
+func backtrackDiff(a, b []string, trace [][]int) []diffOp {
+	var ops []diffOp
+	x, y := len(a), len(b)
+	for d := len(trace) - 1; d > 0; d-- {
+		v := trace[d] // diagonal k is v[k+d]
+		k := x - y
+		prevK := k - 1
+		if k == -d || (k != d && v[k-1+d] < v[k+1+d]) {
+			prevK = k + 1
+		}
+		prevX := v[prevK+d]
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			ops = append(ops, diffOp{' ', a[x-1]})
+			x, y = x-1, y-1
+		}
+		if x == prevX {
+			ops = append(ops, diffOp{'+', b[prevY]})
+		} else {
+			ops = append(ops, diffOp{'-', a[prevX]})
+		}
+		x, y = prevX, prevY
+	}
+	for x > 0 && y > 0 {
+		ops = append(ops, diffOp{' ', a[x-1]})
+		x, y = x-1, y-1
+	}
+
+	for i, j := 0, len(ops)-1; i < j; i, j = i+1, j-1 {
+		ops[i], ops[j] = ops[j], ops[i]
+	}
+	return ops
+}
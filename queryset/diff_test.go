@@ -0,0 +1,52 @@
+package queryset
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	lines := func(n int, changed map[int]string) []byte {
+		var ret []string
+		for i := 1; i <= n; i++ {
+			l, ok := changed[i]
+			if !ok {
+				l = fmt.Sprintf("line %d", i)
+			}
+			if l != "" { // empty line is removed
+				ret = append(ret, l)
+			}
+		}
+		return []byte(strings.Join(ret, "\n") + "\n")
+	}
+
+	prev := lines(30, nil)
+	assert.Equal(t, "", unifiedDiff("f.go", prev, prev))
+
+	cur := lines(30, map[int]string{5: "changed", 20: ""})
+	assert.Equal(t, `--- f.go
++++ f.go (generated)
+@@ -2,7 +2,7 @@
+ line 2
+ line 3
+ line 4
+-line 5
++changed
+ line 6
+ line 7
+ line 8
+@@ -17,7 +17,6 @@
+ line 17
+ line 18
+ line 19
+-line 20
+ line 21
+ line 22
+ line 23
+`, unifiedDiff("f.go", prev, cur))
+
+	assert.Equal(t, "--- f.go\n+++ f.go (generated)\n@@ -0,0 +1,1 @@\n+a\n", unifiedDiff("f.go", nil, []byte("a\n")))
+}
@@ -0,0 +1,52 @@
+package queryset
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/jirfag/go-queryset/queryset/dialect"
+	"github.com/jirfag/go-queryset/queryset/field"
+)
+
+// structErrors are typed errors of struct declared by "errors" option:
+// not found error and duplicate error translated from errors of driver
+type structErrors struct {
+	DuplicateKeyRe string         // regexp of errors of unique indexes violations, it's empty if unknown
+	Indexes        []indexColumns // columns of unique indexes of struct
+}
+
+// indexColumns are comma-separated columns of unique index
+type indexColumns struct {
+	Name    string
+	Columns string
+}
+
+type indexColumnsSlice []indexColumns
+
+func (s indexColumnsSlice) Len() int           { return len(s) }
+func (s indexColumnsSlice) Less(i, j int) bool { return s[i].Name < s[j].Name }
+func (s indexColumnsSlice) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+// getStructErrors returns typed errors of struct or nil if it has no
+// "errors" option
+func getStructErrors(opts structOptions, indexes []field.UniqueIndex, pk *field.Info,
+	d dialect.Dialect) *structErrors {
+
+	if _, ok := opts["errors"]; !ok {
+		return nil
+	}
+
+	e := structErrors{DuplicateKeyRe: d.DuplicateKeyError()}
+	for _, idx := range indexes {
+		var columns []string
+		for _, f := range idx.Fields {
+			columns = append(columns, f.DBName)
+		}
+		e.Indexes = append(e.Indexes, indexColumns{Name: idx.Name, Columns: strings.Join(columns, ", ")})
+	}
+	if pk != nil {
+		e.Indexes = append(e.Indexes, indexColumns{Name: "PRIMARY", Columns: pk.DBName}) // MySQL
+	}
+	sort.Sort(indexColumnsSlice(e.Indexes))
+	return &e
+}
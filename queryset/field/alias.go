@@ -0,0 +1,12 @@
+//go:build go1.22
+// +build go1.22
+
+package field
+
+import "go/types"
+
+// unalias returns type aliased by t if t is an alias: go/types has
+// explicit aliases since Go 1.22
+func unalias(t types.Type) types.Type {
+	return types.Unalias(t)
+}
@@ -0,0 +1,11 @@
+//go:build !go1.22
+// +build !go1.22
+
+package field
+
+import "go/types"
+
+// unalias returns t: aliases are resolved by go/types before Go 1.22
+func unalias(t types.Type) types.Type {
+	return t
+}
@@ -0,0 +1,23 @@
+//go:build go1.22
+// +build go1.22
+
+package field
+
+import (
+	"go/token"
+	"go/types"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAliasTypes(t *testing.T) {
+	pkg := types.NewPackage("github.com/x/models", "models")
+	source := types.NewAlias(types.NewTypeName(token.Pos(0), pkg, "Source", nil), typeString)
+
+	f := NewInfoGenerator(pkg).GenFieldInfo(newTf(fName, source, ""))
+	if assert.NotNil(t, f) {
+		assert.Equal(t, "Source", f.TypeName)
+		assert.True(t, f.IsString)
+	}
+}
@@ -1,10 +1,11 @@
 package field
 
 import (
-	"fmt"
 	"go/types"
 	"reflect"
+	"sort"
 	"strings"
+	"unicode"
 
 	"github.com/jinzhu/gorm"
 )
@@ -16,22 +17,99 @@ type BaseInfo struct {
 	IsStruct  bool
 	IsNumeric bool
 	IsTime    bool
+	IsString  bool // underlying type is string
+	IsBool    bool // underlying type is bool
+	IsDecimal bool // type is decimal.Decimal of github.com/shopspring/decimal
+
+	// ArrayElem is a type of elements of postgres array type of lib/pq,
+	// e.g. string for pq.StringArray
+	ArrayElem string
+
+	IsPrimaryKey   bool     // field is marked by primary_key tag
+	IsSearchBacked bool     // field is marked by queryset:"search" tag
+	IsCAS          bool     // field is marked by queryset:"cas" tag
+	IsFullText     bool     // field is marked by queryset:"fulltext" tag
+	IsWeight       bool     // field is marked by queryset:"weight" tag
+	IsLat          bool     // field is marked by queryset:"lat" tag
+	IsLng          bool     // field is marked by queryset:"lng" tag
+	IsVersion      bool     // field is marked by queryset:"version" tag
+	IsNoFilter     bool     // field is marked by queryset:"nofilter" tag
+	UniqueIndexes  []string // names of unique indexes from unique_index tag
+	Check          string   // check constraint from check tag setting
+	IsJSON         bool     // column has json or jsonb type by type tag setting or by Go type
+	JSONType       string   // json or jsonb type of column from type tag setting
+	Default        string   // default value of column from default tag setting
+
+	EnumValues []EnumValue // constants of named type of field
+}
+
+// EnumValue is a constant of named type of field declared in type's package
+type EnumValue struct {
+	Name  string // name without prefix of type name, e.g. Active for StatusActive
+	Const string // constant as it's written in the package of struct
 }
 
 type Info struct {
 	pointed *BaseInfo
 	BaseInfo
 	IsPointer bool
+
+	// SQLNullValue is a name of value field of nullable type of database/sql
+	// (e.g. String for sql.NullString) or of decimal.NullDecimal. Pointed info
+	// describes the value.
+	SQLNullValue string
 }
 
+// GetPointed returns info of pointed value for pointers and info of value
+// for nullable types
 func (fi Info) GetPointed() Info {
 	return Info{
 		BaseInfo: *fi.pointed,
 	}
 }
 
+// IsArray returns true if field has postgres array type of lib/pq
+func (fi Info) IsArray() bool {
+	return fi.ArrayElem != ""
+}
+
+// IsSQLNull returns true if field has nullable type of database/sql or
+// decimal.NullDecimal
+func (fi Info) IsSQLNull() bool {
+	return fi.SQLNullValue != ""
+}
+
+// decimalPkgPath is an import path of package of decimal.Decimal type
+const decimalPkgPath = "github.com/shopspring/decimal"
+
+// pqPkgPath is an import path of lib/pq with postgres array types
+const pqPkgPath = "github.com/lib/pq"
+
+// pqArrayElems maps postgres array types of lib/pq to types of their elements
+var pqArrayElems = map[string]string{
+	"BoolArray":    "bool",
+	"Float64Array": "float64",
+	"Int64Array":   "int64",
+	"StringArray":  "string",
+}
+
+// sqlNullValueFields maps nullable types to their value fields
+var sqlNullValueFields = map[string]string{
+	"database/sql.NullBool":    "Bool",
+	"database/sql.NullByte":    "Byte",
+	"database/sql.NullFloat64": "Float64",
+	"database/sql.NullInt16":   "Int16",
+	"database/sql.NullInt32":   "Int32",
+	"database/sql.NullInt64":   "Int64",
+	"database/sql.NullString":  "String",
+	"database/sql.NullTime":    "Time",
+
+	decimalPkgPath + ".NullDecimal": "Decimal",
+}
+
 type InfoGenerator struct {
-	pkg *types.Package
+	pkg       *types.Package
+	qualified bool // types of pkg are qualified: code is generated into another package
 }
 
 type Field interface {
@@ -64,14 +142,44 @@ func NewInfoGenerator(pkg *types.Package) *InfoGenerator {
 	}
 }
 
-func (g InfoGenerator) getOriginalTypeName(t *types.Named) string {
-	if t.Obj().Pkg() == g.pkg {
-		// t is from the same package as a struct
-		return t.Obj().Name()
+// NewQualifiedInfoGenerator creates generator of infos of fields of structs
+// of package pkg used by code of another package: types of pkg are qualified
+// by its name, fields of its unexported types are skipped
+func NewQualifiedInfoGenerator(pkg *types.Package) *InfoGenerator {
+	return &InfoGenerator{
+		pkg:       pkg,
+		qualified: true,
 	}
+}
 
-	// t is an imported from another package type
-	return fmt.Sprintf("%s.%s", t.Obj().Pkg().Name(), t.Obj().Name())
+// getTypeName returns name of type t as it's written in the package of
+// struct: types of the same package aren't qualified, imported types are
+// qualified by package name
+func (g InfoGenerator) getTypeName(t types.Type) string {
+	return types.TypeString(t, func(p *types.Package) string {
+		if p == g.pkg && !g.qualified {
+			return ""
+		}
+		return p.Name()
+	})
+}
+
+// isAccessible returns false if type t references unexported types of
+// package of struct, which are inaccessible in another package
+func (g InfoGenerator) isAccessible(t types.Type) bool {
+	switch t := t.(type) {
+	case *types.Named:
+		return t.Obj().Pkg() != g.pkg || t.Obj().Exported()
+	case *types.Pointer:
+		return g.isAccessible(t.Elem())
+	case *types.Slice:
+		return g.isAccessible(t.Elem())
+	case *types.Array:
+		return g.isAccessible(t.Elem())
+	case *types.Map:
+		return g.isAccessible(t.Key()) && g.isAccessible(t.Elem())
+	}
+	return true
 }
 
 // parseTagSetting is copy-pasted from gorm source code.
@@ -92,11 +200,139 @@ func parseTagSetting(tags reflect.StructTag) map[string]string {
 	return setting
 }
 
+func isJSONType(sqlType string) bool {
+	switch strings.ToLower(strings.TrimSpace(sqlType)) {
+	case "json", "jsonb":
+		return true
+	}
+	return false
+}
+
+// jsonGoTypes are Go types of JSON columns: import path of package and name
+var jsonGoTypes = [][2]string{
+	{"encoding/json", "RawMessage"},
+	{"github.com/jinzhu/gorm/dialects/postgres", "Jsonb"},
+}
+
+// isJSONGoType returns true if t is a type of JSON column without type tag
+// setting: json.RawMessage or postgres.Jsonb of GORM
+func isJSONGoType(t types.Type) bool {
+	named, ok := t.(*types.Named)
+	if !ok {
+		return false
+	}
+
+	for _, jt := range jsonGoTypes {
+		if isNamedType(named, jt[0], jt[1]) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseQuerySetTag parses go-queryset options from tag like `queryset:"opt1,opt2"`
+func parseQuerySetTag(tags reflect.StructTag) map[string]bool {
+	options := map[string]bool{}
+	for _, opt := range strings.Split(tags.Get("queryset"), ",") {
+		if opt = strings.TrimSpace(opt); opt != "" {
+			options[opt] = true
+		}
+	}
+	return options
+}
+
+// parseUniqueIndexes returns names of unique indexes from unique_index tag
+// setting. Unnamed index is named by db name of field.
+func parseUniqueIndexes(tagSetting map[string]string, dbName string) []string {
+	v, ok := tagSetting["UNIQUE_INDEX"]
+	if !ok {
+		return nil
+	}
+
+	if v == "UNIQUE_INDEX" {
+		return []string{dbName}
+	}
+
+	return strings.Split(v, ",")
+}
+
+// UniqueIndex is a unique index declared by unique_index tags of fields.
+// It's partial if Where predicate is set.
+type UniqueIndex struct {
+	Name   string
+	Fields []Info
+	Where  string
+}
+
+// GetUniqueIndexes groups fields into unique indexes in order of declaration
+func GetUniqueIndexes(fields []Info) []UniqueIndex {
+	var ret []UniqueIndex
+	nameToIndex := map[string]int{}
+	for _, f := range fields {
+		for _, name := range f.UniqueIndexes {
+			i, ok := nameToIndex[name]
+			if !ok {
+				i = len(ret)
+				nameToIndex[name] = i
+				ret = append(ret, UniqueIndex{Name: name})
+			}
+			ret[i].Fields = append(ret[i].Fields, f)
+		}
+	}
+	return ret
+}
+
+// Relation is an association of struct with another struct declared by
+// field: belongs to (Blog *Blog) or has many (Posts []Post)
+type Relation struct {
+	Name       string // name of association field
+	TypeName   string // name of related struct
+	IsHasMany  bool
+	ForeignKey string // name of foreign key field from foreignkey tag setting
+}
+
+// GenRelation returns relation declared by field f: it's nil if f isn't
+// an association with struct from the same package
+func (g InfoGenerator) GenRelation(f Field) *Relation {
+	tagSetting := parseTagSetting(f.Tag())
+	if tagSetting["-"] != "" { // skipped by tag field
+		return nil
+	}
+
+	r := Relation{
+		Name:       f.Name(),
+		ForeignKey: tagSetting["FOREIGNKEY"],
+	}
+	t := f.Type()
+	if st, ok := t.(*types.Slice); ok {
+		r.IsHasMany = true
+		t = st.Elem()
+	}
+	if pt, ok := t.(*types.Pointer); ok {
+		t = pt.Elem()
+	}
+
+	nt, ok := t.(*types.Named)
+	if !ok || nt.Obj().Pkg() != g.pkg {
+		return nil
+	}
+	if _, ok = nt.Underlying().(*types.Struct); !ok {
+		return nil
+	}
+
+	r.TypeName = nt.Obj().Name()
+	return &r
+}
+
 func (g InfoGenerator) GenFieldInfo(f Field) *Info {
 	tagSetting := parseTagSetting(f.Tag())
+	qsOptions := parseQuerySetTag(f.Tag())
 	if tagSetting["-"] != "" { // skipped by tag field
 		return nil
 	}
+	if g.qualified && !g.isAccessible(f.Type()) {
+		return nil
+	}
 
 	dbName := gorm.ToDBName(f.Name())
 	if dbColName := tagSetting["COLUMN"]; dbColName != "" {
@@ -104,8 +340,36 @@ func (g InfoGenerator) GenFieldInfo(f Field) *Info {
 	}
 	bi := BaseInfo{
 		Name:     f.Name(),
-		TypeName: f.Type().String(),
+		TypeName: g.getTypeName(f.Type()),
 		DBName:   dbName,
+
+		IsPrimaryKey:   tagSetting["PRIMARY_KEY"] != "",
+		IsSearchBacked: qsOptions["search"],
+		IsCAS:          qsOptions["cas"],
+		IsFullText:     qsOptions["fulltext"],
+		IsWeight:       qsOptions["weight"],
+		IsLat:          qsOptions["lat"],
+		IsLng:          qsOptions["lng"],
+		IsVersion:      qsOptions["version"],
+		IsNoFilter:     qsOptions["nofilter"],
+		UniqueIndexes:  parseUniqueIndexes(tagSetting, dbName),
+		Check:          tagSetting["CHECK"],
+		IsJSON:         isJSONType(tagSetting["TYPE"]) || isJSONGoType(f.Type()),
+		Default:        tagSetting["DEFAULT"],
+	}
+
+	if bi.IsJSON {
+		if isJSONType(tagSetting["TYPE"]) {
+			bi.JSONType = strings.ToLower(strings.TrimSpace(tagSetting["TYPE"]))
+		}
+		// JSON is stored in strings, []byte or types like json.RawMessage and
+		// postgres.Jsonb: strings are filtered like other strings too
+		if b, ok := f.Type().Underlying().(*types.Basic); ok {
+			bi.IsString = b.Info()&types.IsString != 0
+		}
+		return &Info{
+			BaseInfo: bi,
+		}
 	}
 
 	if bi.TypeName == "time.Time" {
@@ -116,20 +380,56 @@ func (g InfoGenerator) GenFieldInfo(f Field) *Info {
 		}
 	}
 
+	if u := unalias(f.Type()); u != f.Type() {
+		// alias is resolved to aliased type, but its name is kept
+		r := g.GenFieldInfo(field{
+			name: f.Name(),
+			typ:  u,
+			tag:  f.Tag(),
+		})
+		if r != nil {
+			r.TypeName = bi.TypeName
+		}
+		return r
+	}
+
 	switch t := f.Type().(type) {
 	case *types.Basic:
 		bi.IsNumeric = t.Info()&types.IsNumeric != 0
+		bi.IsString = t.Info()&types.IsString != 0
+		bi.IsBool = t.Info()&types.IsBoolean != 0
 		return &Info{
 			BaseInfo: bi,
 		}
 	case *types.Named:
+		if r := g.genSQLNullFieldInfo(t, bi); r != nil {
+			return r
+		}
+
+		if elem := pqArrayElems[t.Obj().Name()]; elem != "" && isNamedType(t, pqPkgPath, t.Obj().Name()) {
+			bi.ArrayElem = elem
+			return &Info{
+				BaseInfo: bi,
+			}
+		}
+
+		if isNamedType(t, decimalPkgPath, "Decimal") {
+			// decimal is a struct, but it's compared like numbers
+			bi.IsDecimal = true
+			bi.IsNumeric = true
+			return &Info{
+				BaseInfo: bi,
+			}
+		}
+
 		r := g.GenFieldInfo(field{
 			name: f.Name(),
 			typ:  t.Underlying(),
 			tag:  f.Tag(),
 		})
 		if r != nil {
-			r.TypeName = g.getOriginalTypeName(t)
+			r.TypeName = g.getTypeName(t)
+			r.EnumValues = g.getEnumValues(t)
 		}
 		return r
 	case *types.Struct:
@@ -152,3 +452,100 @@ func (g InfoGenerator) GenFieldInfo(f Field) *Info {
 		return nil
 	}
 }
+
+// constsSlice sorts constants by position of declaration
+type constsSlice []*types.Const
+
+func (s constsSlice) Len() int           { return len(s) }
+func (s constsSlice) Less(i, j int) bool { return s[i].Pos() < s[j].Pos() }
+func (s constsSlice) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+// getEnumValues returns constants of named type t in order of declaration:
+// only types of package of structs are enums, e.g. time.Duration and
+// time.Month aren't, and only exported constants of qualified package are
+// accessible
+func (g InfoGenerator) getEnumValues(t *types.Named) []EnumValue {
+	pkg := t.Obj().Pkg()
+	if pkg == nil || pkg != g.pkg {
+		return nil
+	}
+
+	var consts constsSlice
+	scope := pkg.Scope()
+	for _, name := range scope.Names() {
+		c, ok := scope.Lookup(name).(*types.Const)
+		if !ok || !types.Identical(c.Type(), t) || (g.qualified && !c.Exported()) {
+			continue
+		}
+		consts = append(consts, c)
+	}
+	sort.Sort(consts)
+
+	var ret []EnumValue
+	names := map[string]bool{}
+	for _, c := range consts {
+		name := strings.TrimPrefix(c.Name(), t.Obj().Name())
+		if name == "" || !unicode.IsUpper([]rune(name)[0]) {
+			name = c.Name() // name isn't prefixed by type name
+		}
+		name = strings.ToUpper(name[:1]) + name[1:]
+		if names[name] {
+			continue
+		}
+		names[name] = true
+
+		v := EnumValue{
+			Name:  name,
+			Const: c.Name(),
+		}
+		if g.qualified {
+			v.Const = pkg.Name() + "." + c.Name()
+		}
+		ret = append(ret, v)
+	}
+	return ret
+}
+
+// isNamedType returns true if t is a type name of package with import path pkgPath
+func isNamedType(t *types.Named, pkgPath, name string) bool {
+	return t.Obj().Pkg() != nil && t.Obj().Pkg().Path() == pkgPath && t.Obj().Name() == name
+}
+
+// genSQLNullFieldInfo returns info of field of nullable type t of database/sql
+// or of decimal.NullDecimal and nil if t isn't such type
+func (g InfoGenerator) genSQLNullFieldInfo(t *types.Named, bi BaseInfo) *Info {
+	if t.Obj().Pkg() == nil {
+		return nil
+	}
+
+	valueField, ok := sqlNullValueFields[t.Obj().Pkg().Path()+"."+t.Obj().Name()]
+	if !ok {
+		return nil
+	}
+
+	s := t.Underlying().(*types.Struct)
+	for i := 0; i < s.NumFields(); i++ {
+		if s.Field(i).Name() != valueField {
+			continue
+		}
+
+		vf := g.GenFieldInfo(field{
+			name: bi.Name,
+			typ:  s.Field(i).Type(),
+		})
+		if vf == nil {
+			return nil
+		}
+
+		value := vf.BaseInfo
+		value.DBName = bi.DBName // column can be set by tag
+		bi.TypeName = g.getTypeName(t)
+		return &Info{
+			BaseInfo:     bi,
+			pointed:      &value,
+			SQLNullValue: valueField,
+		}
+	}
+
+	return nil
+}
@@ -1,6 +1,7 @@
 package field
 
 import (
+	"go/constant"
 	"go/token"
 	"go/types"
 	"reflect"
@@ -73,3 +74,186 @@ func TestColumnNameSetInTag(t *testing.T) {
 	assert.Equal(t, fName, info.Name)
 	assert.Equal(t, typeNamedString.String(), info.TypeName)
 }
+
+func TestQuerySetTagOptions(t *testing.T) {
+	assert.True(t, genFieldInfo(newTf(fName, typeString, `queryset:"search"`)).IsSearchBacked)
+	assert.True(t, genFieldInfo(newTf(fName, typeString, `queryset:"x, search"`)).IsSearchBacked)
+	assert.False(t, genFieldInfo(newTf(fName, typeString, `gorm:"search"`)).IsSearchBacked)
+	assert.True(t, genFieldInfo(newTf(fName, typeString, `queryset:"fulltext"`)).IsFullText)
+	assert.True(t, genFieldInfo(newTf(fName, types.Typ[types.Int], `queryset:"weight"`)).IsWeight)
+	assert.True(t, genFieldInfo(newTf(fName, types.Typ[types.Float64], `queryset:"lat"`)).IsLat)
+	assert.True(t, genFieldInfo(newTf(fName, types.Typ[types.Float64], `queryset:"lng"`)).IsLng)
+	assert.True(t, genFieldInfo(newTf(fName, types.Typ[types.Int], `queryset:"version"`)).IsVersion)
+}
+
+func TestJSONColumn(t *testing.T) {
+	bytes := types.NewSlice(types.Universe.Lookup("byte").Type())
+	f := genFieldInfo(newTf(fName, bytes, `gorm:"type:JSONB"`))
+	if assert.NotNil(t, f) {
+		assert.True(t, f.IsJSON)
+		assert.Equal(t, "[]byte", f.TypeName)
+	}
+	f = genFieldInfo(newTf(fName, typeString, `gorm:"type:json"`))
+	assert.True(t, f.IsJSON)
+	assert.True(t, f.IsString)
+	assert.Equal(t, "json", f.JSONType)
+	assert.False(t, genFieldInfo(newTf(fName, typeString, `gorm:"type:text"`)).IsJSON)
+	assert.Nil(t, genFieldInfo(newTf(fName, bytes, "")))
+
+	// JSON types are detected without type tag setting
+	jsonPkg := types.NewPackage("encoding/json", "json")
+	rawMessage := types.NewNamed(types.NewTypeName(token.Pos(0), jsonPkg, "RawMessage", nil), bytes, nil)
+	postgresPkg := types.NewPackage("github.com/jinzhu/gorm/dialects/postgres", "postgres")
+	jsonb := types.NewNamed(types.NewTypeName(token.Pos(0), postgresPkg, "Jsonb", nil), types.NewStruct(
+		[]*types.Var{types.NewField(token.Pos(0), postgresPkg, "RawMessage", rawMessage, true)}, nil), nil)
+	for _, typ := range []types.Type{rawMessage, jsonb} {
+		f = genFieldInfo(newTf(fName, typ, ""))
+		if assert.NotNil(t, f) {
+			assert.True(t, f.IsJSON, f.TypeName)
+			assert.False(t, f.IsStruct, f.TypeName)
+		}
+	}
+	assert.Equal(t, "postgres.Jsonb", f.TypeName)
+}
+
+func TestUniqueIndexes(t *testing.T) {
+	fields := []Info{
+		*genFieldInfo(newTf("A", typeString, `gorm:"unique_index:idx_ab"`)),
+		*genFieldInfo(newTf("B", typeString, `gorm:"unique_index:idx_ab,idx_b"`)),
+		*genFieldInfo(newTf("C", typeString, `gorm:"unique_index"`)),
+		*genFieldInfo(newTf("D", typeString, "")),
+	}
+
+	indexes := GetUniqueIndexes(fields)
+	if assert.Len(t, indexes, 3) {
+		assert.Equal(t, "idx_ab", indexes[0].Name)
+		assert.Equal(t, []Info{fields[0], fields[1]}, indexes[0].Fields)
+		assert.Equal(t, "idx_b", indexes[1].Name)
+		assert.Equal(t, "c", indexes[2].Name)
+	}
+}
+
+func TestGenRelation(t *testing.T) {
+	pkg := types.NewPackage("models", "models")
+	blog := types.NewNamed(types.NewTypeName(token.Pos(0), pkg, "Blog", nil), types.NewStruct(nil, nil), nil)
+	g := NewInfoGenerator(pkg)
+
+	r := g.GenRelation(newTf("Blog", types.NewPointer(blog), ""))
+	assert.Equal(t, &Relation{Name: "Blog", TypeName: "Blog"}, r)
+
+	r = g.GenRelation(newTf("Blogs", types.NewSlice(blog), `gorm:"foreignkey:OwnerID"`))
+	assert.Equal(t, &Relation{Name: "Blogs", TypeName: "Blog", IsHasMany: true, ForeignKey: "OwnerID"}, r)
+
+	assert.Nil(t, g.GenRelation(newTf("Blog", blog, `gorm:"-"`)))
+	assert.Nil(t, g.GenRelation(newTf(fName, typeNamedString, "")))
+	assert.Nil(t, NewInfoGenerator(nil).GenRelation(newTf("Blog", blog, "")))
+}
+
+func TestNamedTypes(t *testing.T) {
+	pkg := types.NewPackage("github.com/x/models", "models")
+	otherPkg := types.NewPackage("github.com/x/other", "other")
+	status := types.NewNamed(types.NewTypeName(token.Pos(0), pkg, "Status", nil), typeString, nil)
+	kind := types.NewNamed(types.NewTypeName(token.Pos(0), otherPkg, "Kind", nil), typeString, nil)
+	g := NewInfoGenerator(pkg)
+
+	f := g.GenFieldInfo(newTf(fName, status, ""))
+	assert.Equal(t, "Status", f.TypeName)
+	assert.True(t, f.IsString)
+
+	f = g.GenFieldInfo(newTf(fName, types.NewPointer(status), ""))
+	assert.Equal(t, "*Status", f.TypeName)
+	assert.Equal(t, "Status", f.GetPointed().TypeName)
+
+	f = g.GenFieldInfo(newTf(fName, types.NewPointer(kind), ""))
+	assert.Equal(t, "*other.Kind", f.TypeName)
+	assert.Equal(t, "other.Kind", f.GetPointed().TypeName)
+}
+
+func TestEnumValues(t *testing.T) {
+	pkg := types.NewPackage("github.com/x/models", "models")
+	status := types.NewNamed(types.NewTypeName(token.Pos(0), pkg, "Status", nil), typeString, nil)
+	for i, name := range []string{"StatusNew", "StatusActive", "deleted", "StatusActivex"} {
+		c := types.NewConst(token.Pos(i+1), pkg, name, status, constant.MakeString(name))
+		pkg.Scope().Insert(c)
+	}
+	pkg.Scope().Insert(types.NewConst(token.Pos(10), pkg, "Other", typeString, constant.MakeString("")))
+
+	f := NewInfoGenerator(pkg).GenFieldInfo(newTf(fName, status, ""))
+	assert.Equal(t, []EnumValue{
+		{Name: "New", Const: "StatusNew"},
+		{Name: "Active", Const: "StatusActive"},
+		{Name: "Deleted", Const: "deleted"},
+		{Name: "Activex", Const: "StatusActivex"},
+	}, f.EnumValues)
+
+	// imported types like time.Month aren't enums of structs
+	f = NewInfoGenerator(types.NewPackage("github.com/x/api", "api")).GenFieldInfo(newTf(fName, status, ""))
+	assert.Equal(t, "models.Status", f.TypeName)
+	assert.Nil(t, f.EnumValues)
+}
+
+func TestQualifiedInfoGenerator(t *testing.T) {
+	pkg := types.NewPackage("github.com/x/models", "models")
+	status := types.NewNamed(types.NewTypeName(token.Pos(0), pkg, "Status", nil), typeString, nil)
+	pkg.Scope().Insert(types.NewConst(token.Pos(1), pkg, "StatusNew", status, constant.MakeString("new")))
+	pkg.Scope().Insert(types.NewConst(token.Pos(2), pkg, "hidden", status, constant.MakeString("hidden")))
+	level := types.NewNamed(types.NewTypeName(token.Pos(0), pkg, "level", nil), types.Typ[types.Int], nil)
+
+	g := NewQualifiedInfoGenerator(pkg)
+	f := g.GenFieldInfo(newTf(fName, status, ""))
+	if assert.NotNil(t, f) {
+		assert.Equal(t, "models.Status", f.TypeName)
+		assert.Equal(t, []EnumValue{{Name: "New", Const: "models.StatusNew"}}, f.EnumValues)
+	}
+
+	// unexported types are inaccessible in package of generated code
+	assert.Nil(t, g.GenFieldInfo(newTf(fName, level, "")))
+	assert.Nil(t, g.GenFieldInfo(newTf(fName, types.NewSlice(types.NewPointer(level)), "")))
+	assert.NotNil(t, NewInfoGenerator(pkg).GenFieldInfo(newTf(fName, level, "")))
+}
+
+func TestDecimal(t *testing.T) {
+	pkg := types.NewPackage("github.com/x/models", "models")
+	decimalPkg := types.NewPackage("github.com/shopspring/decimal", "decimal")
+	decimal := types.NewNamed(types.NewTypeName(token.Pos(0), decimalPkg, "Decimal", nil),
+		types.NewStruct(nil, nil), nil)
+	nullDecimal := types.NewNamed(types.NewTypeName(token.Pos(0), decimalPkg, "NullDecimal", nil),
+		types.NewStruct([]*types.Var{
+			types.NewField(token.Pos(0), decimalPkg, "Decimal", decimal, false),
+			types.NewField(token.Pos(0), decimalPkg, "Valid", types.Typ[types.Bool], false),
+		}, nil), nil)
+	g := NewInfoGenerator(pkg)
+
+	f := g.GenFieldInfo(newTf(fName, decimal, ""))
+	assert.Equal(t, "decimal.Decimal", f.TypeName)
+	assert.True(t, f.IsDecimal)
+	assert.True(t, f.IsNumeric)
+	assert.False(t, f.IsStruct)
+
+	f = g.GenFieldInfo(newTf(fName, nullDecimal, ""))
+	assert.Equal(t, "Decimal", f.SQLNullValue)
+	assert.True(t, f.GetPointed().IsDecimal)
+
+	f = g.GenFieldInfo(newTf(fName, types.NewPointer(decimal), ""))
+	assert.Equal(t, "*decimal.Decimal", f.TypeName)
+	assert.True(t, f.GetPointed().IsDecimal)
+}
+
+func TestPqArrays(t *testing.T) {
+	pqPkg := types.NewPackage("github.com/lib/pq", "pq")
+	stringArray := types.NewNamed(types.NewTypeName(token.Pos(0), pqPkg, "StringArray", nil),
+		types.NewSlice(typeString), nil)
+	byteaArray := types.NewNamed(types.NewTypeName(token.Pos(0), pqPkg, "ByteaArray", nil),
+		types.NewSlice(types.NewSlice(types.Typ[types.Byte])), nil)
+
+	f := genFieldInfo(newTf(fName, stringArray, ""))
+	if assert.NotNil(t, f) {
+		assert.True(t, f.IsArray())
+		assert.Equal(t, "string", f.ArrayElem)
+		assert.Equal(t, "pq.StringArray", f.TypeName)
+	}
+
+	assert.Nil(t, genFieldInfo(newTf(fName, byteaArray, "")))
+	myArray := types.NewNamed(types.NewTypeName(token.Pos(0), nil, "StringArray", nil), types.NewSlice(typeString), nil)
+	assert.Nil(t, genFieldInfo(newTf(fName, myArray, "")))
+}
@@ -0,0 +1,69 @@
+package queryset
+
+import (
+	"fmt"
+
+	"github.com/jirfag/go-queryset/parser"
+	"github.com/jirfag/go-queryset/queryset/dialect"
+	"github.com/jirfag/go-queryset/queryset/field"
+)
+
+// geoPoint is a location of struct on map: its latitude and longitude
+// fields are marked by queryset:"lat" and queryset:"lng" tags
+type geoPoint struct {
+	Lat, Lng field.Info
+
+	Within        string // condition of bounding box
+	WithinWrapped string // condition of bounding box crossing antimeridian
+	Columns       string // format of selected columns and distance from bound point by quoted table %[1]s
+	Order         string // order by distance
+}
+
+// getGeoPoint returns location of struct or nil if it has no fields marked
+// by queryset:"lat" and queryset:"lng" tags
+func getGeoPoint(s parser.ParsedStruct, fields []field.Info, d dialect.Dialect) (*geoPoint, error) {
+	var lat, lng *field.Info
+	for i, f := range fields {
+		if !f.IsLat && !f.IsLng {
+			continue
+		}
+
+		v := f
+		if f.IsPointer || f.IsSQLNull() {
+			v = f.GetPointed()
+		}
+		if !v.IsNumeric || v.IsTime {
+			return nil, fmt.Errorf("only numbers can be coordinates, field %s of struct %s isn't number",
+				f.Name, s.TypeName)
+		}
+
+		p := &lat
+		if f.IsLng {
+			p = &lng
+		}
+		if f.IsLat && f.IsLng || *p != nil {
+			return nil, fmt.Errorf("struct %s has more than one latitude or longitude field", s.TypeName)
+		}
+		*p = &fields[i]
+	}
+
+	switch {
+	case lat == nil && lng == nil:
+		return nil, nil
+	case lat == nil || lng == nil:
+		return nil, fmt.Errorf("struct %s must have both latitude and longitude fields", s.TypeName)
+	case d.GeoDistance() == "":
+		return nil, fmt.Errorf("geo queries of struct %s aren't supported by %s dialect", s.TypeName, d.Name())
+	}
+
+	latColumn, lngColumn := d.Quote(lat.DBName), d.Quote(lng.DBName)
+	distance := fmt.Sprintf(d.GeoDistance(), latColumn, lngColumn)
+	return &geoPoint{
+		Lat:           *lat,
+		Lng:           *lng,
+		Within:        fmt.Sprintf("%s BETWEEN ? AND ? AND %s BETWEEN ? AND ?", latColumn, lngColumn),
+		WithinWrapped: fmt.Sprintf("%s BETWEEN ? AND ? AND (%s >= ? OR %s <= ?)", latColumn, lngColumn, lngColumn),
+		Columns:       fmt.Sprintf("%%[1]s.*, %s AS %s", distance, d.Quote("distance")),
+		Order:         d.Quote("distance"),
+	}, nil
+}
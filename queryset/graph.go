@@ -0,0 +1,206 @@
+package queryset
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/jirfag/go-queryset/parser"
+	"github.com/jirfag/go-queryset/queryset/field"
+	"golang.org/x/tools/go/loader"
+)
+
+// graphRelation is a relation of model: it's unresolved if Err isn't nil
+type graphRelation struct {
+	field.Relation
+	Column string // db name of foreign key column
+	Err    error  // reason why join of relation isn't generated
+}
+
+// graphModel is a struct with queryset as generation sees it
+type graphModel struct {
+	Name      string
+	Fields    []field.Info // columns, relation fields are excluded
+	PK        *field.Info
+	Relations []graphRelation
+}
+
+func (m graphModel) isForeignKey(f field.Info) bool {
+	for _, r := range m.Relations {
+		if r.Err == nil && !r.IsHasMany && r.Column == f.DBName {
+			return true
+		}
+	}
+	return false
+}
+
+type graphWriter func(w io.Writer, models []graphModel) error
+
+var graphWriters = map[string]graphWriter{
+	"dot": writeDOTGraph,
+	"d2":  writeD2Graph,
+}
+
+// GraphFormats returns names of supported formats of models graph
+func GraphFormats() []string {
+	var ret []string
+	for name := range graphWriters {
+		ret = append(ret, name)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+func getGraphModels(pkgInfo *loader.PackageInfo, structs parser.ParsedStructs) []graphModel {
+	g := field.NewInfoGenerator(pkgInfo.Pkg)
+	structsFields := map[string][]field.Info{}
+	for _, s := range structs {
+		if doesNeedToGenerateQuerySet(s.Doc, false) {
+			structsFields[s.TypeName] = genStructFieldInfos(s, g)
+		}
+	}
+
+	var ret []graphModel
+	for _, s := range structs {
+		fields, ok := structsFields[s.TypeName]
+		if !ok {
+			continue
+		}
+
+		m := graphModel{
+			Name: s.TypeName,
+			PK:   getPrimaryKeyField(fields),
+		}
+		for _, f := range fields {
+			if !f.IsStruct && !(f.IsPointer && f.GetPointed().IsStruct) {
+				m.Fields = append(m.Fields, f)
+			}
+		}
+		for _, r := range getRelations(s, pkgInfo) {
+			j, err := resolveJoin(s, r, structsFields)
+			gr := graphRelation{
+				Relation: j.Relation,
+				Column:   j.Column,
+				Err:      err,
+			}
+			if r.IsHasMany {
+				gr.Column = j.RelatedColumn
+			}
+			m.Relations = append(m.Relations, gr)
+		}
+		ret = append(ret, m)
+	}
+
+	sort.Sort(graphModelsSlice(ret))
+	return ret
+}
+
+type graphModelsSlice []graphModel
+
+func (s graphModelsSlice) Len() int           { return len(s) }
+func (s graphModelsSlice) Less(i, j int) bool { return s[i].Name < s[j].Name }
+func (s graphModelsSlice) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+// relationLabel returns label of relation edge
+func relationLabel(r graphRelation) string {
+	kind := "belongs to"
+	if r.IsHasMany {
+		kind = "has many"
+	}
+
+	if r.Err != nil {
+		return fmt.Sprintf("%s (%s): %s", r.Name, kind, r.Err)
+	}
+	return fmt.Sprintf("%s (%s, %s)", r.Name, kind, r.Column)
+}
+
+var dotRecordEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "{", `\{`, "}", `\}`,
+	"|", `\|`, "<", `\<`, ">", `\>`)
+
+func writeDOTGraph(w io.Writer, models []graphModel) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "digraph models {")
+	fmt.Fprintln(bw, "\tnode [shape=record];")
+	for _, m := range models {
+		var columns []string
+		for _, f := range m.Fields {
+			column := fmt.Sprintf("%s: %s", f.DBName, f.TypeName)
+			if m.PK != nil && m.PK.Name == f.Name {
+				column += " (PK)"
+			} else if m.isForeignKey(f) {
+				column += " (FK)"
+			}
+			columns = append(columns, dotRecordEscaper.Replace(column)+`\l`)
+		}
+		fmt.Fprintf(bw, "\t%q [label=\"{%s|%s}\"];\n", m.Name, m.Name, strings.Join(columns, ""))
+	}
+
+	for _, m := range models {
+		for _, r := range m.Relations {
+			attrs := fmt.Sprintf("label=%q", relationLabel(r))
+			if r.Err != nil {
+				attrs += ", style=dashed, color=red"
+			}
+			fmt.Fprintf(bw, "\t%q -> %q [%s];\n", m.Name, r.TypeName, attrs)
+		}
+	}
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
+
+func writeD2Graph(w io.Writer, models []graphModel) error {
+	bw := bufio.NewWriter(w)
+	for _, m := range models {
+		fmt.Fprintf(bw, "%q: {\n", m.Name)
+		fmt.Fprintln(bw, "  shape: sql_table")
+		for _, f := range m.Fields {
+			var constraint string
+			if m.PK != nil && m.PK.Name == f.Name {
+				constraint = " {constraint: primary_key}"
+			} else if m.isForeignKey(f) {
+				constraint = " {constraint: foreign_key}"
+			}
+			fmt.Fprintf(bw, "  %q: %q%s\n", f.DBName, f.TypeName, constraint)
+		}
+		fmt.Fprintln(bw, "}")
+	}
+
+	for _, m := range models {
+		for _, r := range m.Relations {
+			var style string
+			if r.Err != nil {
+				style = " {style.stroke-dash: 3; style.stroke: red}"
+			}
+			fmt.Fprintf(bw, "%q -> %q: %q%s\n", m.Name, r.TypeName, relationLabel(r), style)
+		}
+	}
+	return bw.Flush()
+}
+
+// WriteModelsGraph writes diagram of structs with querysets of file
+// inFilePath, their columns and relations in format (dot or d2).
+// Relations without generated joins are marked with the reason.
+func WriteModelsGraph(inFilePath string, w io.Writer, format string) error {
+	writeGraph, ok := graphWriters[format]
+	if !ok {
+		return fmt.Errorf("unknown graph format %q, supported formats: %s",
+			format, strings.Join(GraphFormats(), ", "))
+	}
+
+	pkgInfo, structs, err := parser.GetStructsInFile(inFilePath)
+	if err != nil {
+		return fmt.Errorf("can't parse file %s to get structs: %s", inFilePath, err)
+	}
+
+	models := getGraphModels(pkgInfo, structs)
+	if len(models) == 0 {
+		return fmt.Errorf("no structs with querysets in %s", inFilePath)
+	}
+
+	if err = writeGraph(w, models); err != nil {
+		return fmt.Errorf("can't write graph: %s", err)
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package queryset
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/jirfag/go-queryset/queryset/field"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteModelsGraph(t *testing.T) {
+	var b bytes.Buffer
+	assert.Nil(t, WriteModelsGraph("test/models.go", &b, "dot"))
+	assert.Contains(t, b.String(), `"Post" -> "Blog" [label="Blog (belongs to, blog_id)"];`)
+	assert.Contains(t, b.String(), `"User" -> "Post" [label="Posts (has many, user_id)"];`)
+	assert.Contains(t, b.String(), `blog_id: *uint (FK)\l`)
+
+	b.Reset()
+	assert.Nil(t, WriteModelsGraph("test/models.go", &b, "d2"))
+	assert.Contains(t, b.String(), `"id": "uint" {constraint: primary_key}`)
+	assert.Contains(t, b.String(), `"Post" -> "User": "User (belongs to, user_id)"`)
+
+	assert.Error(t, WriteModelsGraph("test/models.go", &b, "svg"))
+}
+
+func TestWriteDOTGraphMarksUnresolvedRelations(t *testing.T) {
+	models := []graphModel{
+		{
+			Name: "User",
+			Fields: []field.Info{
+				{BaseInfo: field.BaseInfo{Name: "Tags", DBName: "tags", TypeName: "map<string>"}},
+			},
+			Relations: []graphRelation{
+				{
+					Relation: field.Relation{Name: "Profile", TypeName: "Profile"},
+					Err:      errors.New("struct Profile has no queryset"),
+				},
+			},
+		},
+	}
+
+	var b bytes.Buffer
+	assert.Nil(t, writeDOTGraph(&b, models))
+	assert.Contains(t, b.String(), `"User" [label="{User|tags: map\<string\>\l}"];`)
+	assert.Contains(t, b.String(), `"User" -> "Profile" [label="Profile (belongs to): `+
+		`struct Profile has no queryset", style=dashed, color=red];`)
+}
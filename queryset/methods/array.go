@@ -0,0 +1,52 @@
+package methods
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// ArrayFilterMethod is a filter method of postgres array column. Arrays are
+// passed to GORM by pointers: it expands slices into lists of placeholders.
+type ArrayFilterMethod struct {
+	chainedQuerySetMethod
+	onFieldMethod
+	nArgsMethod
+	qsCallGormMethod
+}
+
+func newArrayFilterMethod(ctx QsFieldContext, cond, argsFmt string, args ...oneArgMethod) ArrayFilterMethod {
+	return ArrayFilterMethod{
+		onFieldMethod:         ctx.onFieldMethod(),
+		nArgsMethod:           newNArgsMethod(args...),
+		chainedQuerySetMethod: ctx.chainedQuerySetMethod(),
+		qsCallGormMethod: newQsCallGormMethod("Where", "%s, %s",
+			strconv.Quote(fmt.Sprintf(cond, ctx.quotedFieldDBName())), argsFmt),
+	}
+}
+
+// NewArrayContainsMethod creates <Field>Contains method of array column
+func NewArrayContainsMethod(ctx QsFieldContext) ArrayFilterMethod {
+	ctx = ctx.WithOperationName("Contains")
+	r := newArrayFilterMethod(ctx, ctx.Dialect().ArrayContains(), fmt.Sprintf("&%s{elem}", ctx.fieldTypeName()),
+		newOneArgMethod("elem", ctx.f.ArrayElem))
+	r.setDoc(fmt.Sprintf(`// %s filters by %s containing elem`, r.GetMethodName(), ctx.fieldName()))
+	return r
+}
+
+// NewArrayOverlapsMethod creates <Field>Overlaps method of array column
+func NewArrayOverlapsMethod(ctx QsFieldContext) ArrayFilterMethod {
+	ctx = ctx.WithOperationName("Overlaps")
+	r := newArrayFilterMethod(ctx, ctx.Dialect().ArrayOverlaps(), fmt.Sprintf("(*%s)(&elems)", ctx.fieldTypeName()),
+		newOneArgMethod("elems", "..."+ctx.f.ArrayElem))
+	r.setDoc(fmt.Sprintf(`// %s filters by %s having at least one of elems`, r.GetMethodName(), ctx.fieldName()))
+	return r
+}
+
+// NewArrayLengthEqMethod creates <Field>LengthEq method of array column
+func NewArrayLengthEqMethod(ctx QsFieldContext) ArrayFilterMethod {
+	ctx = ctx.WithOperationName("LengthEq")
+	r := newArrayFilterMethod(ctx, ctx.Dialect().ArrayLengthEq(), "n", newOneArgMethod("n", "int"))
+	r.setDoc(fmt.Sprintf(`// %s filters by number of elements of %s: empty and NULL
+	// arrays have no elements`, r.GetMethodName(), ctx.fieldName()))
+	return r
+}
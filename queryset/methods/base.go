@@ -32,6 +32,15 @@ func newReceiverMethod(decl string) *receiverMethod {
 	}
 }
 
+// funcMethod is a func without receiver
+
+type funcMethod struct{}
+
+// GetReceiverDeclaration returns empty receiver declaration
+func (m funcMethod) GetReceiverDeclaration() string {
+	return ""
+}
+
 // structMethod
 
 type structMethod struct {
@@ -0,0 +1,458 @@
+package methods
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jirfag/go-queryset/queryset/dialect"
+	"github.com/jirfag/go-queryset/queryset/field"
+)
+
+// CreateBatchMethod generates Create<Struct>Batch func
+type CreateBatchMethod struct {
+	funcMethod
+	namedMethod
+	nArgsMethod
+	errorRetMethod
+	constBodyMethod
+}
+
+func progressFuncTypeName(ctx QsStructContext) string {
+	return ctx.s.TypeName + "ProgressFunc"
+}
+
+func isAutoTimeField(f field.Info) bool {
+	return f.IsTime && !f.IsPointer && (f.Name == "CreatedAt" || f.Name == "UpdatedAt")
+}
+
+// NewCreateBatchMethod creates Create<Struct>Batch func. It inserts objects
+// by multi-row inserts of batchSize rows in one transaction.
+func NewCreateBatchMethod(ctx QsStructContext, fields []field.Info, pk *field.Info) CreateBatchMethod {
+	var columns, values, timeSets, prepare []string
+	for _, f := range fields {
+		if isRelationField(f) || (pk != nil && f.Name == pk.Name) {
+			continue
+		}
+
+		columns = append(columns, fmt.Sprintf("%q", f.DBName))
+		values = append(values, "o."+f.Name)
+		if isAutoTimeField(f) {
+			timeSets = append(timeSets,
+				fmt.Sprintf("if chunk[i].%s.IsZero() {", f.Name),
+				fmt.Sprintf("chunk[i].%s = now", f.Name),
+				"}")
+		}
+	}
+
+	// primary key column is inserted only if it's set: zero numeric
+	// primary keys are autoincremented by DB
+	var pkColumn, pkArg, check string
+	if pk != nil && pk.IsNumeric && !ctx.sequence {
+		// zero primary keys would be inserted as 0 into column of batch with
+		// set primary keys: such batches are rejected before any insert
+		check = fmt.Sprintf(`withPKs := 0
+			for i := range objs {
+				if objs[i].%[1]s != 0 {
+					withPKs++
+				}
+			}
+			if withPKs != 0 && withPKs != len(objs) {
+				return fmt.Errorf("can't create batch of %%d %[2]s: %[1]s is set only in %%d of them, "+
+					"create them by separate batches", len(objs), withPKs)
+			}
+
+			`, pk.Name, ctx.s.TypeName)
+	}
+	if pk != nil && ctx.sequence {
+		prepare = append(prepare,
+			"for i := range chunk {",
+			"if err := chunk[i].nextID(db); err != nil {",
+			"return err",
+			"}",
+			"}")
+	}
+	if pk != nil {
+		if pk.IsNumeric {
+			prepare = append(prepare,
+				"withPK := false",
+				"for i := range chunk {",
+				fmt.Sprintf("if chunk[i].%s != 0 {", pk.Name),
+				"withPK = true",
+				"}",
+				"}")
+		} else {
+			prepare = append(prepare, "withPK := true")
+		}
+		pkColumn = fmt.Sprintf(`if withPK {
+			columns = append([]string{%q}, columns...)
+		}
+		`, pk.DBName)
+		pkArg = fmt.Sprintf(`if withPK {
+				args = append(args, o.%s)
+			}
+			`, pk.Name)
+	}
+
+	if len(timeSets) != 0 {
+		prepare = append(prepare, "now := time.Now()", "for i := range chunk {")
+		prepare = append(prepare, timeSets...)
+		prepare = append(prepare, "}")
+	}
+
+	// rows of one statement must fit into limit of bind variables of DB
+	nColumns := len(columns)
+	if pk != nil {
+		nColumns++
+	}
+	maxRows := ctx.Dialect().MaxBindVars() / nColumns
+	if maxRows < 1 {
+		maxRows = 1
+	}
+
+	const tmpl = `%sif batchSize <= 0 || batchSize > %d {
+		batchSize = %d // rows fitting into limit of bind variables
+	}
+
+	started := time.Now()
+	total, processed := len(objs), 0
+	create := func(db *gorm.DB) error {
+		for len(objs) > 0 {
+			n := batchSize
+			if n > len(objs) {
+				n = len(objs)
+			}
+			chunk := objs[:n]
+			objs = objs[n:]
+
+		%s
+
+		columns := []string{%s}
+		%sscope := db.NewScope(&%s{})
+		for i := range columns {
+			columns[i] = scope.Quote(columns[i])
+		}
+		placeholders := "(" + strings.Repeat("?,", len(columns)-1) + "?)"
+
+		var rows []string
+		var args []interface{}
+		for _, o := range chunk {
+			%sargs = append(args, %s)
+			rows = append(rows, placeholders)
+		}
+
+		query := fmt.Sprintf("INSERT INTO %%s (%%s) VALUES %%s", scope.QuotedTableName(),
+			strings.Join(columns, ","), strings.Join(rows, ","))
+		err := call%sBreaker(db, func() error {
+			return execWithHook(db, query, args...).Error
+		})
+		if err != nil {
+			return fmt.Errorf("can't create batch of %%d %s: %%s", len(chunk), err)
+		}
+
+		processed += len(chunk)
+		report%sBatchProgress(progress, processed, total, started)
+	}
+	return nil
+	}
+
+	if len(objs) <= batchSize {
+		return create(db)
+	}
+	return WithTransaction(db, create)`
+
+	name := fmt.Sprintf("Create%sBatch", ctx.s.TypeName)
+	r := CreateBatchMethod{
+		namedMethod: newNamedMethod(name),
+		nArgsMethod: newNArgsMethod(
+			newOneArgMethod("db", "*gorm.DB"),
+			newOneArgMethod("objs", "[]"+ctx.s.TypeName),
+			newOneArgMethod("batchSize", "int"),
+			newOneArgMethod("progress", "..."+progressFuncTypeName(ctx)),
+		),
+		constBodyMethod: newConstBodyMethod(tmpl, check, maxRows, maxRows, strings.Join(prepare, "\n"),
+			strings.Join(columns, ", "), pkColumn, ctx.s.TypeName,
+			pkArg, strings.Join(values, ", "), ctx.s.TypeName, ctx.s.TypeName, ctx.s.TypeName),
+	}
+	r.setDoc(fmt.Sprintf(`// %s creates objs by multi-row inserts of batchSize rows.
+	// Batches are capped by limit of bind variables of DB, it's used if batchSize
+	// isn't positive. Multiple batches are inserted in one transaction.
+	// Relations aren't saved and autoincremented primary keys aren't set into objs.
+	// Primary keys must be set in all objs or in none of them.
+	// Progress funcs are called after every batch.`, name))
+	return r
+}
+
+// UpdateBatchMethod generates Update<Struct>Batch func
+type UpdateBatchMethod struct {
+	funcMethod
+	namedMethod
+	nArgsMethod
+	errorRetMethod
+	constBodyMethod
+}
+
+func updateBatchArgs(ctx QsStructContext) nArgsMethod {
+	return newNArgsMethod(
+		newOneArgMethod("db", "*gorm.DB"),
+		newOneArgMethod("objs", "[]"+ctx.s.TypeName),
+		newOneArgMethod("fields", "..."+ctx.dbSchemaFieldTypeName()),
+	)
+}
+
+// NewUpdateBatchMethod creates Update<Struct>Batch func calling its Num variant:
+// if strict is true, it returns ErrNoRowsAffected if no rows were updated
+func NewUpdateBatchMethod(ctx QsStructContext, strict bool) UpdateBatchMethod {
+	name := fmt.Sprintf("Update%sBatch", ctx.s.TypeName)
+	body := fmt.Sprintf(`_, err := %sNum(db, objs, fields...)
+	return err`, name)
+	doc := ""
+	if strict {
+		body = fmt.Sprintf(`n, err := %sNum(db, objs, fields...)
+	if err == nil && n == 0 && len(objs) != 0 {
+		return ErrNoRowsAffected
+	}
+	return err`, name)
+		doc = "\n// ErrNoRowsAffected is returned if no rows were updated."
+	}
+
+	r := UpdateBatchMethod{
+		namedMethod:     newNamedMethod(name),
+		nArgsMethod:     updateBatchArgs(ctx),
+		constBodyMethod: newConstBodyMethod("%s", body),
+	}
+	r.setDoc(fmt.Sprintf(`// %s updates fields of objs by primary key in one statement
+	// instead of updating them one by one. Large batches are split into statements
+	// fitting into limit of bind variables of DB, they are run in one transaction.
+	// Hooks of GORM aren't called.%s`, name, doc))
+	return r
+}
+
+// UpdateBatchNumMethod generates Update<Struct>BatchNum func
+type UpdateBatchNumMethod struct {
+	funcMethod
+	namedMethod
+	nArgsMethod
+	constRetMethod
+	constBodyMethod
+}
+
+// NewUpdateBatchNumMethod creates Update<Struct>BatchNum func. It updates fields of
+// objects by primary key pk in one statement per chunk of rows fitting into
+// limit of bind variables of dialect: from VALUES table if dialect supports
+// it or by CASE expressions otherwise. UpdatedAt is updated too. If validate
+// is true, check constraints of updated fields of all objects are checked first.
+func NewUpdateBatchNumMethod(ctx QsStructContext, fields []field.Info, pk field.Info,
+	validate bool) UpdateBatchNumMethod {
+	var validation string
+	if validate {
+		validation = `for i := range objs {
+		if err := objs[i].validate(fields...); err != nil {
+			return 0, err
+		}
+	}
+
+	`
+	}
+
+	var values []string
+	var touch string
+	for _, f := range fields {
+		if isRelationField(f) {
+			continue
+		}
+		values = append(values, fmt.Sprintf("%s.%s: o.%s,", ctx.dbSchemaTypeName(), f.Name, f.Name))
+		if isAutoTimeField(f) && f.Name == "UpdatedAt" {
+			touch = fmt.Sprintf(`touched := false
+	for _, f := range fields {
+		touched = touched || f == %[1]s.UpdatedAt
+	}
+	if !touched {
+		fields = append(fields[:len(fields):len(fields)], %[1]s.UpdatedAt)
+	}
+	now := time.Now()
+	for i := range objs {
+		objs[i].UpdatedAt = now
+	}
+	`, ctx.dbSchemaTypeName())
+		}
+	}
+
+	d := ctx.Dialect()
+	bindVarsPerRow := "len(fields) + 1" // primary key and fields
+	if d.UpdateFromValues() == "" {
+		bindVarsPerRow = "2*len(fields) + 1" // primary key and fields of CASE, primary key of IN
+	}
+
+	const tmpl = `if len(objs) == 0 {
+		return 0, nil
+	}
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("no fields to update in batch of %%d %[1]s", len(objs))
+	}
+
+	%[10]s%[2]srows := make([][]interface{}, 0, len(objs))
+	for i := range objs {
+		o := &objs[i]
+		values := map[%[3]s]interface{}{
+			%[4]s
+		}
+		row := []interface{}{o.%[5]s}
+		for _, f := range fields {
+			v, ok := values[f]
+			if !ok {
+				return 0, fmt.Errorf("can't update batch of %[1]s: unknown field %%s", f)
+			}
+			row = append(row, v)
+		}
+		rows = append(rows, row)
+	}
+
+	scope := db.NewScope(&%[1]s{})
+	pk := scope.Quote(%[6]q)
+	chunkSize := %[7]d / (%[8]s)
+	if chunkSize < 1 {
+		chunkSize = 1
+	}
+	var n int64
+	update := func(db *gorm.DB) error {
+		for start := 0; start < len(rows); start += chunkSize {
+			end := start + chunkSize
+			if end > len(rows) {
+				end = len(rows)
+			}
+			chunk := rows[start:end]
+			%[9]s
+
+			err := call%[1]sBreaker(db, func() error {
+				res := execWithHook(db, query, args...)
+				n += res.RowsAffected
+				return res.Error
+			})
+			if err != nil {
+				return fmt.Errorf("can't update batch of %%d %[1]s: %%s", len(chunk), err)
+			}
+		}
+		return nil
+	}
+
+	var err error
+	if len(rows) <= chunkSize {
+		err = update(db)
+	} else {
+		err = WithTransaction(db, update)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return n, nil`
+
+	name := fmt.Sprintf("Update%sBatchNum", ctx.s.TypeName)
+	r := UpdateBatchNumMethod{
+		namedMethod:    newNamedMethod(name),
+		nArgsMethod:    updateBatchArgs(ctx),
+		constRetMethod: newConstRetMethod("(int64, error)"),
+		constBodyMethod: newConstBodyMethod(tmpl, ctx.s.TypeName, touch, ctx.dbSchemaFieldTypeName(),
+			strings.Join(values, "\n"), pk.Name, pk.DBName, d.MaxBindVars(), bindVarsPerRow,
+			updateBatchStatement(d), validation),
+	}
+	r.setDoc(fmt.Sprintf(`// %s is Update%sBatch returning number of updated rows`, name, ctx.s.TypeName))
+	return r
+}
+
+// updateBatchStatement returns code building UPDATE of rows by dialect d:
+// from VALUES table or by CASE expressions
+func updateBatchStatement(d dialect.Dialect) string {
+	if d.UpdateFromValues() == "" {
+		return `var updates []string
+	var args []interface{}
+	for i, f := range fields {
+		cases := strings.Repeat(" WHEN ? THEN ?", len(chunk))
+		updates = append(updates, fmt.Sprintf("%s = CASE %s%s END", scope.Quote(string(f)), pk, cases))
+		for _, row := range chunk {
+			args = append(args, row[0], row[i+1])
+		}
+	}
+	for _, row := range chunk {
+		args = append(args, row[0])
+	}
+	query := fmt.Sprintf("UPDATE %s SET %s WHERE %s IN (%s)", scope.QuotedTableName(),
+		strings.Join(updates, ","), pk, strings.Repeat("?,", len(chunk)-1)+"?")`
+	}
+
+	return fmt.Sprintf(`columns := []string{pk}
+	var updates []string
+	for _, f := range fields {
+		qc := scope.Quote(string(f))
+		columns = append(columns, qc)
+		updates = append(updates, fmt.Sprintf(%q, qc))
+	}
+	placeholders := "(" + strings.Repeat("?,", len(columns)-1) + "?)"
+	var values []string
+	var args []interface{}
+	for _, row := range chunk {
+		values = append(values, placeholders)
+		args = append(args, row...)
+	}
+	query := fmt.Sprintf(%q, scope.QuotedTableName(), strings.Join(columns, ","),
+		strings.Join(values, ","), strings.Join(updates, ","), pk)`,
+		"%[1]s = "+d.Quote("source")+".%[1]s", d.UpdateFromValues())
+}
+
+// AllInBatchesMethod generates AllInBatches method
+type AllInBatchesMethod struct {
+	baseQuerySetMethod
+	namedMethod
+	nArgsMethod
+	errorRetMethod
+	constBodyMethod
+}
+
+// NewAllInBatchesMethod creates AllInBatches method: it pages through records
+// of queryset by primary key pk instead of OFFSET
+func NewAllInBatchesMethod(ctx QsStructContext, pk field.Info) AllInBatchesMethod {
+	const tmpl = `if batchSize < 1 {
+		return fmt.Errorf("invalid batch size %%d", batchSize)
+	}
+	if err := %s.checkMatchedNum(); err != nil {
+		return err
+	}
+
+	var lastPK %s
+	for {
+		var batch []%s
+		err := %s.Where(%s, lastPK).Order(%s, true).Limit(batchSize).Find(&batch).Error
+		if err != nil {
+			return err
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+
+		if err = fn(batch); err != nil {
+			return err
+		}
+
+		if len(batch) < batchSize {
+			return nil
+		}
+		lastPK = batch[len(batch)-1].%s
+	}`
+
+	quotedPK := ctx.Dialect().Quote(pk.DBName)
+	r := AllInBatchesMethod{
+		baseQuerySetMethod: newBaseQuerySetMethod(ctx.qsTypeName()),
+		namedMethod:        newNamedMethod("AllInBatches"),
+		nArgsMethod: newNArgsMethod(
+			newOneArgMethod("batchSize", "int"),
+			newOneArgMethod("fn", fmt.Sprintf("func(batch []%s) error", ctx.s.TypeName)),
+		),
+		constBodyMethod: newConstBodyMethod(tmpl, qsReceiverName, pk.TypeName, ctx.s.TypeName, qsDbName,
+			strconv.Quote(quotedPK+" > ?"), strconv.Quote(quotedPK+" ASC"), pk.Name),
+	}
+	r.setDoc(`// AllInBatches pages through records of queryset by primary key and passes
+	// batches of batchSize records to fn: unlike OFFSET pagination every page is
+	// fetched by index. Order and limit of queryset are ignored, limit of
+	// FailIfMoreThan is checked before the first batch. batchSize must be positive.`)
+	return r
+}
@@ -0,0 +1,191 @@
+package methods
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/jirfag/go-queryset/queryset/field"
+)
+
+var (
+	checkAndRe  = regexp.MustCompile(`(?i)\s+AND\s+`)
+	checkCondRe = regexp.MustCompile(`^(?i:(char_length|length)\(\s*(\w+)\s*\)|(\w+))\s*(>=|<=|<>|!=|=|>|<)\s*(.+)$`)
+)
+
+var checkOpToGo = map[string]string{
+	"=":  "==",
+	"<>": "!=",
+}
+
+// CheckCond is a condition of check constraint converted into Go
+type CheckCond struct {
+	SQL string // condition in SQL
+	Go  string // equivalent Go expression, it's true if condition holds
+}
+
+// ParseCheck parses check constraint of field f declared by check tag setting:
+// conditions on field's column or on its length joined by AND, e.g.
+// "rating >= 0 AND rating <= 5" or "char_length(name) > 0"
+func ParseCheck(f field.Info) ([]CheckCond, error) {
+	if f.Check == "" {
+		return nil, nil
+	}
+
+	v := newFakeFieldValue(f)
+	var ret []CheckCond
+	for _, cond := range splitCheck(strings.TrimSpace(f.Check)) {
+		m := checkCondRe.FindStringSubmatch(cond)
+		if m == nil {
+			return nil, fmt.Errorf("can't parse check condition %q of field %s", cond, f.Name)
+		}
+
+		isLen, column := m[1] != "", m[2]+m[3]
+		if column != f.DBName {
+			return nil, fmt.Errorf("check condition %q of field %s must be on column %s",
+				cond, f.Name, f.DBName)
+		}
+
+		lhs, value, err := checkOperands(v, isLen, strings.TrimSpace(m[5]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid check condition %q of field %s: %s", cond, f.Name, err)
+		}
+
+		op := m[4]
+		if goOp := checkOpToGo[op]; goOp != "" {
+			op = goOp
+		}
+		goCond := fmt.Sprintf("%s %s %s", lhs, op, value)
+		if v.null != "" {
+			goCond = fmt.Sprintf("%s || %s", v.null, goCond) // NULL passes checks
+		}
+		ret = append(ret, CheckCond{
+			SQL: cond,
+			Go:  goCond,
+		})
+	}
+
+	return ret, nil
+}
+
+// splitCheck splits check constraint into conditions joined by AND: AND in
+// string literals doesn't split it
+func splitCheck(check string) []string {
+	masked := []byte(check) // string literals are masked not to be matched
+	quoted := false
+	for i := range masked {
+		if masked[i] == '\'' {
+			quoted = !quoted // escaped quote '' toggles it twice
+		} else if quoted {
+			masked[i] = '_'
+		}
+	}
+
+	var ret []string
+	start := 0
+	for _, loc := range checkAndRe.FindAllIndex(masked, -1) {
+		ret = append(ret, check[start:loc[0]])
+		start = loc[1]
+	}
+	return append(ret, check[start:])
+}
+
+// checkOperands returns Go expressions of field value (or its length) and
+// of literal to compare with
+func checkOperands(v fakeFieldValue, isLen bool, literal string) (string, string, error) {
+	if isLen {
+		if !v.f.IsString {
+			return "", "", fmt.Errorf("length is checked only for strings")
+		}
+		if _, err := strconv.Atoi(literal); err != nil {
+			return "", "", fmt.Errorf("length must be compared with integer")
+		}
+		return fmt.Sprintf("utf8.RuneCountInString(%s)", v.stringExpr()), literal, nil
+	}
+
+	if v.f.IsTime || v.f.IsStruct || v.f.IsArray() {
+		return "", "", fmt.Errorf("only numbers and strings can be checked")
+	}
+
+	if v.f.IsNumeric {
+		if _, err := strconv.ParseFloat(literal, 64); err != nil {
+			return "", "", fmt.Errorf("number must be compared with number")
+		}
+		if v.f.IsDecimal {
+			return fmt.Sprintf("%s.Cmp(decimal.RequireFromString(%q))", v.expr, literal), "0", nil
+		}
+		return v.expr, literal, nil
+	}
+
+	if len(literal) < 2 || literal[0] != '\'' || literal[len(literal)-1] != '\'' {
+		return "", "", fmt.Errorf("string must be compared with string literal in single quotes")
+	}
+	s := strings.Replace(literal[1:len(literal)-1], "''", "'", -1)
+	return v.expr, strconv.Quote(s), nil
+}
+
+// ValidateMethod generates validate method
+type ValidateMethod struct {
+	namedMethod
+	structMethod
+	oneArgMethod
+	errorRetMethod
+	constBodyMethod
+}
+
+// NewValidateMethod creates validate method: it checks constraints of fields
+// (all fields if no fields were passed) and returns <Struct>CheckError
+// on the first violation
+func NewValidateMethod(ctx QsStructContext, fields []field.Info) ValidateMethod {
+	fieldTypeName := ctx.dbSchemaFieldTypeName()
+	lines := []string{
+		fmt.Sprintf("checked := map[%s]bool{}", fieldTypeName),
+		"for _, f := range fields {",
+		"checked[f] = true",
+		"}",
+		"",
+	}
+	for _, f := range fields {
+		conds, _ := ParseCheck(f) // checks were validated before generation
+		schemaField := fmt.Sprintf("%s.%s", ctx.dbSchemaTypeName(), f.Name)
+		for _, c := range conds {
+			lines = append(lines,
+				fmt.Sprintf("if (len(checked) == 0 || checked[%s]) && !(%s) {", schemaField, c.Go),
+				fmt.Sprintf("return %sCheckError{Field: %s, Check: %q}", ctx.s.TypeName, schemaField, c.SQL),
+				"}")
+		}
+	}
+	lines = append(lines, "", "return nil")
+
+	r := ValidateMethod{
+		namedMethod:     newNamedMethod("validate"),
+		structMethod:    newStructMethod("o", "*"+ctx.s.TypeName),
+		oneArgMethod:    newOneArgMethod("fields", "..."+fieldTypeName),
+		constBodyMethod: newConstBodyMethod("%s", strings.Join(lines, "\n")),
+	}
+	r.setDoc(`// validate checks constraints of fields: all fields are checked
+	// if no fields were passed`)
+	return r
+}
+
+// ValidatedMethod is a method, which validates object before running
+type ValidatedMethod struct {
+	Method
+}
+
+// GetBody returns method's body with validation
+func (m ValidatedMethod) GetBody() string {
+	return `if err := o.validate(); err != nil {
+		return err
+	}
+
+	` + m.Method.GetBody()
+}
+
+// NewValidatedMethod wraps object's method m to validate object before running
+func NewValidatedMethod(m Method) ValidatedMethod {
+	return ValidatedMethod{
+		Method: m,
+	}
+}
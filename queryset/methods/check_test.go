@@ -0,0 +1,51 @@
+package methods
+
+import (
+	"testing"
+
+	"github.com/jirfag/go-queryset/queryset/field"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCheck(t *testing.T) {
+	t.Parallel()
+	rating := field.Info{BaseInfo: field.BaseInfo{Name: "Rating", DBName: "rating", TypeName: "int", IsNumeric: true}}
+	name := field.Info{BaseInfo: field.BaseInfo{Name: "Name", DBName: "name", TypeName: "string", IsString: true}}
+	status := field.Info{BaseInfo: field.BaseInfo{Name: "Status", DBName: "status", TypeName: "Status", IsString: true}}
+	price := field.Info{BaseInfo: field.BaseInfo{Name: "Price", DBName: "price", TypeName: "decimal.Decimal", IsNumeric: true, IsDecimal: true}}
+	cases := []struct {
+		f     field.Info
+		check string
+		goes  []string
+		ok    bool
+	}{
+		{rating, "rating >= 0 AND rating <= 5", []string{"o.Rating >= 0", "o.Rating <= 5"}, true},
+		{rating, "rating <> 3", []string{"o.Rating != 3"}, true},
+		{name, "char_length(name) > 0 and name != 'it''s'", []string{"utf8.RuneCountInString(o.Name) > 0", `o.Name != "it's"`}, true},
+		{name, "name = 'a'", []string{`o.Name == "a"`}, true},
+		{name, "name <> 'A AND B' AND name <> 'it''s and'", []string{`o.Name != "A AND B"`, `o.Name != "it's and"`}, true},
+		{status, "length(status) < 8 AND status <> 'new'", []string{"utf8.RuneCountInString(string(o.Status)) < 8", `o.Status != "new"`}, true},
+		{price, "price > 0.5", []string{`o.Price.Cmp(decimal.RequireFromString("0.5")) > 0`}, true},
+		{rating, "other > 0", nil, false},
+		{rating, "rating > 'a'", nil, false},
+		{name, "name > 1", nil, false},
+		{rating, "length(rating) > 1", nil, false},
+		{rating, "rating IN (1, 2)", nil, false},
+	}
+
+	for _, c := range cases {
+		c.f.Check = c.check
+		conds, err := ParseCheck(c.f)
+		if !c.ok {
+			assert.NotNil(t, err, c.check)
+			continue
+		}
+
+		assert.Nil(t, err, c.check)
+		var goes []string
+		for _, cond := range conds {
+			goes = append(goes, cond.Go)
+		}
+		assert.Equal(t, c.goes, goes, c.check)
+	}
+}
@@ -0,0 +1,46 @@
+package methods
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TranslatedErrorsMethod is a method, which returns typed errors of struct
+// instead of errors of GORM and driver: they are translated by
+// Translate<Struct>Error
+type TranslatedErrorsMethod struct {
+	Method
+	structName string
+}
+
+// GetBody returns method's body with translation of error
+func (m TranslatedErrorsMethod) GetBody() string {
+	translate := "Translate" + m.structName + "Error"
+	ret := m.Method.GetReturnValuesDeclaration()
+	if ret == "error" {
+		return fmt.Sprintf(`err := func() error {
+			%s
+		}()
+		return %s(err)`, m.Method.GetBody(), translate)
+	}
+
+	return fmt.Sprintf(`v, err := func() %s {
+		%s
+	}()
+	return v, %s(err)`, ret, m.Method.GetBody(), translate)
+}
+
+// GetDoc returns doc of method m with typed error of not found struct
+func (m TranslatedErrorsMethod) GetDoc(methodName string) string {
+	return strings.Replace(m.Method.GetDoc(methodName), "gorm.ErrRecordNotFound",
+		"Err"+m.structName+"NotFound", -1)
+}
+
+// NewTranslatedErrorsMethod wraps method m of struct structName returning
+// error or value and error to return typed errors of struct
+func NewTranslatedErrorsMethod(m Method, structName string) TranslatedErrorsMethod {
+	return TranslatedErrorsMethod{
+		Method:     m,
+		structName: structName,
+	}
+}
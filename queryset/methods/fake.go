@@ -0,0 +1,369 @@
+package methods
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jirfag/go-queryset/queryset/field"
+)
+
+// FakeQsStructContext is a context of in-memory fake queryset of struct
+type FakeQsStructContext struct {
+	QsStructContext
+}
+
+// NewFakeQsStructContext creates context of fake queryset
+func NewFakeQsStructContext(ctx QsStructContext) FakeQsStructContext {
+	return FakeQsStructContext{
+		QsStructContext: ctx,
+	}
+}
+
+func (ctx FakeQsStructContext) fakeQsTypeName() string {
+	return "Fake" + ctx.qsTypeName()
+}
+
+// FakeMethod is a method of fake queryset
+type FakeMethod struct {
+	namedMethod
+	baseQuerySetMethod
+	nArgsMethod
+	constRetMethod
+	constBodyMethod
+}
+
+func newFakeChainedMethod(ctx FakeQsStructContext, name, body string, args ...oneArgMethod) FakeMethod {
+	r := FakeMethod{
+		namedMethod:        newNamedMethod(name),
+		baseQuerySetMethod: newBaseQuerySetMethod(ctx.fakeQsTypeName()),
+		nArgsMethod:        newNArgsMethod(args...),
+		constRetMethod:     newConstRetMethod(ctx.fakeQsTypeName()),
+		constBodyMethod:    newConstBodyMethod("%s", body),
+	}
+	r.setDoc(fmt.Sprintf("// %s is a fake of %s.%s", name, ctx.qsTypeName(), name))
+	return r
+}
+
+// fakeFieldValue describes how to get value of field from object o
+type fakeFieldValue struct {
+	f       field.Info // info of value, pointed info for pointers
+	expr    string     // expression of value
+	guard   string     // condition of value presence: it's not nil pointer
+	null    string     // condition of value absence (NULL), it's empty for not nullable fields
+	notNull string     // negation of null condition
+}
+
+func newFakeFieldValue(f field.Info) fakeFieldValue {
+	if f.IsPointer {
+		return fakeFieldValue{
+			f:       f.GetPointed(),
+			expr:    "(*o." + f.Name + ")",
+			guard:   fmt.Sprintf("o.%s != nil && ", f.Name),
+			null:    fmt.Sprintf("o.%s == nil", f.Name),
+			notNull: fmt.Sprintf("o.%s != nil", f.Name),
+		}
+	}
+
+	if f.IsSQLNull() {
+		return fakeFieldValue{
+			f:       f.GetPointed(),
+			expr:    fmt.Sprintf("o.%s.%s", f.Name, f.SQLNullValue),
+			guard:   fmt.Sprintf("o.%s.Valid && ", f.Name),
+			null:    fmt.Sprintf("!o.%s.Valid", f.Name),
+			notNull: fmt.Sprintf("o.%s.Valid", f.Name),
+		}
+	}
+
+	return fakeFieldValue{
+		f:    f,
+		expr: "o." + f.Name,
+	}
+}
+
+func (v fakeFieldValue) compare(op, arg string) string {
+	if v.f.IsDecimal {
+		return fmt.Sprintf("%s.Cmp(%s) %s 0", v.expr, arg, op)
+	}
+	if !v.f.IsTime {
+		return fmt.Sprintf("%s %s %s", v.expr, op, arg)
+	}
+
+	switch op {
+	case "==":
+		return fmt.Sprintf("%s.Equal(%s)", v.expr, arg)
+	case "!=":
+		return fmt.Sprintf("!%s.Equal(%s)", v.expr, arg)
+	case "<":
+		return fmt.Sprintf("%s.Before(%s)", v.expr, arg)
+	case ">":
+		return fmt.Sprintf("%s.After(%s)", v.expr, arg)
+	case "<=":
+		return fmt.Sprintf("!%s.After(%s)", v.expr, arg)
+	case ">=":
+		return fmt.Sprintf("!%s.Before(%s)", v.expr, arg)
+	}
+
+	panic(fmt.Sprintf("unknown operation %q", op))
+}
+
+// stringExpr returns expression of value converted to string: it's needed
+// for named string types
+func (v fakeFieldValue) stringExpr() string {
+	if v.f.TypeName == "string" {
+		return v.expr
+	}
+	return fmt.Sprintf("string(%s)", v.expr)
+}
+
+func (ctx FakeQsStructContext) newFilter(v fakeFieldValue, name, cond string, args ...oneArgMethod) FakeMethod {
+	body := fmt.Sprintf(`return qs.filter(func(o *%s) bool {
+		return %s%s
+	})`, ctx.s.TypeName, v.guard, cond)
+	return newFakeChainedMethod(ctx, name, body, args...)
+}
+
+func (ctx FakeQsStructContext) newBinaryFilter(v fakeFieldValue, operationName, op string) FakeMethod {
+	argName := fieldNameToArgName(v.f.Name)
+	return ctx.newFilter(v, ctx.n.FilterName(v.f.Name, operationName), v.compare(op, argName),
+		newOneArgMethod(argName, v.f.TypeName))
+}
+
+func (ctx FakeQsStructContext) newInFilter(v fakeFieldValue, operationName string, in bool) FakeMethod {
+	argName := fieldNameToArgName(v.f.Name)
+	cond := fmt.Sprintf(`func() bool {
+		for _, arg := range append([]%s{%s}, %sRest...) {
+			if %s {
+				return %t
+			}
+		}
+		return %t
+	}()`, v.f.TypeName, argName, argName, v.compare("==", "arg"), in, !in)
+	return ctx.newFilter(v, ctx.n.FilterName(v.f.Name, operationName), cond,
+		newOneArgMethod(argName, v.f.TypeName),
+		newOneArgMethod(argName+"Rest", "..."+v.f.TypeName))
+}
+
+// newMatchesFilter creates fake of Matches filter: pattern is compiled once and
+// invalid pattern panics, fake can't return error of database from filter
+func (ctx FakeQsStructContext) newMatchesFilter(v fakeFieldValue) FakeMethod {
+	name := ctx.n.FilterName(v.f.Name, "Matches")
+	body := fmt.Sprintf(`re := regexp.MustCompile(pattern)
+	return qs.filter(func(o *%s) bool {
+		return %sre.MatchString(%s)
+	})`, ctx.s.TypeName, v.guard, v.stringExpr())
+	r := newFakeChainedMethod(ctx, name, body, newOneArgMethod("pattern", "string"))
+	r.setDoc(fmt.Sprintf(`// %[1]s is a fake of %[2]s.%[1]s: pattern is matched in RE2 syntax of Go,
+	// which differs from regular expressions of database (POSIX of PostgreSQL,
+	// ICU of MySQL 8, Oracle's), e.g. in character classes, flags and
+	// backreferences, so patterns should be tested against database too.
+	// Invalid pattern panics instead of returning error of database.`, name, ctx.qsTypeName()))
+	return r
+}
+
+func (ctx FakeQsStructContext) newLikeFilter(v fakeFieldValue, operationName string, fold bool) FakeMethod {
+	cond := fmt.Sprintf("fake%sLike(%s, pattern, %t)", ctx.s.TypeName, v.stringExpr(), fold)
+	return ctx.newFilter(v, ctx.n.FilterName(v.f.Name, operationName), cond, newOneArgMethod("pattern", "string"))
+}
+
+// NewFakeEqIfSetMethods creates fake of <Field>EqIfSet method of field f,
+// nothing is created if f isn't string or numeric
+func NewFakeEqIfSetMethods(ctx FakeQsStructContext, f field.Info) []Method {
+	v := newFakeFieldValue(f)
+	if f.IsJSON || f.IsArray() || !(v.f.IsString || v.f.IsNumeric) {
+		return nil
+	}
+
+	argName := fieldNameToArgName(f.Name)
+	return []Method{newFakeChainedMethod(ctx, ctx.n.FilterName(f.Name, "EqIfSet"),
+		eqIfSetBody(ctx.n, v.f, argName), newOneArgMethod(argName, v.f.TypeName))}
+}
+
+// newArrayFilters creates filters of array field f
+func (ctx FakeQsStructContext) newArrayFilters(f field.Info) []Method {
+	v := newFakeFieldValue(f)
+	has := func(elems string) string {
+		return fmt.Sprintf(`func() bool {
+			for _, elem := range %s {
+				for _, e := range o.%s {
+					if e == elem {
+						return true
+					}
+				}
+			}
+			return false
+		}()`, elems, f.Name)
+	}
+
+	return []Method{
+		ctx.newFilter(v, ctx.n.FilterName(f.Name, "Contains"), has("[]"+f.ArrayElem+"{elem}"),
+			newOneArgMethod("elem", f.ArrayElem)),
+		ctx.newFilter(v, ctx.n.FilterName(f.Name, "Overlaps"), has("elems"), newOneArgMethod("elems", "..."+f.ArrayElem)),
+		ctx.newFilter(v, ctx.n.FilterName(f.Name, "LengthEq"), fmt.Sprintf("len(o.%s) == n", f.Name),
+			newOneArgMethod("n", "int")),
+	}
+}
+
+// newOrder creates Order(Asc|Desc)By method: NULL values go first in
+// ascending order
+func (ctx FakeQsStructContext) newOrder(v fakeFieldValue, operationName string, desc bool) FakeMethod {
+	var nullsCmp string
+	if v.null != "" {
+		nullsCmp = fmt.Sprintf(`if %[1]s || %[2]s {
+			if %[1]s && %[2]s {
+				return 0
+			}
+			if %[1]s {
+				return -1
+			}
+			return 1
+		}
+		`, replaceReceiver(v.null, "a"), replaceReceiver(v.null, "b"))
+	}
+
+	a := fakeFieldValue{f: v.f, expr: replaceReceiver(v.expr, "a")}
+	b := replaceReceiver(v.expr, "b")
+	cmp := fmt.Sprintf(`%sif %s {
+			return -1
+		}
+		if %s {
+			return 1
+		}
+		return 0`, nullsCmp, a.compare("<", b), a.compare(">", b))
+
+	ret := "return qs.order(cmp)"
+	if desc {
+		ret = fmt.Sprintf(`return qs.order(func(a, b *%s) int {
+			return -cmp(a, b)
+		})`, ctx.s.TypeName)
+	}
+	body := fmt.Sprintf(`cmp := func(a, b *%s) int {
+		%s
+	}
+	%s`, ctx.s.TypeName, cmp, ret)
+	return newFakeChainedMethod(ctx, operationName+v.f.Name, body)
+}
+
+// newFakePluck creates Pluck<Field> method collecting field of matching rows
+func newFakePluck(ctx FakeQsStructContext, f field.Info) FakeMethod {
+	name := "Pluck" + f.Name
+	r := FakeMethod{
+		namedMethod:        newNamedMethod(name),
+		baseQuerySetMethod: newBaseQuerySetMethod(ctx.fakeQsTypeName()),
+		constRetMethod:     newConstRetMethod(fmt.Sprintf("([]%s, error)", f.TypeName)),
+		constBodyMethod: newConstBodyMethod(`indexes := qs.indexes()
+			if err := qs.checkRowsNum(len(indexes), true); err != nil {
+				return nil, err
+			}
+
+			var ret []%s
+			for _, i := range indexes {
+				ret = append(ret, (*qs.rows)[i].%s)
+			}
+			return ret, nil`, f.TypeName, f.Name),
+	}
+	r.setDoc(fmt.Sprintf("// %s is a fake of %s.%s", name, ctx.qsTypeName(), name))
+	return r
+}
+
+// replaceReceiver replaces object o in expression expr by receiver
+func replaceReceiver(expr, receiver string) string {
+	return strings.Replace(expr, "o.", receiver+".", 1)
+}
+
+// NewFakeFieldMethods creates methods of fake queryset for field f: they
+// mirror methods of real queryset
+func NewFakeFieldMethods(ctx FakeQsStructContext, f field.Info) []Method {
+	if f.IsStruct || (f.IsPointer && f.GetPointed().IsStruct) {
+		// relations aren't loaded by fake
+		return []Method{newFakeChainedMethod(ctx, "Preload"+f.Name, "return qs")}
+	}
+
+	pluck := newFakePluck(ctx, f)
+	if f.IsJSON {
+		return []Method{pluck} // JSON filters aren't faked
+	}
+	if f.IsArray() {
+		if ctx.Dialect().ArrayContains() == "" {
+			return []Method{pluck}
+		}
+		return append([]Method{pluck}, ctx.newArrayFilters(f)...)
+	}
+
+	v := newFakeFieldValue(f)
+	ret := []Method{
+		pluck,
+		ctx.newBinaryFilter(v, "Eq", "=="),
+		ctx.newBinaryFilter(v, "Ne", "!="),
+	}
+	if !v.f.IsTime {
+		ret = append(ret,
+			ctx.newInFilter(v, "In", true),
+			ctx.newInFilter(v, "NotIn", false))
+	}
+
+	for _, e := range v.f.EnumValues {
+		ret = append(ret, ctx.newFilter(v, ctx.n.FilterName(f.Name, "Eq"+e.Name), v.compare("==", e.Const)))
+	}
+
+	if v.f.IsNumeric {
+		ret = append(ret,
+			ctx.newBinaryFilter(v, "Lt", "<"),
+			ctx.newBinaryFilter(v, "Gt", ">"),
+			ctx.newBinaryFilter(v, "Lte", "<="),
+			ctx.newBinaryFilter(v, "Gte", ">="),
+			ctx.newOrder(v, "OrderAscBy", false),
+			ctx.newOrder(v, "OrderDescBy", true))
+	}
+	if v.f.IsDecimal {
+		ret = append(ret, ctx.newFilter(v, ctx.n.FilterName(f.Name, "Between"),
+			fmt.Sprintf("%s && %s", v.compare(">=", "from"), v.compare("<=", "to")),
+			newOneArgMethod("from", v.f.TypeName), newOneArgMethod("to", v.f.TypeName)))
+	}
+	if v.f.IsTime {
+		ret = append(ret,
+			ctx.newBinaryFilter(v, "Before", "<"),
+			ctx.newBinaryFilter(v, "After", ">"),
+			ctx.newFilter(v, ctx.n.FilterName(f.Name, "Within"), v.compare(">=", "time.Now().Add(-d)"),
+				newOneArgMethod("d", "time.Duration")))
+	}
+	if v.f.IsBool {
+		ret = append(ret,
+			ctx.newFilter(v, ctx.n.FilterName(f.Name, "IsTrue"), v.expr),
+			ctx.newFilter(v, ctx.n.FilterName(f.Name, "IsFalse"), "!"+v.expr))
+	}
+	if v.f.IsString {
+		ret = append(ret,
+			ctx.newLikeFilter(v, "Like", false),
+			ctx.newLikeFilter(v, "ILike", true),
+			ctx.newFilter(v, ctx.n.FilterName(f.Name, "EqFold"),
+				fmt.Sprintf("strings.EqualFold(%s, string(%s))", v.stringExpr(), fieldNameToArgName(f.Name)),
+				newOneArgMethod(fieldNameToArgName(f.Name), v.f.TypeName)),
+			ctx.newFilter(v, ctx.n.FilterName(f.Name, "StartsWith"),
+				fmt.Sprintf("strings.HasPrefix(%s, prefix)", v.stringExpr()), newOneArgMethod("prefix", "string")),
+			ctx.newFilter(v, ctx.n.FilterName(f.Name, "EndsWith"),
+				fmt.Sprintf("strings.HasSuffix(%s, suffix)", v.stringExpr()), newOneArgMethod("suffix", "string")),
+			ctx.newFilter(v, ctx.n.FilterName(f.Name, "Contains"),
+				fmt.Sprintf("strings.Contains(%s, substr)", v.stringExpr()), newOneArgMethod("substr", "string")))
+		if ctx.Dialect().RegexpMatch() != "" {
+			ret = append(ret, ctx.newMatchesFilter(v))
+		}
+	}
+
+	if v.null != "" {
+		isNull := fakeFieldValue{f: v.f}
+		ret = append(ret,
+			ctx.newFilter(isNull, ctx.n.FilterName(f.Name, "IsNull"), v.null),
+			ctx.newFilter(isNull, ctx.n.FilterName(f.Name, "IsNotNull"), v.notNull))
+	}
+	if f.IsPointer {
+		argName := fieldNameToArgName(f.Name)
+		ret = append(ret, newFakeChainedMethod(ctx, ctx.n.FilterName(f.Name, "EqNullable"),
+			fmt.Sprintf(`if %[1]s == nil {
+				return qs.%[2]s()
+			}
+			return qs.%[3]s(*%[1]s)`, argName, ctx.n.FilterName(f.Name, "IsNull"), ctx.n.FilterName(f.Name, "Eq")),
+			newOneArgMethod(argName, f.TypeName)))
+	}
+
+	return ret
+}
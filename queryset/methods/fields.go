@@ -1,6 +1,10 @@
 package methods
 
-import "strings"
+import (
+	"strings"
+
+	"github.com/jirfag/go-queryset/queryset/field"
+)
 
 // onFieldMethod
 
@@ -8,6 +12,7 @@ type onFieldMethod struct {
 	namedMethod
 	fieldName        string
 	isFieldNameFirst bool
+	prefix           string // prefix of name starting with field name
 }
 
 func (m *onFieldMethod) setFieldNameFirst(isFieldNameFirst bool) {
@@ -19,8 +24,9 @@ func (m onFieldMethod) GetMethodName() string {
 	args := []string{m.fieldName, strings.Title(m.name)}
 	if !m.isFieldNameFirst {
 		args[0], args[1] = args[1], args[0]
+		return args[0] + args[1]
 	}
-	return args[0] + args[1]
+	return m.prefix + args[0] + args[1]
 }
 
 func newOnFieldMethod(name, fieldName string) onFieldMethod {
@@ -30,3 +36,9 @@ func newOnFieldMethod(name, fieldName string) onFieldMethod {
 		isFieldNameFirst: true,
 	}
 }
+
+// isRelationField returns true if field is an association: struct or
+// pointer to struct. Such fields aren't columns of struct's table.
+func isRelationField(f field.Info) bool {
+	return f.IsStruct || (f.IsPointer && f.GetPointed().IsStruct)
+}
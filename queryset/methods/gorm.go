@@ -2,8 +2,12 @@ package methods
 
 import "fmt"
 
+// wrapToGormScope wraps code calling GORM methods of db into op of queryset:
+// op is replayed by every chain call, so code must not evaluate args with side effects
 func wrapToGormScope(code string) string {
-	const tmpl = `return qs.w(%s)`
+	const tmpl = `return qs.w(func(db *gorm.DB) *gorm.DB {
+		return %s
+	})`
 	return fmt.Sprintf(tmpl, code)
 }
 
@@ -0,0 +1,61 @@
+package methods
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jirfag/go-queryset/queryset/field"
+)
+
+// indexNameToMethodSuffix converts index name in snake case into camel case:
+// active_email -> ActiveEmail
+func indexNameToMethodSuffix(name string) string {
+	var ret string
+	for _, part := range strings.Split(name, "_") {
+		if part == "" {
+			continue
+		}
+
+		if upper := strings.ToUpper(part); commonInitialisms[upper] {
+			ret += upper
+			continue
+		}
+		ret += strings.ToUpper(part[:1]) + part[1:]
+	}
+	return ret
+}
+
+// UniqueIndexFilterMethod generates By<Index> method: it filters by all
+// columns and predicate of unique index
+type UniqueIndexFilterMethod struct {
+	namedMethod
+	chainedQuerySetMethod
+	nArgsMethod
+	constBodyMethod
+}
+
+// NewUniqueIndexFilterMethod creates By<Index> method for unique index idx
+func NewUniqueIndexFilterMethod(ctx QsStructContext, idx field.UniqueIndex) UniqueIndexFilterMethod {
+	var args []oneArgMethod
+	var conds []string
+	for _, f := range idx.Fields {
+		argName := fieldNameToArgName(f.Name)
+		args = append(args, newOneArgMethod(argName, f.TypeName))
+		conds = append(conds, fmt.Sprintf("Where(%s, %s)",
+			strconv.Quote(ctx.Dialect().Quote(f.DBName)+" = ?"), argName))
+	}
+	if idx.Where != "" {
+		conds = append(conds, fmt.Sprintf("Where(%s)", strconv.Quote(idx.Where)))
+	}
+
+	r := UniqueIndexFilterMethod{
+		namedMethod:           newNamedMethod("By" + indexNameToMethodSuffix(idx.Name)),
+		chainedQuerySetMethod: newChainedQuerySetMethod(ctx.qsTypeName()),
+		nArgsMethod:           newNArgsMethod(args...),
+		constBodyMethod:       newConstBodyMethod("%s", wrapToGormScope(qsOpDbName+"."+strings.Join(conds, "."))),
+	}
+	r.setDoc(fmt.Sprintf(`// %s filters by columns of unique index %s: it's
+	// a lookup of no more than one record`, r.GetMethodName(), idx.Name))
+	return r
+}
@@ -0,0 +1,133 @@
+package methods
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/jinzhu/gorm"
+	"github.com/jirfag/go-queryset/queryset/field"
+)
+
+// Join is a join of related struct by columns of relation
+type Join struct {
+	field.Relation
+	QuerySetName  string // type name of queryset of related struct
+	Column        string // db name of joined column of struct
+	RelatedColumn string // db name of joined column of related struct
+
+	// IsRelatedSoftDeleted is true if related struct has DeletedAt field:
+	// soft deleted related records aren't counted by relation filters
+	IsRelatedSoftDeleted bool
+}
+
+// JoinMethod generates Join<Relation> method
+type JoinMethod struct {
+	namedMethod
+	chainedQuerySetMethod
+	oneArgMethod
+	constBodyMethod
+}
+
+// NewJoinMethod creates Join<Relation> method: it joins subquery of related
+// queryset, so only records having related records matching it are selected.
+// Subquery selects only distinct joined column under unique alias: it doesn't
+// make columns of queryset ambiguous and doesn't duplicate has many records.
+func NewJoinMethod(ctx QsStructContext, j Join) JoinMethod {
+	const tmpl = `sql, vars := %[1]s.rawSQL("SELECT DISTINCT %[2]s AS %[3]s FROM %%[1]s %%[2]s")
+	join := fmt.Sprintf("JOIN (?) %[4]s ON %[4]s.%[3]s = %%s.%[5]s",
+		%[6]s.db.NewScope(&%[7]s{}).QuotedTableName())
+	%[6]s = %[6]s.w(func(db *gorm.DB) *gorm.DB {
+		return db.Joins(join, gorm.Expr(sql, vars...))
+	})
+	%[6]s.joined = true
+	return %[6]s`
+
+	d := ctx.Dialect()
+	alias := gorm.ToDBName("Join" + j.Name)
+	argName := fieldNameToArgName(j.Name)
+	unquote := func(s string) string {
+		q := strconv.Quote(s)
+		return q[1 : len(q)-1]
+	}
+
+	r := JoinMethod{
+		namedMethod:           newNamedMethod("Join" + j.Name),
+		chainedQuerySetMethod: newChainedQuerySetMethod(ctx.qsTypeName()),
+		oneArgMethod:          newOneArgMethod(argName, j.QuerySetName),
+		constBodyMethod: newConstBodyMethod(tmpl, argName,
+			unquote(d.Quote(j.RelatedColumn)), unquote(d.Quote(alias+"_key")), unquote(d.Quote(alias)),
+			unquote(d.Quote(j.Column)), qsReceiverName, ctx.s.TypeName),
+	}
+
+	fk := j.Column
+	if j.IsHasMany {
+		fk = j.RelatedColumn
+	}
+	r.setDoc(fmt.Sprintf(`// %s joins %s by %s column: only records having %s
+	// matching %s queryset are selected`,
+		r.GetMethodName(), j.TypeName, fk, argName, argName))
+	return r
+}
+
+// RelationCountMethod generates Has<Relation> and <Relation>CountGt filters
+type RelationCountMethod struct {
+	namedMethod
+	chainedQuerySetMethod
+	nArgsMethod
+	constBodyMethod
+}
+
+// relationSubquery returns format of correlated subquery selecting expr from
+// related records of has many relation j: %[1]s is a quoted table of related
+// struct, %[2]s is a quoted table of struct. Soft deleted records aren't
+// selected.
+func relationSubquery(ctx QsStructContext, j Join, expr string) string {
+	d := ctx.Dialect()
+	alias := d.Quote(gorm.ToDBName("Related" + j.Name))
+	cond := fmt.Sprintf("%s.%s = %%[2]s.%s", alias, d.Quote(j.RelatedColumn), d.Quote(j.Column))
+	if j.IsRelatedSoftDeleted {
+		cond += fmt.Sprintf(" AND %s.%s IS NULL", alias, d.Quote("deleted_at"))
+	}
+	return fmt.Sprintf("SELECT %s FROM %%[1]s %s WHERE %s", expr, alias, cond)
+}
+
+func newRelationCountMethod(ctx QsStructContext, j Join, name, cond string,
+	args ...oneArgMethod) RelationCountMethod {
+
+	const tmpl = `cond := fmt.Sprintf(%q, %s.db.NewScope(&%s{}).QuotedTableName(),
+		%[2]s.db.NewScope(&%[4]s{}).QuotedTableName())
+	return %[2]s.w(func(db *gorm.DB) *gorm.DB {
+		return db.Where(cond%[5]s)
+	})`
+
+	var vars string
+	for _, a := range args {
+		vars += ", " + a.getArgName()
+	}
+	return RelationCountMethod{
+		namedMethod:           newNamedMethod(name),
+		chainedQuerySetMethod: newChainedQuerySetMethod(ctx.qsTypeName()),
+		nArgsMethod:           newNArgsMethod(args...),
+		constBodyMethod:       newConstBodyMethod(tmpl, cond, qsReceiverName, j.TypeName, ctx.s.TypeName, vars),
+	}
+}
+
+// NewHasRelationMethod creates Has<Relation> filter of has many relation j:
+// only records having related records are selected
+func NewHasRelationMethod(ctx QsStructContext, j Join) RelationCountMethod {
+	r := newRelationCountMethod(ctx, j, ctx.n.FilterName("Has"+j.Name, ""),
+		"EXISTS ("+relationSubquery(ctx, j, "1")+")")
+	r.setDoc(fmt.Sprintf(`// %s selects only records having %s records by %s column`,
+		r.GetMethodName(), j.TypeName, j.RelatedColumn))
+	return r
+}
+
+// NewRelationCountGtMethod creates <Relation>CountGt filter of has many
+// relation j: only records having more than n related records are selected
+func NewRelationCountGtMethod(ctx QsStructContext, j Join) RelationCountMethod {
+	r := newRelationCountMethod(ctx, j, ctx.n.FilterName(j.Name, "CountGt"),
+		"("+relationSubquery(ctx, j, "COUNT(*)")+") > ?", newOneArgMethod("n", "int"))
+	r.setDoc(fmt.Sprintf(`// %s selects only records having more than n %s
+	// records by %s column`, r.GetMethodName(), j.TypeName, j.RelatedColumn))
+	return r
+}
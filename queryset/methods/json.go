@@ -0,0 +1,64 @@
+package methods
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// JSONFilterMethod is a filter method of JSON column
+type JSONFilterMethod struct {
+	chainedQuerySetMethod
+	onFieldMethod
+	nArgsMethod
+	qsCallGormMethod
+}
+
+// NewJSONPathEqMethod creates <Field>JSONPathEq method of JSON column: it
+// filters by text value at path of dot-separated keys, e.g. "address.city"
+func NewJSONPathEqMethod(ctx QsFieldContext) JSONFilterMethod {
+	ctx = ctx.WithOperationName("JSONPathEq")
+	d := ctx.Dialect()
+	r := JSONFilterMethod{
+		onFieldMethod: ctx.onFieldMethod(),
+		nArgsMethod: newNArgsMethod(
+			newOneArgMethod("path", "string"),
+			newOneArgMethod("value", "string")),
+		chainedQuerySetMethod: ctx.chainedQuerySetMethod(),
+		qsCallGormMethod: newQsCallGormMethod("Where", "%s, %s, value",
+			strconv.Quote(fmt.Sprintf(d.JSONPathEq(), ctx.quotedFieldDBName())),
+			fmt.Sprintf(d.JSONPath(), "path")),
+	}
+	r.setDoc(fmt.Sprintf(`// %s filters by text value of %s at path of dot-separated
+	// keys, e.g. "address.city"`, r.GetMethodName(), ctx.fieldName()))
+	return r
+}
+
+// NewJSONContainsMethod creates <Field>JSONContains method of JSON column
+func NewJSONContainsMethod(ctx QsFieldContext) JSONFilterMethod {
+	ctx = ctx.WithOperationName("JSONContains")
+	r := JSONFilterMethod{
+		onFieldMethod:         ctx.onFieldMethod(),
+		nArgsMethod:           newNArgsMethod(newOneArgMethod("doc", "string")),
+		chainedQuerySetMethod: ctx.chainedQuerySetMethod(),
+		qsCallGormMethod: newQsCallGormMethod("Where", "%s, doc",
+			strconv.Quote(fmt.Sprintf(ctx.Dialect().JSONContains(), ctx.quotedFieldDBName()))),
+	}
+	r.setDoc(fmt.Sprintf(`// %s filters by %s containing JSON document doc,
+	// e.g. {"tags": ["go"]}`, r.GetMethodName(), ctx.fieldName()))
+	return r
+}
+
+// NewJSONHasKeyMethod creates <Field>JSONHasKey method of JSON column
+func NewJSONHasKeyMethod(ctx QsFieldContext) JSONFilterMethod {
+	ctx = ctx.WithOperationName("JSONHasKey")
+	r := JSONFilterMethod{
+		onFieldMethod:         ctx.onFieldMethod(),
+		nArgsMethod:           newNArgsMethod(newOneArgMethod("key", "string")),
+		chainedQuerySetMethod: ctx.chainedQuerySetMethod(),
+		qsCallGormMethod: newQsCallGormMethod("Where", "%s, key",
+			strconv.Quote(fmt.Sprintf(ctx.Dialect().JSONHasKey(), ctx.quotedFieldDBName()))),
+	}
+	r.setDoc(fmt.Sprintf(`// %s filters by %s having top-level key`,
+		r.GetMethodName(), ctx.fieldName()))
+	return r
+}
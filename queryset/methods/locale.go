@@ -0,0 +1,31 @@
+package methods
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// NewLocalizedOrderAscByMethod creates OrderAscBy<Field> method of string
+// field: it orders in collation of locale of queryset
+func NewLocalizedOrderAscByMethod(ctx QsFieldContext) FieldOperationNoArgsMethod {
+	return newLocalizedOrderMethod(ctx.WithOperationName("OrderAscBy"), "ASC")
+}
+
+// NewLocalizedOrderDescByMethod creates OrderDescBy<Field> method of string
+// field: it orders in collation of locale of queryset
+func NewLocalizedOrderDescByMethod(ctx QsFieldContext) FieldOperationNoArgsMethod {
+	return newLocalizedOrderMethod(ctx.WithOperationName("OrderDescBy"), "DESC")
+}
+
+func newLocalizedOrderMethod(ctx QsFieldContext, direction string) FieldOperationNoArgsMethod {
+	r := FieldOperationNoArgsMethod{
+		onFieldMethod: ctx.onFieldMethod(),
+		qsCallGormMethod: newQsCallGormMethod("Order", "%s.collate(%s) + %s",
+			qsReceiverName, strconv.Quote(ctx.quotedFieldDBName()), strconv.Quote(" "+direction)),
+		chainedQuerySetMethod: ctx.chainedQuerySetMethod(),
+	}
+	r.setFieldNameFirst(false)
+	r.setDoc(fmt.Sprintf(`// %s orders by %s in collation of locale set by Localized`,
+		r.GetMethodName(), ctx.fieldName()))
+	return r
+}
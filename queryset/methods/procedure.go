@@ -0,0 +1,58 @@
+package methods
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ProcedureArg is an argument of stored procedure
+type ProcedureArg struct {
+	Name string
+	Type string // Go type of argument
+}
+
+// Procedure is a stored procedure (or table function) returning rows of struct
+type Procedure struct {
+	Name    string // Go name of procedure, call wrapper is named Call<Name>
+	SQLName string // name of procedure in database
+	Args    []ProcedureArg
+}
+
+// ProcedureMethod generates Call<Procedure> func
+type ProcedureMethod struct {
+	funcMethod
+	namedMethod
+	nArgsMethod
+	constRetMethod
+	constBodyMethod
+}
+
+// NewProcedureMethod creates Call<Procedure> func: it calls stored procedure p
+// by dialect rules and scans returned rows into structs
+func NewProcedureMethod(ctx QsStructContext, p Procedure) ProcedureMethod {
+	args := []oneArgMethod{newOneArgMethod("db", "*gorm.DB")}
+	var argNames []string
+	for _, a := range p.Args {
+		args = append(args, newOneArgMethod(a.Name, a.Type))
+		argNames = append(argNames, a.Name)
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(p.Args)), ", ")
+	call := fmt.Sprintf(ctx.Dialect().CallProcedure(), p.SQLName, placeholders)
+	callArgs := ""
+	if len(argNames) != 0 {
+		callArgs = ", " + strings.Join(argNames, ", ")
+	}
+
+	r := ProcedureMethod{
+		namedMethod:    newNamedMethod("Call" + p.Name),
+		nArgsMethod:    newNArgsMethod(args...),
+		constRetMethod: newConstRetMethod(fmt.Sprintf("([]%s, error)", ctx.s.TypeName)),
+		constBodyMethod: newConstBodyMethod(`var ret []%s
+			err := db.Raw(%q%s).Scan(&ret).Error
+			return ret, err`, ctx.s.TypeName, call, callArgs),
+	}
+	r.setDoc(fmt.Sprintf(`// %s calls stored procedure %s and scans returned rows into %s`,
+		r.GetMethodName(), p.SQLName, ctx.s.TypeName))
+	return r
+}
@@ -4,28 +4,111 @@ import (
 	"fmt"
 	"go/token"
 	"log"
+	"strconv"
 	"strings"
 	"unicode"
 
 	"github.com/jirfag/go-queryset/parser"
+	"github.com/jirfag/go-queryset/queryset/dialect"
 	"github.com/jirfag/go-queryset/queryset/field"
 )
 
 const qsReceiverName = "qs"
 const qsDbName = qsReceiverName + ".db"
 
+// qsOpDbName is a name of db passed to op of queryset by w
+const qsOpDbName = "db"
+
 type QsStructContext struct {
 	s parser.ParsedStruct
+	d dialect.Dialect
+	n Naming
+
+	sequence bool // primary key is set by nextID method before inserts
+}
+
+// Naming is a naming scheme of generated queryset of struct
+type Naming struct {
+	QuerySet     string // type name of queryset
+	Constructor  string // name of func constructing queryset
+	FilterPrefix string // prefix of field filters names, e.g. Filter for FilterNameEq
+
+	// AllTenantsConstructor is a name of unexported func constructing queryset
+	// of rows of all tenants, it's set only for structs with tenant field:
+	// their Constructor needs tenant
+	AllTenantsConstructor string
+}
+
+// DefaultNaming returns default naming scheme of struct: <Struct>QuerySet
+// constructed by New<Struct>QuerySet, filters have no prefix
+func DefaultNaming(structName string) Naming {
+	qsName := structName + "QuerySet"
+	return Naming{
+		QuerySet:    qsName,
+		Constructor: "New" + qsName,
+	}
+}
+
+// InternalConstructor returns name of func constructing queryset in
+// generated code: it doesn't filter rows by tenant
+func (n Naming) InternalConstructor() string {
+	if n.AllTenantsConstructor != "" {
+		return n.AllTenantsConstructor
+	}
+	return n.Constructor
+}
+
+// FilterName returns name of filter by operation op (e.g. Eq) of field
+func (n Naming) FilterName(fieldName, op string) string {
+	return n.FilterPrefix + fieldName + op
 }
 
-func NewQsStructContext(s parser.ParsedStruct) QsStructContext {
+func NewQsStructContext(s parser.ParsedStruct, d dialect.Dialect) QsStructContext {
 	return QsStructContext{
 		s: s,
+		d: d,
+		n: DefaultNaming(s.TypeName),
 	}
 }
 
+// WithNaming returns ctx with changed naming scheme
+func (ctx QsStructContext) WithNaming(n Naming) QsStructContext {
+	ctx.n = n
+	return ctx
+}
+
+// WithSequence returns ctx of struct, which primary key is set by sequence:
+// inserting methods call nextID method
+func (ctx QsStructContext) WithSequence() QsStructContext {
+	ctx.sequence = true
+	return ctx
+}
+
+// Dialect returns SQL dialect of generated code
+func (ctx QsStructContext) Dialect() dialect.Dialect {
+	return ctx.d
+}
+
 func (ctx QsStructContext) qsTypeName() string {
-	return ctx.s.TypeName + "QuerySet"
+	return ctx.n.QuerySet
+}
+
+func (ctx QsStructContext) qsConstructorName() string {
+	return ctx.n.InternalConstructor()
+}
+
+// breakerCallName returns name of func making call, which doesn't run GORM
+// callbacks, through circuit breaker of struct
+func (ctx QsStructContext) breakerCallName() string {
+	return "call" + ctx.s.TypeName + "Breaker"
+}
+
+func (ctx QsStructContext) dbSchemaTypeName() string {
+	return ctx.s.TypeName + "DBSchema"
+}
+
+func (ctx QsStructContext) dbSchemaFieldTypeName() string {
+	return ctx.s.TypeName + "DBSchemaField"
 }
 
 func (ctx QsStructContext) FieldCtx(f field.Info) QsFieldContext {
@@ -51,12 +134,19 @@ func (ctx QsFieldContext) fieldDBName() string {
 	return ctx.f.DBName
 }
 
+// quotedFieldDBName returns db name of field quoted by dialect rules
+func (ctx QsFieldContext) quotedFieldDBName() string {
+	return ctx.Dialect().Quote(ctx.fieldDBName())
+}
+
 func (ctx QsFieldContext) fieldTypeName() string {
 	return ctx.f.TypeName
 }
 
 func (ctx QsFieldContext) onFieldMethod() onFieldMethod {
-	return newOnFieldMethod(ctx.operationName, ctx.fieldName())
+	r := newOnFieldMethod(ctx.operationName, ctx.fieldName())
+	r.prefix = ctx.n.FilterPrefix
+	return r
 }
 
 func (ctx QsFieldContext) chainedQuerySetMethod() chainedQuerySetMethod {
@@ -187,6 +277,11 @@ var commonInitialisms = map[string]bool{
 	"XSS":   true,
 }
 
+// ArgName returns name of argument of generated methods for field fieldName
+func ArgName(fieldName string) string {
+	return fieldNameToArgName(fieldName)
+}
+
 func fieldNameToArgName(fieldName string) string {
 	if commonInitialisms[fieldName] {
 		return fieldName
@@ -213,21 +308,22 @@ func newStructOperationOneArgMethod(name, argTypeName, qsTypeName string) Struct
 		namedMethod:           newNamedMethod(name),
 		chainedQuerySetMethod: newChainedQuerySetMethod(qsTypeName),
 		oneArgMethod:          newOneArgMethod(argName, argTypeName),
-		qsCallGormMethod:      newQsCallGormMethod(name, argName),
+		qsCallGormMethod:      newQsCallGormMethod(name, "%s", argName),
 	}
 }
 
 type qsCallGormMethod struct {
 	callGormMethod
+	prepare string // code evaluating args of call once, before op
 }
 
 func (m qsCallGormMethod) GetBody() string {
-	return wrapToGormScope(m.callGormMethod.GetBody())
+	return m.prepare + wrapToGormScope(m.callGormMethod.GetBody())
 }
 
 func newQsCallGormMethod(name, argsFmt string, argsArgs ...interface{}) qsCallGormMethod {
 	return qsCallGormMethod{
-		callGormMethod: newCallGormMethod(name, fmt.Sprintf(argsFmt, argsArgs...), qsDbName),
+		callGormMethod: newCallGormMethod(name, fmt.Sprintf(argsFmt, argsArgs...), qsOpDbName),
 	}
 }
 
@@ -246,9 +342,151 @@ func NewBinaryFilterMethod(ctx QsFieldContext) BinaryFilterMethod {
 		onFieldMethod:         ctx.onFieldMethod(),
 		oneArgMethod:          newOneArgMethod(argName, ctx.fieldTypeName()),
 		chainedQuerySetMethod: ctx.chainedQuerySetMethod(),
-		qsCallGormMethod: newQsCallGormMethod("Where", `"%s %s", %s`,
-			ctx.fieldDBName(), getWhereCondition(ctx.operationName), argName),
+		qsCallGormMethod: newQsCallGormMethod("Where", "%s, %s",
+			strconv.Quote(ctx.quotedFieldDBName()+" "+getWhereCondition(ctx.operationName)), argName),
+	}
+}
+
+// NewLikeFilterMethod creates <Field>Like filter method
+func NewLikeFilterMethod(ctx QsFieldContext) BinaryFilterMethod {
+	return newPatternFilterMethod(ctx.WithOperationName("Like"), "%[1]s LIKE ?")
+}
+
+// NewILikeFilterMethod creates <Field>ILike filter method: it's a
+// case-insensitive LIKE spelled by dialect rules
+func NewILikeFilterMethod(ctx QsFieldContext) BinaryFilterMethod {
+	return newPatternFilterMethod(ctx.WithOperationName("ILike"), ctx.Dialect().ILike())
+}
+
+// NewEqFoldFilterMethod creates <Field>EqFold filter method: it's
+// a case-insensitive equality, e.g. of emails
+func NewEqFoldFilterMethod(ctx QsFieldContext) BinaryFilterMethod {
+	ctx = ctx.WithOperationName("EqFold")
+	argName := fieldNameToArgName(ctx.fieldName())
+	r := BinaryFilterMethod{
+		onFieldMethod:         ctx.onFieldMethod(),
+		oneArgMethod:          newOneArgMethod(argName, ctx.fieldTypeName()),
+		chainedQuerySetMethod: ctx.chainedQuerySetMethod(),
+		qsCallGormMethod: newQsCallGormMethod("Where", "%s, %s",
+			strconv.Quote(fmt.Sprintf("LOWER(%s) = LOWER(?)", ctx.quotedFieldDBName())), argName),
 	}
+	r.setDoc(fmt.Sprintf(`// %s filters by %s equal to %s ignoring case: index
+	// on %s isn't used, index on LOWER(%s) is`,
+		r.GetMethodName(), ctx.fieldName(), argName, ctx.fieldDBName(), ctx.fieldDBName()))
+	return r
+}
+
+// NewStartsWithFilterMethod creates <Field>StartsWith filter method
+func NewStartsWithFilterMethod(ctx QsFieldContext) BinaryFilterMethod {
+	return newSubstringFilterMethod(ctx.WithOperationName("StartsWith"), "prefix", `likeEscaper.Replace(prefix) + "%%"`)
+}
+
+// NewEndsWithFilterMethod creates <Field>EndsWith filter method
+func NewEndsWithFilterMethod(ctx QsFieldContext) BinaryFilterMethod {
+	return newSubstringFilterMethod(ctx.WithOperationName("EndsWith"), "suffix", `"%%" + likeEscaper.Replace(suffix)`)
+}
+
+// NewContainsFilterMethod creates <Field>Contains filter method
+func NewContainsFilterMethod(ctx QsFieldContext) BinaryFilterMethod {
+	return newSubstringFilterMethod(ctx.WithOperationName("Contains"), "substr",
+		`"%%" + likeEscaper.Replace(substr) + "%%"`)
+}
+
+// newSubstringFilterMethod creates LIKE filter by pattern patternFmt made of
+// argName: wildcards in the argument are escaped and match literally
+func newSubstringFilterMethod(ctx QsFieldContext, argName, patternFmt string) BinaryFilterMethod {
+	r := BinaryFilterMethod{
+		onFieldMethod:         ctx.onFieldMethod(),
+		oneArgMethod:          newOneArgMethod(argName, "string"),
+		chainedQuerySetMethod: ctx.chainedQuerySetMethod(),
+		qsCallGormMethod: newQsCallGormMethod("Where", "%s, "+patternFmt,
+			strconv.Quote(ctx.quotedFieldDBName()+" LIKE ? ESCAPE '!'")),
+	}
+	r.setDoc(fmt.Sprintf(`// %s filters by %s having %s: %% and _ in %s aren't wildcards`,
+		r.GetMethodName(), ctx.fieldName(), argName, argName))
+	return r
+}
+
+// NewMatchesFilterMethod creates <Field>Matches filter method by regular
+// expression spelled by dialect rules
+func NewMatchesFilterMethod(ctx QsFieldContext) BinaryFilterMethod {
+	r := newPatternFilterMethod(ctx.WithOperationName("Matches"), ctx.Dialect().RegexpMatch())
+	r.setDoc(fmt.Sprintf(`// %s filters by %s matching regular expression pattern in syntax
+	// of database, pattern isn't anchored`, r.GetMethodName(), ctx.fieldName()))
+	return r
+}
+
+func newPatternFilterMethod(ctx QsFieldContext, condFmt string) BinaryFilterMethod {
+	r := BinaryFilterMethod{
+		onFieldMethod:         ctx.onFieldMethod(),
+		oneArgMethod:          newOneArgMethod("pattern", "string"),
+		chainedQuerySetMethod: ctx.chainedQuerySetMethod(),
+		qsCallGormMethod: newQsCallGormMethod("Where", "%s, pattern",
+			strconv.Quote(fmt.Sprintf(condFmt, ctx.quotedFieldDBName()))),
+	}
+	r.setDoc(fmt.Sprintf(`// %s filters by pattern with wildcards %% and _`, r.GetMethodName()))
+	return r
+}
+
+// NewBeforeFilterMethod creates <Field>Before filter method of time field
+func NewBeforeFilterMethod(ctx QsFieldContext) BinaryFilterMethod {
+	return newTimeFilterMethod(ctx, "Before", "lt", "earlier")
+}
+
+// NewAfterFilterMethod creates <Field>After filter method of time field
+func NewAfterFilterMethod(ctx QsFieldContext) BinaryFilterMethod {
+	return newTimeFilterMethod(ctx, "After", "gt", "later")
+}
+
+func newTimeFilterMethod(ctx QsFieldContext, operationName, filterOperationName, comparative string) BinaryFilterMethod {
+	r := NewBinaryFilterMethod(ctx.WithOperationName(filterOperationName))
+	r.onFieldMethod = ctx.WithOperationName(operationName).onFieldMethod()
+	r.setDoc(fmt.Sprintf(`// %s filters by %s %s than %s`,
+		r.GetMethodName(), ctx.fieldName(), comparative, fieldNameToArgName(ctx.fieldName())))
+	return r
+}
+
+// NewWithinFilterMethod creates <Field>Within filter method of time field:
+// it selects records with field within duration d before now
+func NewWithinFilterMethod(ctx QsFieldContext) BinaryFilterMethod {
+	ctx = ctx.WithOperationName("Within")
+	r := BinaryFilterMethod{
+		onFieldMethod:         ctx.onFieldMethod(),
+		oneArgMethod:          newOneArgMethod("d", "time.Duration"),
+		chainedQuerySetMethod: ctx.chainedQuerySetMethod(),
+		qsCallGormMethod: newQsCallGormMethod("Where", "%s, since",
+			strconv.Quote(ctx.quotedFieldDBName()+" >= ?")),
+	}
+	r.prepare = "since := time.Now().Add(-d)\n"
+	r.setDoc(fmt.Sprintf(`// %s filters by %s within duration d before now`,
+		r.GetMethodName(), ctx.fieldName()))
+	return r
+}
+
+// BetweenFilterMethod filters by range of values
+type BetweenFilterMethod struct {
+	chainedQuerySetMethod
+	onFieldMethod
+	nArgsMethod
+	qsCallGormMethod
+}
+
+// NewBetweenFilterMethod creates <Field>Between filter method: bounds of
+// range are included
+func NewBetweenFilterMethod(ctx QsFieldContext) BetweenFilterMethod {
+	ctx = ctx.WithOperationName("Between")
+	r := BetweenFilterMethod{
+		onFieldMethod: ctx.onFieldMethod(),
+		nArgsMethod: newNArgsMethod(
+			newOneArgMethod("from", ctx.fieldTypeName()),
+			newOneArgMethod("to", ctx.fieldTypeName())),
+		chainedQuerySetMethod: ctx.chainedQuerySetMethod(),
+		qsCallGormMethod: newQsCallGormMethod("Where", "%s, from, to",
+			strconv.Quote(ctx.quotedFieldDBName()+" BETWEEN ? AND ?")),
+	}
+	r.setDoc(fmt.Sprintf(`// %s filters by %s from "from" to "to" inclusive`,
+		r.GetMethodName(), ctx.fieldName()))
+	return r
 }
 
 // InFilterMethod filters with IN condition
@@ -280,8 +518,8 @@ func newInFilterMethodImpl(ctx QsFieldContext, operationName, sql string) InFilt
 		onFieldMethod:         ctx.onFieldMethod(),
 		nArgsMethod:           args,
 		chainedQuerySetMethod: ctx.chainedQuerySetMethod(),
-		qsCallGormMethod: newQsCallGormMethod("Where", `"%s %s (?)", iArgs`,
-			ctx.fieldDBName(), sql),
+		qsCallGormMethod: newQsCallGormMethod("Where", "%s, iArgs",
+			strconv.Quote(ctx.quotedFieldDBName()+" "+sql+" (?)")),
 	}
 }
 
@@ -295,6 +533,60 @@ func NewNotInFilterMethod(ctx QsFieldContext) InFilterMethod {
 	return newInFilterMethodImpl(ctx, "NotIn", "NOT IN")
 }
 
+// ChunkedInFilterMethod filters with IN condition split into chunks of
+// limited size: sharding proxies mishandle large IN lists
+type ChunkedInFilterMethod struct {
+	InFilterMethod
+	cond, sep string
+}
+
+// inChunkSize is a max number of values in one IN list of ChunkedInFilterMethod
+const inChunkSize = 500
+
+// GetBody returns method's body
+func (m ChunkedInFilterMethod) GetBody() string {
+	const tmpl = `iArgs := []interface{}{%[1]s}
+	for _, arg := range %[2]s {
+		iArgs = append(iArgs, arg)
+	}
+
+	var conds []string
+	var chunks []interface{}
+	for len(iArgs) != 0 {
+		n := len(iArgs)
+		if n > %[3]d {
+			n = %[3]d
+		}
+		conds = append(conds, %[4]q)
+		chunks = append(chunks, iArgs[:n])
+		iArgs = iArgs[n:]
+	}
+	return qs.w(func(db *gorm.DB) *gorm.DB {
+		return db.Where(strings.Join(conds, %[5]q), chunks...)
+	})`
+	return fmt.Sprintf(tmpl, m.getArgName(0), m.getArgName(1), inChunkSize, m.cond, m.sep)
+}
+
+func newChunkedInFilterMethod(ctx QsFieldContext, operationName, sql, sep string) ChunkedInFilterMethod {
+	return ChunkedInFilterMethod{
+		InFilterMethod: newInFilterMethodImpl(ctx, operationName, sql),
+		cond:           ctx.quotedFieldDBName() + " " + sql + " (?)",
+		sep:            sep,
+	}
+}
+
+// NewChunkedInFilterMethod creates IN filter method of sharded struct:
+// chunks are joined by OR
+func NewChunkedInFilterMethod(ctx QsFieldContext) ChunkedInFilterMethod {
+	return newChunkedInFilterMethod(ctx, "In", "IN", " OR ")
+}
+
+// NewChunkedNotInFilterMethod creates NOT IN filter method of sharded struct:
+// chunks are joined by AND
+func NewChunkedNotInFilterMethod(ctx QsFieldContext) ChunkedInFilterMethod {
+	return newChunkedInFilterMethod(ctx, "NotIn", "NOT IN", " AND ")
+}
+
 func getWhereCondition(name string) string {
 	nameToOp := map[string]string{
 		"eq":  "=",
@@ -323,8 +615,8 @@ type UnaryFilterMethod struct {
 func newUnaryFilterMethod(ctx QsFieldContext, op string) UnaryFilterMethod {
 	r := UnaryFilterMethod{
 		onFieldMethod: ctx.onFieldMethod(),
-		qsCallGormMethod: newQsCallGormMethod("Where", `"%s %s"`,
-			ctx.fieldDBName(), op),
+		qsCallGormMethod: newQsCallGormMethod("Where", "%s",
+			strconv.Quote(ctx.quotedFieldDBName()+" "+op)),
 		chainedQuerySetMethod: ctx.chainedQuerySetMethod(),
 	}
 	return r
@@ -340,6 +632,13 @@ type SelectMethod struct {
 	gormErroredMethod
 }
 
+// GetBody returns method's body: result is memoized if queryset is Memoized
+func (m SelectMethod) GetBody() string {
+	return fmt.Sprintf(`return %s.memoize(%q, ret, func() error {
+		%s
+	})`, qsReceiverName, m.GetMethodName(), m.gormErroredMethod.GetBody())
+}
+
 func newSelectMethod(name, gormName, argTypeName, qsTypeName string) SelectMethod {
 	return SelectMethod{
 		namedMethod:        newNamedMethod(name),
@@ -385,6 +684,26 @@ func NewDeleteMethod(qsTypeName, structTypeName string) DeleteMethod {
 	}
 }
 
+// DeleteNumMethod creates DeleteNum method
+type DeleteNumMethod struct {
+	baseQuerySetMethod
+	namedMethod
+	noArgsMethod
+	constRetMethod
+	constBodyMethod
+}
+
+// NewDeleteNumMethod creates DeleteNum method returning number of deleted rows
+func NewDeleteNumMethod(qsTypeName, structTypeName string) DeleteNumMethod {
+	return DeleteNumMethod{
+		baseQuerySetMethod: newBaseQuerySetMethod(qsTypeName),
+		namedMethod:        newNamedMethod("DeleteNum"),
+		constRetMethod:     newConstRetMethod("(int64, error)"),
+		constBodyMethod: newConstBodyMethod(`db := %s.Delete(%s{})
+			return db.RowsAffected, db.Error`, qsDbName, structTypeName),
+	}
+}
+
 // CountMethod creates Count method
 type CountMethod struct {
 	baseQuerySetMethod
@@ -395,22 +714,169 @@ type CountMethod struct {
 }
 
 // NewCountMethod returns new CountMethod
-func NewCountMethod(qsTypeName string) CountMethod {
+func NewCountMethod(ctx QsStructContext) CountMethod {
 	return CountMethod{
-		baseQuerySetMethod: newBaseQuerySetMethod(qsTypeName),
+		baseQuerySetMethod: newBaseQuerySetMethod(ctx.qsTypeName()),
 		namedMethod:        newNamedMethod("Count"),
 		constRetMethod:     newConstRetMethod("(int, error)"),
 		constBodyMethod: newConstBodyMethod(`var count int
-			err := %s.Count(&count).Error
-			return count, err`, qsDbName),
+			err := %[1]s.memoize("Count", &count, func() error {
+				err := %[3]s(%[2]s, func() error {
+					return %[2]s.Count(&count).Error
+				})
+				if err != nil {
+					return err
+				}
+				return %[1]s.checkRowsNum(count, false)
+			})
+			if err != nil {
+				return 0, err
+			}
+			return count, nil`, qsReceiverName, qsDbName, ctx.breakerCallName()),
+	}
+}
+
+// CountDistinctMethod generates CountDistinct<Field> method
+type CountDistinctMethod struct {
+	namedMethod
+	baseQuerySetMethod
+	noArgsMethod
+	constRetMethod
+	constBodyMethod
+}
+
+// NewCountDistinctMethod creates CountDistinct<Field> method: it counts
+// distinct values of field. Order is dropped like in Count: postgres
+// doesn't allow ordering by not aggregated columns.
+func NewCountDistinctMethod(ctx QsFieldContext) CountDistinctMethod {
+	name := "CountDistinct" + ctx.fieldName()
+	r := CountDistinctMethod{
+		namedMethod:        newNamedMethod(name),
+		baseQuerySetMethod: newBaseQuerySetMethod(ctx.qsTypeName()),
+		constRetMethod:     newConstRetMethod("(int, error)"),
+		constBodyMethod: newConstBodyMethod(`var count int
+			err := %[1]s.memoize(%[2]q, &count, func() error {
+				if err := %[1]s.checkMatchedNum(); err != nil {
+					return err
+				}
+				return %[3]s(%[4]s, func() error {
+					return %[4]s.Order("", true).Select(%[5]s).Row().Scan(&count)
+				})
+			})
+			return count, err`, qsReceiverName, name, ctx.breakerCallName(), qsDbName,
+			strconv.Quote("COUNT(DISTINCT "+ctx.quotedFieldDBName()+")")),
+	}
+	r.setDoc(fmt.Sprintf(`// %s counts distinct values of %s column`, name, ctx.fieldDBName()))
+	return r
+}
+
+// DecimalAggregateMethod generates Sum<Field> and Avg<Field> methods
+type DecimalAggregateMethod struct {
+	namedMethod
+	baseQuerySetMethod
+	noArgsMethod
+	constRetMethod
+	constBodyMethod
+}
+
+// NewSumMethod creates Sum<Field> method of decimal field
+func NewSumMethod(ctx QsFieldContext) DecimalAggregateMethod {
+	return newDecimalAggregateMethod(ctx, "Sum", "SUM")
+}
+
+// NewAvgMethod creates Avg<Field> method of decimal field
+func NewAvgMethod(ctx QsFieldContext) DecimalAggregateMethod {
+	return newDecimalAggregateMethod(ctx, "Avg", "AVG")
+}
+
+// newDecimalAggregateMethod creates method returning aggregate function
+// of decimal column: it's zero if there are no rows (or only NULL values).
+// Order is dropped like in Count.
+func newDecimalAggregateMethod(ctx QsFieldContext, name, function string) DecimalAggregateMethod {
+	name += ctx.fieldName()
+	r := DecimalAggregateMethod{
+		namedMethod:        newNamedMethod(name),
+		baseQuerySetMethod: newBaseQuerySetMethod(ctx.qsTypeName()),
+		constRetMethod:     newConstRetMethod("(decimal.Decimal, error)"),
+		constBodyMethod: newConstBodyMethod(`if err := %[1]s.checkMatchedNum(); err != nil {
+				return decimal.Decimal{}, err
+			}
+
+			var ret decimal.NullDecimal
+			err := %[2]s(%[3]s, func() error {
+				return %[3]s.Order("", true).Select(%[4]s).Row().Scan(&ret)
+			})
+			return ret.Decimal, err`, qsReceiverName, ctx.breakerCallName(), qsDbName,
+			strconv.Quote(function+"("+ctx.quotedFieldDBName()+")")),
+	}
+	r.setDoc(fmt.Sprintf(`// %s returns %s of %s column: it's zero if there are no values`,
+		name, function, ctx.fieldDBName()))
+	return r
+}
+
+// DistinctMethod generates Distinct method
+type DistinctMethod struct {
+	namedMethod
+	chainedQuerySetMethod
+	noArgsMethod
+	constBodyMethod
+}
+
+// NewDistinctMethod creates Distinct method: it selects only distinct rows
+func NewDistinctMethod(ctx QsStructContext) DistinctMethod {
+	r := DistinctMethod{
+		namedMethod:           newNamedMethod("Distinct"),
+		chainedQuerySetMethod: newChainedQuerySetMethod(ctx.qsTypeName()),
+		constBodyMethod: newConstBodyMethod(
+			`return %[1]s.selectColumns("DISTINCT " + %[1]s.db.NewScope(&%[2]s{}).QuotedTableName() + ".*")`,
+			qsReceiverName, ctx.s.TypeName),
 	}
+	r.setDoc(`// Distinct selects only distinct rows: duplicates produced by joins are removed`)
+	return r
 }
 
 // Concrete methods
 
-// NewPreloadMethod creates new Preload method
-func NewPreloadMethod(ctx QsFieldContext) FieldOperationNoArgsMethod {
-	r := newFieldOperationNoArgsMethod(ctx.WithOperationName("Preload"), false)
+// PreloadMethod generates Preload<Field> method
+type PreloadMethod struct {
+	onFieldMethod
+	noArgsMethod
+	chainedQuerySetMethod
+	constBodyMethod
+}
+
+// NewPreloadMethod creates new Preload method: preloaded associations are
+// tracked by queryset for CacheKey
+func NewPreloadMethod(ctx QsFieldContext) PreloadMethod {
+	ctx = ctx.WithOperationName("Preload")
+	r := PreloadMethod{
+		onFieldMethod:         ctx.onFieldMethod(),
+		chainedQuerySetMethod: ctx.chainedQuerySetMethod(),
+		constBodyMethod:       newConstBodyMethod("return %s.preload(%q)", qsReceiverName, ctx.fieldName()),
+	}
+	r.setFieldNameFirst(false) // UserPreload -> PreloadUser
+	return r
+}
+
+// DistinctFieldMethod generates Distinct<Field> method
+type DistinctFieldMethod struct {
+	onFieldMethod
+	noArgsMethod
+	chainedQuerySetMethod
+	constBodyMethod
+}
+
+// NewDistinctFieldMethod creates Distinct<Field> method: it selects only
+// distinct values of field, other fields of selected objects are empty
+func NewDistinctFieldMethod(ctx QsFieldContext) DistinctFieldMethod {
+	ctx = ctx.WithOperationName("Distinct")
+	r := DistinctFieldMethod{
+		onFieldMethod:         ctx.onFieldMethod(),
+		chainedQuerySetMethod: ctx.chainedQuerySetMethod(),
+		constBodyMethod: newConstBodyMethod("return %s.selectColumns(%s)",
+			qsReceiverName, strconv.Quote("DISTINCT "+ctx.quotedFieldDBName())),
+	}
+	r.setFieldNameFirst(false) // NameDistinct -> DistinctName
 	return r
 }
 
@@ -418,7 +884,7 @@ func NewPreloadMethod(ctx QsFieldContext) FieldOperationNoArgsMethod {
 func NewOrderAscByMethod(ctx QsFieldContext) FieldOperationNoArgsMethod {
 	r := newFieldOperationNoArgsMethod(ctx.WithOperationName("OrderAscBy"), true)
 	r.setGormMethodName("Order")
-	r.setGormMethodArgs(fmt.Sprintf(`"%s ASC"`, ctx.fieldDBName()))
+	r.setGormMethodArgs(strconv.Quote(ctx.quotedFieldDBName() + " ASC"))
 	return r
 }
 
@@ -426,29 +892,412 @@ func NewOrderAscByMethod(ctx QsFieldContext) FieldOperationNoArgsMethod {
 func NewOrderDescByMethod(ctx QsFieldContext) FieldOperationNoArgsMethod {
 	r := newFieldOperationNoArgsMethod(ctx.WithOperationName("OrderDescBy"), true)
 	r.setGormMethodName("Order")
-	r.setGormMethodArgs(fmt.Sprintf(`"%s DESC"`, ctx.fieldDBName()))
+	r.setGormMethodArgs(strconv.Quote(ctx.quotedFieldDBName() + " DESC"))
 	return r
 }
 
-// NewLimitMethod creates Limit method
-func NewLimitMethod(qsTypeName string) StructOperationOneArgMethod {
-	return newStructOperationOneArgMethod("Limit", "int", qsTypeName)
+// PagingMethod generates Limit and Offset methods
+type PagingMethod struct {
+	namedMethod
+	chainedQuerySetMethod
+	oneArgMethod
+	constBodyMethod
+}
+
+// NewLimitMethod creates Limit method: it marks query as limited if dialect
+// needs LIMIT for OFFSET
+func NewLimitMethod(ctx QsStructContext) PagingMethod {
+	call := "db.Limit(limit)"
+	if ctx.Dialect().OffsetNeedsLimit() {
+		call = fmt.Sprintf(`db.Set("%s:limited", true).Limit(limit)`, ctx.qsTypeName())
+	}
+	return newPagingMethod(ctx, "Limit", "", call)
+}
+
+// NewOffsetMethod creates Offset method: it sets maximal limit if dialect
+// needs LIMIT for OFFSET and query isn't limited
+func NewOffsetMethod(ctx QsStructContext) PagingMethod {
+	var limit string
+	if ctx.Dialect().OffsetNeedsLimit() {
+		limit = fmt.Sprintf(`if _, ok := db.Get("%s:limited"); !ok {
+			db = db.Limit(int(^uint(0) >> 1))
+		}
+		`, ctx.qsTypeName())
+	}
+	return newPagingMethod(ctx, "Offset", limit, "db.Offset(offset)")
+}
+
+// newPagingMethod creates Limit or Offset method: query is ordered once by
+// PagingOrder of dialect if it's set
+func newPagingMethod(ctx QsStructContext, name, prepare, call string) PagingMethod {
+	if order := ctx.Dialect().PagingOrder(); order != "" {
+		prepare += fmt.Sprintf(`if _, ok := db.Get("%[1]s:paged"); !ok {
+			db = db.Set("%[1]s:paged", true).Order(%[2]q)
+		}
+		`, ctx.qsTypeName(), order)
+	}
+
+	argName := strings.ToLower(name)
+	return PagingMethod{
+		namedMethod:           newNamedMethod(name),
+		chainedQuerySetMethod: newChainedQuerySetMethod(ctx.qsTypeName()),
+		oneArgMethod:          newOneArgMethod(argName, "int"),
+		constBodyMethod: newConstBodyMethod(`return qs.w(func(db *gorm.DB) *gorm.DB {
+		%sreturn %s
+	})`, prepare, call),
+	}
+}
+
+// WhereMethod generates Where method
+type WhereMethod struct {
+	namedMethod
+	chainedQuerySetMethod
+	nArgsMethod
+	qsCallGormMethod
+}
+
+// NewWhereMethod creates Where method: it adds raw SQL condition
+func NewWhereMethod(qsTypeName string) WhereMethod {
+	r := WhereMethod{
+		namedMethod:           newNamedMethod("Where"),
+		chainedQuerySetMethod: newChainedQuerySetMethod(qsTypeName),
+		nArgsMethod: newNArgsMethod(
+			newOneArgMethod("condition", "string"),
+			newOneArgMethod("args", "...interface{}"),
+		),
+		qsCallGormMethod: newQsCallGormMethod("Where", "condition, args..."),
+	}
+	r.setDoc(`// Where adds raw SQL condition with bind vars args: it's an escape hatch
+	// for conditions without generated methods. Columns of conditions passed as
+	// literals are checked by generator with -check-where flag.`)
+	return r
+}
+
+// ConditionGroupMethod generates Or and Not methods: they group conditions
+// added by functions of querysets
+type ConditionGroupMethod struct {
+	namedMethod
+	chainedQuerySetMethod
+	oneArgMethod
+	constBodyMethod
+}
+
+// branchConditionsTmpl gets WHERE conditions of branch of queryset: branch gets
+// new unscoped queryset not to repeat soft delete condition in the group
+const branchConditionsTmpl = `sql, vars := %[1]s(qs.w(func(db *gorm.DB) *gorm.DB {
+			return db.New().Unscoped()
+		})).rawSQL("%%[2]s")
+		sql = strings.TrimPrefix(strings.TrimSpace(sql), "WHERE ")
+		if sql == "" {
+			sql = "1 = 1" // no conditions
+		}`
+
+// NewOrMethod creates Or method: it adds conditions of branches joined by OR
+func NewOrMethod(qsTypeName string) ConditionGroupMethod {
+	const tmpl = `if len(branches) == 0 {
+		return qs
+	}
+
+	var conds []string
+	var args []interface{}
+	for _, branch := range branches {
+		%s
+		conds = append(conds, "("+sql+")")
+		args = append(args, vars...)
+	}
+	return qs.w(func(db *gorm.DB) *gorm.DB {
+		return db.Where(strings.Join(conds, " OR "), args...)
+	})`
+
+	r := ConditionGroupMethod{
+		namedMethod:           newNamedMethod("Or"),
+		chainedQuerySetMethod: newChainedQuerySetMethod(qsTypeName),
+		oneArgMethod:          newOneArgMethod("branches", fmt.Sprintf("...func(qs %s) %s", qsTypeName, qsTypeName)),
+		constBodyMethod: newConstBodyMethod(tmpl,
+			fmt.Sprintf(branchConditionsTmpl, "branch")),
+	}
+	r.setDoc(`// Or adds group of conditions of branches joined by OR: every branch adds
+	// filters to passed queryset, e.g. qs.Or(func(qs UserQuerySet) UserQuerySet {
+	// return qs.NameEq(name) }, ...). Branches must add only filters.`)
+	return r
+}
+
+// NewNotMethod creates Not method: it adds negation of conditions of branch
+func NewNotMethod(qsTypeName string) ConditionGroupMethod {
+	const tmpl = `%s
+	return qs.w(func(db *gorm.DB) *gorm.DB {
+		return db.Where("NOT ("+sql+")", vars...)
+	})`
+
+	r := ConditionGroupMethod{
+		namedMethod:           newNamedMethod("Not"),
+		chainedQuerySetMethod: newChainedQuerySetMethod(qsTypeName),
+		oneArgMethod:          newOneArgMethod("branch", fmt.Sprintf("func(qs %s) %s", qsTypeName, qsTypeName)),
+		constBodyMethod: newConstBodyMethod(tmpl,
+			fmt.Sprintf(branchConditionsTmpl, "branch")),
+	}
+	r.setDoc(`// Not adds negation of conditions added by branch to passed queryset.
+	// Branch must add only filters.`)
+	return r
+}
+
+// ScopeMethod generates Scope method
+type ScopeMethod struct {
+	namedMethod
+	chainedQuerySetMethod
+	oneArgMethod
+	constBodyMethod
+}
+
+// NewScopeMethod creates Scope method: it applies scope functions in order
+func NewScopeMethod(qsTypeName string) ScopeMethod {
+	r := ScopeMethod{
+		namedMethod:           newNamedMethod("Scope"),
+		chainedQuerySetMethod: newChainedQuerySetMethod(qsTypeName),
+		oneArgMethod:          newOneArgMethod("scopes", fmt.Sprintf("...func(qs %s) %s", qsTypeName, qsTypeName)),
+		constBodyMethod: newConstBodyMethod(`for _, scope := range scopes {
+				%[1]s = scope(%[1]s)
+			}
+			return %[1]s`, qsReceiverName),
+	}
+	r.setDoc(`// Scope applies scopes to queryset in order: scope is a reusable combination
+	// of filters, e.g. func ActiveUsers(qs UserQuerySet) UserQuerySet declared next
+	// to model`)
+	return r
+}
+
+// AllMethod generates All method
+type AllMethod struct {
+	SelectMethod
+}
+
+// GetBody returns method's body: number of rows is checked against limit
+// of FailIfMoreThan, ret is reset if it's exceeded
+func (m AllMethod) GetBody() string {
+	return fmt.Sprintf(`return %[1]s.memoize(%[2]q, ret, func() error {
+		if err := %[3]s.Find(ret).Error; err != nil {
+			return err
+		}
+		if err := %[1]s.checkRowsNum(len(*ret), true); err != nil {
+			*ret = nil
+			return err
+		}
+		return nil
+	})`, qsReceiverName, m.GetMethodName(), qsDbName)
 }
 
 // NewAllMethod creates All method
-func NewAllMethod(structName, qsTypeName string) SelectMethod {
-	return newSelectMethod("All", "Find", fmt.Sprintf("*[]%s", structName), qsTypeName)
+func NewAllMethod(structName, qsTypeName string) AllMethod {
+	return AllMethod{
+		SelectMethod: newSelectMethod("All", "Find", fmt.Sprintf("*[]%s", structName), qsTypeName),
+	}
+}
+
+// OneMethod generates One method
+type OneMethod struct {
+	SelectMethod
+	take bool
+}
+
+// GetBody returns method's body: number of matched rows is checked against
+// limit of FailIfMoreThan before loading
+func (m OneMethod) GetBody() string {
+	load := qsDbName + ".First(ret)"
+	if m.take {
+		// Find doesn't limit rows: LIMIT 1 is set by Limit of queryset
+		load = qsReceiverName + ".Limit(1).db.Find(ret)"
+	}
+	return fmt.Sprintf(`return %[1]s.memoize(%[2]q, ret, func() error {
+		if err := %[1]s.checkMatchedNum(); err != nil {
+			return err
+		}
+		return %[3]s.Error
+	})`, qsReceiverName, m.GetMethodName(), load)
 }
 
-// NewOneMethod creates One method
-func NewOneMethod(structName, qsTypeName string) SelectMethod {
-	r := newSelectMethod("One", "First", fmt.Sprintf("*%s", structName), qsTypeName)
-	const doc = `// One is used to retrieve one result. It returns gorm.ErrRecordNotFound
-	// if nothing was fetched`
+// NewOneMethod creates One method: it selects the first row ordered by
+// primary key or, if take is true, any matching row without ordering
+func NewOneMethod(structName, qsTypeName string, take bool) OneMethod {
+	r := OneMethod{
+		SelectMethod: newSelectMethod("One", "First", fmt.Sprintf("*%s", structName), qsTypeName),
+		take:         take,
+	}
+	doc := `// One is used to retrieve one result: the first one ordered by primary key,
+	// query has LIMIT 1. It returns gorm.ErrRecordNotFound if nothing was fetched`
+	if take {
+		doc = `// One is used to retrieve one result: any matching one, query has LIMIT 1
+	// without ordering by primary key. It returns gorm.ErrRecordNotFound if nothing
+	// was fetched`
+	}
 	r.setDoc(doc)
 	return r
 }
 
+// ExactlyOneMethod generates ExactlyOne method
+type ExactlyOneMethod struct {
+	namedMethod
+	baseQuerySetMethod
+	oneArgMethod
+	errorRetMethod
+	constBodyMethod
+}
+
+// NewExactlyOneMethod creates ExactlyOne method: it selects up to two records
+// to detect that more than one record matches
+func NewExactlyOneMethod(ctx QsStructContext) ExactlyOneMethod {
+	r := ExactlyOneMethod{
+		namedMethod:        newNamedMethod("ExactlyOne"),
+		baseQuerySetMethod: newBaseQuerySetMethod(ctx.qsTypeName()),
+		oneArgMethod:       newOneArgMethod("ret", "*"+ctx.s.TypeName),
+		constBodyMethod: newConstBodyMethod(`return %[1]s.memoize("ExactlyOne", ret, func() error {
+				if err := %[1]s.checkMatchedNum(); err != nil {
+					return err
+				}
+
+				var rows []%[2]s
+				if err := %[3]s.Limit(2).Find(&rows).Error; err != nil {
+					return err
+				}
+
+				switch len(rows) {
+				case 0:
+					return gorm.ErrRecordNotFound
+				case 1:
+					*ret = rows[0]
+					return nil
+				}
+				return ErrMultipleRecords
+			})`, qsReceiverName, ctx.s.TypeName, qsDbName),
+	}
+	r.setDoc(`// ExactlyOne is used to retrieve the only result. It returns gorm.ErrRecordNotFound
+	// if nothing was fetched and ErrMultipleRecords if more than one record matches:
+	// unlike One it doesn't hide violations of uniqueness`)
+	return r
+}
+
+// ValueSelectMethod generates First and Last methods
+type ValueSelectMethod struct {
+	namedMethod
+	baseQuerySetMethod
+	noArgsMethod
+	constRetMethod
+	constBodyMethod
+}
+
+func newValueSelectMethod(ctx QsStructContext, name string) ValueSelectMethod {
+	return ValueSelectMethod{
+		namedMethod:        newNamedMethod(name),
+		baseQuerySetMethod: newBaseQuerySetMethod(ctx.qsTypeName()),
+		constRetMethod:     newConstRetMethod(fmt.Sprintf("(%s, error)", ctx.s.TypeName)),
+		constBodyMethod: newConstBodyMethod(`var ret %[1]s
+			err := %[2]s.memoize(%[3]q, &ret, func() error {
+				if err := %[2]s.checkMatchedNum(); err != nil {
+					return err
+				}
+				return %[4]s.%[3]s(&ret).Error
+			})
+			return ret, err`, ctx.s.TypeName, qsReceiverName, name, qsDbName),
+	}
+}
+
+// NewFirstMethod creates First method: it's One returning result by value
+func NewFirstMethod(ctx QsStructContext) ValueSelectMethod {
+	r := newValueSelectMethod(ctx, "First")
+	r.setDoc(`// First returns the first result ordered by primary key. It returns
+	// gorm.ErrRecordNotFound if nothing was fetched`)
+	return r
+}
+
+// NewLastMethod creates Last method
+func NewLastMethod(ctx QsStructContext) ValueSelectMethod {
+	r := newValueSelectMethod(ctx, "Last")
+	r.setDoc(`// Last returns the last result ordered by primary key. It returns
+	// gorm.ErrRecordNotFound if nothing was fetched`)
+	return r
+}
+
+// IterateMethod generates Iterate method
+type IterateMethod struct {
+	namedMethod
+	baseQuerySetMethod
+	oneArgMethod
+	errorRetMethod
+	constBodyMethod
+}
+
+// NewIterateMethod creates Iterate method: it streams rows of queryset one
+// at a time instead of loading all of them like All
+func NewIterateMethod(ctx QsStructContext) IterateMethod {
+	const tmpl = `if err := %[4]s.checkMatchedNum(); err != nil {
+		return err
+	}
+
+	var rows *sql.Rows
+	err := %[3]s(%[1]s, func() (err error) {
+		rows, err = %[1]s.Rows()
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var o %[2]s
+		if err = %[1]s.ScanRows(rows, &o); err != nil {
+			return err
+		}
+		if err = fn(o); err != nil {
+			return err
+		}
+	}
+	return rows.Err()`
+
+	r := IterateMethod{
+		namedMethod:        newNamedMethod("Iterate"),
+		baseQuerySetMethod: newBaseQuerySetMethod(ctx.qsTypeName()),
+		oneArgMethod:       newOneArgMethod("fn", fmt.Sprintf("func(o %s) error", ctx.s.TypeName)),
+		constBodyMethod:    newConstBodyMethod(tmpl, qsDbName, ctx.s.TypeName, ctx.breakerCallName(), qsReceiverName),
+	}
+	r.setDoc(`// Iterate streams rows of queryset one at a time into fn: memory usage
+	// doesn't depend on number of rows. Relations aren't preloaded and AfterFind
+	// hooks aren't called. Iteration stops on the first error returned by fn.`)
+	return r
+}
+
+// PluckMethod generates Pluck<Field> method
+type PluckMethod struct {
+	namedMethod
+	baseQuerySetMethod
+	noArgsMethod
+	constRetMethod
+	constBodyMethod
+}
+
+// NewPluckMethod creates Pluck<Field> method: it selects only column of field
+// into slice instead of loading full models
+func NewPluckMethod(ctx QsFieldContext) PluckMethod {
+	r := PluckMethod{
+		namedMethod:        newNamedMethod("Pluck" + ctx.fieldName()),
+		baseQuerySetMethod: newBaseQuerySetMethod(ctx.qsTypeName()),
+		constRetMethod:     newConstRetMethod(fmt.Sprintf("([]%s, error)", ctx.fieldTypeName())),
+		constBodyMethod: newConstBodyMethod(`var ret []%[1]s
+			err := %[4]s(%[2]s, func() error {
+				return %[2]s.Pluck(%[3]s, &ret).Error
+			})
+			if err != nil {
+				return nil, err
+			}
+			if err = %[5]s.checkRowsNum(len(ret), true); err != nil {
+				return nil, err
+			}
+			return ret, nil`, ctx.fieldTypeName(), qsDbName,
+			strconv.Quote(ctx.quotedFieldDBName()), ctx.breakerCallName(), qsReceiverName),
+	}
+	r.setDoc(fmt.Sprintf(`// %s selects %s column of queryset's rows`, r.GetMethodName(), ctx.fieldDBName()))
+	return r
+}
+
 // NewIsNullMethod create IsNull method
 func NewIsNullMethod(ctx QsFieldContext) UnaryFilterMethod {
 	return newUnaryFilterMethod(ctx.WithOperationName("IsNull"), "IS NULL")
@@ -458,3 +1307,148 @@ func NewIsNullMethod(ctx QsFieldContext) UnaryFilterMethod {
 func NewIsNotNullMethod(ctx QsFieldContext) UnaryFilterMethod {
 	return newUnaryFilterMethod(ctx.WithOperationName("IsNotNull"), "IS NOT NULL")
 }
+
+// EqNullableMethod is a null-safe equality filter of pointer field
+type EqNullableMethod struct {
+	chainedQuerySetMethod
+	onFieldMethod
+	oneArgMethod
+	constBodyMethod
+}
+
+// NewEqNullableMethod creates <Field>EqNullable method of pointer field: it
+// calls <Field>IsNull for nil argument and <Field>Eq otherwise
+func NewEqNullableMethod(ctx QsFieldContext) EqNullableMethod {
+	argName := fieldNameToArgName(ctx.fieldName())
+	r := EqNullableMethod{
+		onFieldMethod:         ctx.WithOperationName("EqNullable").onFieldMethod(),
+		oneArgMethod:          newOneArgMethod(argName, ctx.fieldTypeName()),
+		chainedQuerySetMethod: ctx.chainedQuerySetMethod(),
+		constBodyMethod: newConstBodyMethod(`if %[1]s == nil {
+			return %[2]s.%[3]s()
+		}
+		return %[2]s.%[4]s(*%[1]s)`, argName, qsReceiverName, ctx.n.FilterName(ctx.fieldName(), "IsNull"),
+			ctx.n.FilterName(ctx.fieldName(), "Eq")),
+	}
+	r.setDoc(fmt.Sprintf(`// %s filters by %s IS NULL if %s is nil and by equality
+	// to value of %s otherwise`, r.GetMethodName(), ctx.fieldName(), argName, argName))
+	return r
+}
+
+// EqIfSetMethod is an equality filter skipped for zero value of argument
+type EqIfSetMethod struct {
+	chainedQuerySetMethod
+	onFieldMethod
+	oneArgMethod
+	constBodyMethod
+}
+
+// NewEqIfSetMethod creates <Field>EqIfSet method of string or numeric field:
+// it calls <Field>Eq only if argument isn't zero value, e.g. for optional
+// fields of search forms
+func NewEqIfSetMethod(ctx QsFieldContext) EqIfSetMethod {
+	argName := fieldNameToArgName(ctx.fieldName())
+	r := EqIfSetMethod{
+		onFieldMethod:         ctx.WithOperationName("EqIfSet").onFieldMethod(),
+		oneArgMethod:          newOneArgMethod(argName, ctx.fieldTypeName()),
+		chainedQuerySetMethod: ctx.chainedQuerySetMethod(),
+		constBodyMethod:       newConstBodyMethod("%s", eqIfSetBody(ctx.n, ctx.f, argName)),
+	}
+	r.setDoc(fmt.Sprintf(`// %s filters by %s equal to %s if %s isn't zero value,
+	// otherwise queryset isn't changed`, r.GetMethodName(), ctx.fieldName(), argName, argName))
+	return r
+}
+
+// eqIfSetBody returns body of <Field>EqIfSet method of real or fake queryset
+func eqIfSetBody(n Naming, f field.Info, argName string) string {
+	zero := argName + " == 0"
+	switch {
+	case f.IsTime || f.IsDecimal:
+		zero = argName + ".IsZero()"
+	case f.IsString:
+		zero = argName + ` == ""`
+	}
+	return fmt.Sprintf(`if %s {
+		return %s
+	}
+	return %s.%s(%s)`, zero, qsReceiverName, qsReceiverName, n.FilterName(f.Name, "Eq"), argName)
+}
+
+// NewIsTrueMethod creates IsTrue method of bool field
+func NewIsTrueMethod(ctx QsFieldContext) UnaryFilterMethod {
+	return newBoolFilterMethod(ctx.WithOperationName("IsTrue"), true)
+}
+
+// NewIsFalseMethod creates IsFalse method of bool field
+func NewIsFalseMethod(ctx QsFieldContext) UnaryFilterMethod {
+	return newBoolFilterMethod(ctx.WithOperationName("IsFalse"), false)
+}
+
+// newBoolFilterMethod creates filter by constant value of bool field: value
+// is passed as arg because dialects spell bool literals differently
+func newBoolFilterMethod(ctx QsFieldContext, value bool) UnaryFilterMethod {
+	r := UnaryFilterMethod{
+		onFieldMethod:         ctx.onFieldMethod(),
+		chainedQuerySetMethod: ctx.chainedQuerySetMethod(),
+		qsCallGormMethod: newQsCallGormMethod("Where", "%s, %t",
+			strconv.Quote(ctx.quotedFieldDBName()+" = ?"), value),
+	}
+	r.setDoc(fmt.Sprintf(`// %s filters by %s equal to %t`, r.GetMethodName(), ctx.fieldName(), value))
+	return r
+}
+
+// NewEnumEqMethod creates Eq<Value> method of field of named type: it filters
+// by constant v of the type
+func NewEnumEqMethod(ctx QsFieldContext, v field.EnumValue) UnaryFilterMethod {
+	ctx = ctx.WithOperationName("Eq" + v.Name)
+	r := UnaryFilterMethod{
+		onFieldMethod:         ctx.onFieldMethod(),
+		chainedQuerySetMethod: ctx.chainedQuerySetMethod(),
+		qsCallGormMethod: newQsCallGormMethod("Where", "%s, %s",
+			strconv.Quote(ctx.quotedFieldDBName()+" = ?"), v.Const),
+	}
+	r.setDoc(fmt.Sprintf(`// %s filters by %s equal to %s`, r.GetMethodName(), ctx.fieldName(), v.Const))
+	return r
+}
+
+// LockMethod generates method locking selected rows
+type LockMethod struct {
+	namedMethod
+	chainedQuerySetMethod
+	noArgsMethod
+	constBodyMethod
+}
+
+// NewForUpdateMethod creates ForUpdate method
+func NewForUpdateMethod(ctx QsStructContext) LockMethod {
+	r := newLockMethod(ctx, "ForUpdate", ctx.Dialect().ForUpdate())
+	r.setDoc(`// ForUpdate locks selected rows for update until the end of transaction:
+	// use it in transaction for read-modify-write flows`)
+	return r
+}
+
+// NewForShareMethod creates ForShare method
+func NewForShareMethod(ctx QsStructContext) LockMethod {
+	r := newLockMethod(ctx, "ForShare", ctx.Dialect().ForShare())
+	r.setDoc(`// ForShare locks selected rows against concurrent updates until the end
+	// of transaction, but doesn't block other readers`)
+	return r
+}
+
+// NewForUpdateSkipLockedMethod creates ForUpdateSkipLocked method
+func NewForUpdateSkipLockedMethod(ctx QsStructContext) LockMethod {
+	r := newLockMethod(ctx, "ForUpdateSkipLocked", ctx.Dialect().ForUpdateSkipLocked())
+	r.setDoc(`// ForUpdateSkipLocked locks selected rows for update like ForUpdate, but rows
+	// locked by concurrent transactions are skipped instead of waiting for them:
+	// use it to distribute rows between concurrent workers`)
+	return r
+}
+
+func newLockMethod(ctx QsStructContext, name, clause string) LockMethod {
+	return LockMethod{
+		namedMethod:           newNamedMethod(name),
+		chainedQuerySetMethod: newChainedQuerySetMethod(ctx.qsTypeName()),
+		constBodyMethod: newConstBodyMethod("%s",
+			wrapToGormScope(fmt.Sprintf(`%s.Set("gorm:query_option", %q)`, qsDbName, clause))),
+	}
+}
@@ -0,0 +1,46 @@
+package methods
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/jirfag/go-queryset/queryset/field"
+)
+
+// SampleWeightedMethod generates SampleWeighted method
+type SampleWeightedMethod struct {
+	baseQuerySetMethod
+	namedMethod
+	oneArgMethod
+	constRetMethod
+	constBodyMethod
+}
+
+// NewSampleWeightedMethod creates SampleWeighted method: it selects random
+// rows with probability proportional to weight field f. Rows are ordered by
+// random keys -ln(u)/weight: n rows with the least keys are a weighted sample
+// without replacement.
+func NewSampleWeightedMethod(ctx QsStructContext, f field.Info) SampleWeightedMethod {
+	d := ctx.Dialect()
+	weight := d.Quote(f.DBName)
+	r := SampleWeightedMethod{
+		baseQuerySetMethod: newBaseQuerySetMethod(ctx.qsTypeName()),
+		namedMethod:        newNamedMethod("SampleWeighted"),
+		oneArgMethod:       newOneArgMethod("n", "int"),
+		constRetMethod:     newConstRetMethod(fmt.Sprintf("([]%s, error)", ctx.s.TypeName)),
+		constBodyMethod: newConstBodyMethod(`if err := %s.checkMatchedNum(); err != nil {
+				return nil, err
+			}
+
+			var ret []%s
+			err := %s(%s, func() error {
+				return %s.Where(%s).Order(%s, true).Limit(n).Find(&ret).Error
+			})
+			return ret, err`, qsReceiverName, ctx.s.TypeName, ctx.breakerCallName(), qsDbName, qsDbName,
+			strconv.Quote(weight+" > 0"), strconv.Quote(fmt.Sprintf(d.WeightedRandomKey(), weight))),
+	}
+	r.setDoc(fmt.Sprintf(`// SampleWeighted returns n random rows of queryset: probability of row to be
+	// sampled is proportional to %s, rows without positive %s aren't sampled.
+	// Order and limit of queryset are ignored.`, f.Name, f.Name))
+	return r
+}
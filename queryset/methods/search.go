@@ -0,0 +1,270 @@
+package methods
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jirfag/go-queryset/queryset/field"
+)
+
+const searchDocTypeName = "map[string]interface{}"
+
+// ToSearchDocumentMethod generates ToSearchDocument method: it converts
+// struct into flat document for search engines indexing
+type ToSearchDocumentMethod struct {
+	namedMethod
+	structMethod
+	oneArgMethod
+	constRetMethod
+	constBodyMethod
+}
+
+// searchRelationTypeName returns type name of related struct if field f
+// is a relation to a struct with generated ToSearchDocument method
+func searchRelationTypeName(f field.Info, searchableStructs map[string]bool) string {
+	if f.IsStruct && searchableStructs[f.TypeName] {
+		return f.TypeName
+	}
+
+	if f.IsPointer {
+		p := f.GetPointed()
+		if p.IsStruct && searchableStructs[p.TypeName] {
+			return p.TypeName
+		}
+	}
+
+	return ""
+}
+
+// NewToSearchDocumentMethod creates ToSearchDocument method. Fields of
+// preloaded relations are flattened into the document with "relation." prefix.
+func NewToSearchDocumentMethod(ctx QsStructContext, fields []field.Info,
+	searchableStructs map[string]bool) ToSearchDocumentMethod {
+
+	fieldTypeName := ctx.dbSchemaFieldTypeName()
+	lines := []string{
+		fmt.Sprintf("selected := map[%s]bool{}", fieldTypeName),
+		"for _, f := range fields {",
+		"selected[f] = true",
+		"}",
+		fmt.Sprintf("isSelected := func(f %s) bool {", fieldTypeName),
+		"return len(selected) == 0 || selected[f]",
+		"}",
+		"",
+		fmt.Sprintf("doc := %s{}", searchDocTypeName),
+	}
+
+	for _, f := range fields {
+		schemaField := fmt.Sprintf("%s.%s", ctx.dbSchemaTypeName(), f.Name)
+		if isRelationField(f) {
+			if searchRelationTypeName(f, searchableStructs) == "" {
+				continue // relation without search document
+			}
+
+			cond := fmt.Sprintf("isSelected(%s)", schemaField)
+			if f.IsPointer {
+				cond = fmt.Sprintf("o.%s != nil && %s", f.Name, cond)
+			}
+			lines = append(lines,
+				fmt.Sprintf("if %s {", cond),
+				fmt.Sprintf("for k, v := range o.%s.ToSearchDocument() {", f.Name),
+				fmt.Sprintf(`doc[string(%s)+"."+k] = v`, schemaField),
+				"}",
+				"}")
+			continue
+		}
+
+		if f.IsSQLNull() { // NULL is nil like nil pointer
+			lines = append(lines,
+				fmt.Sprintf("if isSelected(%s) {", schemaField),
+				fmt.Sprintf("doc[string(%s)] = nil", schemaField),
+				fmt.Sprintf("if o.%s.Valid {", f.Name),
+				fmt.Sprintf("doc[string(%s)] = o.%s.%s", schemaField, f.Name, f.SQLNullValue),
+				"}",
+				"}")
+			continue
+		}
+
+		lines = append(lines,
+			fmt.Sprintf("if isSelected(%s) {", schemaField),
+			fmt.Sprintf("doc[string(%s)] = o.%s", schemaField, f.Name),
+			"}")
+	}
+	lines = append(lines, "", "return doc")
+
+	r := ToSearchDocumentMethod{
+		namedMethod:     newNamedMethod("ToSearchDocument"),
+		structMethod:    newStructMethod("o", "*"+ctx.s.TypeName),
+		oneArgMethod:    newOneArgMethod("fields", "..."+fieldTypeName),
+		constRetMethod:  newConstRetMethod(searchDocTypeName),
+		constBodyMethod: newConstBodyMethod("%s", strings.Join(lines, "\n")),
+	}
+	r.setDoc(`// ToSearchDocument converts object into flat document for search indexing.
+	// Only passed fields are included; all fields are included if none were passed.`)
+	return r
+}
+
+// ReindexAllMethod generates ReindexAll method
+type ReindexAllMethod struct {
+	baseQuerySetMethod
+	namedMethod
+	nArgsMethod
+	errorRetMethod
+	constBodyMethod
+}
+
+// NewReindexAllMethod creates ReindexAll method: it walks over all records of
+// queryset in batches ordered by primary key pk and passes search document
+// of every record to callback
+func NewReindexAllMethod(ctx QsStructContext, pk field.Info) ReindexAllMethod {
+	const tmpl = `if batchSize < 1 {
+		return fmt.Errorf("invalid batch size %%d", batchSize)
+	}
+	if err := %s.checkMatchedNum(); err != nil {
+		return err
+	}
+
+	var lastPK %s
+	for {
+		var batch []%s
+		err := %s.Where(%s, lastPK).Order(%s).Limit(batchSize).Find(&batch).Error
+		if err != nil {
+			return err
+		}
+
+		for i := range batch {
+			if err = fn(batch[i].ToSearchDocument(fields...)); err != nil {
+				return err
+			}
+		}
+
+		if len(batch) < batchSize {
+			return nil
+		}
+		lastPK = batch[len(batch)-1].%s
+	}`
+
+	quotedPK := ctx.Dialect().Quote(pk.DBName)
+	r := ReindexAllMethod{
+		baseQuerySetMethod: newBaseQuerySetMethod(ctx.qsTypeName()),
+		namedMethod:        newNamedMethod("ReindexAll"),
+		nArgsMethod: newNArgsMethod(
+			newOneArgMethod("batchSize", "int"),
+			newOneArgMethod("fn", fmt.Sprintf("func(doc %s) error", searchDocTypeName)),
+			newOneArgMethod("fields", "..."+ctx.dbSchemaFieldTypeName()),
+		),
+		constBodyMethod: newConstBodyMethod(tmpl, qsReceiverName, pk.TypeName, ctx.s.TypeName, qsDbName,
+			strconv.Quote(quotedPK+" > ?"), strconv.Quote(quotedPK+" ASC"), pk.Name),
+	}
+	r.setDoc(`// ReindexAll walks over all records of queryset in batches of batchSize
+	// ordered by primary key and passes search document of every record to fn.
+	// Limit of FailIfMoreThan is checked before the first batch. batchSize must
+	// be positive.`)
+	return r
+}
+
+// SearchBackedFilterMethod generates <Field>Search method: it filters
+// queryset by primary keys of records found by external search engine
+type SearchBackedFilterMethod struct {
+	onFieldMethod
+	nArgsMethod
+	baseQuerySetMethod
+	constRetMethod
+	constBodyMethod
+}
+
+// NewSearchBackedFilterMethod creates <Field>Search method for search-backed field
+func NewSearchBackedFilterMethod(ctx QsFieldContext, pk field.Info) SearchBackedFilterMethod {
+	ctx = ctx.WithOperationName("search")
+	const tmpl = `ids, err := client.SearchIDs(string(%s.%s), query)
+	if err != nil {
+		return qs, fmt.Errorf("can't search %s by %%s: %%s", %s.%s, err)
+	}
+
+	if len(ids) == 0 {
+		return qs.w(func(db *gorm.DB) *gorm.DB {
+			return %s.Where("1 = 0") // nothing was found
+		}), nil
+	}
+
+	return qs.%s(ids[0], ids[1:]...), nil`
+
+	r := SearchBackedFilterMethod{
+		onFieldMethod: ctx.onFieldMethod(),
+		nArgsMethod: newNArgsMethod(
+			newOneArgMethod("client", ctx.s.TypeName+"SearchClient"),
+			newOneArgMethod("query", "string"),
+		),
+		baseQuerySetMethod: newBaseQuerySetMethod(ctx.qsTypeName()),
+		constRetMethod:     newConstRetMethod(fmt.Sprintf("(%s, error)", ctx.qsTypeName())),
+		constBodyMethod: newConstBodyMethod(tmpl,
+			ctx.dbSchemaTypeName(), ctx.fieldName(), ctx.s.TypeName,
+			ctx.dbSchemaTypeName(), ctx.fieldName(), qsOpDbName, ctx.n.FilterName(pk.Name, "In")),
+	}
+	r.setDoc(fmt.Sprintf(`// %s filters by primary keys of records, which field %s
+	// matches query in external search engine`, r.GetMethodName(), ctx.fieldName()))
+	return r
+}
+
+// FullTextSearchMethod generates Search<Field> and Search methods
+type FullTextSearchMethod struct {
+	namedMethod
+	chainedQuerySetMethod
+	oneArgMethod
+	qsCallGormMethod
+
+	configCall *qsCallGormMethod // match in text search config of locale
+}
+
+// GetBody returns method's body: text search config of locale of queryset
+// is used if it's set
+func (m FullTextSearchMethod) GetBody() string {
+	if m.configCall == nil {
+		return m.qsCallGormMethod.GetBody()
+	}
+
+	return fmt.Sprintf(`if config := %s.locale().SearchConfig; config != "" {
+		%s
+	}
+	%s`, qsReceiverName, m.configCall.GetBody(), m.qsCallGormMethod.GetBody())
+}
+
+// NewFieldFullTextSearchMethod creates Search<Field> method: it filters by
+// full-text match of query in field f. Localized method matches in text
+// search config of locale of queryset.
+func NewFieldFullTextSearchMethod(ctx QsStructContext, f field.Info, localized bool) FullTextSearchMethod {
+	return newFullTextSearchMethod(ctx, "Search"+f.Name, []field.Info{f}, localized)
+}
+
+// NewFullTextSearchMethod creates Search method: it filters by full-text
+// match of query in text of all fields
+func NewFullTextSearchMethod(ctx QsStructContext, fields []field.Info, localized bool) FullTextSearchMethod {
+	return newFullTextSearchMethod(ctx, "Search", fields, localized)
+}
+
+func newFullTextSearchMethod(ctx QsStructContext, name string, fields []field.Info,
+	localized bool) FullTextSearchMethod {
+
+	var columns, names []string
+	for _, f := range fields {
+		columns = append(columns, ctx.Dialect().Quote(f.DBName))
+		names = append(names, f.Name)
+	}
+
+	r := FullTextSearchMethod{
+		namedMethod:           newNamedMethod(name),
+		chainedQuerySetMethod: newChainedQuerySetMethod(ctx.qsTypeName()),
+		oneArgMethod:          newOneArgMethod("query", "string"),
+		qsCallGormMethod: newQsCallGormMethod("Where", "%s, query",
+			strconv.Quote(fmt.Sprintf(ctx.Dialect().FullTextMatch(), strings.Join(columns, ", ")))),
+	}
+	if match := ctx.Dialect().FullTextMatchConfig(); localized && match != "" {
+		call := newQsCallGormMethod("Where", "%s, config, config, query",
+			strconv.Quote(fmt.Sprintf(match, strings.Join(columns, ", "))))
+		r.configCall = &call
+	}
+	r.setDoc(fmt.Sprintf(`// %s filters by full-text match of query in natural language
+	// in %s`, name, strings.Join(names, ", ")))
+	return r
+}
@@ -0,0 +1,88 @@
+package methods
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// SoftDeleteScopeMethod generates method changing soft deleted records scope
+type SoftDeleteScopeMethod struct {
+	namedMethod
+	chainedQuerySetMethod
+	noArgsMethod
+	constBodyMethod
+}
+
+// NewWithDeletedMethod creates WithDeleted method: it includes soft deleted
+// records like gorm's Unscoped
+func NewWithDeletedMethod(ctx QsStructContext) SoftDeleteScopeMethod {
+	r := SoftDeleteScopeMethod{
+		namedMethod:           newNamedMethod("WithDeleted"),
+		chainedQuerySetMethod: newChainedQuerySetMethod(ctx.qsTypeName()),
+		constBodyMethod:       newConstBodyMethod("%s", wrapToGormScope(qsDbName+".Unscoped()")),
+	}
+	r.setDoc(`// WithDeleted includes soft deleted records. Delete of such queryset
+	// deletes records permanently, use SoftDelete to mark them as deleted.`)
+	return r
+}
+
+// NewDeletedOnlyMethod creates DeletedOnly method: it selects only soft
+// deleted records
+func NewDeletedOnlyMethod(ctx QsStructContext) SoftDeleteScopeMethod {
+	cond := strconv.Quote(ctx.Dialect().Quote("deleted_at") + " IS NOT NULL")
+	r := SoftDeleteScopeMethod{
+		namedMethod:           newNamedMethod("DeletedOnly"),
+		chainedQuerySetMethod: newChainedQuerySetMethod(ctx.qsTypeName()),
+		constBodyMethod: newConstBodyMethod("%s",
+			wrapToGormScope(fmt.Sprintf("%s.Unscoped().Where(%s)", qsDbName, cond))),
+	}
+	r.setDoc(`// DeletedOnly selects only soft deleted records`)
+	return r
+}
+
+// SoftDeleteMethod generates SoftDelete method
+type SoftDeleteMethod struct {
+	namedMethod
+	baseQuerySetMethod
+	noArgsMethod
+	errorRetMethod
+	constBodyMethod
+}
+
+// NewSoftDeleteMethod creates SoftDelete method: it marks records as deleted
+// even if queryset includes soft deleted records
+func NewSoftDeleteMethod(ctx QsStructContext) SoftDeleteMethod {
+	r := SoftDeleteMethod{
+		namedMethod:        newNamedMethod("SoftDelete"),
+		baseQuerySetMethod: newBaseQuerySetMethod(ctx.qsTypeName()),
+		constBodyMethod: newConstBodyMethod(`return %s.UpdateColumn("deleted_at", gorm.NowFunc()).Error`,
+			qsDbName),
+	}
+	r.setDoc(`// SoftDelete marks records as deleted by setting DeletedAt: unlike Delete
+	// it never deletes records permanently`)
+	return r
+}
+
+// SoftDeleteNumMethod generates SoftDeleteNum method
+type SoftDeleteNumMethod struct {
+	namedMethod
+	baseQuerySetMethod
+	noArgsMethod
+	constRetMethod
+	constBodyMethod
+}
+
+// NewSoftDeleteNumMethod creates SoftDeleteNum method: it's SoftDelete
+// returning number of marked records
+func NewSoftDeleteNumMethod(ctx QsStructContext) SoftDeleteNumMethod {
+	r := SoftDeleteNumMethod{
+		namedMethod:        newNamedMethod("SoftDeleteNum"),
+		baseQuerySetMethod: newBaseQuerySetMethod(ctx.qsTypeName()),
+		constRetMethod:     newConstRetMethod("(int64, error)"),
+		constBodyMethod: newConstBodyMethod(`db := %s.UpdateColumn("deleted_at", gorm.NowFunc())
+			return db.RowsAffected, db.Error`, qsDbName),
+	}
+	r.setDoc(`// SoftDeleteNum marks records as deleted like SoftDelete and returns
+	// number of marked records`)
+	return r
+}
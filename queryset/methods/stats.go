@@ -0,0 +1,117 @@
+package methods
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jirfag/go-queryset/queryset/field"
+)
+
+// StatsField is a field of <Struct>Stats type
+type StatsField struct {
+	Name     string
+	TypeName string
+}
+
+// statsFields returns fields with statistics: timestamps (min and max are
+// computed) and enums (rows are counted by values). Enum fields are returned
+// with info of pointed value for pointers. DeletedAt is skipped: it's always
+// NULL in rows of default soft delete scope.
+func statsFields(fields []field.Info) (times, enums []field.Info) {
+	for _, f := range fields {
+		v := f
+		if f.IsPointer || f.IsSQLNull() {
+			v = f.GetPointed()
+			v.Name, v.DBName = f.Name, f.DBName
+		}
+
+		switch {
+		case v.IsTime && f.Name != "DeletedAt":
+			times = append(times, f)
+		case len(v.EnumValues) != 0:
+			enums = append(enums, v)
+		}
+	}
+	return times, enums
+}
+
+// GetStatsFields returns fields of <Struct>Stats type for struct fields:
+// number of rows, min and max of timestamps and numbers of rows by enum values
+func GetStatsFields(fields []field.Info) []StatsField {
+	ret := []StatsField{{Name: "Count", TypeName: "int"}}
+	times, enums := statsFields(fields)
+	for _, f := range times {
+		ret = append(ret,
+			StatsField{Name: "Min" + f.Name, TypeName: "*time.Time"},
+			StatsField{Name: "Max" + f.Name, TypeName: "*time.Time"})
+	}
+	for _, f := range enums {
+		ret = append(ret, StatsField{Name: f.Name + "Counts", TypeName: fmt.Sprintf("map[%s]int", f.TypeName)})
+	}
+	return ret
+}
+
+// StatsMethod generates Stats method
+type StatsMethod struct {
+	namedMethod
+	baseQuerySetMethod
+	noArgsMethod
+	constRetMethod
+	constBodyMethod
+}
+
+// NewStatsMethod creates Stats method: it selects statistics of rows of
+// queryset in one query. Order is dropped like in Count.
+func NewStatsMethod(ctx QsStructContext, fields []field.Info) StatsMethod {
+	d := ctx.Dialect()
+	columns, dests := []string{"COUNT(*)"}, []string{"&s.Count"}
+	times, enums := statsFields(fields)
+	for _, f := range times {
+		columns = append(columns, fmt.Sprintf("MIN(%[1]s), MAX(%[1]s)", d.Quote(f.DBName)))
+		dests = append(dests, "&s.Min"+f.Name, "&s.Max"+f.Name)
+	}
+
+	var args, decls, sets []string // enum counts are scanned into arrays and set after scan
+	for _, f := range enums {
+		counts := fieldNameToArgName(f.Name) + "Counts"
+		decls = append(decls, fmt.Sprintf("var %s [%d]int", counts, len(f.EnumValues)))
+		sets = append(sets, fmt.Sprintf("s.%sCounts = map[%s]int{", f.Name, f.TypeName))
+		for i, v := range f.EnumValues {
+			columns = append(columns, fmt.Sprintf(d.CountFilter(), d.Quote(f.DBName)+" = ?"))
+			args = append(args, v.Const)
+			dests = append(dests, fmt.Sprintf("&%s[%d]", counts, i))
+			sets = append(sets, fmt.Sprintf("%s: %s[%d],", v.Const, counts, i))
+		}
+		sets = append(sets, "}")
+	}
+
+	name := ctx.s.TypeName + "Stats"
+	r := StatsMethod{
+		namedMethod:        newNamedMethod("Stats"),
+		baseQuerySetMethod: newBaseQuerySetMethod(ctx.qsTypeName()),
+		constRetMethod:     newConstRetMethod(fmt.Sprintf("(%s, error)", name)),
+		constBodyMethod: newConstBodyMethod(`var s %s
+			if err := %s.checkMatchedNum(); err != nil {
+				return s, err
+			}
+
+			%s
+			err := %s(%s, func() error {
+				return %s.Order("", true).Select(%s).Row().Scan(%s)
+			})
+			if err != nil {
+				return s, err
+			}
+
+			%s
+			return s, nil`, name, qsReceiverName, strings.Join(decls, "\n"), ctx.breakerCallName(), qsDbName, qsDbName,
+			strings.Join(append([]string{strconv.Quote(strings.Join(columns, ", "))}, args...), ", "),
+			strings.Join(dests, ", "),
+			strings.Join(sets, "\n")),
+	}
+	r.setDoc(`// Stats returns statistics of rows of queryset in one query: number of rows,
+	// min and max of timestamps (nil if there are no values) and numbers of rows
+	// by values of enums`)
+	return r
+}
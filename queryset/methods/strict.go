@@ -0,0 +1,43 @@
+package methods
+
+import (
+	"fmt"
+	"strings"
+)
+
+// StrictMethod is a mutating method, which returns ErrNoRowsAffected if no
+// rows were affected: it calls <Method>Num variant returning their number
+type StrictMethod struct {
+	Method
+	callArgs string
+}
+
+// GetBody returns method's body calling its Num variant
+func (m StrictMethod) GetBody() string {
+	receiver := strings.Fields(m.Method.GetReceiverDeclaration())[0]
+	return fmt.Sprintf(`n, err := %s.%sNum(%s)
+	if err == nil && n == 0 {
+		return ErrNoRowsAffected
+	}
+	return err`, receiver, m.Method.GetMethodName(), m.callArgs)
+}
+
+// GetDoc returns doc of method m with ErrNoRowsAffected, it's documented
+// before linter directives
+func (m StrictMethod) GetDoc(methodName string) string {
+	const line = "// ErrNoRowsAffected is returned if no rows were affected."
+	doc := m.Method.GetDoc(methodName)
+	if i := strings.Index(doc, "// nolint"); i != -1 {
+		return doc[:i] + line + "\n" + doc[i:]
+	}
+	return doc + "\n" + line
+}
+
+// NewStrictMethod wraps mutating method m returning error, callArgs are
+// arguments of m passed to its Num variant
+func NewStrictMethod(m Method, callArgs string) StrictMethod {
+	return StrictMethod{
+		Method:   m,
+		callArgs: callArgs,
+	}
+}
@@ -1,5 +1,14 @@
 package methods
 
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jirfag/go-queryset/queryset/dialect"
+	"github.com/jirfag/go-queryset/queryset/field"
+)
+
 // StructModifierMethod represents method, modifying current struct
 type StructModifierMethod struct {
 	namedMethod
@@ -18,3 +27,658 @@ func NewStructModifierMethod(name, structTypeName string) StructModifierMethod {
 	}
 	return r
 }
+
+// NotifyingStructModifierMethod represents method, modifying current struct
+// and notifying about it
+type NotifyingStructModifierMethod struct {
+	namedMethod
+	structMethod
+	dbArgMethod
+	errorRetMethod
+	constBodyMethod
+}
+
+// NewNotifyingStructModifierMethod creates method calling gorm method name and
+// notifying <Struct>NotifyChannel about it in the same transaction
+func NewNotifyingStructModifierMethod(name, structTypeName string) NotifyingStructModifierMethod {
+	r := NotifyingStructModifierMethod{
+		namedMethod:  newNamedMethod(name),
+		dbArgMethod:  newDbArgMethod(),
+		structMethod: newStructMethod("o", "*"+structTypeName),
+		constBodyMethod: newConstBodyMethod(`return o.notify(db, %q, func(tx *gorm.DB) error {
+			return tx.%s(o).Error
+		})`, strings.ToLower(name), name),
+	}
+	r.setDoc(fmt.Sprintf(`// %s is an autogenerated method: it notifies %sNotifyChannel
+	// about mutation in the same transaction`, name, structTypeName))
+	return r
+}
+
+// TxStructModifierMethod represents method, modifying current struct in
+// transaction with isolation level of struct
+type TxStructModifierMethod struct {
+	namedMethod
+	structMethod
+	dbArgMethod
+	errorRetMethod
+	constBodyMethod
+}
+
+// NewTxStructModifierMethod creates method calling gorm method name in
+// transaction begun by Begin<Struct>Tx
+func NewTxStructModifierMethod(name, structTypeName string) TxStructModifierMethod {
+	r := TxStructModifierMethod{
+		namedMethod:  newNamedMethod(name),
+		dbArgMethod:  newDbArgMethod(),
+		structMethod: newStructMethod("o", "*"+structTypeName),
+		constBodyMethod: newConstBodyMethod(`return o.inTx(db, func(tx *gorm.DB) error {
+			return tx.%s(o).Error
+		})`, name),
+	}
+	r.setDoc(fmt.Sprintf(`// %s is an autogenerated method: it runs in transaction with
+	// %sIsolationLevel unless db is already a transaction`, name, structTypeName))
+	return r
+}
+
+// StructModifierNumMethod represents method, modifying current struct and
+// returning number of affected rows
+type StructModifierNumMethod struct {
+	namedMethod
+	structMethod
+	dbArgMethod
+	constRetMethod
+	constBodyMethod
+}
+
+func newStructModifierNumMethod(name, structTypeName, body string) StructModifierNumMethod {
+	return StructModifierNumMethod{
+		namedMethod:     newNamedMethod(name + "Num"),
+		dbArgMethod:     newDbArgMethod(),
+		structMethod:    newStructMethod("o", "*"+structTypeName),
+		constRetMethod:  newConstRetMethod("(int64, error)"),
+		constBodyMethod: newConstBodyMethod("%s", body),
+	}
+}
+
+// NewStructModifierNumMethod creates <name>Num method calling gorm method name
+func NewStructModifierNumMethod(name, structTypeName string) StructModifierNumMethod {
+	return newStructModifierNumMethod(name, structTypeName, fmt.Sprintf(`res := db.%s(o)
+		return res.RowsAffected, res.Error`, name))
+}
+
+// NewNotifyingStructModifierNumMethod creates <name>Num method calling gorm
+// method name and notifying <Struct>NotifyChannel about it in the same
+// transaction
+func NewNotifyingStructModifierNumMethod(name, structTypeName string) StructModifierNumMethod {
+	r := newStructModifierNumMethod(name, structTypeName, fmt.Sprintf(`var n int64
+		err := o.notify(db, %q, func(tx *gorm.DB) error {
+			res := tx.%s(o)
+			n = res.RowsAffected
+			return res.Error
+		})
+		return n, err`, strings.ToLower(name), name))
+	r.setDoc(fmt.Sprintf(`// %sNum is an autogenerated method: it notifies %sNotifyChannel
+	// about mutation in the same transaction`, name, structTypeName))
+	return r
+}
+
+// NewTxStructModifierNumMethod creates <name>Num method calling gorm method
+// name in transaction begun by Begin<Struct>Tx
+func NewTxStructModifierNumMethod(name, structTypeName string) StructModifierNumMethod {
+	r := newStructModifierNumMethod(name, structTypeName, fmt.Sprintf(`var n int64
+		err := o.inTx(db, func(tx *gorm.DB) error {
+			res := tx.%s(o)
+			n = res.RowsAffected
+			return res.Error
+		})
+		return n, err`, name))
+	r.setDoc(fmt.Sprintf(`// %sNum is an autogenerated method: it runs in transaction with
+	// %sIsolationLevel unless db is already a transaction`, name, structTypeName))
+	return r
+}
+
+// insertedColumns returns code of insertion of all columns of object o
+// except relations: preparation of its timestamps, columns, values and
+// appending of primary key to them if it's set
+func insertedColumns(ctx QsStructContext, fields []field.Info, pk *field.Info) (prepare, columns, values []string,
+	pkColumn string) {
+
+	for _, f := range fields {
+		if isRelationField(f) || (pk != nil && f.Name == pk.Name) {
+			continue
+		}
+
+		columns = append(columns, fmt.Sprintf("%s.%s", ctx.dbSchemaTypeName(), f.Name))
+		values = append(values, "o."+f.Name)
+		if isAutoTimeField(f) {
+			if f.Name == "CreatedAt" {
+				prepare = append(prepare,
+					"if o.CreatedAt.IsZero() {",
+					"o.CreatedAt = now",
+					"}")
+			} else {
+				prepare = append(prepare, fmt.Sprintf("o.%s = now", f.Name))
+			}
+		}
+	}
+	if len(prepare) != 0 {
+		prepare = append([]string{"now := time.Now()"}, prepare...)
+		prepare = append(prepare, "")
+	}
+
+	if pk != nil {
+		cond := "true"
+		if pk.IsNumeric {
+			cond = fmt.Sprintf("o.%s != 0", pk.Name) // zero is autoincremented
+		}
+		pkColumn = fmt.Sprintf(`if %s {
+			columns = append(columns, %s.%s)
+			values = append(values, o.%s)
+		}
+		`, cond, ctx.dbSchemaTypeName(), pk.Name, pk.Name)
+	}
+	return prepare, columns, values, pkColumn
+}
+
+// SequencedMethod is an inserting method, which sets primary key by sequence
+// before running
+type SequencedMethod struct {
+	Method
+}
+
+// GetBody returns method's body with setting of primary key
+func (m SequencedMethod) GetBody() string {
+	return `if err := o.nextID(db); err != nil {
+		return err
+	}
+
+	` + m.Method.GetBody()
+}
+
+// NewSequencedMethod wraps object's method m to set primary key of object
+// by sequence before running
+func NewSequencedMethod(m Method) SequencedMethod {
+	return SequencedMethod{
+		Method: m,
+	}
+}
+
+// UpsertMethod generates Upsert method
+type UpsertMethod struct {
+	namedMethod
+	structMethod
+	nArgsMethod
+	errorRetMethod
+	constBodyMethod
+}
+
+// NewUpsertMethod creates upsert method: it inserts object or updates all
+// it's fields except conflict columns, primary key and creation time if
+// row with the same conflict columns already exists. Predicate where of
+// partial unique index is passed to upsert method.
+func NewUpsertMethod(ctx QsStructContext, fields []field.Info, pk *field.Info) UpsertMethod {
+	prepare, columns, values, pkColumn := insertedColumns(ctx, fields, pk)
+	var notUpdated []string
+	for _, f := range fields {
+		if f.Name == "CreatedAt" && isAutoTimeField(f) {
+			notUpdated = append(notUpdated, fmt.Sprintf("%s.%s", ctx.dbSchemaTypeName(), f.Name))
+		}
+	}
+	if pk != nil {
+		notUpdated = append(notUpdated, fmt.Sprintf("%s.%s", ctx.dbSchemaTypeName(), pk.Name))
+	}
+
+	fieldTypeName := ctx.dbSchemaFieldTypeName()
+	const tmpl = `%s
+	columns := []%s{%s}
+	values := []interface{}{%s}
+	%snotUpdated := map[%s]bool{%s}
+	for _, c := range conflictColumns {
+		notUpdated[c] = true
+	}
+
+	scope := db.NewScope(o)
+	var quotedColumns, quotedConflictColumns, updates []string
+	for _, c := range columns {
+		qc := scope.Quote(string(c))
+		quotedColumns = append(quotedColumns, qc)
+		if !notUpdated[c] {
+			updates = append(updates, fmt.Sprintf(%q, qc))
+		}
+	}
+	for _, c := range conflictColumns {
+		quotedConflictColumns = append(quotedConflictColumns, scope.Quote(string(c)))
+	}
+
+	var wherePredicate string
+	if where != "" {
+		wherePredicate = " WHERE " + where
+	}
+
+	placeholders := strings.Repeat("?,", len(columns)-1) + "?"
+	%s
+	%s
+	if err != nil {
+		return fmt.Errorf("can't upsert %s %%v: %%s", o, err)
+	}
+
+	return nil`
+
+	notUpdatedDecl := ""
+	for _, f := range notUpdated {
+		notUpdatedDecl += f + ": true,"
+	}
+
+	exec := fmt.Sprintf(`err := call%sBreaker(db, func() error {
+		return execWithHook(db, query, values...).Error
+	})`, ctx.s.TypeName)
+	if ctx.Dialect().UpsertMerge() == "" {
+		if returning, ok := insertReturningPK(ctx, pk, ":=", ""); ok {
+			exec = returning
+		}
+	}
+
+	r := UpsertMethod{
+		namedMethod:  newNamedMethod("upsert"),
+		structMethod: newStructMethod("o", "*"+ctx.s.TypeName),
+		nArgsMethod: newNArgsMethod(
+			newOneArgMethod("db", "*gorm.DB"),
+			newOneArgMethod("where", "string"),
+			newOneArgMethod("conflictColumns", "..."+fieldTypeName),
+		),
+		constBodyMethod: newConstBodyMethod(tmpl, strings.Join(prepare, "\n"),
+			fieldTypeName, strings.Join(columns, ", "), strings.Join(values, ", "),
+			pkColumn, fieldTypeName, notUpdatedDecl,
+			ctx.Dialect().UpsertUpdate(), upsertStatement(ctx.Dialect(), pk), exec, ctx.s.TypeName),
+	}
+	r.setDoc(`// upsert is an implementation of upserts: where is a predicate
+	// of partial unique index on conflictColumns`)
+	return r
+}
+
+// insertReturningPK returns code running INSERT query with clause returning
+// numeric primary key pk set by database into object: ok is false if dialect
+// can't return rows by INSERT. Conflicting row left as is returns no rows,
+// inserted is set to true only if row was returned. Error of code is assigned
+// by assign operator.
+func insertReturningPK(ctx QsStructContext, pk *field.Info, assign, inserted string) (code string, ok bool) {
+	d := ctx.Dialect()
+	if pk == nil || !pk.IsNumeric || d.Returning() == "" {
+		return "", false
+	}
+
+	var setInserted string
+	if inserted != "" {
+		setInserted = inserted + " = err == nil\n"
+	}
+	return fmt.Sprintf(`query += %q
+	err %s call%sBreaker(db, func() error {
+		err := db.New().Raw(query, values...).Row().Scan(&o.%s)
+		if err == sql.ErrNoRows {
+			return nil // conflicting row was left as is
+		}
+		%sreturn err
+	})`, " "+fmt.Sprintf(d.Returning(), d.Quote(pk.DBName)), assign, ctx.s.TypeName, pk.Name, setInserted), true
+}
+
+// upsertStatement returns code building upsert query of dialect d: INSERT
+// with upsert clause or MERGE. Conflicting row is left as is if all columns
+// are conflict columns: there is nothing to update.
+func upsertStatement(d dialect.Dialect, pk *field.Info) string {
+	if d.UpsertMerge() == "" {
+		quotedPK := "quotedColumns[0]"
+		if pk != nil {
+			quotedPK = strconv.Quote(d.Quote(pk.DBName))
+		}
+		return fmt.Sprintf(`clause := %q
+	if len(updates) == 0 {
+		clause = %q
+	}
+	upsert := fmt.Sprintf(clause, strings.Join(quotedConflictColumns, ","), strings.Join(updates, ","),
+		wherePredicate, %s)
+	query := fmt.Sprintf("INSERT INTO %%s (%%s) VALUES (%%s) %%s", scope.QuotedTableName(),
+		strings.Join(quotedColumns, ","), placeholders, upsert)`, d.UpsertClause(), d.UpsertNothingClause(), quotedPK)
+	}
+
+	target, source := d.Quote("target"), d.Quote("source")
+	return fmt.Sprintf(`var matches, sourceValues []string
+	for _, qc := range quotedConflictColumns {
+		matches = append(matches, fmt.Sprintf(%q, qc))
+	}
+	for _, qc := range quotedColumns {
+		sourceValues = append(sourceValues, %q+qc)
+	}
+	var matched string
+	if len(updates) != 0 {
+		matched = "WHEN MATCHED THEN UPDATE SET " + strings.Join(updates, ",") + " "
+	}
+	query := fmt.Sprintf(%q, scope.QuotedTableName(), strings.Join(quotedColumns, ","), placeholders,
+		strings.Join(matches, " AND "), matched, wherePredicate, strings.Join(sourceValues, ","))`,
+		target+".%[1]s = "+source+".%[1]s", source+".", d.UpsertMerge())
+}
+
+// insertOrStatement returns code of INSERT of object by InsertOr format of
+// dialect resolving conflict on primary key by action
+func insertOrStatement(ctx QsStructContext, fields []field.Info, pk field.Info, action string) string {
+	prepare, columns, values, pkColumn := insertedColumns(ctx, fields, &pk)
+	return fmt.Sprintf(`%s
+	columns := []%s{%s}
+	values := []interface{}{%s}
+	%sscope := db.NewScope(o)
+	var quotedColumns []string
+	for _, c := range columns {
+		quotedColumns = append(quotedColumns, scope.Quote(string(c)))
+	}
+	query := fmt.Sprintf(%q, scope.QuotedTableName(), strings.Join(quotedColumns, ","),
+		strings.Repeat("?,", len(columns)-1)+"?")`, strings.Join(prepare, "\n"),
+		ctx.dbSchemaFieldTypeName(), strings.Join(columns, ", "), strings.Join(values, ", "), pkColumn,
+		fmt.Sprintf(ctx.Dialect().InsertOr(), "%[1]s", "%[2]s", "%[3]s", action))
+}
+
+// NewInsertOrUpdateMethod creates Upsert method of dialects upserting by
+// InsertOr format: conflict is detected only by primary key pk
+func NewInsertOrUpdateMethod(ctx QsStructContext, fields []field.Info, pk field.Info) UpsertMethod {
+	const tmpl = `%s
+
+	err := call%sBreaker(db, func() error {
+		return execWithHook(db, query, values...).Error
+	})
+	if err != nil {
+		return fmt.Errorf("can't upsert %s %%v: %%s", o, err)
+	}
+
+	return nil`
+
+	r := UpsertMethod{
+		namedMethod:  newNamedMethod("Upsert"),
+		structMethod: newStructMethod("o", "*"+ctx.s.TypeName),
+		nArgsMethod:  newNArgsMethod(newOneArgMethod("db", "*gorm.DB")),
+		constBodyMethod: newConstBodyMethod(tmpl, insertOrStatement(ctx, fields, pk, "UPDATE"),
+			ctx.s.TypeName, ctx.s.TypeName),
+	}
+	r.setDoc(fmt.Sprintf(`// Upsert inserts %s or updates all it's fields if row with the same primary
+	// key already exists: %s detects conflicts only by primary key. Creation time
+	// is updated too unless it's set.`, ctx.s.TypeName, ctx.Dialect().Name()))
+	return r
+}
+
+// NewUpsertByColumnsMethod creates Upsert method: it calls upsert with
+// conflict columns passed by caller
+func NewUpsertByColumnsMethod(ctx QsStructContext) UpsertMethod {
+	r := UpsertMethod{
+		namedMethod:  newNamedMethod("Upsert"),
+		structMethod: newStructMethod("o", "*"+ctx.s.TypeName),
+		nArgsMethod: newNArgsMethod(
+			newOneArgMethod("db", "*gorm.DB"),
+			newOneArgMethod("conflictColumns", "..."+ctx.dbSchemaFieldTypeName()),
+		),
+		constBodyMethod: newConstBodyMethod(`return o.upsert(db, "", conflictColumns...)`),
+	}
+	r.setDoc(fmt.Sprintf(`// Upsert inserts %s or updates all it's fields except conflictColumns, primary key
+	// and creation time if row with the same conflictColumns already exists.
+	// Conflict is detected by %s rules.`, ctx.s.TypeName, ctx.Dialect().Name()))
+	return r
+}
+
+// NewUpsertByIndexMethod creates UpsertBy<Index> method: it calls upsert with
+// columns and predicate of unique index idx
+func NewUpsertByIndexMethod(ctx QsStructContext, idx field.UniqueIndex) UpsertMethod {
+	var columns []string
+	for _, f := range idx.Fields {
+		columns = append(columns, fmt.Sprintf("%s.%s", ctx.dbSchemaTypeName(), f.Name))
+	}
+
+	r := UpsertMethod{
+		namedMethod:  newNamedMethod("UpsertBy" + indexNameToMethodSuffix(idx.Name)),
+		structMethod: newStructMethod("o", "*"+ctx.s.TypeName),
+		nArgsMethod:  newNArgsMethod(newOneArgMethod("db", "*gorm.DB")),
+		constBodyMethod: newConstBodyMethod("return o.upsert(db, %s, %s)",
+			strconv.Quote(idx.Where), strings.Join(columns, ", ")),
+	}
+	r.setDoc(fmt.Sprintf(`// %s is Upsert with conflict on unique index %s`, r.GetMethodName(), idx.Name))
+	return r
+}
+
+// CreateIfNotExistsMethod generates CreateIfNotExists method
+type CreateIfNotExistsMethod struct {
+	namedMethod
+	structMethod
+	nArgsMethod
+	constRetMethod
+	constBodyMethod
+}
+
+// NewCreateIfNotExistsMethod creates CreateIfNotExists method: it inserts
+// object by one statement unless row with the same values of unique fields
+// exists. Object is validated before insert if validate is true.
+func NewCreateIfNotExistsMethod(ctx QsStructContext, fields []field.Info, pk *field.Info,
+	validate bool) CreateIfNotExistsMethod {
+
+	prepare, columns, values, pkColumn := insertedColumns(ctx, fields, pk)
+	if ctx.sequence {
+		prepare = append([]string{
+			"if err := o.nextID(db); err != nil {",
+			"return false, err",
+			"}",
+			"",
+		}, prepare...)
+	}
+	if validate {
+		prepare = append([]string{
+			"if err := o.validate(); err != nil {",
+			"return false, err",
+			"}",
+			"",
+		}, prepare...)
+	}
+
+	d := ctx.Dialect()
+	var query, duplicate string
+	if clause := d.InsertIgnoreClause(); clause != "" {
+		quotedPK := "quotedColumns[0]"
+		if pk != nil {
+			quotedPK = strconv.Quote(d.Quote(pk.DBName))
+		}
+		query = fmt.Sprintf(`var quotedUniqueColumns []string
+		for _, c := range uniqueFields {
+			quotedUniqueColumns = append(quotedUniqueColumns, scope.Quote(string(c)))
+		}
+		ignore := fmt.Sprintf(%q, strings.Join(quotedUniqueColumns, ","), %s)
+		query := fmt.Sprintf("INSERT INTO %%s (%%s) VALUES (%%s) %%s", scope.QuotedTableName(),
+			strings.Join(quotedColumns, ","), placeholders, ignore)`, clause, quotedPK)
+	} else {
+		if re := d.DuplicateKeyError(); re != "" {
+			// row inserted concurrently after check of NOT EXISTS violates unique index
+			duplicate = fmt.Sprintf(`if regexp.MustCompile(%q).MatchString(err.Error()) {
+				return false, nil
+			}
+			`, re)
+		}
+		query = fmt.Sprintf(`var conds []string
+		for _, u := range uniqueFields {
+			conds = append(conds, scope.Quote(string(u))+" = ?")
+		}
+		values = append(values, uniqueValues...)
+		query := fmt.Sprintf(%q, scope.QuotedTableName(), strings.Join(quotedColumns, ","), placeholders,
+			strings.Join(conds, " AND "))`, d.InsertWhereNotExists())
+	}
+
+	const tmpl = `if len(uniqueFields) == 0 {
+		return false, errors.New("no unique fields of %[1]s to check existence by")
+	}
+
+	%[2]s
+	columns := []%[3]s{%[4]s}
+	values := []interface{}{%[5]s}
+	%[6]s
+	scope := db.NewScope(o)
+	var quotedColumns []string
+	for _, c := range columns {
+		quotedColumns = append(quotedColumns, scope.Quote(string(c)))
+	}
+	placeholders := strings.Repeat("?,", len(columns)-1) + "?"
+
+	// conflict isn't possible on columns which aren't inserted
+	var uniqueValues []interface{}
+	for _, u := range uniqueFields {
+		i := 0
+		for i < len(columns) && columns[i] != u {
+			i++
+		}
+		if i == len(columns) {
+			return false, fmt.Errorf("unique field %%s of %[1]s isn't inserted", u)
+		}
+		uniqueValues = append(uniqueValues, values[i])
+	}
+	%[7]s
+
+	%[9]s
+	if err != nil {
+		%[8]sreturn false, fmt.Errorf("can't create %[1]s %%v if not exists: %%s", o, err)
+	}
+
+	return %[10]s, nil`
+
+	exec, created := insertReturningPK(ctx, pk, "=", "created")
+	pkDoc := "Primary key isn't set."
+	if created {
+		pkDoc = "Primary key of created row is set."
+	} else {
+		exec = fmt.Sprintf(`var res *gorm.DB
+	err = call%sBreaker(db, func() error {
+		res = execWithHook(db, query, values...)
+		return res.Error
+	})`, ctx.s.TypeName)
+	}
+	createdExpr := "res.RowsAffected != 0"
+	if created {
+		createdExpr = "created"
+	}
+
+	r := CreateIfNotExistsMethod{
+		namedMethod:  newNamedMethod("CreateIfNotExists"),
+		structMethod: newStructMethod("o", "*"+ctx.s.TypeName),
+		nArgsMethod: newNArgsMethod(
+			newOneArgMethod("db", "*gorm.DB"),
+			newOneArgMethod("uniqueFields", "..."+ctx.dbSchemaFieldTypeName()),
+		),
+		constRetMethod: newConstRetMethod("(created bool, err error)"),
+		constBodyMethod: newConstBodyMethod(tmpl, ctx.s.TypeName, strings.Join(prepare, "\n"),
+			ctx.dbSchemaFieldTypeName(), strings.Join(columns, ", "), strings.Join(values, ", "), pkColumn, query,
+			duplicate, exec, createdExpr),
+	}
+	r.setDoc(fmt.Sprintf(`// CreateIfNotExists inserts %s by one statement unless row with the same
+	// values of uniqueFields exists (including soft deleted one): created is false
+	// then. %s %s`, ctx.s.TypeName, pkDoc, createIfNotExistsRaceDoc(d)))
+	return r
+}
+
+// NewInsertOrIgnoreMethod creates CreateIfNotExists method of dialects
+// inserting by InsertOr format: conflict is detected only by primary key pk.
+// Object is validated before insert if validate is true.
+func NewInsertOrIgnoreMethod(ctx QsStructContext, fields []field.Info, pk field.Info,
+	validate bool) CreateIfNotExistsMethod {
+
+	var prepare []string
+	if validate {
+		prepare = append(prepare,
+			"if err := o.validate(); err != nil {",
+			"return false, err",
+			"}",
+			"")
+	}
+
+	const tmpl = `%[2]s
+	%[3]s
+
+	var res *gorm.DB
+	err = call%[1]sBreaker(db, func() error {
+		res = execWithHook(db, query, values...)
+		return res.Error
+	})
+	if err != nil {
+		return false, fmt.Errorf("can't create %[1]s %%v if not exists: %%s", o, err)
+	}
+
+	return res.RowsAffected != 0, nil`
+
+	r := CreateIfNotExistsMethod{
+		namedMethod:    newNamedMethod("CreateIfNotExists"),
+		structMethod:   newStructMethod("o", "*"+ctx.s.TypeName),
+		nArgsMethod:    newNArgsMethod(newOneArgMethod("db", "*gorm.DB")),
+		constRetMethod: newConstRetMethod("(created bool, err error)"),
+		constBodyMethod: newConstBodyMethod(tmpl, ctx.s.TypeName, strings.Join(prepare, "\n"),
+			insertOrStatement(ctx, fields, pk, "IGNORE")),
+	}
+	r.setDoc(fmt.Sprintf(`// CreateIfNotExists inserts %s by one statement unless row with the same
+	// primary key exists (including soft deleted one): created is false then.
+	// %s detects conflicts only by primary key, violation of other unique
+	// index fails insert.`, ctx.s.TypeName, ctx.Dialect().Name()))
+	return r
+}
+
+// createIfNotExistsRaceDoc returns doc of CreateIfNotExists about concurrent
+// inserts of the same row in dialect d
+func createIfNotExistsRaceDoc(d dialect.Dialect) string {
+	switch {
+	case d.InsertIgnoreClause() != "" && d.Name() == "mysql":
+		return `Conflict is detected
+	// on any unique index, uniqueFields must have one. Existing row isn't changed,
+	// created is detected by rows affected: DSN mustn't set clientFoundRows.`
+	case d.InsertIgnoreClause() != "":
+		return `Conflict is detected
+	// by unique index on uniqueFields, so concurrent inserts don't fail.`
+	case d.DuplicateKeyError() != "":
+		return `Row inserted
+	// concurrently after check of existence violates unique index: created is
+	// false then too, but for violation of any unique index.`
+	default:
+		return `Row inserted
+	// concurrently after check of existence isn't detected: unique index on
+	// uniqueFields fails insert by error of driver.`
+	}
+}
+
+// CASMethod generates CAS<Struct><Field> func
+type CASMethod struct {
+	funcMethod
+	namedMethod
+	nArgsMethod
+	constRetMethod
+	constBodyMethod
+}
+
+// NewCASMethod creates CAS<Struct><Field> func: it sets field f of record with
+// primary key pk to new value only if field has expected value (compare-and-set).
+// Records of structs with tenant field are swapped only for tenant passed to func.
+func NewCASMethod(ctx QsStructContext, f, pk field.Info, tenant *field.Info) CASMethod {
+	pkArgName := fieldNameToArgName(pk.Name)
+	args := []oneArgMethod{newOneArgMethod("db", "*gorm.DB")}
+	constructor := ctx.qsConstructorName() + "(db)"
+	if tenant != nil {
+		args = append(args, newOneArgMethod("tenantID", tenant.TypeName))
+		constructor = ctx.n.Constructor + "(db, tenantID)"
+	}
+	args = append(args,
+		newOneArgMethod(pkArgName, pk.TypeName),
+		newOneArgMethod("from", f.TypeName),
+		newOneArgMethod("to", f.TypeName))
+
+	r := CASMethod{
+		namedMethod:    newNamedMethod("CAS" + ctx.s.TypeName + f.Name),
+		nArgsMethod:    newNArgsMethod(args...),
+		constRetMethod: newConstRetMethod("(bool, error)"),
+		constBodyMethod: newConstBodyMethod(`n, err := %s.%s(%s).%s(from).GetUpdater().Set%s(to).UpdateNum()
+			return n != 0, err`, constructor, ctx.n.FilterName(pk.Name, "Eq"), pkArgName,
+			ctx.n.FilterName(f.Name, "Eq"), f.Name),
+	}
+	doc := fmt.Sprintf(`// %s sets %s of %s with primary key %s to value to only if
+	// it's equal to from in one statement. It returns true if value was swapped:
+	// it's false if %s was changed concurrently or there is no such record.`,
+		r.GetMethodName(), f.Name, ctx.s.TypeName, pkArgName, f.Name)
+	if tenant != nil {
+		doc += "\n\t// Only record of tenant tenantID can be swapped."
+	}
+	r.setDoc(doc)
+	return r
+}
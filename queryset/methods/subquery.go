@@ -0,0 +1,67 @@
+package methods
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// SubqueryFilterMethod filters by values of column selected by subquery of
+// another queryset
+type SubqueryFilterMethod struct {
+	chainedQuerySetMethod
+	onFieldMethod
+	oneArgMethod
+	qsCallGormMethod
+}
+
+func newSubqueryFilterMethod(ctx QsFieldContext, operationName, sql string) SubqueryFilterMethod {
+	ctx = ctx.WithOperationName(operationName)
+	r := SubqueryFilterMethod{
+		onFieldMethod:         ctx.onFieldMethod(),
+		oneArgMethod:          newOneArgMethod("sub", "SubQuery"),
+		chainedQuerySetMethod: ctx.chainedQuerySetMethod(),
+		qsCallGormMethod: newQsCallGormMethod("Where", "%s, sub.Expr()",
+			strconv.Quote(ctx.quotedFieldDBName()+" "+sql+" (?)")),
+	}
+	return r
+}
+
+// NewInSubqueryFilterMethod creates <Field>InSubquery filter method
+func NewInSubqueryFilterMethod(ctx QsFieldContext) SubqueryFilterMethod {
+	r := newSubqueryFilterMethod(ctx, "InSubquery", "IN")
+	r.setDoc(fmt.Sprintf(`// %s filters by %s selected by subquery sub, e.g. by
+	// Select<Field> of another queryset`, r.GetMethodName(), ctx.fieldName()))
+	return r
+}
+
+// NewNotInSubqueryFilterMethod creates <Field>NotInSubquery filter method
+func NewNotInSubqueryFilterMethod(ctx QsFieldContext) SubqueryFilterMethod {
+	r := newSubqueryFilterMethod(ctx, "NotInSubquery", "NOT IN")
+	r.setDoc(fmt.Sprintf(`// %s filters by %s not selected by subquery sub`,
+		r.GetMethodName(), ctx.fieldName()))
+	return r
+}
+
+// SelectFieldMethod generates Select<Field> method
+type SelectFieldMethod struct {
+	namedMethod
+	baseQuerySetMethod
+	noArgsMethod
+	constRetMethod
+	constBodyMethod
+}
+
+// NewSelectFieldMethod creates Select<Field> method: it returns subquery
+// selecting only column of field to be used by <Field>InSubquery filters
+func NewSelectFieldMethod(ctx QsFieldContext) SelectFieldMethod {
+	r := SelectFieldMethod{
+		namedMethod:        newNamedMethod("Select" + ctx.fieldName()),
+		baseQuerySetMethod: newBaseQuerySetMethod(ctx.qsTypeName()),
+		constRetMethod:     newConstRetMethod("SubQuery"),
+		constBodyMethod: newConstBodyMethod("return %s.SubQuery(%s.%s)",
+			qsReceiverName, ctx.dbSchemaTypeName(), ctx.fieldName()),
+	}
+	r.setDoc(fmt.Sprintf(`// %s returns subquery selecting %s column of queryset's rows,
+	// e.g. for <Field>InSubquery filter of another queryset`, r.GetMethodName(), ctx.fieldDBName()))
+	return r
+}
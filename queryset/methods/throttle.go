@@ -0,0 +1,242 @@
+package methods
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/jirfag/go-queryset/queryset/field"
+)
+
+func throttledTypeName(ctx QsStructContext) string {
+	return ctx.s.TypeName + "Throttled"
+}
+
+func newThrottledStructMethod(ctx QsStructContext) structMethod {
+	return newStructMethod("t", throttledTypeName(ctx))
+}
+
+// ThrottledMethod generates Throttled method of queryset
+type ThrottledMethod struct {
+	baseQuerySetMethod
+	namedMethod
+	nArgsMethod
+	constRetMethod
+	constBodyMethod
+}
+
+// NewThrottledMethod creates Throttled method: it returns runner of
+// mutations waiting for limiter before every batch
+func NewThrottledMethod(ctx QsStructContext) ThrottledMethod {
+	r := ThrottledMethod{
+		baseQuerySetMethod: newBaseQuerySetMethod(ctx.qsTypeName()),
+		namedMethod:        newNamedMethod("Throttled"),
+		nArgsMethod: newNArgsMethod(
+			newOneArgMethod("ctx", "context.Context"),
+			newOneArgMethod("limiter", ctx.s.TypeName+"Limiter"),
+		),
+		constRetMethod: newConstRetMethod(throttledTypeName(ctx)),
+		constBodyMethod: newConstBodyMethod(`return %s{
+			ctx: ctx,
+			qs: %s,
+			limiter: limiter,
+		}`, throttledTypeName(ctx), qsReceiverName),
+	}
+	r.setDoc(`// Throttled returns runner of batch mutations of queryset records, which waits
+	// for limiter before every batch not to saturate DB (e.g. in backfills)`)
+	return r
+}
+
+// ThrottledBatchesMethod generates inBatches method of throttled runner
+type ThrottledBatchesMethod struct {
+	structMethod
+	namedMethod
+	nArgsMethod
+	constRetMethod
+	constBodyMethod
+}
+
+// NewThrottledBatchesMethod creates inBatches method: it walks over records
+// of queryset in batches ordered by primary key pk
+func NewThrottledBatchesMethod(ctx QsStructContext, pk field.Info) ThrottledBatchesMethod {
+	const tmpl = `if batchSize < 1 {
+		return 0, fmt.Errorf("invalid batch size %%d", batchSize)
+	}
+
+	total := 0 // it's unknown if progress isn't reported
+	if len(t.progress) != 0 {
+		var err error
+		if total, err = t.qs.Count(); err != nil {
+			return 0, err
+		}
+	}
+
+	started := time.Now()
+	var lastPK %s
+	var affected int64
+	processed := 0
+	for {
+		var pks []%s
+		err := %s(t.qs.db, func() error {
+			return t.qs.db.Where(%s, lastPK).Order(%s, true).Limit(batchSize).Pluck(%s, &pks).Error
+		})
+		if err != nil {
+			return affected, err
+		}
+		if len(pks) == 0 {
+			return affected, nil
+		}
+
+		if err = t.limiter.Wait(t.ctx); err != nil {
+			return affected, err
+		}
+
+		n, err := fn(%s(t.qs.db.New()).%s(pks[0], pks[1:]...))
+		affected += n
+		if err != nil {
+			return affected, err
+		}
+
+		processed += len(pks)
+		report%sBatchProgress(t.progress, processed, total, started)
+
+		if len(pks) < batchSize {
+			return affected, nil
+		}
+		lastPK = pks[len(pks)-1]
+	}`
+
+	quotedPK := ctx.Dialect().Quote(pk.DBName)
+	r := ThrottledBatchesMethod{
+		structMethod: newThrottledStructMethod(ctx),
+		namedMethod:  newNamedMethod("inBatches"),
+		nArgsMethod: newNArgsMethod(
+			newOneArgMethod("batchSize", "int"),
+			newOneArgMethod("fn", fmt.Sprintf("func(qs %s) (int64, error)", ctx.qsTypeName())),
+		),
+		constRetMethod: newConstRetMethod("(int64, error)"),
+		constBodyMethod: newConstBodyMethod(tmpl, pk.TypeName, pk.TypeName, ctx.breakerCallName(),
+			strconv.Quote(quotedPK+" > ?"), strconv.Quote(quotedPK+" ASC"), strconv.Quote(quotedPK),
+			ctx.qsConstructorName(), ctx.n.FilterName(pk.Name, "In"), ctx.s.TypeName),
+	}
+	r.setDoc(`// inBatches passes querysets of batches of batchSize records ordered by
+	// primary key to fn and returns total number of affected rows.
+	// Own order of queryset is replaced by primary key one. batchSize must be positive.`)
+	return r
+}
+
+// ThrottledMutationMethod generates Update and Delete methods of throttled runner
+type ThrottledMutationMethod struct {
+	structMethod
+	namedMethod
+	nArgsMethod
+	constRetMethod
+	constBodyMethod
+}
+
+// NewThrottledUpdateMethod creates Update method of throttled runner
+func NewThrottledUpdateMethod(ctx QsStructContext) ThrottledMutationMethod {
+	updaterTypeName := ctx.s.TypeName + "Updater"
+	r := ThrottledMutationMethod{
+		structMethod: newThrottledStructMethod(ctx),
+		namedMethod:  newNamedMethod("Update"),
+		nArgsMethod: newNArgsMethod(
+			newOneArgMethod("batchSize", "int"),
+			newOneArgMethod("set", fmt.Sprintf("func(u %s) %s", updaterTypeName, updaterTypeName)),
+		),
+		constRetMethod: newConstRetMethod("(int64, error)"),
+		constBodyMethod: newConstBodyMethod(`return t.inBatches(batchSize, func(qs %s) (int64, error) {
+			return set(qs.GetUpdater()).UpdateNum()
+		})`, ctx.qsTypeName()),
+	}
+	r.setDoc(`// Update updates records of queryset by fields set by set in batches
+	// of batchSize records and returns number of updated records. batchSize must be positive`)
+	return r
+}
+
+// NewThrottledDeleteMethod creates Delete method of throttled runner
+func NewThrottledDeleteMethod(ctx QsStructContext) ThrottledMutationMethod {
+	r := ThrottledMutationMethod{
+		structMethod:   newThrottledStructMethod(ctx),
+		namedMethod:    newNamedMethod("Delete"),
+		nArgsMethod:    newNArgsMethod(newOneArgMethod("batchSize", "int")),
+		constRetMethod: newConstRetMethod("(int64, error)"),
+		constBodyMethod: newConstBodyMethod(`return t.inBatches(batchSize, func(qs %s) (int64, error) {
+			db := qs.db.Delete(%s{})
+			return db.RowsAffected, db.Error
+		})`, ctx.qsTypeName(), ctx.s.TypeName),
+	}
+	r.setDoc(`// Delete deletes records of queryset in batches of batchSize records
+	// and returns number of deleted records. batchSize must be positive`)
+	return r
+}
+
+// ThrottledCreateBatchMethod generates CreateBatch method of throttled runner
+type ThrottledCreateBatchMethod struct {
+	structMethod
+	namedMethod
+	nArgsMethod
+	errorRetMethod
+	constBodyMethod
+}
+
+// NewThrottledCreateBatchMethod creates CreateBatch method of throttled runner
+func NewThrottledCreateBatchMethod(ctx QsStructContext) ThrottledCreateBatchMethod {
+	const tmpl = `started := time.Now()
+	total, processed := len(objs), 0
+	for len(objs) > 0 {
+		n := batchSize
+		if n <= 0 || n > len(objs) {
+			n = len(objs)
+		}
+
+		if err := t.limiter.Wait(t.ctx); err != nil {
+			return err
+		}
+		if err := Create%sBatch(t.qs.db, objs[:n], n); err != nil {
+			return err
+		}
+		objs = objs[n:]
+
+		processed += n
+		report%sBatchProgress(t.progress, processed, total, started)
+	}
+
+	return nil`
+
+	r := ThrottledCreateBatchMethod{
+		structMethod: newThrottledStructMethod(ctx),
+		namedMethod:  newNamedMethod("CreateBatch"),
+		nArgsMethod: newNArgsMethod(
+			newOneArgMethod("objs", "[]"+ctx.s.TypeName),
+			newOneArgMethod("batchSize", "int"),
+		),
+		constBodyMethod: newConstBodyMethod(tmpl, ctx.s.TypeName, ctx.s.TypeName),
+	}
+	r.setDoc(fmt.Sprintf(`// CreateBatch creates objs by Create%sBatch in batches of batchSize rows`,
+		ctx.s.TypeName))
+	return r
+}
+
+// ThrottledWithProgressMethod generates WithProgress method of throttled runner
+type ThrottledWithProgressMethod struct {
+	structMethod
+	namedMethod
+	oneArgMethod
+	constRetMethod
+	constBodyMethod
+}
+
+// NewThrottledWithProgressMethod creates WithProgress method of throttled runner
+func NewThrottledWithProgressMethod(ctx QsStructContext) ThrottledWithProgressMethod {
+	r := ThrottledWithProgressMethod{
+		structMethod:   newThrottledStructMethod(ctx),
+		namedMethod:    newNamedMethod("WithProgress"),
+		oneArgMethod:   newOneArgMethod("fn", progressFuncTypeName(ctx)),
+		constRetMethod: newConstRetMethod(throttledTypeName(ctx)),
+		constBodyMethod: newConstBodyMethod(`t.progress = append(t.progress[:len(t.progress):len(t.progress)], fn)
+		return t`),
+	}
+	r.setDoc(`// WithProgress returns runner, which calls fn after every batch. Total number
+	// of records to update or delete is counted before the first batch.`)
+	return r
+}
@@ -85,7 +85,7 @@ func NewUpdaterUpdateNumMethod(updaterTypeName string) UpdaterUpdateNumMethod {
 		namedMethod:       newNamedMethod("UpdateNum"),
 		baseUpdaterMethod: newBaseUpdaterMethod(updaterTypeName),
 		constRetMethod:    newConstRetMethod("(int64, error)"),
-		constBodyMethod: newConstBodyMethod(
+		constBodyMethod: newConstBodyMethod("%s",
 			strings.Join([]string{
 				"db := u.db.Updates(u.fields)",
 				"return db.RowsAffected, db.Error",
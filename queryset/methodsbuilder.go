@@ -2,39 +2,112 @@ package queryset
 
 import (
 	"github.com/jirfag/go-queryset/parser"
+	"github.com/jirfag/go-queryset/queryset/dialect"
 	"github.com/jirfag/go-queryset/queryset/field"
 	"github.com/jirfag/go-queryset/queryset/methods"
 )
 
 type methodsBuilder struct {
-	fields []field.Info
-	s      parser.ParsedStruct
-	ret    []methods.Method
-	sctx   methods.QsStructContext
+	fields     []field.Info
+	s          parser.ParsedStruct
+	ret        []methods.Method
+	sctx       methods.QsStructContext
+	qsStructs  map[string]bool // names of all structs with generated querysets
+	opts       structOptions
+	indexes    []field.UniqueIndex
+	joins      []methods.Join
+	procedures []methods.Procedure
+	naming     methods.Naming
+	tenant     *field.Info // field of tenant, it's nil if struct has no tenant
+	outPkg     bool        // code is generated into another package: object methods are dropped
 }
 
 func (b *methodsBuilder) qsTypeName() string {
-	return b.s.TypeName + "QuerySet"
+	return b.naming.QuerySet
 }
 
-func newMethodsBuilder(s parser.ParsedStruct, fields []field.Info) *methodsBuilder {
+func newMethodsBuilder(s parser.ParsedStruct, fields []field.Info,
+	qsStructs map[string]bool, d dialect.Dialect, n methods.Naming, opts structOptions,
+	indexes []field.UniqueIndex, joins []methods.Join, procedures []methods.Procedure,
+	tenant *field.Info) *methodsBuilder {
+
+	sctx := methods.NewQsStructContext(s, d).WithNaming(n)
+	if _, ok := opts["sequence"]; ok {
+		sctx = sctx.WithSequence()
+	}
 	return &methodsBuilder{
-		s:      s,
-		sctx:   methods.NewQsStructContext(s),
-		fields: fields,
+		s:          s,
+		sctx:       sctx,
+		naming:     n,
+		fields:     fields,
+		qsStructs:  qsStructs,
+		opts:       opts,
+		indexes:    indexes,
+		joins:      joins,
+		procedures: procedures,
+		tenant:     tenant,
 	}
 }
 
+// getPrimaryKeyField returns field marked as primary key or field with db
+// name "id" like gorm does. It returns nil if there is no such field.
+func getPrimaryKeyField(fields []field.Info) *field.Info {
+	for i := range fields {
+		if fields[i].IsPrimaryKey {
+			return &fields[i]
+		}
+	}
+
+	for i := range fields {
+		if fields[i].DBName == "id" {
+			return &fields[i]
+		}
+	}
+
+	return nil
+}
+
+func (b *methodsBuilder) getPrimaryKeyField() *field.Info {
+	return getPrimaryKeyField(b.fields)
+}
+
 func (b *methodsBuilder) getQuerySetMethodsForField(f field.Info) []methods.Method {
 	fctx := b.sctx.FieldCtx(f)
-	basicTypeMethods := []methods.Method{
-		methods.NewBinaryFilterMethod(fctx.WithOperationName("eq")),
-		methods.NewBinaryFilterMethod(fctx.WithOperationName("ne")),
+	if f.IsJSON && !f.IsString {
+		return b.getJSONMethods(fctx)
+	}
+	if f.IsArray() {
+		return b.getArrayMethods(fctx)
+	}
+
+	var basicTypeMethods []methods.Method
+	if f.IsJSON {
+		basicTypeMethods = b.getJSONMethods(fctx)
 	}
-	if !f.IsTime {
+	if !b.hasEquality(f) {
+		// JSON strings are filtered only by LIKE
+		return append(basicTypeMethods, b.getStringMethods(fctx)...)
+	}
+
+	basicTypeMethods = append(basicTypeMethods,
+		methods.NewBinaryFilterMethod(fctx.WithOperationName("eq")),
+		methods.NewBinaryFilterMethod(fctx.WithOperationName("ne")))
+	if !f.IsTime && b.hasOption("sharded") {
+		basicTypeMethods = append(basicTypeMethods,
+			methods.NewChunkedInFilterMethod(fctx),
+			methods.NewChunkedNotInFilterMethod(fctx))
+	} else if !f.IsTime {
 		inMethod := methods.NewInFilterMethod(fctx)
 		notInMethod := methods.NewNotInFilterMethod(fctx)
-		basicTypeMethods = append(basicTypeMethods, inMethod, notInMethod)
+		basicTypeMethods = append(basicTypeMethods, inMethod, notInMethod,
+			methods.NewInSubqueryFilterMethod(fctx),
+			methods.NewNotInSubqueryFilterMethod(fctx))
+	}
+	for _, v := range f.EnumValues {
+		basicTypeMethods = append(basicTypeMethods, methods.NewEnumEqMethod(fctx, v))
+	}
+	if b.hasOption("ifset") && (f.IsString || f.IsNumeric) {
+		basicTypeMethods = append(basicTypeMethods, methods.NewEqIfSetMethod(fctx))
 	}
 
 	numericMethods := []methods.Method{
@@ -46,6 +119,16 @@ func (b *methodsBuilder) getQuerySetMethodsForField(f field.Info) []methods.Meth
 		methods.NewOrderDescByMethod(fctx),
 	}
 
+	if f.IsTime {
+		numericMethods = append(numericMethods,
+			methods.NewBeforeFilterMethod(fctx),
+			methods.NewAfterFilterMethod(fctx),
+			methods.NewWithinFilterMethod(fctx))
+	}
+	if f.IsDecimal {
+		numericMethods = append(numericMethods, methods.NewBetweenFilterMethod(fctx))
+	}
+
 	if f.IsNumeric {
 		return append(basicTypeMethods, numericMethods...)
 	}
@@ -55,20 +138,107 @@ func (b *methodsBuilder) getQuerySetMethodsForField(f field.Info) []methods.Meth
 		return []methods.Method{methods.NewPreloadMethod(fctx)}
 	}
 
-	if f.IsPointer {
-		ptrMethods := b.getQuerySetMethodsForField(f.GetPointed())
-		return append(ptrMethods,
+	if f.IsPointer || f.IsSQLNull() {
+		ptrMethods := append(b.getQuerySetMethodsForField(f.GetPointed()),
 			methods.NewIsNullMethod(fctx),
 			methods.NewIsNotNullMethod(fctx))
+		if p := f.GetPointed(); f.IsPointer && !p.IsStruct && !p.IsJSON && !p.IsArray() {
+			ptrMethods = append(ptrMethods, methods.NewEqNullableMethod(fctx))
+		}
+		return ptrMethods
 	}
 
-	// it's a string
+	if f.IsBool {
+		basicTypeMethods = append(basicTypeMethods,
+			methods.NewIsTrueMethod(fctx),
+			methods.NewIsFalseMethod(fctx))
+	}
+
+	if f.IsString {
+		basicTypeMethods = append(basicTypeMethods, b.getStringMethods(fctx)...)
+	}
+
+	// it's a string or bool
 	return basicTypeMethods
 }
 
+// getStringMethods returns filters and orders of string field
+func (b *methodsBuilder) getStringMethods(fctx methods.QsFieldContext) []methods.Method {
+	ret := []methods.Method{
+		methods.NewLikeFilterMethod(fctx),
+		methods.NewILikeFilterMethod(fctx),
+		methods.NewEqFoldFilterMethod(fctx),
+		methods.NewStartsWithFilterMethod(fctx),
+		methods.NewEndsWithFilterMethod(fctx),
+		methods.NewContainsFilterMethod(fctx),
+	}
+	if b.sctx.Dialect().RegexpMatch() != "" {
+		ret = append(ret, methods.NewMatchesFilterMethod(fctx))
+	}
+	if b.hasOption("locale") {
+		ret = append(ret,
+			methods.NewLocalizedOrderAscByMethod(fctx),
+			methods.NewLocalizedOrderDescByMethod(fctx))
+	}
+	return ret
+}
+
+// hasEquality returns false if column of field f can't be compared by =:
+// json type of postgres has no equality operator unlike jsonb
+func (b *methodsBuilder) hasEquality(f field.Info) bool {
+	return !(f.JSONType == "json" && b.sctx.Dialect().Name() == "postgres")
+}
+
+// getJSONMethods returns filters of JSON column supported by dialect
+func (b *methodsBuilder) getJSONMethods(fctx methods.QsFieldContext) []methods.Method {
+	var ret []methods.Method
+	d := b.sctx.Dialect()
+	if d.JSONPathEq() != "" {
+		ret = append(ret, methods.NewJSONPathEqMethod(fctx))
+	}
+	if d.JSONContains() != "" {
+		ret = append(ret, methods.NewJSONContainsMethod(fctx))
+	}
+	if d.JSONHasKey() != "" {
+		ret = append(ret, methods.NewJSONHasKeyMethod(fctx))
+	}
+	return ret
+}
+
+// getArrayMethods returns filters of array column supported by dialect
+func (b *methodsBuilder) getArrayMethods(fctx methods.QsFieldContext) []methods.Method {
+	if b.sctx.Dialect().ArrayContains() == "" {
+		return nil
+	}
+
+	return []methods.Method{
+		methods.NewArrayContainsMethod(fctx),
+		methods.NewArrayOverlapsMethod(fctx),
+		methods.NewArrayLengthEqMethod(fctx),
+	}
+}
+
 func (b *methodsBuilder) buildQuerySetFieldMethods(f field.Info) *methodsBuilder {
-	methods := b.getQuerySetMethodsForField(f)
-	b.ret = append(b.ret, methods...)
+	b.ret = append(b.ret, b.getQuerySetMethodsForField(f)...)
+	if f.IsStruct || (f.IsPointer && f.GetPointed().IsStruct) {
+		return b
+	}
+
+	fctx := b.sctx.FieldCtx(f)
+	b.ret = append(b.ret, methods.NewPluckMethod(fctx))
+	if b.hasEquality(f) {
+		b.ret = append(b.ret,
+			methods.NewDistinctFieldMethod(fctx),
+			methods.NewCountDistinctMethod(fctx))
+	}
+	if !f.IsJSON && !f.IsArray() && !b.hasOption("sharded") {
+		b.ret = append(b.ret, methods.NewSelectFieldMethod(fctx))
+	}
+	if f.IsDecimal || ((f.IsPointer || f.IsSQLNull()) && f.GetPointed().IsDecimal) {
+		b.ret = append(b.ret,
+			methods.NewSumMethod(fctx),
+			methods.NewAvgMethod(fctx))
+	}
 	return b
 }
 
@@ -77,14 +247,31 @@ func getUpdaterTypeName(structTypeName string) string {
 }
 
 func (b *methodsBuilder) buildUpdaterStructMethods() {
+	if b.readOnly() {
+		return
+	}
+
 	updaterTypeName := getUpdaterTypeName(b.s.TypeName)
 	b.ret = append(b.ret,
-		methods.NewUpdaterUpdateMethod(updaterTypeName),
+		b.strict(methods.NewUpdaterUpdateMethod(updaterTypeName), ""),
 		methods.NewUpdaterUpdateNumMethod(updaterTypeName),
 	)
 }
 
+// strict makes mutating method m return ErrNoRowsAffected if no rows were
+// affected for structs with "strict" option, callArgs are arguments of m
+func (b *methodsBuilder) strict(m methods.Method, callArgs string) methods.Method {
+	if !b.hasOption("strict") {
+		return m
+	}
+	return methods.NewStrictMethod(m, callArgs)
+}
+
 func (b *methodsBuilder) buildUpdaterFieldMethods(f field.Info) {
+	if b.readOnly() {
+		return
+	}
+
 	if f.IsPointer {
 		p := f.GetPointed()
 		if p.IsStruct {
@@ -106,35 +293,323 @@ func (b *methodsBuilder) buildUpdaterFieldMethods(f field.Info) {
 func (b *methodsBuilder) buildStructSelectMethods() *methodsBuilder {
 	b.ret = append(b.ret,
 		methods.NewAllMethod(b.s.TypeName, b.qsTypeName()),
-		methods.NewOneMethod(b.s.TypeName, b.qsTypeName()),
-		methods.NewLimitMethod(b.qsTypeName()))
+		methods.NewOneMethod(b.s.TypeName, b.qsTypeName(), b.opts["one"] == "take"),
+		methods.NewExactlyOneMethod(b.sctx),
+		methods.NewFirstMethod(b.sctx),
+		methods.NewLastMethod(b.sctx),
+		methods.NewIterateMethod(b.sctx),
+		methods.NewLimitMethod(b.sctx),
+		methods.NewOffsetMethod(b.sctx),
+		methods.NewOrMethod(b.qsTypeName()),
+		methods.NewNotMethod(b.qsTypeName()),
+		methods.NewWhereMethod(b.qsTypeName()),
+		methods.NewScopeMethod(b.qsTypeName()))
+	if pk := b.getPrimaryKeyField(); pk != nil {
+		b.ret = append(b.ret, methods.NewAllInBatchesMethod(b.sctx, *pk))
+	}
+	for _, f := range b.fields {
+		if f.IsWeight {
+			b.ret = append(b.ret, methods.NewSampleWeightedMethod(b.sctx, f))
+		}
+	}
+	return b
+}
+
+// buildLockMethods builds methods of row-level locks supported by dialect
+func (b *methodsBuilder) buildLockMethods() *methodsBuilder {
+	d := b.sctx.Dialect()
+	if d.ForUpdate() != "" {
+		b.ret = append(b.ret, methods.NewForUpdateMethod(b.sctx))
+	}
+	if d.ForShare() != "" {
+		b.ret = append(b.ret, methods.NewForShareMethod(b.sctx))
+	}
+	if d.ForUpdateSkipLocked() != "" {
+		b.ret = append(b.ret, methods.NewForUpdateSkipLockedMethod(b.sctx))
+	}
 	return b
 }
 
 func (b *methodsBuilder) buildAggrMethods() *methodsBuilder {
 	b.ret = append(b.ret,
-		methods.NewCountMethod(b.qsTypeName()))
+		methods.NewCountMethod(b.sctx),
+		methods.NewDistinctMethod(b.sctx))
+	if b.hasOption("stats") {
+		b.ret = append(b.ret, methods.NewStatsMethod(b.sctx, b.fields))
+	}
 	return b
 }
 
+// translateErrors makes finishers of one row and Create return typed errors
+// of struct
+func (b *methodsBuilder) translateErrors() {
+	qs, o := "qs "+b.qsTypeName(), "o *"+b.s.TypeName
+	for i, m := range b.ret {
+		switch r := m.GetReceiverDeclaration(); m.GetMethodName() {
+		case "One", "ExactlyOne", "First", "Last":
+			if r != qs {
+				continue
+			}
+		case "Create":
+			if r != o {
+				continue
+			}
+		default:
+			continue
+		}
+		b.ret[i] = methods.NewTranslatedErrorsMethod(m, b.s.TypeName)
+	}
+}
+
+func (b *methodsBuilder) hasOption(name string) bool {
+	_, ok := b.opts[name]
+	return ok
+}
+
+// readOnly returns true if rows of struct are only displayed: mutating methods
+// aren't generated for such structs
+func (b *methodsBuilder) readOnly() bool {
+	return b.hasOption("readonly")
+}
+
 func (b *methodsBuilder) buildCRUDMethods() *methodsBuilder {
+	if hasChecks(b.fields) {
+		b.ret = append(b.ret, methods.NewValidateMethod(b.sctx, b.fields))
+	}
+	if b.readOnly() {
+		return b
+	}
+
 	b.ret = append(b.ret,
 		methods.NewGetUpdaterMethod(b.qsTypeName(), getUpdaterTypeName(b.s.TypeName)),
-		methods.NewDeleteMethod(b.qsTypeName(), b.s.TypeName),
-		methods.NewStructModifierMethod("Create", b.s.TypeName),
-		methods.NewStructModifierMethod("Delete", b.s.TypeName))
+		b.strict(methods.NewDeleteMethod(b.qsTypeName(), b.s.TypeName), ""),
+		methods.NewDeleteNumMethod(b.qsTypeName(), b.s.TypeName),
+		methods.NewCreateBatchMethod(b.sctx, b.fields, b.getPrimaryKeyField()))
+	if pk := b.getPrimaryKeyField(); pk != nil {
+		b.ret = append(b.ret,
+			methods.NewUpdateBatchMethod(b.sctx, b.hasOption("strict")),
+			methods.NewUpdateBatchNumMethod(b.sctx, b.fields, *pk, hasChecks(b.fields) && !b.outPkg))
+	}
+
+	for _, name := range []string{"Create", "Delete"} {
+		var m methods.Method
+		if b.hasOption("notify") {
+			m = methods.NewNotifyingStructModifierMethod(name, b.s.TypeName)
+		} else if b.hasOption("isolation") {
+			m = methods.NewTxStructModifierMethod(name, b.s.TypeName)
+		} else {
+			m = methods.NewStructModifierMethod(name, b.s.TypeName)
+		}
+
+		if name == "Create" && b.hasOption("sequence") {
+			m = methods.NewSequencedMethod(m)
+		}
+		if name == "Create" && hasChecks(b.fields) {
+			m = methods.NewValidatedMethod(m)
+		}
+		if name == "Delete" {
+			m = b.strict(m, "db")
+		}
+		b.ret = append(b.ret, m)
+	}
+
+	var deleteNum methods.Method
+	if b.hasOption("notify") {
+		deleteNum = methods.NewNotifyingStructModifierNumMethod("Delete", b.s.TypeName)
+	} else if b.hasOption("isolation") {
+		deleteNum = methods.NewTxStructModifierNumMethod("Delete", b.s.TypeName)
+	} else {
+		deleteNum = methods.NewStructModifierNumMethod("Delete", b.s.TypeName)
+	}
+	b.ret = append(b.ret, deleteNum)
+
+	for _, f := range b.fields {
+		if f.IsCAS {
+			b.ret = append(b.ret, methods.NewCASMethod(b.sctx, f, *b.getPrimaryKeyField(), b.tenant))
+		}
+	}
+	return b
+}
+
+func (b *methodsBuilder) buildUpsertMethods() *methodsBuilder {
+	if b.readOnly() {
+		return b
+	}
+
+	d := b.sctx.Dialect()
+	if d.InsertOr() != "" {
+		if pk := b.getPrimaryKeyField(); pk != nil {
+			var upsert methods.Method = methods.NewInsertOrUpdateMethod(b.sctx, b.fields, *pk)
+			if hasChecks(b.fields) {
+				upsert = methods.NewValidatedMethod(upsert)
+			}
+			b.ret = append(b.ret, upsert)
+		}
+		return b // conflict is detected only by primary key
+	}
+	if d.UpsertClause() == "" && d.UpsertMerge() == "" {
+		return b // upsert isn't supported by dialect
+	}
+
+	var upsert methods.Method = methods.NewUpsertMethod(b.sctx, b.fields, b.getPrimaryKeyField())
+	if b.hasOption("sequence") {
+		upsert = methods.NewSequencedMethod(upsert)
+	}
+	if hasChecks(b.fields) {
+		upsert = methods.NewValidatedMethod(upsert)
+	}
+	b.ret = append(b.ret, upsert, methods.NewUpsertByColumnsMethod(b.sctx))
+	for _, idx := range b.indexes {
+		b.ret = append(b.ret, methods.NewUpsertByIndexMethod(b.sctx, idx))
+	}
+	return b
+}
+
+func (b *methodsBuilder) buildCreateIfNotExistsMethods() *methodsBuilder {
+	if b.readOnly() {
+		return b
+	}
+
+	d := b.sctx.Dialect()
+	if d.InsertOr() != "" {
+		if pk := b.getPrimaryKeyField(); pk != nil {
+			b.ret = append(b.ret, methods.NewInsertOrIgnoreMethod(b.sctx, b.fields, *pk, hasChecks(b.fields)))
+		}
+		return b
+	}
+	if d.InsertIgnoreClause() == "" && d.InsertWhereNotExists() == "" {
+		return b // conditional insert isn't supported by dialect
+	}
+
+	b.ret = append(b.ret, methods.NewCreateIfNotExistsMethod(b.sctx, b.fields, b.getPrimaryKeyField(), hasChecks(b.fields)))
+	return b
+}
+
+func (b *methodsBuilder) buildUniqueIndexMethods() *methodsBuilder {
+	for _, idx := range b.indexes {
+		b.ret = append(b.ret, methods.NewUniqueIndexFilterMethod(b.sctx, idx))
+	}
+	return b
+}
+
+func (b *methodsBuilder) buildJoinMethods() *methodsBuilder {
+	for _, j := range b.joins {
+		b.ret = append(b.ret, methods.NewJoinMethod(b.sctx, j))
+		if j.IsHasMany {
+			b.ret = append(b.ret, methods.NewHasRelationMethod(b.sctx, j), methods.NewRelationCountGtMethod(b.sctx, j))
+		}
+	}
+	return b
+}
+
+func (b *methodsBuilder) buildSearchMethods() *methodsBuilder {
+	b.ret = append(b.ret,
+		methods.NewToSearchDocumentMethod(b.sctx, b.fields, b.qsStructs))
+
+	var fullTextFields []field.Info
+	for _, f := range b.fields {
+		if f.IsFullText {
+			fullTextFields = append(fullTextFields, f)
+			b.ret = append(b.ret, methods.NewFieldFullTextSearchMethod(b.sctx, f, b.hasOption("locale")))
+		}
+	}
+	if len(fullTextFields) != 0 {
+		b.ret = append(b.ret, methods.NewFullTextSearchMethod(b.sctx, fullTextFields, b.hasOption("locale")))
+	}
+
+	pk := b.getPrimaryKeyField()
+	if pk == nil {
+		return b
+	}
+
+	b.ret = append(b.ret, methods.NewReindexAllMethod(b.sctx, *pk))
+	for _, f := range b.fields {
+		if f.IsSearchBacked {
+			b.ret = append(b.ret, methods.NewSearchBackedFilterMethod(b.sctx.FieldCtx(f), *pk))
+		}
+	}
+	return b
+}
+
+func (b *methodsBuilder) buildSoftDeleteMethods() *methodsBuilder {
+	if !isSoftDeleted(b.fields) {
+		return b
+	}
+
+	b.ret = append(b.ret,
+		methods.NewWithDeletedMethod(b.sctx),
+		methods.NewDeletedOnlyMethod(b.sctx))
+	if !b.readOnly() {
+		b.ret = append(b.ret,
+			b.strict(methods.NewSoftDeleteMethod(b.sctx), ""),
+			methods.NewSoftDeleteNumMethod(b.sctx))
+	}
+	return b
+}
+
+func (b *methodsBuilder) buildThrottledMethods() *methodsBuilder {
+	pk := b.getPrimaryKeyField()
+	if pk == nil {
+		return b
+	}
+
+	b.ret = append(b.ret,
+		methods.NewThrottledMethod(b.sctx),
+		methods.NewThrottledBatchesMethod(b.sctx, *pk),
+		methods.NewThrottledWithProgressMethod(b.sctx))
+	if !b.readOnly() {
+		b.ret = append(b.ret,
+			methods.NewThrottledUpdateMethod(b.sctx),
+			methods.NewThrottledDeleteMethod(b.sctx),
+			methods.NewThrottledCreateBatchMethod(b.sctx))
+	}
+	return b
+}
+
+func (b *methodsBuilder) buildProcedureMethods() *methodsBuilder {
+	for _, p := range b.procedures {
+		b.ret = append(b.ret, methods.NewProcedureMethod(b.sctx, p))
+	}
+	return b
+}
+
+func (b *methodsBuilder) buildFakeMethods() *methodsBuilder {
+	if !b.hasOption("fake") {
+		return b
+	}
+
+	ctx := methods.NewFakeQsStructContext(b.sctx)
+	for _, f := range b.fields {
+		b.ret = append(b.ret, methods.NewFakeFieldMethods(ctx, f)...)
+		if b.hasOption("ifset") {
+			b.ret = append(b.ret, methods.NewFakeEqIfSetMethods(ctx, f)...)
+		}
+	}
 	return b
 }
 
 func (b methodsBuilder) Build() []methods.Method {
 	b.buildStructSelectMethods().
+		buildLockMethods().
 		buildAggrMethods().
 		buildCRUDMethods().
+		buildSoftDeleteMethods().
+		buildUpsertMethods().
+		buildCreateIfNotExistsMethods().
+		buildUniqueIndexMethods().
+		buildJoinMethods().
+		buildSearchMethods().
+		buildThrottledMethods().
+		buildProcedureMethods().
+		buildFakeMethods().
 		buildUpdaterStructMethods()
 
 	for _, f := range b.fields {
 		b.buildQuerySetFieldMethods(f).buildUpdaterFieldMethods(f)
 	}
 
+	if b.hasOption("errors") {
+		b.translateErrors()
+	}
 	return b.ret
 }
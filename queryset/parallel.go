@@ -0,0 +1,76 @@
+package queryset
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// generationErrors are errors of independent units of generation (structs,
+// out files), which are generated concurrently
+type generationErrors []error
+
+func (errs generationErrors) Error() string {
+	msgs := make([]string, 0, len(errs))
+	for _, err := range errs {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// positionError returns error attributed to line of file, line is omitted
+// if it's zero
+func positionError(file string, line int, err error) error {
+	if file == "" {
+		return err
+	}
+	if line == 0 {
+		return fmt.Errorf("%s: %s", file, err)
+	}
+	return fmt.Errorf("%s:%d: %s", file, line, err)
+}
+
+// runParallel calls f for units 0..n-1 by at most jobs goroutines (by number
+// of CPUs if jobs isn't positive). Errors of all units are returned in order
+// of units, so they don't depend on scheduling.
+func runParallel(jobs, n int, f func(i int) error) error {
+	if jobs <= 0 {
+		jobs = runtime.NumCPU()
+	}
+	if jobs > n {
+		jobs = n
+	}
+
+	errs := make([]error, n)
+	units := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < jobs; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range units {
+				errs[i] = f(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		units <- i
+	}
+	close(units)
+	wg.Wait()
+
+	var ret generationErrors
+	for _, err := range errs {
+		if err != nil {
+			ret = append(ret, err)
+		}
+	}
+	switch len(ret) {
+	case 0:
+		return nil
+	case 1:
+		return ret[0]
+	}
+	return ret
+}
@@ -4,29 +4,338 @@ import (
 	"bytes"
 	"fmt"
 	"go/ast"
+	"go/token"
 	"io"
+	"regexp"
 	"sort"
+	"strconv"
 	"strings"
+	"unicode"
 
+	"github.com/jinzhu/gorm"
 	"golang.org/x/tools/go/loader"
 
 	"github.com/jirfag/go-queryset/parser"
+	"github.com/jirfag/go-queryset/queryset/dialect"
 	"github.com/jirfag/go-queryset/queryset/field"
 	"github.com/jirfag/go-queryset/queryset/methods"
 )
 
 type querySetStructConfig struct {
+	methods.Naming
 	StructName string
 	Name       string
 	Methods    methodsSlice
 	Fields     []field.Info
+	PrimaryKey *field.Info
+	Options    structOptions
+
+	// SetIsolation is a statement setting isolation level of transactions
+	// of mutations, it's set by "isolation=level" option
+	SetIsolation string
+
+	// Queue is a job queue of struct, it's set by "queue=ready:claimed" option
+	Queue *jobQueue
+
+	// Sequence is a query selecting next value of sequence of primary key,
+	// it's set by "sequence=name" option
+	Sequence string
+
+	// Geo is a location of struct, it's set by queryset:"lat" and
+	// queryset:"lng" tags of fields
+	Geo *geoPoint
+
+	// AsOfSystemTime is a format of clause of snapshot reads supported by dialect
+	AsOfSystemTime string
+
+	// Explain is a format of statement selecting plan of query supported by dialect
+	Explain string
+
+	// UnionSelect is a format of operand of UNION of querysets in dialect
+	UnionSelect string
+
+	// OrderedSubquery is a clause making ordered operand of UNION valid
+	// subquery in dialect if it isn't limited
+	OrderedSubquery string
+
+	// Collate is a format of expression of column in collation of locale
+	Collate string
+
+	// Tenant is a field of tenant set by Config.TenantField: querysets are
+	// constructed only for rows of one tenant, TenantCond filters them
+	Tenant     *field.Info
+	TenantCond string
+
+	// Version is a version column of optimistic locking: it's a field marked
+	// by queryset:"version" tag or Version field, VersionCond matches it
+	Version     *field.Info
+	VersionCond string
+
+	// Errors are typed errors of struct, they are set by "errors" option
+	Errors *structErrors
+
+	// FilterColumns are columns which can be filtered by ApplyFilters and
+	// ordered by OrderByParam
+	FilterColumns []filteredColumn
+
+	// ModelPkg is a name of package of struct if querysets are generated
+	// into another package (Config.OutPkg): object methods aren't generated
+	ModelPkg string
+}
+
+// TimestampLayout returns layout of time in clause of snapshot reads
+func (c querySetStructConfig) TimestampLayout() string {
+	return dialect.TimestampLayout
+}
+
+// NotFoundError returns error of fakes of querysets if nothing was found
+func (c querySetStructConfig) NotFoundError() string {
+	if c.Errors != nil {
+		return "Err" + c.StructName + "NotFound"
+	}
+	return "gorm.ErrRecordNotFound"
+}
+
+// HasOption returns true if struct has "gen:qs" option
+func (c querySetStructConfig) HasOption(name string) bool {
+	_, ok := c.Options[name]
+	return ok
+}
+
+// NotifyChannel returns channel for mutations notifications: it's set
+// by "notify=channel" option or is a struct name in snake case
+func (c querySetStructConfig) NotifyChannel() string {
+	if ch := c.Options["notify"]; ch != "" {
+		return ch
+	}
+
+	return gorm.ToDBName(c.StructName)
+}
+
+// getNaming returns naming scheme of struct: type name of queryset, its
+// constructor and prefix of filters can be set by "name", "constructor"
+// and "prefix" options, default prefix is filterPrefix
+func getNaming(s parser.ParsedStruct, opts structOptions, filterPrefix string) (methods.Naming, error) {
+	n := methods.DefaultNaming(s.TypeName)
+	if name := opts["name"]; name != "" {
+		n.QuerySet, n.Constructor = name, "New"+name
+	}
+	if constructor := opts["constructor"]; constructor != "" {
+		n.Constructor = constructor
+	}
+	n.FilterPrefix = filterPrefix
+	if prefix, ok := opts["prefix"]; ok {
+		n.FilterPrefix = prefix
+	}
+
+	for _, name := range []string{n.QuerySet, n.Constructor} {
+		if !isIdentifier(name) {
+			return n, fmt.Errorf("invalid name %q of struct %s queryset", name, s.TypeName)
+		}
+	}
+	if n.FilterPrefix != "" && !isIdentifier(n.FilterPrefix) {
+		return n, fmt.Errorf("invalid filters prefix %q of struct %s", n.FilterPrefix, s.TypeName)
+	}
+	return n, nil
+}
+
+// isIdentifier returns true if name is a Go identifier and not a keyword:
+// it's token.IsIdentifier of Go 1.13
+func isIdentifier(name string) bool {
+	if name == "" || token.Lookup(name).IsKeyword() {
+		return false
+	}
+	for i, c := range name {
+		if !unicode.IsLetter(c) && c != '_' && (i == 0 || !unicode.IsDigit(c)) {
+			return false
+		}
+	}
+	return true
+}
+
+// isolationLevels are SQL names of isolation levels of "isolation" option
+var isolationLevels = map[string]string{
+	"read_uncommitted": "READ UNCOMMITTED",
+	"read_committed":   "READ COMMITTED",
+	"repeatable_read":  "REPEATABLE READ",
+	"serializable":     "SERIALIZABLE",
+}
+
+// IsolationLevel returns SQL name of isolation level set by "isolation" option
+func (c querySetStructConfig) IsolationLevel() string {
+	return isolationLevels[c.Options["isolation"]]
+}
+
+// getSetIsolation returns statement setting isolation level of "isolation"
+// option or empty string if there is no such option
+func getSetIsolation(s parser.ParsedStruct, opts structOptions, d dialect.Dialect) (string, error) {
+	name, ok := opts["isolation"]
+	if !ok {
+		return "", nil
+	}
+
+	level := isolationLevels[name]
+	if level == "" {
+		return "", fmt.Errorf("unknown isolation level %q of struct %s, expected one of "+
+			"read_uncommitted, read_committed, repeatable_read, serializable", name, s.TypeName)
+	}
+	if d.SetIsolation() == "" {
+		return "", fmt.Errorf("isolation option of struct %s isn't supported by %s dialect",
+			s.TypeName, d.Name())
+	}
+	return fmt.Sprintf(d.SetIsolation(), level), nil
+}
+
+// sequenceNameRe matches names of sequences optionally qualified by schema
+var sequenceNameRe = regexp.MustCompile(`^[A-Za-z_]\w*(\.[A-Za-z_]\w*)?$`)
+
+// getSequence returns query selecting next value of sequence of "sequence"
+// option: numeric primary key is set by it before inserts
+func getSequence(s parser.ParsedStruct, opts structOptions, pk *field.Info, d dialect.Dialect) (string, error) {
+	name, ok := opts["sequence"]
+	if !ok {
+		return "", nil
+	}
+
+	if !sequenceNameRe.MatchString(name) {
+		return "", fmt.Errorf("invalid sequence name %q of struct %s", name, s.TypeName)
+	}
+	if pk == nil || !pk.IsNumeric || pk.IsPointer {
+		return "", fmt.Errorf("struct %s has no numeric primary key to be set by sequence", s.TypeName)
+	}
+	if d.NextSequenceValue() == "" {
+		return "", fmt.Errorf("sequence option of struct %s isn't supported by %s dialect",
+			s.TypeName, d.Name())
+	}
+	return fmt.Sprintf(d.NextSequenceValue(), name), nil
+}
+
+// SearchBackedFields returns fields filtered by external search engine
+func (c querySetStructConfig) SearchBackedFields() (ret []field.Info) {
+	for _, f := range c.Fields {
+		if f.IsSearchBacked {
+			ret = append(ret, f)
+		}
+	}
+	return ret
+}
+
+// PrimaryKeyArgName returns name of argument of primary key of generated funcs
+func (c querySetStructConfig) PrimaryKeyArgName() string {
+	return methods.ArgName(c.PrimaryKey.Name)
+}
+
+// HasChecks returns true if any field has check constraint
+func (c querySetStructConfig) HasChecks() bool {
+	return hasChecks(c.Fields)
+}
+
+// IsSoftDeleted returns true if struct has DeletedAt field: gorm marks
+// records as deleted instead of deleting them
+func (c querySetStructConfig) IsSoftDeleted() bool {
+	return isSoftDeleted(c.Fields)
+}
+
+// SyncedFields returns fields compared by SyncSet: relations, primary key and
+// timestamps of GORM aren't compared
+func (c querySetStructConfig) SyncedFields() (ret []field.Info) {
+	for _, f := range c.Fields {
+		if f.IsStruct || (f.IsPointer && f.GetPointed().IsStruct) || f.IsPrimaryKey {
+			continue
+		}
+		switch f.Name {
+		case "CreatedAt", "UpdatedAt", "DeletedAt":
+			continue
+		}
+		if c.PrimaryKey != nil && f.Name == c.PrimaryKey.Name {
+			continue
+		}
+		ret = append(ret, f)
+	}
+	return ret
+}
+
+// StatsFields returns fields of <Struct>Stats type
+func (c querySetStructConfig) StatsFields() []methods.StatsField {
+	return methods.GetStatsFields(c.Fields)
+}
+
+// HasCreatedAt returns true if struct has CreatedAt time field set by GORM
+func (c querySetStructConfig) HasCreatedAt() bool {
+	for _, f := range c.Fields {
+		if f.Name == "CreatedAt" && f.IsTime {
+			return true
+		}
+	}
+	return false
+}
+
+func hasChecks(fields []field.Info) bool {
+	for _, f := range fields {
+		if f.Check != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func isSoftDeleted(fields []field.Info) bool {
+	for _, f := range fields {
+		if f.Name == "DeletedAt" && f.IsPointer && f.GetPointed().IsTime {
+			return true
+		}
+	}
+	return false
+}
+
+// QuerySetMethods returns exported methods of queryset type: they are
+// included into queryset's interface
+func (c querySetStructConfig) QuerySetMethods() (ret methodsSlice) {
+	for _, m := range c.Methods {
+		name := m.GetMethodName()
+		if m.GetReceiverDeclaration() == "qs "+c.Name && ast.IsExported(name) {
+			ret = append(ret, m)
+		}
+	}
+	return ret
+}
+
+// fakeFinisherNames are names of finishers of fake queryset defined in template
+var fakeFinisherNames = map[string]bool{
+	"All": true, "Iterate": true, "AllInBatches": true, "One": true, "ExactlyOne": true,
+	"First": true, "Last": true, "Count": true, "Delete": true, "DeleteNum": true,
+	"SoftDelete": true, "SoftDeleteNum": true,
+}
+
+// FakeFinisherMethods returns methods of queryset implemented by its fake with
+// the same signature: finishers not returning queryset. Both queryset and fake
+// are asserted to implement interface of them, so they can't drift apart.
+func (c querySetStructConfig) FakeFinisherMethods() (ret methodsSlice) {
+	fakeNames := map[string]bool{}
+	for _, m := range c.Methods {
+		if m.GetReceiverDeclaration() == "qs Fake"+c.Name {
+			fakeNames[m.GetMethodName()] = true
+		}
+	}
+
+	for _, m := range c.QuerySetMethods() {
+		name := m.GetMethodName()
+		if (fakeFinisherNames[name] || fakeNames[name]) && m.GetReturnValuesDeclaration() != c.Name {
+			ret = append(ret, m)
+		}
+	}
+	return ret
 }
 
 type methodsSlice []methods.Method
 
 func (s methodsSlice) Len() int { return len(s) }
 func (s methodsSlice) Less(i, j int) bool {
-	return strings.Compare(s[i].GetMethodName(), s[j].GetMethodName()) < 0
+	if c := strings.Compare(s[i].GetMethodName(), s[j].GetMethodName()); c != 0 {
+		return c < 0
+	}
+	// methods of queryset and updater can have the same name
+	return strings.Compare(s[i].GetReceiverDeclaration(), s[j].GetReceiverDeclaration()) < 0
 }
 func (s methodsSlice) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
 
@@ -38,26 +347,401 @@ func (s querySetStructConfigSlice) Less(i, j int) bool {
 }
 func (s querySetStructConfigSlice) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
 
-func doesNeedToGenerateQuerySet(doc *ast.CommentGroup) bool {
-	if doc == nil {
-		return false
+// structOptions are options of struct set in doc-comment line after
+// "gen:qs", e.g. "gen:qs cache". Option can have value: "option=value".
+type structOptions map[string]string
+
+// parseGenQsComment parses "gen:qs [option ...]" doc-comment line
+func parseGenQsComment(line string) (structOptions, bool) {
+	parts := strings.SplitN(strings.TrimSpace(strings.TrimPrefix(line, "//")), ":", 2)
+	if len(parts) != 2 || strings.TrimSpace(parts[0]) != "gen" {
+		return nil, false
 	}
 
-	for _, c := range doc.List {
-		parts := strings.Split(strings.TrimSpace(c.Text), ":")
-		ok := len(parts) == 2 &&
-			strings.TrimSpace(strings.TrimPrefix(parts[0], "//")) == "gen" &&
-			strings.TrimSpace(parts[1]) == "qs"
-		if ok {
-			return true
+	words := strings.Fields(parts[1])
+	if len(words) == 0 || words[0] != "qs" {
+		return nil, false
+	}
+
+	opts := structOptions{}
+	for _, w := range words[1:] {
+		kv := strings.SplitN(w, "=", 2)
+		if len(kv) == 2 {
+			opts[kv[0]] = kv[1]
+		} else {
+			opts[kv[0]] = ""
 		}
 	}
+	return opts, true
+}
 
-	return false
+// getQuerySetOptions returns options of struct and false if struct doesn't
+// need queryset: its doc doesn't contain "gen:qs" line or, if allStructs is
+// true, it contains "gen:qs skip" line
+func getQuerySetOptions(doc *ast.CommentGroup, allStructs bool) (structOptions, bool) {
+	if doc != nil {
+		for _, c := range doc.List {
+			if opts, ok := parseGenQsComment(c.Text); ok {
+				if _, skip := opts["skip"]; skip {
+					return nil, false
+				}
+				return opts, true
+			}
+		}
+	}
+
+	if allStructs {
+		return structOptions{}, true
+	}
+	return nil, false
+}
+
+// parseGenIndexComment parses "gen:index name WHERE predicate" doc-comment
+// line declaring predicate of partial unique index
+func parseGenIndexComment(line string) (name, where string, ok bool) {
+	line = strings.TrimSpace(strings.TrimPrefix(line, "//"))
+	if !strings.HasPrefix(line, "gen:index ") {
+		return "", "", false
+	}
+
+	parts := strings.Fields(strings.TrimPrefix(line, "gen:index "))
+	if len(parts) < 3 || strings.ToUpper(parts[1]) != "WHERE" {
+		return "", "", false
+	}
+
+	return parts[0], strings.Join(parts[2:], " "), true
+}
+
+// getUniqueIndexes returns unique indexes of struct fields with predicates
+// of partial indexes declared in struct's doc
+func getUniqueIndexes(s parser.ParsedStruct, fields []field.Info) ([]field.UniqueIndex, error) {
+	indexes := field.GetUniqueIndexes(fields)
+	if s.Doc == nil {
+		return indexes, nil
+	}
+
+	for _, c := range s.Doc.List {
+		name, where, ok := parseGenIndexComment(c.Text)
+		if !ok {
+			continue
+		}
+
+		found := false
+		for i := range indexes {
+			if indexes[i].Name == name {
+				indexes[i].Where = where
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("struct %s has no fields with unique_index:%s tag for partial index",
+				s.TypeName, name)
+		}
+	}
+
+	return indexes, nil
+}
+
+var (
+	genProcRe     = regexp.MustCompile(`^gen:proc\s+(\w+)\s+([\w.]+)\s*\((.*)\)$`)
+	procArgNameRe = regexp.MustCompile(`^[A-Za-z_]\w*$`)
+)
+
+// procReservedArgNames are names of arguments and locals of generated call of
+// procedure: arguments can't shadow them
+var procReservedArgNames = map[string]bool{"db": true, "ret": true, "err": true}
+
+// parseGenProcComment parses "gen:proc Name sql_name(arg1 type1, arg2 type2)"
+// doc-comment line declaring stored procedure returning rows of struct
+func parseGenProcComment(line string) (*methods.Procedure, error) {
+	line = strings.TrimSpace(strings.TrimPrefix(line, "//"))
+	if !strings.HasPrefix(line, "gen:proc ") {
+		return nil, nil
+	}
+
+	m := genProcRe.FindStringSubmatch(line)
+	if m == nil {
+		return nil, fmt.Errorf("invalid procedure declaration %q: expected "+
+			"\"gen:proc Name sql_name(arg1 type1, arg2 type2)\"", line)
+	}
+
+	p := methods.Procedure{
+		Name:    m[1],
+		SQLName: m[2],
+	}
+	if strings.TrimSpace(m[3]) == "" {
+		return &p, nil
+	}
+	for _, arg := range strings.Split(m[3], ",") {
+		parts := strings.Fields(arg)
+		if len(parts) != 2 || !procArgNameRe.MatchString(parts[0]) {
+			return nil, fmt.Errorf("invalid argument %q of procedure %s: expected \"name type\"",
+				strings.TrimSpace(arg), p.Name)
+		}
+		if procReservedArgNames[parts[0]] || token.Lookup(parts[0]).IsKeyword() {
+			return nil, fmt.Errorf("argument %s of procedure %s must be renamed: db, ret, err and Go keywords "+
+				"are reserved", parts[0], p.Name)
+		}
+		p.Args = append(p.Args, methods.ProcedureArg{
+			Name: parts[0],
+			Type: parts[1],
+		})
+	}
+	return &p, nil
+}
+
+// getProcedures returns stored procedures declared in struct's doc
+func getProcedures(s parser.ParsedStruct, d dialect.Dialect) ([]methods.Procedure, error) {
+	if s.Doc == nil {
+		return nil, nil
+	}
+
+	var ret []methods.Procedure
+	for _, c := range s.Doc.List {
+		p, err := parseGenProcComment(c.Text)
+		if err != nil {
+			return nil, fmt.Errorf("struct %s: %s", s.TypeName, err)
+		}
+		if p == nil {
+			continue
+		}
+		if d.CallProcedure() == "" {
+			return nil, fmt.Errorf("procedure %s of struct %s isn't supported by %s dialect",
+				p.Name, s.TypeName, d.Name())
+		}
+		ret = append(ret, *p)
+	}
+	return ret, nil
+}
+
+func findField(fields []field.Info, name string) *field.Info {
+	for i := range fields {
+		if fields[i].Name == name {
+			return &fields[i]
+		}
+	}
+	return nil
+}
+
+// resolveJoin resolves columns of join of struct s by relation r. Foreign key
+// is <Relation>ID field of s for belongs to relation and <Struct>ID field of
+// related struct for has many relation.
+func resolveJoin(s parser.ParsedStruct, r field.Relation,
+	structsFields map[string][]field.Info) (methods.Join, error) {
+
+	j := methods.Join{
+		Relation: r,
+	}
+	relatedFields, ok := structsFields[r.TypeName]
+	if !ok {
+		return j, fmt.Errorf("struct %s has no queryset", r.TypeName)
+	}
+
+	fields := structsFields[s.TypeName]
+	fkStruct, pkStruct, fkFields, pkFields := s.TypeName, r.TypeName, fields, relatedFields
+	if r.IsHasMany {
+		fkStruct, pkStruct, fkFields, pkFields = r.TypeName, s.TypeName, relatedFields, fields
+	}
+	if j.ForeignKey == "" {
+		if r.IsHasMany {
+			j.ForeignKey = s.TypeName + "ID"
+		} else {
+			j.ForeignKey = r.Name + "ID"
+		}
+	}
+
+	fk := findField(fkFields, j.ForeignKey)
+	if fk == nil {
+		return j, fmt.Errorf("struct %s has no foreign key field %s", fkStruct, j.ForeignKey)
+	}
+	pk := getPrimaryKeyField(pkFields)
+	if pk == nil {
+		return j, fmt.Errorf("struct %s has no primary key", pkStruct)
+	}
+
+	if r.IsHasMany {
+		j.Column, j.RelatedColumn = pk.DBName, fk.DBName
+	} else {
+		j.Column, j.RelatedColumn = fk.DBName, pk.DBName
+	}
+	j.IsRelatedSoftDeleted = isSoftDeleted(relatedFields)
+	return j, nil
 }
 
-func genStructFieldInfos(s parser.ParsedStruct, pkgInfo *loader.PackageInfo) (ret []field.Info) {
+// objectMethodsOptions are options of struct, which generated code needs
+// object methods
+var objectMethodsOptions = []string{"cache", "mirror", "readonly", "notify", "isolation", "sequence"}
+
+// withoutObjectMethods returns methods without methods of objects of struct
+// and methods calling them: object methods can't be declared in another
+// package
+func withoutObjectMethods(ms []methods.Method, structName string) []methods.Method {
+	var ret []methods.Method
+	for _, m := range ms {
+		if m.GetReceiverDeclaration() != "o *"+structName && !strings.Contains(m.GetBody(), ".ToSearchDocument(") {
+			ret = append(ret, m)
+		}
+	}
+	return ret
+}
+
+// getRelations returns relations declared by fields of struct s
+func getRelations(s parser.ParsedStruct, pkgInfo *loader.PackageInfo) (ret []field.Relation) {
 	g := field.NewInfoGenerator(pkgInfo.Pkg)
+	for _, f := range s.Fields {
+		if r := g.GenRelation(f); r != nil {
+			ret = append(ret, *r)
+		}
+	}
+	return ret
+}
+
+// getJoins returns joins of relations of struct s with structs with querysets.
+// Relations, which can't be resolved, are skipped.
+func getJoins(s parser.ParsedStruct, pkgInfo *loader.PackageInfo,
+	structsFields map[string][]field.Info) (ret []methods.Join) {
+
+	for _, r := range getRelations(s, pkgInfo) {
+		if j, err := resolveJoin(s, r, structsFields); err == nil {
+			ret = append(ret, j)
+		}
+	}
+	return ret
+}
+
+// checkCASField returns error if field f is marked by queryset:"cas" tag,
+// but compare-and-set can't be generated for it
+func checkCASField(f field.Info, pk *field.Info) error {
+	if !f.IsCAS {
+		return nil
+	}
+
+	switch {
+	case pk == nil:
+		return fmt.Errorf("no primary key to compare-and-set field %s", f.Name)
+	case f.Name == pk.Name:
+		return fmt.Errorf("primary key %s can't be compared-and-set", f.Name)
+	case f.IsPointer || f.IsSQLNull() || f.IsStruct || f.IsJSON:
+		return fmt.Errorf("only not nullable columns can be compared-and-set, field %s isn't such", f.Name)
+	}
+	return nil
+}
+
+// checkFullTextField returns error if field f is marked by queryset:"fulltext"
+// tag, but full-text search can't be generated for it
+func checkFullTextField(f field.Info, d dialect.Dialect) error {
+	if !f.IsFullText {
+		return nil
+	}
+
+	if d.FullTextMatch() == "" {
+		return fmt.Errorf("full-text search of field %s isn't supported by %s dialect", f.Name, d.Name())
+	}
+	if !f.IsString && !((f.IsPointer || f.IsSQLNull()) && f.GetPointed().IsString) {
+		return fmt.Errorf("only strings can be searched by full-text search, field %s isn't string", f.Name)
+	}
+	return nil
+}
+
+// checkWeightField returns error if field f is marked by queryset:"weight"
+// tag, but weighted sampling can't be generated for it
+func checkWeightField(f field.Info, d dialect.Dialect) error {
+	if !f.IsWeight {
+		return nil
+	}
+
+	if d.WeightedRandomKey() == "" {
+		return fmt.Errorf("weighted sampling by field %s isn't supported by %s dialect", f.Name, d.Name())
+	}
+	v := f
+	if f.IsPointer || f.IsSQLNull() {
+		v = f.GetPointed()
+	}
+	if !v.IsNumeric || v.IsTime {
+		return fmt.Errorf("only numbers can be weights, field %s isn't number", f.Name)
+	}
+	return nil
+}
+
+// filteredColumn is a column filtered by ApplyFilters: values of filters must
+// have type TypeName, it's a pointed type for nullable fields
+type filteredColumn struct {
+	Field    string
+	Quoted   string
+	TypeName string
+	Nullable bool
+}
+
+// getFilterColumns returns columns of fields, which have comparison filters
+// and aren't marked by queryset:"nofilter" tag
+func getFilterColumns(fields []field.Info, d dialect.Dialect) []filteredColumn {
+	var ret []filteredColumn
+	for _, f := range fields {
+		if f.IsStruct || f.IsJSON || f.IsArray() || f.IsNoFilter {
+			continue
+		}
+		typeName := f.TypeName
+		if f.IsPointer || f.IsSQLNull() {
+			p := f.GetPointed()
+			if p.IsStruct || p.IsJSON || p.IsArray() {
+				continue
+			}
+			typeName = p.TypeName
+		}
+		ret = append(ret, filteredColumn{
+			Field:    f.Name,
+			Quoted:   d.Quote(f.DBName),
+			TypeName: typeName,
+			Nullable: f.IsPointer || f.IsSQLNull(),
+		})
+	}
+	return ret
+}
+
+// getVersionField returns version field of optimistic locking of struct:
+// field marked by queryset:"version" tag or integer field Version
+func getVersionField(s parser.ParsedStruct, fields []field.Info) (*field.Info, error) {
+	var ret *field.Info
+	for i, f := range fields {
+		if f.IsVersion || f.Name == "Version" && ret == nil {
+			ret = &fields[i]
+		}
+	}
+	if ret == nil || !ret.IsVersion && (!ret.IsNumeric || ret.IsTime) {
+		return nil, nil // not tagged Version field of other type isn't a version
+	}
+
+	if !ret.IsNumeric || ret.IsTime || ret.IsDecimal || ret.IsPointer {
+		return nil, fmt.Errorf("version field %s of struct %s must be not nullable integer", ret.Name, s.TypeName)
+	}
+	return ret, nil
+}
+
+// getTenantField returns field of tenant of struct named name or nil if
+// struct has no such field
+func getTenantField(s parser.ParsedStruct, fields []field.Info, name string) (*field.Info, error) {
+	if name == "" {
+		return nil, nil
+	}
+
+	for i, f := range fields {
+		if f.Name != name {
+			continue
+		}
+		if f.IsStruct || f.IsPointer || f.IsSQLNull() {
+			return nil, fmt.Errorf("tenant field %s of struct %s must be not nullable column", name, s.TypeName)
+		}
+		return &fields[i], nil
+	}
+	return nil, nil
+}
+
+func doesNeedToGenerateQuerySet(doc *ast.CommentGroup, allStructs bool) bool {
+	_, ok := getQuerySetOptions(doc, allStructs)
+	return ok
+}
+
+func genStructFieldInfos(s parser.ParsedStruct, g *field.InfoGenerator) (ret []field.Info) {
 	for _, f := range s.Fields {
 		fi := g.GenFieldInfo(f)
 		if fi == nil {
@@ -69,48 +753,436 @@ func genStructFieldInfos(s parser.ParsedStruct, pkgInfo *loader.PackageInfo) (re
 }
 
 func generateQuerySetConfigs(pkgInfo *loader.PackageInfo,
-	structs parser.ParsedStructs) querySetStructConfigSlice {
+	structs parser.ParsedStructs, d dialect.Dialect, cfg Config) (querySetStructConfigSlice, error) {
+
+	// structs are iterated in order of names: errors must not depend on order
+	// of map iteration
+	names := make([]string, 0, len(structs))
+	for name := range structs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
 
-	querySetStructConfigs := querySetStructConfigSlice{}
+	qsStructs := map[string]bool{}
+	shardedStructs := map[string]bool{} // structs with sharded option
+	namings := map[string]methods.Naming{}
+	for _, name := range names {
+		s := structs[name]
+		opts, ok := getStructQuerySetOptions(s, cfg)
+		if !ok {
+			continue
+		}
 
-	for _, s := range structs {
-		if !doesNeedToGenerateQuerySet(s.Doc) {
+		qsStructs[s.TypeName] = true
+		if _, ok = opts["sharded"]; ok {
+			shardedStructs[s.TypeName] = true
+		}
+
+		n, err := getNaming(s, opts, cfg.FilterPrefix)
+		if err != nil {
+			return nil, err
+		}
+		namings[s.TypeName] = n
+	}
+
+	// code of other package uses only exported fields and types of structs
+	g := field.NewInfoGenerator(pkgInfo.Pkg)
+	if cfg.OutPkg != "" {
+		g = field.NewQualifiedInfoGenerator(pkgInfo.Pkg)
+	}
+	structsFields := map[string][]field.Info{}
+	tenants := map[string]*field.Info{}
+	for _, name := range names {
+		s := structs[name]
+		if !qsStructs[s.TypeName] {
 			continue
 		}
 
-		fields := genStructFieldInfos(s, pkgInfo)
-		b := newMethodsBuilder(s, fields)
-		methods := b.Build()
+		structsFields[s.TypeName] = genStructFieldInfos(s, g)
+		tenant, err := getTenantField(s, structsFields[s.TypeName], cfg.TenantField)
+		if err != nil {
+			return nil, err
+		}
+		if tenant != nil {
+			n := namings[s.TypeName]
+			n.AllTenantsConstructor = "new" + n.QuerySet + "AllTenants"
+			namings[s.TypeName], tenants[s.TypeName] = n, tenant
+		}
+	}
+
+	c := querySetConfigsContext{
+		pkgInfo:        pkgInfo,
+		d:              d,
+		cfg:            cfg,
+		qsStructs:      qsStructs,
+		shardedStructs: shardedStructs,
+		namings:        namings,
+		structsFields:  structsFields,
+		tenants:        tenants,
+	}
+	var qsNames []string
+	for _, name := range names {
+		if qsStructs[structs[name].TypeName] {
+			qsNames = append(qsNames, name)
+		}
+	}
+
+	// configs of structs are generated concurrently: errors of all structs
+	// are returned with their positions
+	querySetStructConfigs := make(querySetStructConfigSlice, len(qsNames))
+	err := runParallel(cfg.Jobs, len(qsNames), func(i int) error {
+		s := structs[qsNames[i]]
+		qsConfig, err := c.generateQuerySetConfig(s)
+		if err != nil {
+			return positionError(s.File, s.Line, err)
+		}
+		querySetStructConfigs[i] = qsConfig
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return querySetStructConfigs, nil
+}
+
+// querySetConfigsContext is a context of generation of configs of querysets
+// of structs of package: it's shared by structs and is read-only
+type querySetConfigsContext struct {
+	pkgInfo        *loader.PackageInfo
+	d              dialect.Dialect
+	cfg            Config
+	qsStructs      map[string]bool // structs with querysets
+	shardedStructs map[string]bool // structs with sharded option
+	namings        map[string]methods.Naming
+	structsFields  map[string][]field.Info
+	tenants        map[string]*field.Info // tenant fields by structs
+}
+
+// generateQuerySetConfig generates config of queryset of struct s
+func (c querySetConfigsContext) generateQuerySetConfig(s parser.ParsedStruct) (querySetStructConfig, error) {
+	d := c.d
+
+	opts, _ := getStructQuerySetOptions(s, c.cfg)
+	if c.cfg.Strict {
+		opts["strict"] = ""
+	}
+	fields := c.structsFields[s.TypeName]
+	pk := getPrimaryKeyField(fields)
+	if _, ok := opts["cache"]; ok && pk == nil {
+		return querySetStructConfig{}, fmt.Errorf("struct %s has no primary key to be cached", s.TypeName)
+	}
+	sequence, err := getSequence(s, opts, pk, d)
+	if err != nil {
+		return querySetStructConfig{}, err
+	}
+	if pk != nil && pk.IsNumeric && pk.Default == "" && sequence == "" && !d.AutoIncrement() {
+		return querySetStructConfig{}, fmt.Errorf("numeric primary key %s of struct %s needs default value or "+
+			"sequence option (e.g. gen:qs sequence=%s_seq): %s dialect has no auto-increment",
+			pk.Name, s.TypeName, strings.ToLower(s.TypeName), d.Name())
+	}
+	if _, ok := opts["readonly"]; ok {
+		for _, name := range []string{"mirror", "notify", "isolation", "queue", "sequence"} {
+			if _, ok := opts[name]; ok {
+				return querySetStructConfig{}, fmt.Errorf("%s option of struct %s mutates rows: "+
+					"it can't be used with readonly option", name, s.TypeName)
+			}
+		}
+	}
+	if _, ok := opts["mirror"]; ok && (pk == nil || !pk.IsNumeric) {
+		return querySetStructConfig{}, fmt.Errorf("struct %s has no numeric primary key to be reconciled", s.TypeName)
+	}
+	if _, ok := opts["notify"]; ok {
+		if d.Name() != "postgres" {
+			return querySetStructConfig{}, fmt.Errorf("notify option of struct %s is supported only by postgres dialect",
+				s.TypeName)
+		}
+		if pk == nil {
+			return querySetStructConfig{}, fmt.Errorf("struct %s has no primary key for notifications", s.TypeName)
+		}
+	}
+
+	indexes, err := getUniqueIndexes(s, fields)
+	if err != nil {
+		return querySetStructConfig{}, err
+	}
+
+	var weight *field.Info
+	for i, f := range fields {
+		if _, err = methods.ParseCheck(f); err != nil {
+			return querySetStructConfig{}, fmt.Errorf("struct %s: %s", s.TypeName, err)
+		}
+		if err = checkCASField(f, pk); err != nil {
+			return querySetStructConfig{}, fmt.Errorf("struct %s: %s", s.TypeName, err)
+		}
+		if err = checkFullTextField(f, d); err != nil {
+			return querySetStructConfig{}, fmt.Errorf("struct %s: %s", s.TypeName, err)
+		}
+		if err = checkWeightField(f, d); err != nil {
+			return querySetStructConfig{}, fmt.Errorf("struct %s: %s", s.TypeName, err)
+		}
+		if f.IsWeight {
+			if weight != nil {
+				return querySetStructConfig{}, fmt.Errorf("struct %s has two weight fields %s and %s",
+					s.TypeName, weight.Name, f.Name)
+			}
+			weight = &fields[i]
+		}
+		if maxLen := d.MaxIdentifierLen(); maxLen != 0 && len(f.DBName) > maxLen {
+			return querySetStructConfig{}, fmt.Errorf("column %s of struct %s is longer than %d characters of %s dialect: "+
+				"set truncated name by column tag, e.g. `gorm:\"column:%s\"`", f.DBName, s.TypeName,
+				maxLen, d.Name(), dialect.TruncateIdentifier(f.DBName, maxLen))
+		}
+	}
+
+	if _, ok := opts["locale"]; ok && d.Collate() == "" {
+		return querySetStructConfig{}, fmt.Errorf("locale option of struct %s isn't supported by %s dialect: "+
+			"it has no collations of expressions", s.TypeName, d.Name())
+	}
+
+	if one, ok := opts["one"]; ok && one != "first" && one != "take" {
+		return querySetStructConfig{}, fmt.Errorf("unknown one=%s option of struct %s, expected one=first "+
+			"or one=take", one, s.TypeName)
+	}
+
+	if c.shardedStructs[s.TypeName] && d.Name() != "mysql" {
+		return querySetStructConfig{}, fmt.Errorf("sharded option of struct %s is supported only by mysql dialect "+
+			"(TiDB, Vitess)", s.TypeName)
+	}
+
+	var joins []methods.Join
+	for _, j := range getJoins(s, c.pkgInfo, c.structsFields) {
+		// sharding proxies don't push joins of subqueries down to shards
+		if !c.shardedStructs[s.TypeName] && !c.shardedStructs[j.TypeName] {
+			j.QuerySetName = c.namings[j.TypeName].QuerySet
+			joins = append(joins, j)
+		}
+	}
+	procedures, err := getProcedures(s, d)
+	if err != nil {
+		return querySetStructConfig{}, err
+	}
+
+	setIsolation, err := getSetIsolation(s, opts, d)
+	if err != nil {
+		return querySetStructConfig{}, err
+	}
+
+	queue, err := getJobQueue(s, opts, fields, pk, d)
+	if err != nil {
+		return querySetStructConfig{}, err
+	}
+
+	geo, err := getGeoPoint(s, fields, d)
+	if err != nil {
+		return querySetStructConfig{}, err
+	}
+
+	if c.cfg.OutPkg != "" {
+		for _, o := range objectMethodsOptions {
+			if _, ok := opts[o]; ok {
+				return querySetStructConfig{}, fmt.Errorf("%s option of struct %s isn't supported by out package: "+
+					"its code needs object methods", o, s.TypeName)
+			}
+		}
+	}
+
+	b := newMethodsBuilder(s, fields, c.qsStructs, d, c.namings[s.TypeName], opts, indexes, joins, procedures,
+		c.tenants[s.TypeName])
+	b.outPkg = c.cfg.OutPkg != ""
+	methods := b.Build()
+	if c.cfg.OutPkg != "" {
+		methods = withoutObjectMethods(methods, s.TypeName)
+	}
+
+	qsConfig := querySetStructConfig{
+		Naming:       c.namings[s.TypeName],
+		StructName:   s.TypeName,
+		Name:         c.namings[s.TypeName].QuerySet,
+		Methods:      methods,
+		Fields:       fields,
+		PrimaryKey:   pk,
+		Options:      opts,
+		SetIsolation: setIsolation,
+		Queue:        queue,
+		Sequence:     sequence,
+		Geo:          geo,
 
-		qsConfig := querySetStructConfig{
-			StructName: s.TypeName,
-			Name:       s.TypeName + "QuerySet",
-			Methods:    methods,
-			Fields:     fields,
+		AsOfSystemTime:  d.AsOfSystemTime(),
+		Explain:         d.Explain(),
+		UnionSelect:     d.UnionSelect(),
+		OrderedSubquery: d.OrderedSubquery(),
+		Collate:         d.Collate(),
+		FilterColumns:   getFilterColumns(fields, d),
+	}
+	if c.cfg.OutPkg != "" {
+		qsConfig.ModelPkg = c.pkgInfo.Pkg.Name()
+	}
+	qsConfig.Errors = getStructErrors(opts, indexes, pk, d)
+	if qsConfig.Version, err = getVersionField(s, fields); err != nil {
+		return querySetStructConfig{}, err
+	}
+	if qsConfig.Version != nil {
+		qsConfig.VersionCond = d.Quote(qsConfig.Version.DBName) + " = ?"
+	}
+	if tenant := c.tenants[s.TypeName]; tenant != nil {
+		qsConfig.Tenant, qsConfig.TenantCond = tenant, d.Quote(tenant.DBName)+" = ?"
+	}
+	if patterns := c.cfg.Models[s.TypeName].Methods; len(patterns) != 0 {
+		calls, err := getTemplateCalls(qsConfig, c.cfg)
+		if err != nil {
+			return querySetStructConfig{}, err
 		}
-		sort.Sort(qsConfig.Methods)
-		querySetStructConfigs = append(querySetStructConfigs, qsConfig)
+		qsConfig.Methods = filterQuerySetMethods(qsConfig.Methods, qsConfig.Name, patterns, calls)
+	}
+	sort.Sort(qsConfig.Methods)
+	return qsConfig, nil
+}
+
+func getQuerySetConfigs(pkgInfo *loader.PackageInfo, structs parser.ParsedStructs,
+	cfg Config) (querySetStructConfigSlice, error) {
+
+	d, err := dialect.Get(cfg.Dialect)
+	if err != nil {
+		return nil, err
+	}
+
+	querySetStructConfigs, err := generateQuerySetConfigs(pkgInfo, structs, d, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.CheckWhere {
+		if err = checkWhereConditions(pkgInfo.Files, querySetStructConfigs); err != nil {
+			return nil, err
+		}
+	}
+
+	sort.Sort(querySetStructConfigs)
+	return querySetStructConfigs, nil
+}
+
+// twoPhaseCommit contains statements of two-phase commit of dialect, they
+// have %s placeholder for quoted global identifier of transaction
+type twoPhaseCommit struct {
+	Prepare  string
+	Commit   string
+	Rollback string
+}
+
+func getTwoPhaseCommit(d dialect.Dialect) twoPhaseCommit {
+	if d.PrepareTransaction() == "" {
+		return twoPhaseCommit{}
+	}
+
+	return twoPhaseCommit{
+		Prepare:  fmt.Sprintf(d.PrepareTransaction(), "%s"),
+		Commit:   fmt.Sprintf(d.CommitPrepared(), "%s"),
+		Rollback: fmt.Sprintf(d.RollbackPrepared(), "%s"),
+	}
+}
+
+// deferredConstraints contains statements deferring constraints of dialect
+// and making them immediate, they are empty if dialect can't defer them
+type deferredConstraints struct {
+	Defer     string
+	Immediate string
+}
+
+// likeEscapeChar escapes wildcards in LIKE patterns of StartsWith, EndsWith
+// and Contains filters: backslash isn't an escape character by default in all
+// databases and has to be escaped in string literals of mysql
+const likeEscapeChar = "!"
+
+// getLikeEscapes returns arguments of strings.NewReplacer escaping wildcards
+// of dialect and escape character in LIKE patterns
+func getLikeEscapes(d dialect.Dialect) string {
+	var args []string
+	for _, c := range likeEscapeChar + "%_" + d.LikeWildcards() {
+		args = append(args, strconv.Quote(string(c)), strconv.Quote(likeEscapeChar+string(c)))
 	}
+	return strings.Join(args, ", ")
+}
+
+func getDeferredConstraints(d dialect.Dialect) deferredConstraints {
+	if d.SetConstraints() == "" {
+		return deferredConstraints{}
+	}
+
+	return deferredConstraints{
+		Defer:     fmt.Sprintf(d.SetConstraints(), "DEFERRED"),
+		Immediate: fmt.Sprintf(d.SetConstraints(), "IMMEDIATE"),
+	}
+}
 
-	return querySetStructConfigs
+// querySetsPart is a part of querysets of package generated into one file
+type querySetsPart struct {
+	File         string // only querysets of structs of file are generated if it's set
+	PackageFuncs bool   // generate package level funcs: WithTransaction etc
+}
+
+// configs returns configs of structs of part
+func (p querySetsPart) configs(configs querySetStructConfigSlice,
+	structs parser.ParsedStructs) querySetStructConfigSlice {
+
+	if p.File == "" {
+		return configs
+	}
+
+	var ret querySetStructConfigSlice
+	for _, c := range configs {
+		if structs[c.StructName].File == p.File {
+			ret = append(ret, c)
+		}
+	}
+	return ret
 }
 
 // GenerateQuerySetsForStructs is an internal method to retrieve querysets
 // generated code from parsed structs
-func GenerateQuerySetsForStructs(pkgInfo *loader.PackageInfo, structs parser.ParsedStructs) (io.Reader, error) {
+func GenerateQuerySetsForStructs(pkgInfo *loader.PackageInfo, structs parser.ParsedStructs,
+	cfg Config) (io.Reader, error) {
+
+	configs, err := getQuerySetConfigs(pkgInfo, structs, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return generateQuerySetsPart(configs, structs, cfg, querySetsPart{PackageFuncs: true})
+}
+
+// generateQuerySetsPart generates code of part of querysets of package with
+// configs of all its structs: parts don't depend on each other
+func generateQuerySetsPart(configs querySetStructConfigSlice, structs parser.ParsedStructs,
+	cfg Config, part querySetsPart) (io.Reader, error) {
 
-	querySetStructConfigs := generateQuerySetConfigs(pkgInfo, structs)
+	querySetStructConfigs := part.configs(configs, structs)
 	if len(querySetStructConfigs) == 0 {
 		return nil, nil
 	}
 
-	sort.Sort(querySetStructConfigs)
+	d, err := dialect.Get(cfg.Dialect)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl, err := getQuerySetsTemplate(cfg.TemplatesDir)
+	if err != nil {
+		return nil, err
+	}
 
 	var b bytes.Buffer
-	err := qsTmpl.Execute(&b, struct {
-		Configs querySetStructConfigSlice
+	err = tmpl.Execute(&b, struct {
+		Configs      querySetStructConfigSlice
+		TwoPhase     twoPhaseCommit
+		Constraints  deferredConstraints
+		LikeEscapes  string
+		PackageFuncs bool
 	}{
-		Configs: querySetStructConfigs,
+		Configs:      querySetStructConfigs,
+		TwoPhase:     getTwoPhaseCommit(d),
+		Constraints:  getDeferredConstraints(d),
+		LikeEscapes:  getLikeEscapes(d),
+		PackageFuncs: part.PackageFuncs,
 	})
 
 	if err != nil {
@@ -119,3 +1191,44 @@ func GenerateQuerySetsForStructs(pkgInfo *loader.PackageInfo, structs parser.Par
 
 	return &b, nil
 }
+
+// GenerateDebugVariantsForStructs is an internal method to retrieve generated
+// code of debug methods of querysets (debug) and of their no-op stubs (noDebug)
+func GenerateDebugVariantsForStructs(pkgInfo *loader.PackageInfo, structs parser.ParsedStructs,
+	cfg Config) (debug, noDebug io.Reader, err error) {
+
+	configs, err := getQuerySetConfigs(pkgInfo, structs, cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	return generateDebugVariantsPart(configs, structs, cfg, querySetsPart{})
+}
+
+func generateDebugVariantsPart(configs querySetStructConfigSlice, structs parser.ParsedStructs,
+	cfg Config, part querySetsPart) (debug, noDebug io.Reader, err error) {
+
+	noDebugTag, err := negateBuildTag(cfg.DebugBuildTag)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	data := struct {
+		Configs    querySetStructConfigSlice
+		DebugTag   string
+		NoDebugTag string
+	}{
+		Configs:    part.configs(configs, structs),
+		DebugTag:   cfg.DebugBuildTag,
+		NoDebugTag: noDebugTag,
+	}
+
+	var debugBuf, noDebugBuf bytes.Buffer
+	if err = debugTmpl.Execute(&debugBuf, data); err != nil {
+		return nil, nil, fmt.Errorf("can't generate debug methods: %s", err)
+	}
+	if err = noDebugTmpl.Execute(&noDebugBuf, data); err != nil {
+		return nil, nil, fmt.Errorf("can't generate no-op debug methods: %s", err)
+	}
+
+	return &debugBuf, &noDebugBuf, nil
+}
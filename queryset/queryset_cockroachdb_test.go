@@ -0,0 +1,50 @@
+package queryset
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/jirfag/go-queryset/queryset/test/cockroachdb"
+	"github.com/stretchr/testify/assert"
+
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func TestCockroachDBQueries(t *testing.T) {
+	funcs := []testQueryFunc{
+		testPaymentsAsOfSystemTime,
+	}
+	runTestQueryFuncs(t, funcs, newPostgresDB) // CockroachDB is postgres wire-compatible
+}
+
+func testPaymentsAsOfSystemTime(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	at := time.Date(2020, 1, 2, 3, 4, 5, 600000000, time.FixedZone("", 3600))
+	asOf := `AS OF SYSTEM TIME '2020-01-02 02:04:05.6'`
+	req := `SELECT * FROM "payments" ` + asOf + ` WHERE "payments".deleted_at IS NULL AND (("amount" > $1)) ` +
+		`ORDER BY "id" DESC`
+	m.ExpectQuery(fixedFullRe(req)).WithArgs(10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "amount"}).AddRow(2, 20).AddRow(1, 11))
+	m.ExpectQuery(fixedFullRe(req + " LIMIT 1")).WithArgs(10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "amount"}).AddRow(2, 20))
+	m.ExpectQuery(fixedFullRe(`SELECT count(*) FROM "payments" ` + asOf +
+		` WHERE "payments".deleted_at IS NULL AND (("amount" > $1))`)).WithArgs(10).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+
+	qs := cockroachdb.NewPaymentQuerySet(db).AmountGt(10)
+	var payments []cockroachdb.Payment
+	assert.Nil(t, qs.OrderDescByID().AsOfSystemTime(at).All(&payments))
+	assert.Len(t, payments, 2)
+
+	var p cockroachdb.Payment
+	assert.Nil(t, qs.OrderDescByID().AsOfSystemTime(at).One(&p))
+	assert.Equal(t, 20, p.Amount)
+
+	n, err := qs.AsOfSystemTime(at).Count()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, n)
+
+	cockroachdb.RegisterPaymentBreaker(db, &testBreaker{open: true})
+	_, err = cockroachdb.NewPaymentQuerySet(db).AsOfSystemTime(at).Count()
+	assert.Equal(t, errTestBreakerOpen, err)
+}
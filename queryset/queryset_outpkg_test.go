@@ -0,0 +1,33 @@
+//go:build go1.9
+// +build go1.9
+
+package queryset
+
+import (
+	"testing"
+
+	"github.com/jinzhu/gorm"
+	"github.com/jirfag/go-queryset/queryset/test/outpkg"
+	"github.com/jirfag/go-queryset/queryset/test/outpkg/queries"
+	"github.com/stretchr/testify/assert"
+
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+// querysets of out package declare aliases of models, so they are built
+// only by Go 1.9+
+
+func TestOutPackageQueries(t *testing.T) {
+	runTestQueryFuncs(t, []testQueryFunc{testOutPackageUsers}, newPostgresDB)
+}
+
+func testOutPackageUsers(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	req := `SELECT * FROM "users"  WHERE "users".deleted_at IS NULL AND (("status" = $1))`
+	m.ExpectQuery(fixedFullRe(req)).
+		WithArgs(outpkg.StatusActive).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "status"}).AddRow(1, "a", "active"))
+
+	var users []outpkg.User
+	assert.Nil(t, queries.NewUserQuerySet(db).StatusEq(outpkg.StatusActive).All(&users))
+	assert.Equal(t, []outpkg.User{{Model: gorm.Model{ID: 1}, Name: "a", Status: outpkg.StatusActive}}, users)
+}
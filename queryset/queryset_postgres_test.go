@@ -0,0 +1,400 @@
+package queryset
+
+import (
+	"context"
+	"log"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/jinzhu/gorm"
+	"github.com/jirfag/go-queryset/queryset/test/postgres"
+	"github.com/stretchr/testify/assert"
+
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func newPostgresDB() (sqlmock.Sqlmock, *gorm.DB) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatalf("can't create sqlmock: %s", err)
+	}
+
+	gormDB, gerr := gorm.Open("postgres", db)
+	if gerr != nil {
+		log.Fatalf("can't open gorm connection: %s", err)
+	}
+	gormDB.LogMode(true)
+
+	return mock, gormDB
+}
+
+func TestPostgresQueries(t *testing.T) {
+	funcs := []testQueryFunc{
+		testOrderCreateNotifies,
+		testOrderUpdateInTxNotifies,
+		testOrderFilters,
+		testOrderNumberEqFold,
+		testOrderUpsertByPartialIndex,
+		testOrderCreateIfNotExists,
+		testOrdersUnion,
+		testOrdersJoinItems,
+		testOrderItemsJSONFilters,
+		testOrderItemsUpdateBatch,
+		testOrderItemsFullTextSearch,
+		testOrderItemsLocalized,
+		testOrderForUpdate,
+		testOrderItemCreateIsolated,
+		testOrderItemDeleteInTx,
+		testPrepareTransaction,
+		testWithDeferredConstraints,
+		testShipmentCreateBySequence,
+	}
+	runTestQueryFuncs(t, funcs, newPostgresDB)
+}
+
+func testOrderItemCreateIsolated(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	m.ExpectBegin()
+	m.ExpectExec(fixedFullRe("SET TRANSACTION ISOLATION LEVEL SERIALIZABLE")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	req := `INSERT INTO "order_items" ("created_at","updated_at","deleted_at","order_id","sku","attrs") ` +
+		`VALUES ($1,$2,$3,$4,$5,$6) RETURNING "order_items"."id"`
+	m.ExpectQuery(fixedFullRe(req)).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), 1, "sku", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+	m.ExpectCommit()
+
+	o := postgres.OrderItem{OrderID: 1, SKU: "sku"}
+	assert.Nil(t, o.Create(db))
+	assert.Equal(t, uint(3), o.ID)
+}
+
+func testOrderItemDeleteInTx(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	m.ExpectBegin()
+	m.ExpectExec(fixedFullRe("SET TRANSACTION ISOLATION LEVEL SERIALIZABLE")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	req := `UPDATE "order_items" SET deleted_at=$1 WHERE "order_items".deleted_at IS NULL AND "order_items"."id" = $2`
+	m.ExpectExec(fixedFullRe(req)).WithArgs(sqlmock.AnyArg(), 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	m.ExpectCommit()
+
+	tx := postgres.BeginOrderItemTx(db)
+	assert.Nil(t, tx.Error)
+	o := postgres.OrderItem{Model: gorm.Model{ID: 3}}
+	assert.Nil(t, o.Delete(tx)) // it doesn't begin nested transaction
+	assert.Nil(t, tx.Commit().Error)
+}
+
+func testPrepareTransaction(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	m.ExpectBegin()
+	req := `UPDATE "orders" SET "number" = $1, "updated_at" = $2 WHERE "orders".deleted_at IS NULL AND (("id" = $3))`
+	m.ExpectExec(fixedFullRe(req)).WithArgs("n", sqlmock.AnyArg(), 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	m.ExpectExec(fixedFullRe("PREPARE TRANSACTION 'order''s 1'")).WillReturnResult(sqlmock.NewResult(0, 0))
+	m.ExpectCommit()
+	m.ExpectExec(fixedFullRe("COMMIT PREPARED 'order''s 1'")).WillReturnResult(sqlmock.NewResult(0, 0))
+	m.ExpectExec(fixedFullRe("ROLLBACK PREPARED 'order''s 2'")).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := postgres.PrepareTransaction(db, "order's 1", func(tx *gorm.DB) error {
+		return postgres.NewOrderQuerySetTx(tx).IDEq(1).GetUpdater().SetNumber("n").Update()
+	})
+	assert.Nil(t, err)
+	assert.Nil(t, postgres.CommitPrepared(db, "order's 1"))
+	assert.Nil(t, postgres.RollbackPrepared(db, "order's 2"))
+}
+
+func testWithDeferredConstraints(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	m.ExpectBegin()
+	m.ExpectExec(fixedFullRe("SET CONSTRAINTS ALL DEFERRED")).WillReturnResult(sqlmock.NewResult(0, 0))
+	req := `UPDATE "orders" SET deleted_at=$1 WHERE "orders".deleted_at IS NULL AND (("id" = $2))`
+	m.ExpectExec(fixedFullRe(req)).WithArgs(sqlmock.AnyArg(), 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	m.ExpectExec(fixedFullRe("SET CONSTRAINTS ALL IMMEDIATE")).WillReturnResult(sqlmock.NewResult(0, 0))
+	m.ExpectCommit()
+
+	err := postgres.WithTransaction(db, func(tx *gorm.DB) error {
+		return postgres.WithDeferredConstraints(tx, func(tx *gorm.DB) error {
+			return postgres.NewOrderQuerySetTx(tx).IDEq(1).Delete()
+		})
+	})
+	assert.Nil(t, err)
+
+	err = postgres.WithDeferredConstraints(db, func(tx *gorm.DB) error {
+		return nil
+	})
+	assert.NotNil(t, err) // db isn't a transaction
+}
+
+func testOrderCreateNotifies(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	m.ExpectBegin()
+	req := `INSERT INTO "orders" ("created_at","updated_at","deleted_at","number") VALUES ($1,$2,$3,$4) RETURNING "orders"."id"`
+	m.ExpectQuery(fixedFullRe(req)).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), "1").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+	m.ExpectExec(fixedFullRe("SELECT pg_notify($1, $2)")).
+		WithArgs(postgres.OrderNotifyChannel, `{"Model":"Order","Op":"create","PK":5}`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	m.ExpectCommit()
+
+	o := postgres.Order{Number: "1"}
+	assert.Nil(t, o.Create(db))
+	assert.Equal(t, uint(5), o.ID)
+}
+
+func testOrderUpdateInTxNotifies(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	m.ExpectBegin()
+	// gorm orders updated columns randomly
+	req := fixedFullRe(`UPDATE "orders" SET $1, $2 WHERE "orders".deleted_at IS NULL AND "orders"."id" = $3`)
+	req = strings.Replace(req, regexp.QuoteMeta(`$1, $2`), `("number" = \$1, "updated_at" = \$2|"updated_at" = \$1, "number" = \$2)`, 1)
+	m.ExpectExec(req).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), 7).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	m.ExpectExec(fixedFullRe("SELECT pg_notify($1, $2)")).
+		WithArgs(postgres.OrderNotifyChannel, `{"Model":"Order","Op":"update","PK":7}`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	m.ExpectCommit()
+
+	tx := db.Begin()
+	o := postgres.Order{Model: gorm.Model{ID: 7}, Number: "2"}
+	assert.Nil(t, o.Update(tx, postgres.OrderDBSchema.Number))
+	assert.Nil(t, tx.Commit().Error)
+}
+
+func testOrderFilters(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	req := `SELECT * FROM "orders" WHERE "orders".deleted_at IS NULL AND (("number" ILIKE $1)) ORDER BY "id" DESC LIMIT 10 OFFSET 20`
+	m.ExpectQuery(fixedFullRe(req)).WithArgs("a%").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "number"}).AddRow(1, "A1"))
+
+	var orders []postgres.Order
+	err := postgres.NewOrderQuerySet(db).
+		NumberILike("a%").
+		OrderDescByID().
+		Limit(10).
+		Offset(20).
+		All(&orders)
+	assert.Nil(t, err)
+	assert.Len(t, orders, 1)
+}
+
+func testOrderNumberEqFold(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	req := `SELECT * FROM "orders" WHERE "orders".deleted_at IS NULL AND ((LOWER("number") = LOWER($1)))`
+	m.ExpectQuery(fixedFullRe(req)).WithArgs("A1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "number"}).AddRow(1, "a1"))
+
+	var orders []postgres.Order
+	assert.Nil(t, postgres.NewOrderQuerySet(db).NumberEqFold("A1").All(&orders))
+	assert.Len(t, orders, 1)
+}
+
+func testOrderUpsertByPartialIndex(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	req := `INSERT INTO "orders" ("created_at","updated_at","deleted_at","number") VALUES ($1,$2,$3,$4) ` +
+		`ON CONFLICT ("number") WHERE deleted_at IS NULL DO UPDATE SET "updated_at" = EXCLUDED."updated_at","deleted_at" = EXCLUDED."deleted_at" ` +
+		`RETURNING "id"`
+	m.ExpectQuery(fixedFullRe(req)).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), "3").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+
+	o := postgres.Order{Number: "3"}
+	assert.Nil(t, o.UpsertByActiveNumber(db))
+	assert.Equal(t, uint(5), o.ID)
+
+	// all columns are conflict columns: there is nothing to update
+	req = `INSERT INTO "orders" ("created_at","updated_at","deleted_at","number","id") VALUES ($1,$2,$3,$4,$5) ` +
+		`ON CONFLICT ("number","updated_at","deleted_at") DO NOTHING RETURNING "id"`
+	m.ExpectQuery(fixedFullRe(req)).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), "3", 5).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	assert.Nil(t, o.Upsert(db, postgres.OrderDBSchema.Number, postgres.OrderDBSchema.UpdatedAt,
+		postgres.OrderDBSchema.DeletedAt))
+}
+
+func testOrderCreateIfNotExists(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	req := `INSERT INTO "orders" ("created_at","updated_at","deleted_at","number") VALUES ($1,$2,$3,$4) ` +
+		`ON CONFLICT ("number") DO NOTHING RETURNING "id"`
+	m.ExpectQuery(fixedFullRe(req)).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), "3").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	m.ExpectQuery(fixedFullRe(req)).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), "4").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(6))
+
+	o := postgres.Order{Number: "3"}
+	created, err := o.CreateIfNotExists(db, postgres.OrderDBSchema.Number)
+	assert.Nil(t, err)
+	assert.False(t, created)
+	assert.Zero(t, o.ID)
+
+	o = postgres.Order{Number: "4"}
+	created, err = o.CreateIfNotExists(db, postgres.OrderDBSchema.Number)
+	assert.Nil(t, err)
+	assert.True(t, created)
+	assert.Equal(t, uint(6), o.ID)
+
+	// object is validated before insert
+	_, err = (&postgres.Order{}).CreateIfNotExists(db, postgres.OrderDBSchema.Number)
+	assert.NotNil(t, err)
+}
+
+func testOrdersUnion(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	req := `(SELECT * FROM "orders"  WHERE "orders".deleted_at IS NULL AND (("number" = $1))) UNION ALL ` +
+		`(SELECT * FROM "orders"  WHERE "orders".deleted_at IS NULL AND (("number" = $2)))`
+	m.ExpectQuery(fixedFullRe(req)).WithArgs("1", "2").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	var orders []postgres.Order
+	qs := postgres.NewOrderQuerySet(db)
+	assert.Nil(t, qs.NumberEq("1").UnionAll(qs.NumberEq("2")).All(&orders))
+}
+
+func testOrdersJoinItems(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	req := `SELECT count(*) FROM "orders" JOIN (SELECT DISTINCT "order_id" AS "join_items_key" FROM "order_items" ` +
+		`WHERE "order_items".deleted_at IS NULL AND (("sku" IN ($1,$2)))) "join_items" ` +
+		`ON "join_items"."join_items_key" = "orders"."id" WHERE "orders".deleted_at IS NULL AND (("number" = $3))`
+	m.ExpectQuery(fixedFullRe(req)).WithArgs("a", "b", "1").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	n, err := postgres.NewOrderQuerySet(db).
+		NumberEq("1").
+		JoinItems(postgres.NewOrderItemQuerySet(db).SKUIn("a", "b")).
+		Count()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, n)
+}
+
+func testOrderItemsJSONFilters(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	req := `SELECT * FROM "order_items" WHERE "order_items".deleted_at IS NULL AND (("attrs" #>> $1 = $2) ` +
+		`AND ("attrs"::jsonb @> $3::jsonb) AND ("attrs" -> $4 IS NOT NULL))`
+	m.ExpectQuery(fixedFullRe(req)).WithArgs("{size,eu}", "42", `{"color":"red"}`, "size").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "attrs"}).AddRow(1, []byte(`{"color":"red","size":{"eu":42}}`)))
+
+	var items []postgres.OrderItem
+	err := postgres.NewOrderItemQuerySet(db).
+		AttrsJSONPathEq("size.eu", "42").
+		AttrsJSONContains(`{"color":"red"}`).
+		AttrsJSONHasKey("size").
+		All(&items)
+	assert.Nil(t, err)
+	if assert.Len(t, items, 1) {
+		assert.JSONEq(t, `{"color":"red","size":{"eu":42}}`, string(items[0].Attrs))
+	}
+}
+
+func testOrderItemsUpdateBatch(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	req := `UPDATE "order_items" SET "sku" = "source"."sku","updated_at" = "source"."updated_at" FROM ` +
+		`((SELECT "id","sku","updated_at" FROM "order_items" LIMIT 0) ` +
+		`UNION ALL VALUES ($1,$2,$3),($4,$5,$6)) AS "source" WHERE "order_items"."id" = "source"."id"`
+	m.ExpectExec(fixedFullRe(req)).WithArgs(1, "a", sqlmock.AnyArg(), 2, "b", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	items := []postgres.OrderItem{{SKU: "a"}, {SKU: "b"}}
+	items[0].ID, items[1].ID = 1, 2
+	assert.Nil(t, postgres.UpdateOrderItemBatch(db, items, postgres.OrderItemDBSchema.SKU))
+}
+
+func testOrderItemsFullTextSearch(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	req := `SELECT * FROM "order_items" WHERE "order_items".deleted_at IS NULL AND ` +
+		`((to_tsvector(concat_ws(' ', "sku")) @@ plainto_tsquery($1)))`
+	m.ExpectQuery(fixedFullRe(req)).WithArgs("red shoes").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+	var items []postgres.OrderItem
+	assert.Nil(t, postgres.NewOrderItemQuerySet(db).SearchSKU("red shoes").All(&items))
+	assert.Len(t, items, 1)
+}
+
+func testOrderItemsLocalized(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	req := `SELECT * FROM "order_items" WHERE "order_items".deleted_at IS NULL AND ` +
+		`((to_tsvector($1::regconfig, concat_ws(' ', "sku")) @@ plainto_tsquery($2::regconfig, $3))) ` +
+		`ORDER BY "sku" COLLATE "de-DE-x-icu" DESC`
+	m.ExpectQuery(fixedFullRe(req)).WithArgs("german", "german", "schuhe").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	req = `SELECT * FROM "order_items" WHERE "order_items".deleted_at IS NULL ORDER BY "sku" ASC`
+	m.ExpectQuery(fixedFullRe(req)).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+	ctx := postgres.WithOrderItemLocale(context.Background(),
+		postgres.OrderItemLocale{Collation: "de-DE-x-icu", SearchConfig: "german"})
+	var items []postgres.OrderItem
+	err := postgres.NewOrderItemQuerySet(db).Localized(ctx).SearchSKU("schuhe").OrderDescBySKU().All(&items)
+	assert.Nil(t, err)
+	assert.Len(t, items, 1)
+
+	// queryset isn't localized without locale in ctx
+	err = postgres.NewOrderItemQuerySet(db).Localized(context.Background()).OrderAscBySKU().All(&items)
+	assert.Nil(t, err)
+
+	ctx = postgres.WithOrderItemLocale(ctx, postgres.OrderItemLocale{Collation: `de" ASC; --`})
+	err = postgres.NewOrderItemQuerySet(db).Localized(ctx).OrderAscBySKU().All(&items)
+	assert.Contains(t, err.Error(), "invalid collation")
+}
+
+func testOrderForUpdate(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	req := `SELECT * FROM "orders" WHERE "orders".deleted_at IS NULL AND (("id" = $1)) ` +
+		`ORDER BY "orders"."id" ASC LIMIT 1 FOR UPDATE`
+	m.ExpectBegin()
+	m.ExpectQuery(fixedFullRe(req)).WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "number"}).AddRow(1, "A1"))
+	m.ExpectCommit()
+
+	tx := db.Begin()
+	var o postgres.Order
+	assert.Nil(t, postgres.NewOrderQuerySet(tx).IDEq(1).ForUpdate().One(&o))
+	assert.Nil(t, tx.Commit().Error)
+	assert.Equal(t, "A1", o.Number)
+}
+
+func TestOrderChecks(t *testing.T) {
+	m, db := newPostgresDB()
+	defer checkMock(t, m)
+
+	o := postgres.Order{}
+	expErr := postgres.OrderCheckError{
+		Field: postgres.OrderDBSchema.Number,
+		Check: "char_length(number) > 0",
+	}
+	assert.Equal(t, expErr, o.Validate())
+	assert.Equal(t, expErr, o.Create(db)) // no queries are expected
+	assert.Equal(t, expErr, o.Update(db, postgres.OrderDBSchema.Number))
+
+	o.Number = "1"
+	assert.Nil(t, o.Validate())
+}
+
+func TestHandleOrderEvents(t *testing.T) {
+	payloads := make(chan string, 2)
+	payloads <- `{"Model":"Order","Op":"delete","PK":3}`
+	close(payloads)
+
+	var events []postgres.OrderEvent
+	err := postgres.HandleOrderEvents(payloads, func(e postgres.OrderEvent) error {
+		events = append(events, e)
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []postgres.OrderEvent{{Model: "Order", Op: "delete", PK: 3}}, events)
+
+	_, err = postgres.DecodeOrderEvent("{")
+	assert.NotNil(t, err)
+}
+
+func testShipmentCreateBySequence(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	m.ExpectQuery(fixedFullRe("SELECT nextval('shipments_id_seq')")).
+		WillReturnRows(sqlmock.NewRows([]string{"nextval"}).AddRow(42))
+	m.ExpectBegin()
+	req := `INSERT INTO "shipments" ("id","created_at","updated_at","deleted_at","order_id") ` +
+		`VALUES ($1,$2,$3,$4,$5) RETURNING "shipments"."id"`
+	m.ExpectQuery(fixedFullRe(req)).
+		WithArgs(42, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(42))
+	m.ExpectCommit()
+
+	o := postgres.Shipment{OrderID: 1}
+	assert.Nil(t, o.Create(db))
+	assert.Equal(t, uint(42), o.ID)
+
+	// primary key which is already set isn't replaced
+	m.ExpectBegin()
+	m.ExpectQuery(fixedFullRe(req)).
+		WithArgs(7, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+	m.ExpectCommit()
+
+	o = postgres.Shipment{Model: gorm.Model{ID: 7}, OrderID: 1}
+	assert.Nil(t, o.Create(db))
+}
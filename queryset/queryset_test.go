@@ -1,9 +1,16 @@
 package queryset
 
 import (
+	"bytes"
+	"context"
 	"database/sql"
 	"database/sql/driver"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"go/ast"
+	"go/build"
+	"io/ioutil"
 	"log"
 	"math/rand"
 	"os"
@@ -17,6 +24,10 @@ import (
 
 	"github.com/jinzhu/gorm"
 	_ "github.com/jinzhu/gorm/dialects/mysql"
+	"github.com/jirfag/go-queryset/parser"
+	"github.com/jirfag/go-queryset/queryset/dialect"
+	"github.com/jirfag/go-queryset/queryset/field"
+	"github.com/jirfag/go-queryset/queryset/methods"
 	"github.com/jirfag/go-queryset/queryset/test"
 	"github.com/stretchr/testify/assert"
 
@@ -109,13 +120,77 @@ func TestQueries(t *testing.T) {
 		testUserSelectOne,
 		testUserCreateOne,
 		testUserUpdateFieldsByPK,
+		testUserSave,
 		testUserUpdateByEmail,
 		testUserDeleteByEmail,
 		testUserDeleteByPK,
 		testUserQueryFilters,
 		testUsersCount,
+		testUsersOffset,
 		testUsersUpdateNum,
+		testUsersReindexAll,
+		testUsersCreateBatch,
+		testUsersUpdateBatch,
+		testUsersSyncSet,
+		testUsersSearchByName,
+		testUserCache,
+		testUserUpsert,
+		testUserCreateIfNotExists,
+		testUsersThrottledDelete,
+		testUsersNPlusOneDetector,
+		testPostsJoinBlog,
+		testUsersRelationCount,
+		testUsersInSubquery,
+		testUsersUnion,
+		testUsersNameSubstrings,
+		testUsersNameMatches,
+		testUsersEqIfSet,
+		testPostsApplyFilters,
+		testPostsOrderByParam,
+		testPostsDraftIsFalse,
+		testPostsSQLNullFilters,
+		testPostsTitleEqNullable,
+		testUsersMemoized,
+		testUsersFailIfMoreThan,
+		testUsersSoftDelete,
+		testPostsJSONFilters,
+		testPostsFullTextSearch,
+		testUsersForShare,
+		testJobsClaimNext,
+		testJobsHeartbeatAndReclaim,
+		testJobsSampleWeighted,
+		testPlacesGeo,
+		testPlaceOne,
+		testPlacesViews,
+		testInvoicesTenant,
+		testInvoicesTenantCache,
+		testInvoicesTenantCAS,
+		testInvoicesOptimisticLocking,
+		testInvoicesErrors,
+		testUsersExplain,
+		testUsersIterate,
+		testEventsChunkedIn,
+		testEventsStats,
+		testEventsCASKind,
+		testEventsNamedTypes,
+		testEventsEnumFilters,
+		testUsersAllInBatches,
+		testUsersPluckEmail,
+		testUsersWhere,
+		testUsersScopes,
+		testUsersBranching,
+		testUsersCallTopUsers,
+		testUsersDistinct,
+		testWithTransaction,
+		testUsersFirstLast,
+		testUsersExactlyOne,
+		testCommentsNaming,
+		testCommentsStrict,
 	}
+	runTestQueryFuncs(t, funcs, newDB)
+}
+
+func runTestQueryFuncs(t *testing.T, funcs []testQueryFunc, newDB func() (sqlmock.Sqlmock, *gorm.DB)) {
 	for _, f := range funcs {
 		f := f // save range var
 		funcName := runtime.FuncForPC(reflect.ValueOf(f).Pointer()).Name()
@@ -160,153 +235,2416 @@ func testUserSelectOne(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
 	assert.Equal(t, expUsers[0], user)
 }
 
-type qsQuerier func(qs test.UserQuerySet) test.UserQuerySet
+func testUsersNPlusOneDetector(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	var reports []string
+	reset := test.RegisterUserNPlusOneDetector(db, 2, func(sql string, n int) {
+		reports = append(reports, fmt.Sprintf("%d %s", n, sql))
+	})
 
-type userQueryTestCase struct {
-	q    string
-	args []driver.Value
-	qs   qsQuerier
+	req := "SELECT * FROM `users`  WHERE `users`.deleted_at IS NULL AND ((`email` = ?))"
+	emails := []string{"a@example.com", "b@example.com", "c@example.com"}
+	for _, email := range emails {
+		m.ExpectQuery(fixedFullRe(req)).WithArgs(email).WillReturnRows(getRowsForUsers(nil))
+	}
+	for _, email := range emails {
+		var users []test.User
+		assert.Nil(t, test.NewUserQuerySet(db).EmailEq(email).All(&users))
+	}
+	assert.Equal(t, []string{"2 " + req}, reports)
+
+	reset()
+	m.ExpectQuery(fixedFullRe(req)).WithArgs(emails[0]).WillReturnRows(getRowsForUsers(nil))
+	var users []test.User
+	assert.Nil(t, test.NewUserQuerySet(db).EmailEq(emails[0]).All(&users))
+	assert.Len(t, reports, 1)
 }
 
-func testUserQueryFilters(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
-	cases := []userQueryTestCase{
-		{
-			q:    "((name IN (?)))",
-			args: []driver.Value{"a"},
-			qs: func(qs test.UserQuerySet) test.UserQuerySet {
-				return qs.NameIn("a")
-			},
-		},
-		{
-			q:    "((name IN (?,?)))",
-			args: []driver.Value{"a", "b"},
-			qs: func(qs test.UserQuerySet) test.UserQuerySet {
-				return qs.NameIn("a", "b")
-			},
-		},
-		{
-			q:    "((name NOT IN (?)))",
-			args: []driver.Value{"a"},
-			qs: func(qs test.UserQuerySet) test.UserQuerySet {
-				return qs.NameNotIn("a")
-			},
-		},
-		{
-			q:    "((name NOT IN (?,?)))",
-			args: []driver.Value{"a", "b"},
-			qs: func(qs test.UserQuerySet) test.UserQuerySet {
-				return qs.NameNotIn("a", "b")
-			},
-		},
+func testUsersRelationCount(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	posts := "FROM `posts` `related_posts` WHERE `related_posts`.`user_id` = `users`.`id` AND " +
+		"`related_posts`.`deleted_at` IS NULL"
+	req := "SELECT * FROM `users` WHERE `users`.deleted_at IS NULL AND ((EXISTS (SELECT 1 " + posts + ")) AND " +
+		"((SELECT COUNT(*) " + posts + ") > ?))"
+	m.ExpectQuery(fixedFullRe(req)).WithArgs(2).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	var users []test.User
+	assert.Nil(t, test.NewUserQuerySet(db).HasPosts().PostsCountGt(2).All(&users))
+}
+
+func testUsersInSubquery(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	req := "SELECT * FROM `users` WHERE `users`.deleted_at IS NULL AND ((`id` IN (SELECT `posts`.`user_id` " +
+		"FROM `posts`  WHERE `posts`.deleted_at IS NULL AND ((`draft` = ?)))) AND (`id` NOT IN " +
+		"(SELECT `posts`.`user_id` FROM `posts`  WHERE `posts`.deleted_at IS NULL AND ((`title` = ?)))))"
+	m.ExpectQuery(fixedFullRe(req)).WithArgs(true, "go").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	drafts := test.NewPostQuerySet(db).DraftEq(true).SelectUserID()
+	gophers := test.NewPostQuerySet(db).TitleEq("go").SelectUserID()
+	var users []test.User
+	assert.Nil(t, test.NewUserQuerySet(db).IDInSubquery(drafts).IDNotInSubquery(gophers).All(&users))
+}
+
+func testUsersUnion(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	newest := "(SELECT * FROM `users`  WHERE `users`.deleted_at IS NULL ORDER BY `id` DESC LIMIT 2)"
+	named := "(SELECT * FROM `users`  WHERE `users`.deleted_at IS NULL AND ((`name` = ?)))"
+	m.ExpectQuery(fixedFullRe(newest + " UNION " + named)).WithArgs("a").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	m.ExpectQuery(fixedFullRe("SELECT count(*) FROM ("+newest+" UNION ALL "+named+" UNION "+named+
+		") `union_rows`")).WithArgs("a", "a").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	limited := "(SELECT * FROM `users`  WHERE `users`.deleted_at IS NULL LIMIT 2)"
+	m.ExpectQuery(fixedFullRe(limited + " UNION " + named)).WithArgs("a").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+
+	qs := test.NewUserQuerySet(db)
+	union := qs.OrderDescByID().Limit(2).Union(qs.NameEq("a"))
+	var users []test.User
+	assert.Nil(t, union.All(&users))
+	assert.Len(t, users, 1)
+
+	n, err := qs.OrderDescByID().Limit(2).UnionAll(qs.NameEq("a")).Union(qs.NameEq("a")).Count()
+	assert.Nil(t, err)
+	assert.Equal(t, 3, n)
+
+	err = qs.FailIfMoreThan(1).Union(qs.NameEq("a")).All(&users)
+	assert.Equal(t, test.UserTooManyRowsError{Max: 1}, err)
+	assert.Nil(t, users)
+}
+
+func testUsersNameSubstrings(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	req := "SELECT * FROM `users` WHERE `users`.deleted_at IS NULL AND ((`name` LIKE ? ESCAPE '!') AND " +
+		"(`name` LIKE ? ESCAPE '!') AND (`name` LIKE ? ESCAPE '!'))"
+	m.ExpectQuery(fixedFullRe(req)).WithArgs("a!_b%", "%100!%", "%!!%").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	var users []test.User
+	err := test.NewUserQuerySet(db).NameStartsWith("a_b").NameEndsWith("100%").NameContains("!").All(&users)
+	assert.Nil(t, err)
+}
+
+func testUsersNameMatches(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	req := "SELECT * FROM `users` WHERE `users`.deleted_at IS NULL AND ((`name` REGEXP ?))"
+	m.ExpectQuery(fixedFullRe(req)).WithArgs("^a[0-9]+$").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	var users []test.User
+	assert.Nil(t, test.NewUserQuerySet(db).NameMatches("^a[0-9]+$").All(&users))
+}
+
+func testUsersEqIfSet(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	req := "SELECT * FROM `users` WHERE `users`.deleted_at IS NULL AND ((`name` = ?))"
+	m.ExpectQuery(fixedFullRe(req)).WithArgs("a").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	var users []test.User
+	err := test.NewUserQuerySet(db).NameEqIfSet("a").EmailEqIfSet("").IDEqIfSet(0).
+		CreatedAtEqIfSet(time.Time{}).All(&users)
+	assert.Nil(t, err)
+}
+
+func testPostsApplyFilters(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	req := "SELECT * FROM `posts` WHERE `posts`.deleted_at IS NULL AND ((`published_at` IS NULL) AND " +
+		"(`title` LIKE ?) AND (`user_id` IN (?,?)) AND (`views` >= ?))"
+	m.ExpectQuery(fixedFullRe(req)).WithArgs("go%", 1, 2, 10).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	qs := test.NewPostQuerySet(db)
+	filtered, err := qs.ApplyFilters(map[test.PostDBSchemaField]test.FilterSpec{
+		test.PostDBSchema.PublishedAt: {Op: "isnull"},
+		test.PostDBSchema.Views:       {Op: "gte", Value: int64(10)},
+		test.PostDBSchema.Title:       {Op: "like", Value: "go%"},
+		test.PostDBSchema.UserID:      {Op: "in", Value: []uint{1, 2}},
+	})
+	assert.Nil(t, err)
+	var posts []test.Post
+	assert.Nil(t, filtered.All(&posts))
+
+	for _, filters := range []map[test.PostDBSchemaField]test.FilterSpec{
+		{test.PostDBSchemaField("password"): {Op: "eq", Value: "a"}},
+		{test.PostDBSchema.Meta: {Op: "eq", Value: "{}"}},
+		{test.PostDBSchema.Title: {Op: "regexp", Value: "a"}},
+		{test.PostDBSchema.Views: {Op: "eq", Value: 10}},
+		{test.PostDBSchema.UserID: {Op: "in", Value: []uint{}}},
+		{test.PostDBSchema.Views: {Op: "like", Value: int64(1)}},
+		{test.PostDBSchema.UserID: {Op: "isnull"}},
+		{test.PostDBSchema.Draft: {Op: "eq", Value: true}},
+	} {
+		_, err := qs.ApplyFilters(filters)
+		assert.NotNil(t, err, "%v", filters)
 	}
-	for _, c := range cases {
-		t.Run(c.q, func(t *testing.T) {
-			runUserQueryFilterSubTest(t, c, m, db)
-		})
+}
+
+func testPostsOrderByParam(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	req := "SELECT * FROM `posts` WHERE `posts`.deleted_at IS NULL ORDER BY `created_at` DESC,`title` ASC"
+	m.ExpectQuery(fixedFullRe(req)).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	qs := test.NewPostQuerySet(db)
+	ordered, err := qs.OrderByParam("-created_at, title")
+	assert.Nil(t, err)
+	var posts []test.Post
+	assert.Nil(t, ordered.All(&posts))
+
+	same, err := qs.OrderByParam(" ")
+	assert.Nil(t, err)
+	assert.Equal(t, qs.CacheKey(), same.CacheKey())
+
+	for _, param := range []string{"Title", "-", "title,", "meta", "id; DROP TABLE posts"} {
+		_, err := qs.OrderByParam(param)
+		assert.NotNil(t, err, param)
 	}
 }
 
-func runUserQueryFilterSubTest(t *testing.T, c userQueryTestCase, m sqlmock.Sqlmock, db *gorm.DB) {
-	expUsers := getTestUsers(5)
-	req := "SELECT * FROM `users` WHERE `users`.deleted_at IS NULL AND " + c.q
-	m.ExpectQuery(fixedFullRe(req)).WithArgs(c.args...).
-		WillReturnRows(getRowsForUsers(expUsers))
+func testPostsJoinBlog(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	req := "SELECT `posts`.* FROM `posts` JOIN (SELECT DISTINCT `id` AS `join_blog_key` FROM `blogs` " +
+		"WHERE `blogs`.deleted_at IS NULL AND ((`myname` = ?))) `join_blog` " +
+		"ON `join_blog`.`join_blog_key` = `posts`.`blog_id` WHERE `posts`.deleted_at IS NULL AND ((`title` IS NULL))"
+	m.ExpectQuery(fixedFullRe(req)).WithArgs("go").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "blog_id"}).AddRow(1, 2))
 
-	var users []test.User
-	assert.Nil(t, c.qs(test.NewUserQuerySet(db)).All(&users))
-	assert.Equal(t, expUsers, users)
+	var posts []test.Post
+	err := test.NewPostQuerySet(db).
+		JoinBlog(test.NewBlogQuerySet(db).NameEq("go")).
+		TitleIsNull().
+		All(&posts)
+	assert.Nil(t, err)
+	if assert.Len(t, posts, 1) {
+		assert.Equal(t, uint(1), posts[0].ID)
+	}
 }
 
-func testUserCreateOne(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
-	u := getUserNoID()
-	req := "INSERT INTO `users` (`created_at`,`updated_at`,`deleted_at`,`name`,`email`) VALUES (?,?,?,?,?)"
-	args := []driver.Value{sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
-		u.Name, u.Email}
-	m.ExpectExec(fixedFullRe(req)).
-		WithArgs(args...).
-		WillReturnResult(sqlmock.NewResult(2, 1))
-	assert.Nil(t, u.Create(db))
-	assert.Equal(t, uint(2), u.ID)
+func testUsersMemoized(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	users := getTestUsers(2)
+	req := "SELECT * FROM `users` WHERE `users`.deleted_at IS NULL AND ((`name` = ?))"
+	m.ExpectQuery(fixedFullRe(req)).WithArgs("a").WillReturnRows(getRowsForUsers(users))
+	m.ExpectQuery(fixedFullRe(req)).WithArgs("b").WillReturnRows(getRowsForUsers(users[:1]))
+	countReq := "SELECT count(*) FROM `users` WHERE `users`.deleted_at IS NULL AND ((`name` = ?))"
+	m.ExpectQuery(fixedFullRe(countReq)).WithArgs("a").WillReturnRows(getRowWithFields([]driver.Value{2}))
+
+	ctx := test.WithUserQueryMemo(context.Background())
+	for i := 0; i < 2; i++ {
+		var got []test.User
+		assert.Nil(t, test.NewUserQuerySet(db).Memoized(ctx).NameEq("a").All(&got))
+		assert.Equal(t, users, got)
+		got[0].Name = "changed" // memoized result isn't shared
+	}
+
+	var got []test.User
+	assert.Nil(t, test.NewUserQuerySet(db).NameEq("b").Memoized(ctx).All(&got))
+	assert.Len(t, got, 1)
+
+	for i := 0; i < 2; i++ {
+		n, err := test.NewUserQuerySet(db).NameEq("a").Memoized(ctx).Count()
+		assert.Nil(t, err)
+		assert.Equal(t, 2, n)
+	}
+
+	// partially selected rows aren't returned for all columns
+	distinctReq := "SELECT DISTINCT `name` FROM `users` WHERE `users`.deleted_at IS NULL AND ((`name` = ?))"
+	m.ExpectQuery(fixedFullRe(distinctReq)).WithArgs("a").
+		WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("a"))
+	got = nil
+	assert.Nil(t, test.NewUserQuerySet(db).NameEq("a").DistinctName().Memoized(ctx).All(&got))
+	assert.Len(t, got, 1)
+
+	comments := test.QueryComments(db)
+	assert.NotEqual(t, comments.CacheKey(), comments.PreloadPost().CacheKey())
+	assert.Equal(t, comments.PreloadPost().CacheKey(), comments.PreloadPost().CacheKey())
 }
 
-func testUserUpdateByEmail(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
-	u := getUser()
-	req := "UPDATE `users` SET `name` = ? WHERE `users`.deleted_at IS NULL AND ((email = ?))"
-	m.ExpectExec(fixedFullRe(req)).
-		WithArgs(u.Name, u.Email).
+func testUsersFailIfMoreThan(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	users := getTestUsers(3)
+	req := "SELECT * FROM `users` WHERE `users`.deleted_at IS NULL AND ((`name` = ?)) LIMIT 3"
+	m.ExpectQuery(fixedFullRe(req)).WithArgs("a").WillReturnRows(getRowsForUsers(users))
+	m.ExpectQuery(fixedFullRe(req)).WithArgs("b").WillReturnRows(getRowsForUsers(users[:2]))
+	pluckReq := "SELECT `email` FROM `users` WHERE `users`.deleted_at IS NULL AND ((`name` = ?)) LIMIT 3"
+	m.ExpectQuery(fixedFullRe(pluckReq)).WithArgs("a").
+		WillReturnRows(sqlmock.NewRows([]string{"email"}).AddRow("a@x.com").AddRow("b@x.com").AddRow("c@x.com"))
+	countReq := "SELECT count(*) FROM `users` WHERE `users`.deleted_at IS NULL AND ((`name` = ?))"
+	m.ExpectQuery(fixedFullRe(countReq)).WithArgs("a").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	m.ExpectQuery(fixedFullRe(countReq + " LIMIT 3")).WithArgs("a").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+	var got []test.User
+	err := test.NewUserQuerySet(db).NameEq("a").FailIfMoreThan(2).All(&got)
+	assert.Equal(t, test.UserTooManyRowsError{Max: 2}, err)
+	assert.Nil(t, got)
+	assert.Nil(t, test.NewUserQuerySet(db).NameEq("b").FailIfMoreThan(2).All(&got))
+	assert.Equal(t, users[:2], got)
+
+	emails, err := test.NewUserQuerySet(db).NameEq("a").FailIfMoreThan(2).PluckEmail()
+	assert.Equal(t, test.UserTooManyRowsError{Max: 2}, err)
+	assert.Nil(t, emails)
+
+	var one test.User
+	err = test.NewUserQuerySet(db).NameEq("a").FailIfMoreThan(2).One(&one)
+	assert.Equal(t, test.UserTooManyRowsError{Max: 2}, err)
+	assert.Equal(t, test.User{}, one)
+
+	n, err := test.NewUserQuerySet(db).NameEq("a").FailIfMoreThan(2).Count()
+	assert.Equal(t, test.UserTooManyRowsError{Max: 2}, err)
+	assert.Zero(t, n)
+}
+
+func testUsersSoftDelete(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	users := getTestUsers(2)
+	m.ExpectQuery(fixedFullRe("SELECT * FROM `users` WHERE (`name` = ?)")).WithArgs("a").
+		WillReturnRows(getRowsForUsers(users))
+	m.ExpectQuery(fixedFullRe("SELECT * FROM `users` WHERE (`deleted_at` IS NOT NULL)")).
+		WillReturnRows(getRowsForUsers(users[:1]))
+	m.ExpectExec(fixedFullRe("UPDATE `users` SET `deleted_at` = ? WHERE (`deleted_at` IS NOT NULL)")).
+		WithArgs(sqlmock.AnyArg()).
 		WillReturnResult(sqlmock.NewResult(0, 1))
 
-	err := test.NewUserQuerySet(db).
-		EmailEq(u.Email).
-		GetUpdater().
-		SetName(u.Name).
-		Update()
+	var got []test.User
+	assert.Nil(t, test.NewUserQuerySet(db).WithDeleted().NameEq("a").All(&got))
+	assert.Len(t, got, 2)
+	assert.Nil(t, test.NewUserQuerySet(db).DeletedOnly().All(&got))
+	assert.Len(t, got, 1)
+	assert.Nil(t, test.NewUserQuerySet(db).DeletedOnly().SoftDelete())
+}
+
+func testPostsFullTextSearch(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	req := "SELECT * FROM `posts` WHERE `posts`.deleted_at IS NULL AND " +
+		"((MATCH (`title`) AGAINST (? IN NATURAL LANGUAGE MODE)) AND " +
+		"(MATCH (`title`, `subtitle`) AGAINST (? IN NATURAL LANGUAGE MODE)))"
+	m.ExpectQuery(fixedFullRe(req)).WithArgs("go", "generics").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+	var posts []test.Post
+	err := test.NewPostQuerySet(db).SearchTitle("go").Search("generics").All(&posts)
 	assert.Nil(t, err)
+	assert.Len(t, posts, 1)
 }
 
-func testUserUpdateFieldsByPK(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
-	u := getUser()
-	req := "UPDATE `users` SET `name` = ? WHERE `users`.deleted_at IS NULL AND `users`.`id` = ?"
-	m.ExpectExec(fixedFullRe(req)).
-		WithArgs(u.Name, u.ID).
+func testPostsJSONFilters(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	req := "SELECT * FROM `posts` WHERE `posts`.deleted_at IS NULL AND " +
+		"((JSON_UNQUOTE(JSON_EXTRACT(`meta`, ?)) = ?) AND (JSON_CONTAINS(`meta`, ?)))"
+	m.ExpectQuery(fixedFullRe(req)).WithArgs("$.author.name", "bob", `["go"]`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "meta"}).AddRow(1, `{"author":{"name":"bob"}}`))
+
+	var posts []test.Post
+	err := test.NewPostQuerySet(db).
+		MetaJSONPathEq("author.name", "bob").
+		MetaJSONContains(`["go"]`).
+		All(&posts)
+	assert.Nil(t, err)
+	assert.Len(t, posts, 1)
+
+	// JSON strings are filtered like other strings too
+	req = "SELECT * FROM `posts` WHERE `posts`.deleted_at IS NULL AND ((`meta` != ?) AND (`meta` LIKE ?))"
+	m.ExpectQuery(fixedFullRe(req)).WithArgs("{}", "%bob%").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "meta"}).AddRow(1, `{"author":{"name":"bob"}}`))
+	assert.Nil(t, test.NewPostQuerySet(db).MetaNe("{}").MetaLike("%bob%").All(&posts))
+}
+
+func testUsersForShare(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	req := "SELECT * FROM `users` WHERE `users`.deleted_at IS NULL AND ((`id` > ?)) LOCK IN SHARE MODE"
+	m.ExpectQuery(fixedFullRe(req)).WithArgs(1).WillReturnRows(getRowsForUsers(getTestUsers(1)))
+
+	var users []test.User
+	assert.Nil(t, test.NewUserQuerySet(db).ForShare().IDGt(1).All(&users))
+	assert.Len(t, users, 1)
+}
+
+func testJobsClaimNext(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	req := "SELECT * FROM `jobs` WHERE `jobs`.deleted_at IS NULL AND ((`id` > ?) AND (`status` = ?)) " +
+		"ORDER BY `id` LIMIT 1 FOR UPDATE SKIP LOCKED"
+	claim := "UPDATE `jobs` SET `status` = ?, `locked_by` = ?, `locked_at` = ? WHERE `id` = ?"
+	m.ExpectBegin()
+	m.ExpectQuery(fixedFullRe(req)).WithArgs(1, test.JobStatusPending).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status"}).AddRow(2, test.JobStatusPending))
+	m.ExpectExec(fixedFullRe(claim)).WithArgs(test.JobStatusRunning, "w1", sqlmock.AnyArg(), 2).
 		WillReturnResult(sqlmock.NewResult(0, 1))
+	m.ExpectCommit()
 
-	assert.Nil(t, u.Update(db, test.UserDBSchema.Name))
+	job, err := test.NewJobQuerySet(db).IDGt(1).ClaimNext("w1")
+	assert.Nil(t, err)
+	if assert.NotNil(t, job) {
+		assert.Equal(t, uint(2), job.ID)
+		assert.Equal(t, test.JobStatusRunning, job.Status)
+		assert.Equal(t, "w1", *job.LockedBy)
+		assert.NotNil(t, job.LockedAt)
+	}
+
+	m.ExpectBegin()
+	m.ExpectQuery(fixedFullRe(req)).WithArgs(1, test.JobStatusPending).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	m.ExpectRollback()
+
+	job, err = test.NewJobQuerySet(db).IDGt(1).ClaimNext("w1")
+	assert.Equal(t, gorm.ErrRecordNotFound, err)
+	assert.Nil(t, job)
 }
 
-func testUserDeleteByEmail(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
-	u := getUser()
-	req := "UPDATE `users` SET deleted_at=? WHERE `users`.deleted_at IS NULL AND ((email = ?))"
-	m.ExpectExec(fixedFullRe(req)).
-		WithArgs(sqlmock.AnyArg(), u.Email).
+func testJobsHeartbeatAndReclaim(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	heartbeat := "UPDATE `jobs` SET `locked_at` = ? WHERE `id` = ? AND `status` = ? AND `locked_by` = ?"
+	m.ExpectExec(fixedFullRe(heartbeat)).WithArgs(sqlmock.AnyArg(), 2, test.JobStatusRunning, "w1").
 		WillReturnResult(sqlmock.NewResult(0, 1))
+	m.ExpectExec(fixedFullRe(heartbeat)).WithArgs(sqlmock.AnyArg(), 2, test.JobStatusRunning, "w1").
+		WillReturnResult(sqlmock.NewResult(0, 0))
 
-	err := test.NewUserQuerySet(db).
-		EmailEq(u.Email).
-		Delete()
+	ok, err := test.HeartbeatJob(db, 2, "w1")
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	ok, err = test.HeartbeatJob(db, 2, "w1")
 	assert.Nil(t, err)
+	assert.False(t, ok, "job was reclaimed")
+
+	reclaim := "UPDATE `jobs` SET `status` = ?, `locked_by` = NULL, `locked_at` = NULL " +
+		"WHERE `status` = ? AND `locked_at` < ?"
+	m.ExpectExec(fixedFullRe(reclaim)).WithArgs(test.JobStatusPending, test.JobStatusRunning, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	n, err := test.ReclaimStaleJob(db, time.Minute)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), n)
 }
 
-func testUserDeleteByPK(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
-	u := getUser()
-	req := "UPDATE `users` SET deleted_at=? WHERE `users`.deleted_at IS NULL AND `users`.`id` = ?"
-	m.ExpectExec(fixedFullRe(req)).
-		WithArgs(sqlmock.AnyArg(), u.ID).
+func testJobsSampleWeighted(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	req := "SELECT * FROM `jobs` WHERE `jobs`.deleted_at IS NULL AND ((`status` = ?) AND (`priority` > 0)) " +
+		"ORDER BY -LN(1 - RAND()) / `priority` LIMIT 2"
+	m.ExpectQuery(fixedFullRe(req)).WithArgs(test.JobStatusPending).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "priority"}).AddRow(3, 10).AddRow(1, 1))
+
+	jobs, err := test.NewJobQuerySet(db).StatusEq(test.JobStatusPending).OrderAscByID().Limit(5).SampleWeighted(2)
+	assert.Nil(t, err)
+	if assert.Len(t, jobs, 2) {
+		assert.Equal(t, uint(3), jobs[0].ID)
+		assert.Equal(t, uint(10), jobs[0].Priority)
+	}
+}
+
+func testPlaceOne(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	// one=take option: One isn't ordered by primary key
+	req := "SELECT * FROM `places` WHERE `places`.deleted_at IS NULL AND ((`name` = ?)) LIMIT 1"
+	m.ExpectQuery(fixedFullRe(req)).WithArgs("a").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "a"))
+	m.ExpectQuery(fixedFullRe(req)).WithArgs("a").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	var p, missing test.Place
+	assert.Nil(t, test.NewPlaceQuerySet(db).NameEq("a").One(&p))
+	assert.Equal(t, uint(1), p.ID)
+	assert.Equal(t, gorm.ErrRecordNotFound, test.NewPlaceQuerySet(db).NameEq("a").One(&missing))
+}
+
+func testPlacesGeo(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	within := "SELECT * FROM `places` WHERE `places`.deleted_at IS NULL AND " +
+		"((`lat` BETWEEN ? AND ? AND `lng` BETWEEN ? AND ?))"
+	m.ExpectQuery(fixedFullRe(within)).WithArgs(50.0, 60.0, 30.0, 40.0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "lat", "lng"}).AddRow(1, 55.7, 37.6))
+	wrapped := "SELECT * FROM `places` WHERE `places`.deleted_at IS NULL AND " +
+		"((`lat` BETWEEN ? AND ? AND (`lng` >= ? OR `lng` <= ?)))"
+	m.ExpectQuery(fixedFullRe(wrapped)).WithArgs(50.0, 60.0, 170.0, -170.0).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	var places []test.Place
+	assert.Nil(t, test.NewPlaceQuerySet(db).WithinBoundingBox(50, 30, 60, 40).All(&places))
+	if assert.Len(t, places, 1) {
+		assert.Equal(t, 37.6, places[0].Lng)
+	}
+	assert.Nil(t, test.NewPlaceQuerySet(db).WithinBoundingBox(50, 170, 60, -170).All(&places))
+	assert.Empty(t, places)
+
+	byDistance := "SELECT `places`.*, 12742000 * ASIN(SQRT(POWER(SIN((`lat` - ?) * 0.008726646259971648), 2) + " +
+		"COS(`lat` * 0.017453292519943295) * COS(? * 0.017453292519943295) * " +
+		"POWER(SIN((`lng` - ?) * 0.008726646259971648), 2))) AS `distance` FROM `places` " +
+		"WHERE `places`.deleted_at IS NULL AND ((`name` = ?)) ORDER BY `distance` LIMIT 2"
+	m.ExpectQuery(fixedFullRe(byDistance)).WithArgs(55.7, 55.7, 37.6, "cafe").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "distance"}).AddRow(2, "cafe", 120.5))
+
+	got, err := test.NewPlaceQuerySet(db).NameEq("cafe").OrderAscByID().Limit(2).OrderByDistanceFrom(55.7, 37.6).All()
+	assert.Nil(t, err)
+	if assert.Len(t, got, 1) {
+		assert.Equal(t, uint(2), got[0].ID)
+		assert.Equal(t, "cafe", got[0].Name)
+		assert.Equal(t, 120.5, got[0].Distance)
+	}
+}
+
+func testPlacesViews(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	req := "SELECT * FROM `places` WHERE `places`.deleted_at IS NULL AND ((`name` = ?))"
+	m.ExpectQuery(fixedFullRe(req)).WithArgs("cafe").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "lat"}).AddRow(1, "cafe", 55.7).AddRow(2, "cafe", 59.9))
+	m.ExpectQuery(fixedFullRe(req + " LIMIT 1")).WithArgs("cafe").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+
+	views, err := test.NewPlaceQuerySet(db).NameEq("cafe").AllViews()
+	assert.Nil(t, err)
+	if assert.Len(t, views, 2) {
+		assert.Equal(t, uint(2), views[1].ID())
+		assert.Equal(t, "cafe", views[1].Name())
+		assert.Equal(t, 59.9, views[1].Lat())
+	}
+
+	_, err = test.NewPlaceQuerySet(db).NameEq("cafe").OneView()
+	assert.Equal(t, gorm.ErrRecordNotFound, err)
+}
+
+func testInvoicesTenant(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	req := "SELECT * FROM `invoices` WHERE `invoices`.deleted_at IS NULL AND ((`tenant_id` = ?) AND " +
+		"(((`number` = ?)) OR ((`amount` > ?))))"
+	m.ExpectQuery(fixedFullRe(req)).WithArgs(7, "a-1", 100).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "tenant_id"}).AddRow(1, 7))
+	count := "SELECT count(*) FROM `invoices` WHERE `invoices`.deleted_at IS NULL AND ((`tenant_id` = ?))"
+	m.ExpectQuery(fixedFullRe(count)).WithArgs(7).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+	var invoices []test.Invoice
+	err := test.NewInvoiceQuerySet(db, 7).Or(
+		func(qs test.InvoiceQuerySet) test.InvoiceQuerySet { return qs.NumberEq("a-1") },
+		func(qs test.InvoiceQuerySet) test.InvoiceQuerySet { return qs.AmountGt(100) },
+	).All(&invoices)
+	assert.Nil(t, err)
+	assert.Len(t, invoices, 1)
+
+	n, err := test.NewInvoiceHedged(db, db, time.Hour, 7).Count(func(qs test.InvoiceQuerySet) test.InvoiceQuerySet {
+		return qs
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, n)
+}
+
+func testInvoicesTenantCache(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	req := "SELECT * FROM `invoices` WHERE `invoices`.deleted_at IS NULL AND ((`tenant_id` = ?) AND " +
+		"(`id` = ?)) ORDER BY `invoices`.`id` ASC LIMIT 1"
+	m.ExpectQuery(fixedFullRe(req)).WithArgs(8, 1).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	store := testCacheStore{}
+	assert.Nil(t, test.NewInvoiceCache(store, time.Minute, 7).Set(&test.Invoice{Model: gorm.Model{ID: 1}, TenantID: 7}))
+
+	// invoice of tenant 7 isn't fetched from cache or db of tenant 8
+	c := test.NewInvoiceCache(store, time.Minute, 8)
+	_, err := c.Fetch(db, 1)
+	assert.Equal(t, test.ErrInvoiceNotFound, err)
+	assert.NotNil(t, c.Set(&test.Invoice{Model: gorm.Model{ID: 1}, TenantID: 7}))
+	assert.Len(t, store, 1)
+}
+
+func testInvoicesTenantCAS(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	req := "UPDATE `invoices` SET `number` = ? WHERE `invoices`.deleted_at IS NULL AND " +
+		"((`tenant_id` = ?) AND (`id` = ?) AND (`number` = ?))"
+	m.ExpectExec(fixedFullRe(req)).WithArgs("a-2", 8, 1, "a-1").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	swapped, err := test.CASInvoiceNumber(db, 8, 1, "a-1", "a-2")
+	assert.Nil(t, err)
+	assert.False(t, swapped, "invoice of another tenant isn't swapped")
+}
+
+func testInvoicesOptimisticLocking(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	// gorm doesn't sort columns of updated map
+	req := "^UPDATE `invoices` SET `(amount|version)` = \\?, `(amount|version)` = \\? " +
+		regexp.QuoteMeta("WHERE `invoices`.deleted_at IS NULL AND `invoices`.`id` = ? AND ((`version` = ?))") + "$"
+	m.ExpectExec(req).WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), 1, 3).
 		WillReturnResult(sqlmock.NewResult(0, 1))
+	m.ExpectExec(req).WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), 1, 4).
+		WillReturnResult(sqlmock.NewResult(0, 0))
 
-	assert.Nil(t, u.Delete(db))
+	inv := test.Invoice{Model: gorm.Model{ID: 1}, Amount: 200, Version: 3}
+	assert.Nil(t, inv.Update(db, test.InvoiceDBSchema.Amount))
+	assert.Equal(t, 4, inv.Version)
+
+	inv.Amount = 300
+	assert.Equal(t, test.ErrStaleObject, inv.Update(db, test.InvoiceDBSchema.Amount))
+	assert.Equal(t, 4, inv.Version, "version isn't incremented by failed update")
 }
 
-func testUsersUpdateNum(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
-	usersNum := 2
-	users := getTestUsers(usersNum)
-	req := "UPDATE `users` SET `name` = ? WHERE `users`.deleted_at IS NULL AND ((email IN (?,?)))"
-	m.ExpectExec(fixedFullRe(req)).
-		WithArgs(sqlmock.AnyArg(), users[0].Email, users[1].Email).
-		WillReturnResult(sqlmock.NewResult(0, int64(usersNum)))
+func testInvoicesErrors(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	req := "SELECT * FROM `invoices` WHERE `invoices`.deleted_at IS NULL AND ((`tenant_id` = ?) AND (`number` = ?)) " +
+		"ORDER BY `invoices`.`id` ASC LIMIT 1"
+	m.ExpectQuery(fixedFullRe(req)).WithArgs(7, "a-1").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	ins := "INSERT INTO `invoices` (`created_at`,`updated_at`,`deleted_at`,`tenant_id`,`number`,`amount`,`version`) " +
+		"VALUES (?,?,?,?,?,?,?)"
+	m.ExpectExec(fixedFullRe(ins)).
+		WillReturnError(errors.New("Error 1062: Duplicate entry '7-a-1' for key 'tenant_number'"))
 
-	num, err := test.NewUserQuerySet(db).
-		EmailIn(users[0].Email, users[1].Email).
-		GetUpdater().
-		SetName("some name").
-		UpdateNum()
+	var inv test.Invoice
+	assert.Equal(t, test.ErrInvoiceNotFound, test.NewInvoiceQuerySet(db, 7).NumberEq("a-1").One(&inv))
+
+	inv = test.Invoice{TenantID: 7, Number: "a-1"}
+	err := inv.Create(db)
+	dupErr, ok := err.(test.InvoiceDuplicateError)
+	if assert.True(t, ok, "%v", err) {
+		assert.Equal(t, "tenant_number", dupErr.Index)
+		assert.Equal(t, "tenant_id, number", dupErr.Column)
+	}
+}
+
+func testUsersIterate(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	users := getTestUsers(3)
+	req := "SELECT * FROM `users` WHERE `users`.deleted_at IS NULL AND ((`id` < ?))"
+	m.ExpectQuery(fixedFullRe(req)).WithArgs(10).WillReturnRows(getRowsForUsers(users))
+
+	var got []test.User
+	stop := errors.New("stop")
+	err := test.NewUserQuerySet(db).IDLt(10).Iterate(func(u test.User) error {
+		got = append(got, u)
+		if len(got) == 2 {
+			return stop
+		}
+		return nil
+	})
+	assert.Equal(t, stop, err)
+	assert.Equal(t, users[:2], got)
+}
+
+func testEventsStats(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	req := "SELECT COUNT(*), MIN(`created_at`), MAX(`created_at`), MIN(`updated_at`), MAX(`updated_at`), " +
+		"COUNT(CASE WHEN `kind` = ? THEN 1 END), COUNT(CASE WHEN `kind` = ? THEN 1 END), " +
+		"COUNT(CASE WHEN `prev_kind` = ? THEN 1 END), COUNT(CASE WHEN `prev_kind` = ? THEN 1 END) " +
+		"FROM `events` WHERE `events`.deleted_at IS NULL AND ((`user_id` = ?))"
+	minTime, maxTime := time.Now().Add(-time.Hour), time.Now()
+	m.ExpectQuery(fixedFullRe(req)).WithArgs("login", "logout", "login", "logout", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"count", "min_c", "max_c", "min_u", "max_u",
+			"k1", "k2", "p1", "p2"}).AddRow(3, minTime, maxTime, minTime, maxTime, 2, 1, 1, 0))
+
+	s, err := test.NewEventQuerySet(db).UserIDEq(1).Stats()
 	assert.Nil(t, err)
-	assert.Equal(t, int64(usersNum), num)
+	assert.Equal(t, 3, s.Count)
+	assert.Equal(t, minTime, *s.MinCreatedAt)
+	assert.Equal(t, maxTime, *s.MaxUpdatedAt)
+	assert.Equal(t, map[test.EventKind]int{test.EventKindLogin: 2, test.EventKindLogout: 1}, s.KindCounts)
+	assert.Equal(t, map[test.EventKind]int{test.EventKindLogin: 1, test.EventKindLogout: 0}, s.PrevKindCounts)
 }
 
-func testUsersCount(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
-	expCount := 5
-	req := "SELECT count(*) FROM `users` WHERE `users`.deleted_at IS NULL AND ((name != ?))"
-	m.ExpectQuery(fixedFullRe(req)).WithArgs(driver.Value("")).
-		WillReturnRows(getRowWithFields([]driver.Value{expCount}))
+func testEventsChunkedIn(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	var ids []uint
+	var args []driver.Value
+	for i := 0; i < 1001; i++ {
+		ids = append(ids, uint(i))
+		args = append(args, i)
+	}
 
-	cnt, err := test.NewUserQuerySet(db).NameNe("").Count()
+	chunk := "(" + strings.TrimSuffix(strings.Repeat("?,", 500), ",") + ")"
+	req := "SELECT * FROM `events` WHERE `events`.deleted_at IS NULL AND " +
+		"((`id` IN " + chunk + " OR `id` IN " + chunk + " OR `id` IN (?)) AND (`kind` NOT IN (?)))"
+	m.ExpectQuery(fixedFullRe(req)).WithArgs(append(args, "login")...).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+	var events []test.Event
+	assert.Nil(t, test.NewEventQuerySet(db).IDIn(ids[0], ids[1:]...).KindNotIn("login").All(&events))
+	assert.Len(t, events, 1)
+
+	// sharding proxies don't support joins of subqueries
+	_, ok := reflect.TypeOf(test.EventQuerySet{}).MethodByName("JoinUser")
+	assert.False(t, ok)
+}
+
+func testEventsCASKind(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	req := "UPDATE `events` SET `kind` = ? WHERE `events`.deleted_at IS NULL AND ((`id` = ?) AND (`kind` = ?))"
+	m.ExpectExec(fixedFullRe(req)).WithArgs("logout", 1, "login").WillReturnResult(sqlmock.NewResult(0, 1))
+	m.ExpectExec(fixedFullRe(req)).WithArgs("logout", 1, "login").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	swapped, err := test.CASEventKind(db, 1, "login", "logout")
 	assert.Nil(t, err)
-	assert.Equal(t, expCount, cnt)
+	assert.True(t, swapped)
+
+	swapped, err = test.CASEventKind(db, 1, "login", "logout")
+	assert.Nil(t, err)
+	assert.False(t, swapped, "kind was changed concurrently")
 }
 
-func TestMain(m *testing.M) {
-	err := GenerateQuerySets("test/models.go", "test/autogenerated_models.go")
+func testEventsNamedTypes(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	req := "SELECT * FROM `events` WHERE `events`.deleted_at IS NULL AND " +
+		"((`kind` IN (?,?)) AND (`prev_kind` = ?) AND (`source` LIKE ?))"
+	m.ExpectQuery(fixedFullRe(req)).WithArgs("login", "logout", "signup", "web%").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "kind", "prev_kind"}).AddRow(1, "login", "signup"))
+
+	var events []test.Event
+	kinds := []test.EventKind{"login", "logout"}
+	err := test.NewEventQuerySet(db).KindIn(kinds[0], kinds[1:]...).PrevKindEq("signup").SourceLike("web%").All(&events)
+	assert.Nil(t, err)
+	assert.Len(t, events, 1)
+	assert.Equal(t, test.EventKind("login"), events[0].Kind)
+	assert.Equal(t, test.EventKind("signup"), *events[0].PrevKind)
+}
+
+func testEventsEnumFilters(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	req := "SELECT * FROM `events` WHERE `events`.deleted_at IS NULL AND ((`kind` = ?) AND (`prev_kind` = ?))"
+	m.ExpectQuery(fixedFullRe(req)).WithArgs("logout", "login").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+	var events []test.Event
+	assert.Nil(t, test.NewEventQuerySet(db).KindEqLogout().PrevKindEqLogin().All(&events))
+	assert.Len(t, events, 1)
+}
+
+func testUsersAllInBatches(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	users := getTestUsers(4)[1:]
+	req := "SELECT * FROM `users` WHERE `users`.deleted_at IS NULL AND ((`name` != ?) AND (`id` > ?)) " +
+		"ORDER BY `id` ASC LIMIT 2"
+	m.ExpectQuery(fixedFullRe(req)).WithArgs("a", 0).WillReturnRows(getRowsForUsers(users[:2]))
+	m.ExpectQuery(fixedFullRe(req)).WithArgs("a", users[1].ID).WillReturnRows(getRowsForUsers(users[2:]))
+
+	var batches [][]test.User
+	err := test.NewUserQuerySet(db).NameNe("a").OrderDescByID().AllInBatches(2, func(batch []test.User) error {
+		batches = append(batches, batch)
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, [][]test.User{users[:2], users[2:]}, batches)
+
+	err = test.NewUserQuerySet(db).AllInBatches(0, func(batch []test.User) error {
+		return nil
+	})
+	assert.NotNil(t, err)
+}
+
+func testUsersPluckEmail(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	req := "SELECT `email` FROM `users` WHERE `users`.deleted_at IS NULL AND ((`name` = ?))"
+	m.ExpectQuery(fixedFullRe(req)).WithArgs("a").
+		WillReturnRows(sqlmock.NewRows([]string{"email"}).AddRow("a@x.com").AddRow("b@x.com"))
+
+	emails, err := test.NewUserQuerySet(db).NameEq("a").PluckEmail()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a@x.com", "b@x.com"}, emails)
+}
+
+func testUsersWhere(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	users := getTestUsers(1)
+	req := "SELECT * FROM `users` WHERE `users`.deleted_at IS NULL AND ((`name` = ?) AND (email LIKE ? OR id IN (?,?)))"
+	m.ExpectQuery(fixedFullRe(req)).WithArgs("a", "%@x.com", 1, 2).WillReturnRows(getRowsForUsers(users))
+
+	var got []test.User
+	err := test.NewUserQuerySet(db).NameEq("a").Where("email LIKE ? OR id IN (?)", "%@x.com", []uint{1, 2}).All(&got)
+	assert.Nil(t, err)
+	assert.Equal(t, users, got)
+}
+
+func testUsersScopes(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	users := getTestUsers(1)
+	req := "SELECT * FROM `users` WHERE `users`.deleted_at IS NULL AND ((`name` = ?) AND (`id` > ?))"
+	m.ExpectQuery(fixedFullRe(req)).WithArgs("a", 4).WillReturnRows(getRowsForUsers(users))
+	m.ExpectQuery(fixedFullRe(req)).WithArgs("a", 4).WillReturnRows(getRowsForUsers(users))
+
+	named := func(qs test.UserQuerySet) test.UserQuerySet { return qs.NameEq("a") }
+	recent := func(qs test.UserQuerySet) test.UserQuerySet { return qs.IDGt(4) }
+	test.RegisterUserScope("named", named)
+	test.RegisterUserScope("recent", recent)
+	assert.Equal(t, []string{"named", "recent"}, test.UserScopeNames())
+
+	var got []test.User
+	assert.Nil(t, test.NewUserQuerySet(db).Scope(named, recent).All(&got))
+	assert.Equal(t, users, got)
+	assert.Nil(t, test.NewUserQuerySet(db).Scoped("named", "recent").All(&got))
+	assert.Equal(t, users, got)
+
+	err := test.NewUserQuerySet(db).Scoped("named", "unknown").All(&got)
+	assert.EqualError(t, err, `unknown User scope "unknown"`)
+}
+
+func testUsersBranching(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	users := getTestUsers(1)
+	req := "SELECT * FROM `users` WHERE `users`.deleted_at IS NULL AND " +
+		"((`name` = ?) AND (`email` = ?) AND (`id` > ?) AND (`id` < ?))"
+	m.ExpectQuery(fixedFullRe(req)).WithArgs("a", "a@x.com", 1, 10).WillReturnRows(getRowsForUsers(users))
+	m.ExpectQuery(fixedFullRe(req)).WithArgs("a", "a@x.com", 1, 20).WillReturnRows(getRowsForUsers(users))
+	m.ExpectQuery(fixedFullRe(req)).WithArgs("a", "a@x.com", 1, 10).WillReturnRows(getRowsForUsers(users))
+	baseReq := "SELECT * FROM `users` WHERE `users`.deleted_at IS NULL AND ((`name` = ?) AND (`email` = ?) AND (`id` > ?))"
+	m.ExpectQuery(fixedFullRe(baseReq)).WithArgs("a", "a@x.com", 1).WillReturnRows(getRowsForUsers(users))
+
+	// GORM clones conditions shallowly: branches must not share them
+	base := test.NewUserQuerySet(db).NameEq("a").EmailEq("a@x.com").IDGt(1)
+	first, second := base.IDLt(10), base.IDLt(20)
+	var got []test.User
+	assert.Nil(t, first.All(&got))
+	assert.Nil(t, second.All(&got))
+	assert.Nil(t, first.Clone().All(&got))
+
+	assert.Error(t, base.Scoped("unknown").All(&got))
+	assert.Nil(t, base.All(&got)) // errors of branches don't leak into base
+}
+
+func testUsersCallTopUsers(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	users := getTestUsers(2)
+	since := time.Now()
+	m.ExpectQuery(fixedFullRe("CALL top_users(?, ?)")).WithArgs(3, since).
+		WillReturnRows(getRowsForUsers(users))
+
+	got, err := test.CallTopUsers(db, 3, since)
+	assert.Nil(t, err)
+	assert.Equal(t, users, got)
+}
+
+func testUsersDistinct(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	req := "SELECT DISTINCT `users`.* FROM `users` WHERE `users`.deleted_at IS NULL AND ((`name` = ?))"
+	m.ExpectQuery(fixedFullRe(req)).WithArgs("a").WillReturnRows(getRowsForUsers(getTestUsers(1)))
+	var users []test.User
+	assert.Nil(t, test.NewUserQuerySet(db).NameEq("a").Distinct().All(&users))
+	assert.Len(t, users, 1)
+
+	req = "SELECT DISTINCT `name` FROM `users` WHERE `users`.deleted_at IS NULL"
+	m.ExpectQuery(fixedFullRe(req)).WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("a").AddRow("b"))
+	assert.Nil(t, test.NewUserQuerySet(db).DistinctName().All(&users))
+	assert.Equal(t, []test.User{{Name: "a"}, {Name: "b"}}, users)
+
+	req = "SELECT COUNT(DISTINCT `email`) FROM `users` WHERE `users`.deleted_at IS NULL"
+	m.ExpectQuery(fixedFullRe(req)).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	n, err := test.NewUserQuerySet(db).OrderDescByID().CountDistinctEmail()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, n)
+}
+
+func testWithTransaction(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	m.ExpectBegin()
+	req := "UPDATE `users` SET `name` = ? WHERE `users`.deleted_at IS NULL AND ((`id` = ?))"
+	m.ExpectExec(fixedFullRe(req)).WithArgs("a", 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	m.ExpectRollback()
+
+	fail := errors.New("fail")
+	err := test.WithTransaction(db, func(tx *gorm.DB) error {
+		if err := test.NewUserQuerySetTx(tx).IDEq(1).GetUpdater().SetName("a").Update(); err != nil {
+			return err
+		}
+		return fail
+	})
+	assert.Equal(t, fail, err)
+
+	var users []test.User
+	err = test.NewUserQuerySetTx(db).All(&users)
+	assert.Contains(t, err.Error(), "isn't a transaction")
+}
+
+func testUsersFirstLast(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	users := getTestUsers(2)
+	req := "SELECT * FROM `users` WHERE `users`.deleted_at IS NULL AND ((`name` = ?)) ORDER BY `users`.`id` ASC LIMIT 1"
+	m.ExpectQuery(fixedFullRe(req)).WithArgs("a").WillReturnRows(getRowsForUsers(users[:1]))
+	u, err := test.NewUserQuerySet(db).NameEq("a").First()
+	assert.Nil(t, err)
+	assert.Equal(t, users[0], u)
+
+	req = "SELECT * FROM `users` WHERE `users`.deleted_at IS NULL ORDER BY `users`.`id` DESC LIMIT 1"
+	m.ExpectQuery(fixedFullRe(req)).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	_, err = test.NewUserQuerySet(db).Last()
+	assert.Equal(t, gorm.ErrRecordNotFound, err)
+}
+
+func testUsersExactlyOne(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	users := getTestUsers(2)
+	req := "SELECT * FROM `users` WHERE `users`.deleted_at IS NULL AND ((`name` = ?)) LIMIT 2"
+	m.ExpectQuery(fixedFullRe(req)).WithArgs("a").WillReturnRows(getRowsForUsers(users[:1]))
+	m.ExpectQuery(fixedFullRe(req)).WithArgs("a").WillReturnRows(getRowsForUsers(users))
+	m.ExpectQuery(fixedFullRe(req)).WithArgs("a").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	var u test.User
+	qs := test.NewUserQuerySet(db).NameEq("a")
+	assert.Nil(t, qs.ExactlyOne(&u))
+	assert.Equal(t, users[0], u)
+	assert.Equal(t, test.ErrMultipleRecords, qs.ExactlyOne(&u))
+	assert.Equal(t, gorm.ErrRecordNotFound, qs.ExactlyOne(&u))
+}
+
+func testCommentsNaming(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	req := "SELECT * FROM `comments` WHERE `comments`.deleted_at IS NULL AND ((`text` = ?) AND (((`id` IN (?,?)))))"
+	m.ExpectQuery(fixedFullRe(req)).WithArgs("a", 1, 2).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	var comments []test.Comment
+	err := test.QueryComments(db).FilterTextEq("a").Or(func(qs test.Comments) test.Comments {
+		return qs.FilterIDIn(1, 2)
+	}).All(&comments)
+	assert.Nil(t, err)
+}
+
+func testCommentsStrict(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	req := "UPDATE `comments` SET deleted_at=? WHERE `comments`.deleted_at IS NULL AND ((`text` = ?))"
+	m.ExpectExec(fixedFullRe(req)).WithArgs(sqlmock.AnyArg(), "a").WillReturnResult(sqlmock.NewResult(0, 2))
+	m.ExpectExec(fixedFullRe(req)).WithArgs(sqlmock.AnyArg(), "a").WillReturnResult(sqlmock.NewResult(0, 0))
+	m.ExpectExec(fixedFullRe(req)).WithArgs(sqlmock.AnyArg(), "a").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	n, err := test.QueryComments(db).FilterTextEq("a").DeleteNum()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), n)
+	n, err = test.QueryComments(db).FilterTextEq("a").DeleteNum()
+	assert.Nil(t, err)
+	assert.Zero(t, n)
+	assert.Equal(t, test.ErrNoRowsAffected, test.QueryComments(db).FilterTextEq("a").Delete())
+
+	c := test.Comment{Text: "b"}
+	c.ID = 1
+	req = "UPDATE `comments` SET `text` = ? WHERE `comments`.deleted_at IS NULL AND `comments`.`id` = ?"
+	m.ExpectExec(fixedFullRe(req)).WithArgs("b", 1).WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.Equal(t, test.ErrNoRowsAffected, c.Update(db, test.CommentDBSchema.Text))
+}
+
+func testPostsDraftIsFalse(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	req := "SELECT * FROM `posts` WHERE `posts`.deleted_at IS NULL AND ((`draft` = ?))"
+	m.ExpectQuery(fixedFullRe(req)).WithArgs(false).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "draft"}).AddRow(1, false))
+
+	var posts []test.Post
+	assert.Nil(t, test.NewPostQuerySet(db).DraftIsFalse().All(&posts))
+	assert.Len(t, posts, 1)
+}
+
+func testPostsSQLNullFilters(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	req := "SELECT * FROM `posts` WHERE `posts`.deleted_at IS NULL AND ((`subtitle` = ?) AND " +
+		"(`views` > ?) AND (`published_at` IS NULL))"
+	m.ExpectQuery(fixedFullRe(req)).WithArgs("sub", 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "subtitle", "views"}).AddRow(1, "sub", nil))
+
+	var posts []test.Post
+	err := test.NewPostQuerySet(db).SubtitleEq("sub").ViewsGt(10).PublishedAtIsNull().All(&posts)
+	assert.Nil(t, err)
+	assert.Equal(t, sql.NullString{String: "sub", Valid: true}, posts[0].Subtitle)
+	assert.False(t, posts[0].Views.Valid)
+}
+
+func testPostsTitleEqNullable(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	req := "SELECT * FROM `posts` WHERE `posts`.deleted_at IS NULL AND ((`title` IS NULL))"
+	m.ExpectQuery(fixedFullRe(req)).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	req = "SELECT * FROM `posts` WHERE `posts`.deleted_at IS NULL AND ((`title` = ?))"
+	m.ExpectQuery(fixedFullRe(req)).WithArgs("go").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	var posts []test.Post
+	qs := test.NewPostQuerySet(db)
+	assert.Nil(t, qs.TitleEqNullable(nil).All(&posts))
+	title := "go"
+	assert.Nil(t, qs.TitleEqNullable(&title).All(&posts))
+}
+
+func TestFakePostQuerySetSQLNullFilters(t *testing.T) {
+	posts := []test.Post{
+		{Views: sql.NullInt64{Int64: 5, Valid: true}},
+		{},
+		{Views: sql.NullInt64{Int64: 1, Valid: true}, Subtitle: sql.NullString{String: "a", Valid: true}},
+	}
+	qs := test.NewFakePostQuerySet(&posts)
+
+	var ret []test.Post
+	assert.Nil(t, qs.ViewsIsNull().All(&ret))
+	assert.Equal(t, posts[1:2], ret)
+	assert.Nil(t, qs.OrderAscByViews().All(&ret))
+	assert.Equal(t, []test.Post{posts[1], posts[2], posts[0]}, ret) // NULL goes first
+	assert.Nil(t, qs.SubtitleEq("a").All(&ret))
+	assert.Equal(t, posts[2:], ret)
+	assert.Nil(t, qs.SubtitleNe("a").All(&ret))
+	assert.Empty(t, ret) // NULL doesn't match like in SQL
+
+	title := "go"
+	posts[0].Title = &title
+	assert.Nil(t, qs.TitleEqNullable(&title).All(&ret))
+	assert.Equal(t, posts[:1], ret)
+	assert.Nil(t, qs.TitleEqNullable(nil).All(&ret))
+	assert.Equal(t, posts[1:], ret)
+
+	p := test.Post{Views: sql.NullInt64{Int64: -1}} // invalid value is NULL
+	assert.Nil(t, p.Validate())
+	p.Views.Valid = true
+	assert.Equal(t, test.PostCheckError{Field: test.PostDBSchema.Views, Check: "views >= 0"}, p.Validate())
+	// batch is validated before querying DB
+	err := test.UpdatePostBatch(nil, []test.Post{{}, p}, test.PostDBSchema.Views)
+	assert.Equal(t, test.PostCheckError{Field: test.PostDBSchema.Views, Check: "views >= 0"}, err)
+}
+
+func TestFakeEventQuerySetEnumFilters(t *testing.T) {
+	login := test.EventKindLogin
+	events := []test.Event{
+		{Kind: test.EventKindLogin},
+		{Kind: test.EventKindLogout, PrevKind: &login},
+		{Kind: test.EventKindLogout},
+	}
+	qs := test.NewFakeEventQuerySet(&events)
+
+	var ret []test.Event
+	assert.Nil(t, qs.KindEqLogout().All(&ret))
+	assert.Equal(t, events[1:], ret)
+	assert.Nil(t, qs.KindEqLogout().PrevKindEqLogin().All(&ret))
+	assert.Equal(t, events[1:2], ret)
+}
+
+func TestFakePostQuerySetBoolFilters(t *testing.T) {
+	posts := []test.Post{{Draft: true}, {}, {}}
+	qs := test.NewFakePostQuerySet(&posts)
+
+	n, err := qs.DraftIsTrue().Count()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, n)
+
+	n, err = qs.DraftIsFalse().Count()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, n)
+}
+
+func TestFakeUserQuerySetFailIfMoreThan(t *testing.T) {
+	users := getTestUsers(3)
+	qs := test.NewFakeUserQuerySet(&users)
+
+	var ret []test.User
+	assert.Equal(t, test.UserTooManyRowsError{Max: 2}, qs.FailIfMoreThan(2).All(&ret))
+	assert.Nil(t, qs.FailIfMoreThan(3).All(&ret))
+	assert.Equal(t, users, ret)
+
+	_, err := qs.FailIfMoreThan(1).PluckName()
+	assert.Equal(t, test.UserTooManyRowsError{Max: 1}, err)
+
+	var one test.User
+	assert.Equal(t, test.UserTooManyRowsError{Max: 2}, qs.FailIfMoreThan(2).One(&one))
+	assert.Nil(t, qs.FailIfMoreThan(3).One(&one))
+	_, err = qs.FailIfMoreThan(2).Count()
+	assert.Equal(t, test.UserTooManyRowsError{Max: 2}, err)
+	err = qs.FailIfMoreThan(2).Iterate(func(test.User) error {
+		t.Fatal("rows are iterated")
+		return nil
+	})
+	assert.Equal(t, test.UserTooManyRowsError{Max: 2}, err)
+}
+
+func TestFakeCommentsStrict(t *testing.T) {
+	comments := []test.Comment{{Text: "a"}, {Text: "a"}, {Text: "b"}}
+	qs := test.NewFakeComments(&comments)
+
+	n, err := qs.FilterTextEq("a").DeleteNum()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), n)
+	assert.Equal(t, test.ErrNoRowsAffected, qs.FilterTextEq("c").Delete())
+	assert.Nil(t, qs.FilterTextEq("b").SoftDelete())
+	assert.Equal(t, test.ErrNoRowsAffected, qs.FilterTextEq("b").SoftDelete())
+}
+
+func TestUserDryRun(t *testing.T) {
+	_, db := newDB()
+	sql, args := test.NewUserQuerySet(db).EmailEq("a@example.com").OrderDescByID().Limit(1).DryRun()
+	assert.Equal(t, "SELECT * FROM `users`  WHERE `users`.deleted_at IS NULL AND ((`email` = ?)) "+
+		"ORDER BY `id` DESC LIMIT 1", sql)
+	assert.Equal(t, []interface{}{"a@example.com"}, args)
+}
+
+func TestUserToSQL(t *testing.T) {
+	_, db := newDB()
+	sql, args := test.NewUserQuerySet(db).DistinctName().NameEq("a").ToSQL()
+	assert.Equal(t, "SELECT DISTINCT `name` FROM `users`  WHERE `users`.deleted_at IS NULL AND ((`name` = ?))", sql)
+	assert.Equal(t, []interface{}{"a"}, args)
+
+	sql, args = test.NewUserQuerySet(db).JoinPosts(test.NewPostQuerySet(db).TitleEq("t")).ToSQL()
+	assert.Equal(t, "SELECT `users`.* FROM `users` JOIN (SELECT DISTINCT `user_id` AS `join_posts_key` FROM `posts` "+
+		" WHERE `posts`.deleted_at IS NULL AND ((`title` = ?))) `join_posts` ON `join_posts`.`join_posts_key` = `users`.`id` "+
+		"WHERE `users`.deleted_at IS NULL", sql)
+	assert.Equal(t, []interface{}{"t"}, args)
+
+	base := test.NewUserQuerySet(db).JoinPosts(test.NewPostQuerySet(db))
+	sql, _ = base.Distinct().ToSQL()
+	assert.True(t, strings.HasPrefix(sql, "SELECT DISTINCT `users`.* FROM `users` JOIN"), sql)
+	sql, _ = base.ToSQL() // selected columns of branch don't leak into base
+	assert.True(t, strings.HasPrefix(sql, "SELECT `users`.* FROM `users` JOIN"), sql)
+}
+
+func testUsersExplain(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	req := "EXPLAIN SELECT * FROM `users`  WHERE `users`.deleted_at IS NULL AND ((`email` = ?))"
+	m.ExpectQuery(fixedFullRe(req)).WithArgs("a@example.com").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "table", "key"}).
+			AddRow(1, "users", "email").
+			AddRow(2, "users", nil))
+
+	plan, err := test.NewUserQuerySet(db).EmailEq("a@example.com").Explain(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, "1\tusers\temail\n2\tusers\t", plan)
+}
+
+func getRowsForBlogs(blogs []test.Blog) *sqlmock.Rows {
+	rows := sqlmock.NewRows([]string{"id", "myname", "created_at", "updated_at", "deleted_at"})
+	for _, b := range blogs {
+		rows = rows.AddRow(b.ID, b.Name, b.CreatedAt, b.UpdatedAt, b.DeletedAt)
+	}
+	return rows
+}
+
+func TestUserHedgedReads(t *testing.T) {
+	primary, primaryDB := newDB()
+	replica, replicaDB := newDB()
+	users := getTestUsers(2)
+	const req = "SELECT * FROM `users` WHERE `users`.deleted_at IS NULL AND ((`name` = ?))"
+	primary.ExpectQuery(fixedFullRe(req)).WithArgs("a").
+		WillDelayFor(time.Second).WillReturnRows(getRowsForUsers(users[:1]))
+	replica.ExpectQuery(fixedFullRe(req)).WithArgs("a").
+		WillReturnRows(getRowsForUsers(users[1:]))
+
+	byName := func(qs test.UserQuerySet) test.UserQuerySet {
+		return qs.NameEq("a")
+	}
+	h := test.NewUserHedged(primaryDB, replicaDB, 10*time.Millisecond)
+	var got []test.User
+	assert.Nil(t, h.All(byName, &got))
+	assert.Equal(t, users[1:], got) // replica answered before slow primary
+
+	// replica is queried without delay after failure of primary
+	const countReq = "SELECT count(*) FROM `users` WHERE `users`.deleted_at IS NULL AND ((`name` = ?))"
+	primary.ExpectQuery(fixedFullRe(countReq)).WithArgs("a").WillReturnError(errors.New("down"))
+	replica.ExpectQuery(fixedFullRe(countReq)).WithArgs("a").
+		WillReturnRows(getRowWithFields([]driver.Value{3}))
+	n, err := test.NewUserHedged(primaryDB, replicaDB, time.Hour).Count(byName)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, n)
+
+	// not found record is an answer of primary, replica isn't queried
+	const oneReq = "SELECT * FROM `users` WHERE `users`.deleted_at IS NULL AND ((`name` = ?)) " +
+		"ORDER BY `users`.`id` ASC LIMIT 1"
+	primary.ExpectQuery(fixedFullRe(oneReq)).WithArgs("a").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	var u test.User
+	assert.Equal(t, gorm.ErrRecordNotFound, h.One(byName, &u))
+
+	checkMock(t, primary)
+	checkMock(t, replica)
+}
+
+type testBreaker struct {
+	open                bool
+	successes, failures int
+}
+
+var errTestBreakerOpen = errors.New("circuit is open")
+
+func (b *testBreaker) Allow() error {
+	if b.open {
+		return errTestBreakerOpen
+	}
+	return nil
+}
+
+func (b *testBreaker) Success()          { b.successes++ }
+func (b *testBreaker) Failure(err error) { b.failures++ }
+
+func TestUserBreaker(t *testing.T) {
+	m, db := newDB()
+	b := &testBreaker{}
+	test.RegisterUserBreaker(db, b)
+
+	const req = "SELECT * FROM `users` WHERE `users`.deleted_at IS NULL ORDER BY `users`.`id` ASC LIMIT 1"
+	m.ExpectQuery(fixedFullRe(req)).WillReturnRows(getRowsForUsers(getTestUsers(1)))
+	m.ExpectQuery(fixedFullRe(req)).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	m.ExpectQuery(fixedFullRe(req)).WillReturnError(errors.New("timeout"))
+	m.ExpectQuery(fixedFullRe("SELECT count(*) FROM `users` WHERE `users`.deleted_at IS NULL")).
+		WillReturnError(errors.New("timeout"))
+
+	var u test.User
+	qs := test.NewUserQuerySet(db)
+	assert.Nil(t, qs.One(&u))
+	assert.Equal(t, gorm.ErrRecordNotFound, qs.One(&u)) // it isn't a failure
+	assert.NotNil(t, qs.One(&u))
+	_, err := qs.Count() // row queries don't run callbacks
+	assert.NotNil(t, err)
+	assert.Equal(t, 2, b.successes)
+	assert.Equal(t, 2, b.failures)
+
+	b.open = true
+	assert.Equal(t, errTestBreakerOpen, qs.One(&u))
+	_, err = qs.Count()
+	assert.Equal(t, errTestBreakerOpen, err)
+	u = getUserNoID()
+	assert.Equal(t, errTestBreakerOpen, u.Create(db))
+	var users []test.User
+	assert.Equal(t, errTestBreakerOpen, qs.Union(qs.NameEq("a")).All(&users))
+	_, err = qs.Union(qs.NameEq("a")).Count()
+	assert.Equal(t, errTestBreakerOpen, err)
+	checkMock(t, m)
+
+	var blogs []test.Blog // breaker is per model
+	m.ExpectQuery(fixedFullRe("SELECT * FROM `blogs` WHERE `blogs`.deleted_at IS NULL")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	assert.Nil(t, test.NewBlogQuerySet(db).All(&blogs))
+	checkMock(t, m)
+}
+
+func TestNewUserBreaker(t *testing.T) {
+	test.ConfigureUser(test.UserOptions{BreakerThreshold: 2, BreakerCooldown: time.Hour})
+	defer test.ConfigureUser(test.UserOptions{})
+
+	m, db := newDB()
+	test.RegisterUserBreaker(db, test.NewUserBreaker())
+
+	const req = "SELECT * FROM `users` WHERE `users`.deleted_at IS NULL ORDER BY `users`.`id` ASC LIMIT 1"
+	m.ExpectQuery(fixedFullRe(req)).WillReturnError(errors.New("timeout"))
+	m.ExpectQuery(fixedFullRe(req)).WillReturnRows(getRowsForUsers(getTestUsers(1)))
+	m.ExpectQuery(fixedFullRe(req)).WillReturnError(errors.New("timeout"))
+	m.ExpectQuery(fixedFullRe(req)).WillReturnError(errors.New("timeout"))
+
+	var u test.User
+	qs := test.NewUserQuerySet(db)
+	assert.NotNil(t, qs.One(&u))
+	assert.Nil(t, qs.One(&u)) // success resets consecutive failures
+	assert.NotNil(t, qs.One(&u))
+	assert.NotNil(t, qs.One(&u))
+	assert.Equal(t, test.ErrUserCircuitOpen, qs.One(&u))
+	checkMock(t, m)
+}
+
+func TestUserQueryLog(t *testing.T) {
+	m, db := newDB()
+	var buf bytes.Buffer
+	test.RegisterUserQueryLog(db, &buf)
+
+	const req = "SELECT * FROM `users` WHERE `users`.deleted_at IS NULL AND ((`name` = ?)) " +
+		"ORDER BY `id` DESC"
+	m.ExpectQuery(fixedFullRe(req)).WithArgs("a").WillReturnRows(getRowsForUsers(getTestUsers(2)))
+	m.ExpectQuery(fixedFullRe("SELECT * FROM `users` WHERE `users`.deleted_at IS NULL")).
+		WillReturnError(errors.New("timeout"))
+
+	var users []test.User
+	assert.Nil(t, test.NewUserQuerySet(db).NameEq("a").OrderDescByID().All(&users))
+	assert.NotNil(t, test.NewUserQuerySet(db).All(&users))
+	checkMock(t, m)
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	assert.Len(t, lines, 2)
+	var records []test.UserQueryLogRecord
+	for _, l := range lines {
+		var r test.UserQueryLogRecord
+		assert.Nil(t, json.Unmarshal([]byte(l), &r))
+		records = append(records, r)
+	}
+
+	assert.Equal(t, "User", records[0].Model)
+	assert.Equal(t, []string{"NameEq", "OrderDescByID"}, records[0].Chain)
+	assert.Contains(t, records[0].SQL, "ORDER BY `id` DESC")
+	assert.Equal(t, int64(2), records[0].Rows)
+	assert.Empty(t, records[0].Error)
+
+	assert.Empty(t, records[1].Chain)
+	assert.Equal(t, "timeout", records[1].Error)
+}
+
+type testQueryHookKey struct{}
+
+func TestQueryHook(t *testing.T) {
+	m, db := newDB()
+	type call struct {
+		ctxValue interface{}
+		sql      string
+		args     []interface{}
+		err      error
+	}
+	var calls []call
+	test.SetQueryHook(db, func(ctx context.Context, sql string, args []interface{}, took time.Duration, err error) {
+		assert.True(t, took >= 0)
+		calls = append(calls, call{ctx.Value(testQueryHookKey{}), sql, args, err})
+	})
+
+	const req = "SELECT * FROM `users` WHERE `users`.deleted_at IS NULL AND ((`name` = ?))"
+	m.ExpectQuery(fixedFullRe(req)).WithArgs("a").WillReturnRows(getRowsForUsers(getTestUsers(1)))
+	m.ExpectExec(fixedFullRe("DELETE FROM `users` WHERE (`id` = ?)")).WithArgs(1).
+		WillReturnError(errors.New("timeout"))
+	countReq := "SELECT count(*) FROM `users` WHERE `users`.deleted_at IS NULL AND ((`name` = ?))"
+	m.ExpectQuery(fixedFullRe(countReq)).WithArgs("b").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	u := getUser()
+	upsertReq := "INSERT INTO `users` (`created_at`,`updated_at`,`deleted_at`,`name`,`email`,`id`) VALUES (?,?,?,?,?,?) " +
+		"ON DUPLICATE KEY UPDATE `updated_at` = VALUES(`updated_at`),`deleted_at` = VALUES(`deleted_at`),`name` = VALUES(`name`)"
+	m.ExpectExec(fixedFullRe(upsertReq)).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), u.Name, u.Email, u.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	var users []test.User
+	ctx := context.WithValue(context.Background(), testQueryHookKey{}, "req")
+	assert.Nil(t, test.NewUserQuerySet(db).WithContext(ctx).NameEq("a").All(&users))
+	assert.NotNil(t, test.NewUserQuerySet(db.Unscoped()).IDEq(1).Delete())
+	n, err := test.NewUserQuerySet(db).WithContext(ctx).NameEq("b").Count()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, n)
+	assert.Nil(t, u.Upsert(db, test.UserDBSchema.Email))
+	checkMock(t, m)
+
+	if assert.Len(t, calls, 4) {
+		assert.Equal(t, "req", calls[0].ctxValue)
+		assert.Contains(t, calls[0].sql, "WHERE `users`.deleted_at IS NULL AND ((`name` = ?))")
+		assert.Equal(t, []interface{}{"a"}, calls[0].args)
+		assert.Nil(t, calls[0].err)
+		assert.Nil(t, calls[1].ctxValue)
+		assert.Equal(t, "timeout", calls[1].err.Error())
+		assert.Equal(t, "req", calls[2].ctxValue)
+		assert.Contains(t, calls[2].sql, "SELECT count(*) FROM `users`")
+		assert.Equal(t, []interface{}{"b"}, calls[2].args)
+		assert.Equal(t, upsertReq, calls[3].sql)
+		assert.Len(t, calls[3].args, 6)
+	}
+}
+
+type ttlCacheStore struct {
+	testCacheStore
+	ttl time.Duration
+}
+
+func (s *ttlCacheStore) Set(key string, value []byte, ttl time.Duration) error {
+	s.ttl = ttl
+	return s.testCacheStore.Set(key, value, ttl)
+}
+
+func TestConfigureUser(t *testing.T) {
+	test.ConfigureUser(test.UserOptions{MaxRows: 1, CacheTTL: time.Second, SlowQuery: time.Hour})
+	defer test.ConfigureUser(test.UserOptions{})
+
+	m, db := newDB()
+	var buf bytes.Buffer
+	test.RegisterUserQueryLog(db, &buf)
+
+	m.ExpectQuery(fixedFullRe("SELECT * FROM `users` WHERE `users`.deleted_at IS NULL")).
+		WillReturnRows(getRowsForUsers(getTestUsers(2)))
+	m.ExpectQuery(fixedFullRe("SELECT * FROM `users` WHERE `users`.deleted_at IS NULL LIMIT 6")).
+		WillReturnRows(getRowsForUsers(getTestUsers(2)))
+
+	var users []test.User
+	err := test.NewUserQuerySet(db).All(&users)
+	assert.Equal(t, test.UserTooManyRowsError{Max: 1}, err)
+	assert.Nil(t, test.NewUserQuerySet(db).FailIfMoreThan(5).All(&users))
+	checkMock(t, m)
+	assert.Empty(t, buf.String(), "queries aren't slow")
+
+	store := &ttlCacheStore{testCacheStore: testCacheStore{}}
+	assert.Nil(t, test.NewUserCache(store, time.Minute).Set(&users[0]))
+	assert.Equal(t, time.Second, store.ttl)
+}
+
+func TestBlogReconciler(t *testing.T) {
+	source, sourceDB := newDB()
+	target, targetDB := newDB()
+	blog := func(id uint, name string) test.Blog {
+		b := test.Blog{Name: name}
+		b.ID = id
+		return b
+	}
+
+	const req = "SELECT * FROM `blogs`  ORDER BY `id` ASC LIMIT 2"
+	const nextReq = "SELECT * FROM `blogs`  WHERE (`id` > ?) ORDER BY `id` ASC LIMIT 2"
+	source.ExpectQuery(fixedFullRe(req)).
+		WillReturnRows(getRowsForBlogs([]test.Blog{blog(1, "a"), blog(2, "b")}))
+	source.ExpectQuery(fixedFullRe(nextReq)).WithArgs(2).
+		WillReturnRows(getRowsForBlogs([]test.Blog{blog(3, "c")}))
+	target.ExpectQuery(fixedFullRe(req)).
+		WillReturnRows(getRowsForBlogs([]test.Blog{blog(2, "x"), blog(3, "c")}))
+	target.ExpectExec(fixedFullRe("UPDATE `blogs` SET `created_at` = ?, `updated_at` = ?, "+
+		"`deleted_at` = ?, `myname` = ? WHERE `blogs`.`id` = ?")).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), nil, "b", 2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	target.ExpectQuery(fixedFullRe(nextReq)).WithArgs(3).
+		WillReturnRows(getRowsForBlogs([]test.Blog{blog(4, "d")}))
+	target.ExpectExec(fixedFullRe("DELETE FROM `blogs`  WHERE `blogs`.`id` = ?")).
+		WithArgs(4).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	target.ExpectExec(fixedFullRe("INSERT INTO `blogs` (`id`,`created_at`,`updated_at`,`deleted_at`,`myname`) "+
+		"VALUES (?,?,?,?,?)")).
+		WithArgs(1, sqlmock.AnyArg(), sqlmock.AnyArg(), nil, "a").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	var reported []test.BlogDivergence
+	stats, err := test.NewBlogReconciler(sourceDB, targetDB, 2).WithFix().Run(func(d test.BlogDivergence) error {
+		reported = append(reported, d)
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, test.BlogReconcileStats{Compared: 2, Missing: 1, Extra: 1, Different: 1, Fixed: 3}, stats)
+	if assert.Len(t, reported, 3) {
+		assert.Equal(t, uint(1), reported[0].PK)
+		assert.Nil(t, reported[0].Target)
+		assert.Equal(t, "x", reported[1].Target.Name)
+		assert.Equal(t, uint(4), reported[2].PK)
+		assert.Nil(t, reported[2].Source)
+	}
+	checkMock(t, source)
+	checkMock(t, target)
+}
+
+func TestBlogReconcilerTargetError(t *testing.T) {
+	source, sourceDB := newDB()
+	target, targetDB := newDB()
+	blog := func(id uint) test.Blog {
+		b := test.Blog{Name: "a"}
+		b.ID = id
+		return b
+	}
+
+	const req = "SELECT * FROM `blogs`  ORDER BY `id` ASC LIMIT 2"
+	const nextReq = "SELECT * FROM `blogs`  WHERE (`id` > ?) ORDER BY `id` ASC LIMIT 2"
+	source.ExpectQuery(fixedFullRe(req)).WillReturnRows(getRowsForBlogs([]test.Blog{blog(1), blog(2)}))
+	source.ExpectQuery(fixedFullRe(nextReq)).WithArgs(2).WillReturnRows(getRowsForBlogs([]test.Blog{blog(3)}))
+	target.ExpectQuery(fixedFullRe(req)).WillReturnRows(getRowsForBlogs([]test.Blog{blog(1), blog(2)}))
+	target.ExpectQuery(fixedFullRe(nextReq)).WithArgs(2).WillReturnError(errors.New("connection lost"))
+
+	// rows after failed page of target aren't reported missing and aren't created
+	stats, err := test.NewBlogReconciler(sourceDB, targetDB, 2).WithFix().Run(func(d test.BlogDivergence) error {
+		t.Errorf("unexpected divergence %v", d.PK)
+		return nil
+	})
+	assert.EqualError(t, err, "can't get page of Blog: connection lost")
+	assert.Equal(t, test.BlogReconcileStats{Compared: 2}, stats)
+	checkMock(t, source)
+	checkMock(t, target)
+}
+
+type qsQuerier func(qs test.UserQuerySet) test.UserQuerySet
+
+type userQueryTestCase struct {
+	q    string
+	args []driver.Value
+	qs   qsQuerier
+}
+
+func testUserQueryFilters(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	cases := []userQueryTestCase{
+		{
+			q:    "((`name` IN (?)))",
+			args: []driver.Value{"a"},
+			qs: func(qs test.UserQuerySet) test.UserQuerySet {
+				return qs.NameIn("a")
+			},
+		},
+		{
+			q:    "((`name` IN (?,?)))",
+			args: []driver.Value{"a", "b"},
+			qs: func(qs test.UserQuerySet) test.UserQuerySet {
+				return qs.NameIn("a", "b")
+			},
+		},
+		{
+			q:    "((`name` NOT IN (?)))",
+			args: []driver.Value{"a"},
+			qs: func(qs test.UserQuerySet) test.UserQuerySet {
+				return qs.NameNotIn("a")
+			},
+		},
+		{
+			q:    "((`name` NOT IN (?,?)))",
+			args: []driver.Value{"a", "b"},
+			qs: func(qs test.UserQuerySet) test.UserQuerySet {
+				return qs.NameNotIn("a", "b")
+			},
+		},
+		{
+			q:    "((`email` = ?))",
+			args: []driver.Value{"a@mail.ru"},
+			qs: func(qs test.UserQuerySet) test.UserQuerySet {
+				return qs.ByEmail("a@mail.ru")
+			},
+		},
+		{
+			q:    "((`email` LIKE ?))",
+			args: []driver.Value{"%@mail.ru"},
+			qs: func(qs test.UserQuerySet) test.UserQuerySet {
+				return qs.EmailLike("%@mail.ru")
+			},
+		},
+		{
+			q:    "((LOWER(`name`) LIKE LOWER(?)))",
+			args: []driver.Value{"a%"},
+			qs: func(qs test.UserQuerySet) test.UserQuerySet {
+				return qs.NameILike("a%")
+			},
+		},
+		{
+			q:    "((`created_at` < ?))",
+			args: []driver.Value{time.Unix(100, 0)},
+			qs: func(qs test.UserQuerySet) test.UserQuerySet {
+				return qs.CreatedAtBefore(time.Unix(100, 0))
+			},
+		},
+		{
+			q:    "((`deleted_at` > ?))",
+			args: []driver.Value{time.Unix(100, 0)},
+			qs: func(qs test.UserQuerySet) test.UserQuerySet {
+				return qs.DeletedAtAfter(time.Unix(100, 0))
+			},
+		},
+		{
+			q:    "((`created_at` >= ?))",
+			args: []driver.Value{sqlmock.AnyArg()},
+			qs: func(qs test.UserQuerySet) test.UserQuerySet {
+				return qs.CreatedAtWithin(time.Hour)
+			},
+		},
+		{
+			q:    "((((`name` = ?) AND (`email` IN (?,?))) OR ((`id` > ?))))",
+			args: []driver.Value{"a", "a@mail.ru", "b@mail.ru", 5},
+			qs: func(qs test.UserQuerySet) test.UserQuerySet {
+				return qs.Or(func(qs test.UserQuerySet) test.UserQuerySet {
+					return qs.NameEq("a").EmailIn("a@mail.ru", "b@mail.ru")
+				}, func(qs test.UserQuerySet) test.UserQuerySet {
+					return qs.IDGt(5)
+				})
+			},
+		},
+		{
+			q:    "((`id` > ?) AND (NOT ((((`name` = ?)) OR ((`id` < ?))))))",
+			args: []driver.Value{1, "a", 3},
+			qs: func(qs test.UserQuerySet) test.UserQuerySet {
+				return qs.IDGt(1).Not(func(qs test.UserQuerySet) test.UserQuerySet {
+					return qs.Or(func(qs test.UserQuerySet) test.UserQuerySet {
+						return qs.NameEq("a")
+					}, func(qs test.UserQuerySet) test.UserQuerySet {
+						return qs.IDLt(3)
+					})
+				})
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.q, func(t *testing.T) {
+			runUserQueryFilterSubTest(t, c, m, db)
+		})
+	}
+}
+
+func runUserQueryFilterSubTest(t *testing.T, c userQueryTestCase, m sqlmock.Sqlmock, db *gorm.DB) {
+	expUsers := getTestUsers(5)
+	req := "SELECT * FROM `users` WHERE `users`.deleted_at IS NULL AND " + c.q
+	m.ExpectQuery(fixedFullRe(req)).WithArgs(c.args...).
+		WillReturnRows(getRowsForUsers(expUsers))
+
+	var users []test.User
+	assert.Nil(t, c.qs(test.NewUserQuerySet(db)).All(&users))
+	assert.Equal(t, expUsers, users)
+}
+
+func testUserCreateOne(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	u := getUserNoID()
+	req := "INSERT INTO `users` (`created_at`,`updated_at`,`deleted_at`,`name`,`email`) VALUES (?,?,?,?,?)"
+	args := []driver.Value{sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+		u.Name, u.Email}
+	m.ExpectExec(fixedFullRe(req)).
+		WithArgs(args...).
+		WillReturnResult(sqlmock.NewResult(2, 1))
+	assert.Nil(t, u.Create(db))
+	assert.Equal(t, uint(2), u.ID)
+}
+
+func testUserUpdateByEmail(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	u := getUser()
+	req := "UPDATE `users` SET `name` = ? WHERE `users`.deleted_at IS NULL AND ((`email` = ?))"
+	m.ExpectExec(fixedFullRe(req)).
+		WithArgs(u.Name, u.Email).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := test.NewUserQuerySet(db).
+		EmailEq(u.Email).
+		GetUpdater().
+		SetName(u.Name).
+		Update()
+	assert.Nil(t, err)
+}
+
+func testUserUpdateFieldsByPK(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	u := getUser()
+	req := "UPDATE `users` SET `name` = ? WHERE `users`.deleted_at IS NULL AND `users`.`id` = ?"
+	m.ExpectExec(fixedFullRe(req)).
+		WithArgs(u.Name, u.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.Nil(t, u.Update(db, test.UserDBSchema.Name))
+}
+
+func testUserSave(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	u := getUserNoID()
+	req := "INSERT INTO `users` (`created_at`,`updated_at`,`deleted_at`,`name`,`email`) VALUES (?,?,?,?,?)"
+	m.ExpectExec(fixedFullRe(req)).
+		WillReturnResult(sqlmock.NewResult(2, 1))
+	assert.Nil(t, u.Save(db)) // primary key is zero: created
+	assert.Equal(t, uint(2), u.ID)
+
+	// SET clause is built from map of fields: order of columns isn't fixed
+	req = "^UPDATE `users` SET .+ WHERE `users`.deleted_at IS NULL AND `users`.`id` = \\?$"
+	m.ExpectExec(req).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.Nil(t, u.Save(db))
+}
+
+func testUserDeleteByEmail(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	u := getUser()
+	req := "UPDATE `users` SET deleted_at=? WHERE `users`.deleted_at IS NULL AND ((`email` = ?))"
+	m.ExpectExec(fixedFullRe(req)).
+		WithArgs(sqlmock.AnyArg(), u.Email).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := test.NewUserQuerySet(db).
+		EmailEq(u.Email).
+		Delete()
+	assert.Nil(t, err)
+}
+
+func testUserDeleteByPK(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	u := getUser()
+	req := "UPDATE `users` SET deleted_at=? WHERE `users`.deleted_at IS NULL AND `users`.`id` = ?"
+	m.ExpectExec(fixedFullRe(req)).
+		WithArgs(sqlmock.AnyArg(), u.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.Nil(t, u.Delete(db))
+}
+
+func testUsersUpdateNum(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	usersNum := 2
+	users := getTestUsers(usersNum)
+	req := "UPDATE `users` SET `name` = ? WHERE `users`.deleted_at IS NULL AND ((`email` IN (?,?)))"
+	m.ExpectExec(fixedFullRe(req)).
+		WithArgs(sqlmock.AnyArg(), users[0].Email, users[1].Email).
+		WillReturnResult(sqlmock.NewResult(0, int64(usersNum)))
+
+	num, err := test.NewUserQuerySet(db).
+		EmailIn(users[0].Email, users[1].Email).
+		GetUpdater().
+		SetName("some name").
+		UpdateNum()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(usersNum), num)
+}
+
+func testUsersOffset(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	// mysql has OFFSET only in LIMIT clause
+	req := fmt.Sprintf("SELECT * FROM `users` WHERE `users`.deleted_at IS NULL LIMIT %d OFFSET 2", int(^uint(0)>>1))
+	m.ExpectQuery(fixedFullRe(req)).WillReturnRows(getRowsForUsers(getTestUsers(1)))
+	req = "SELECT * FROM `users` WHERE `users`.deleted_at IS NULL LIMIT 3 OFFSET 2"
+	m.ExpectQuery(fixedFullRe(req)).WillReturnRows(getRowsForUsers(getTestUsers(1)))
+	m.ExpectQuery(fixedFullRe(req)).WillReturnRows(getRowsForUsers(getTestUsers(1)))
+
+	var users []test.User
+	assert.Nil(t, test.NewUserQuerySet(db).Offset(2).All(&users))
+	assert.Nil(t, test.NewUserQuerySet(db).Limit(3).Offset(2).All(&users))
+	assert.Nil(t, test.NewUserQuerySet(db).Offset(2).Limit(3).All(&users))
+}
+
+func testUsersCount(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	expCount := 5
+	req := "SELECT count(*) FROM `users` WHERE `users`.deleted_at IS NULL AND ((`name` != ?))"
+	m.ExpectQuery(fixedFullRe(req)).WithArgs(driver.Value("")).
+		WillReturnRows(getRowWithFields([]driver.Value{expCount}))
+
+	cnt, err := test.NewUserQuerySet(db).NameNe("").Count()
+	assert.Nil(t, err)
+	assert.Equal(t, expCount, cnt)
+}
+
+func testUsersReindexAll(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	users := getTestUsers(3)
+	req := "SELECT * FROM `users` WHERE `users`.deleted_at IS NULL AND ((`id` > ?)) ORDER BY `id` ASC LIMIT 2"
+	m.ExpectQuery(fixedFullRe(req)).WithArgs(0).
+		WillReturnRows(getRowsForUsers(users[:2]))
+	m.ExpectQuery(fixedFullRe(req)).WithArgs(users[1].ID).
+		WillReturnRows(getRowsForUsers(users[2:]))
+
+	var docs []map[string]interface{}
+	err := test.NewUserQuerySet(db).ReindexAll(2, func(doc map[string]interface{}) error {
+		docs = append(docs, doc)
+		return nil
+	}, test.UserDBSchema.ID, test.UserDBSchema.Email)
+	assert.Nil(t, err)
+
+	err = test.NewUserQuerySet(db).ReindexAll(0, func(doc map[string]interface{}) error {
+		return nil
+	})
+	assert.NotNil(t, err)
+
+	var expDocs []map[string]interface{}
+	for _, u := range users {
+		expDocs = append(expDocs, map[string]interface{}{"id": u.ID, "email": u.Email})
+	}
+	assert.Equal(t, expDocs, docs)
+}
+
+func testUsersCreateBatch(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	users := []test.User{getUserNoID(), getUserNoID(), getUserNoID()}
+	const req = "INSERT INTO `users` (`created_at`,`updated_at`,`deleted_at`,`name`,`email`) VALUES "
+	const row = "(?,?,?,?,?)"
+	userArgs := func(u test.User) []driver.Value {
+		return []driver.Value{sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), u.Name, u.Email}
+	}
+
+	m.ExpectBegin()
+	m.ExpectExec(fixedFullRe(req + row + "," + row)).
+		WithArgs(append(userArgs(users[0]), userArgs(users[1])...)...).
+		WillReturnResult(sqlmock.NewResult(2, 2))
+	m.ExpectExec(fixedFullRe(req + row)).
+		WithArgs(userArgs(users[2])...).
+		WillReturnResult(sqlmock.NewResult(3, 1))
+	m.ExpectCommit()
+
+	var processed []int
+	err := test.CreateUserBatch(db, users, 2, func(p test.UserBatchProgress) {
+		assert.Equal(t, len(users), p.Total)
+		processed = append(processed, p.Processed)
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []int{2, 3}, processed)
+
+	// inserted batches are rolled back if next batch fails
+	m.ExpectBegin()
+	m.ExpectExec(fixedFullRe(req + row)).
+		WithArgs(userArgs(users[0])...).
+		WillReturnResult(sqlmock.NewResult(4, 1))
+	m.ExpectExec(fixedFullRe(req + row)).
+		WithArgs(userArgs(users[1])...).
+		WillReturnError(errors.New("db is down"))
+	m.ExpectRollback()
+	assert.NotNil(t, test.CreateUserBatch(db, users[:2], 1))
+
+	// non-positive batch size inserts all rows fitting into limit of bind variables
+	m.ExpectExec(fixedFullRe(req + row + "," + row)).
+		WithArgs(append(userArgs(users[0]), userArgs(users[1])...)...).
+		WillReturnResult(sqlmock.NewResult(2, 2))
+	assert.Nil(t, test.CreateUserBatch(db, users[:2], 0))
+
+	mixed := []test.User{getUserNoID(), getUserNoID()}
+	mixed[1].ID = 5
+	assert.NotNil(t, test.CreateUserBatch(db, mixed, 2))
+}
+
+func testUsersUpdateBatch(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	users := getTestUsers(2)
+	req := "UPDATE `users` SET `name` = CASE `id` WHEN ? THEN ? WHEN ? THEN ? END," +
+		"`email` = CASE `id` WHEN ? THEN ? WHEN ? THEN ? END," +
+		"`updated_at` = CASE `id` WHEN ? THEN ? WHEN ? THEN ? END WHERE `id` IN (?,?)"
+	m.ExpectExec(fixedFullRe(req)).
+		WithArgs(users[0].ID, users[0].Name, users[1].ID, users[1].Name,
+			users[0].ID, users[0].Email, users[1].ID, users[1].Email,
+			users[0].ID, sqlmock.AnyArg(), users[1].ID, sqlmock.AnyArg(), users[0].ID, users[1].ID).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	err := test.UpdateUserBatch(db, users, test.UserDBSchema.Name, test.UserDBSchema.Email)
+	assert.Nil(t, err)
+	assert.False(t, users[0].UpdatedAt.IsZero())
+
+	// 65535 bind vars of mysql fit 9362 rows of 3 fields: the rest is updated by the second statement
+	many := getTestUsers(9363)
+	m.ExpectBegin()
+	m.ExpectExec("^UPDATE `users` SET .* WHERE `id` IN \\((\\?,)+\\?\\)$").
+		WillReturnResult(sqlmock.NewResult(0, 9362))
+	m.ExpectExec("^UPDATE `users` SET .* WHERE `id` IN \\(\\?\\)$").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	m.ExpectCommit()
+	n, err := test.UpdateUserBatchNum(db, many, test.UserDBSchema.Name, test.UserDBSchema.Email)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(len(many)), n)
+
+	assert.Nil(t, test.UpdateUserBatch(db, nil, test.UserDBSchema.Name))
+	assert.NotNil(t, test.UpdateUserBatch(db, users))
+	assert.NotNil(t, test.UpdateUserBatch(db, users, test.UserDBSchemaField("unknown")))
+}
+
+func testUsersSyncSet(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	created := time.Now().Add(-time.Hour)
+	m.ExpectBegin()
+	m.ExpectQuery(fixedFullRe("SELECT * FROM `users` WHERE `users`.deleted_at IS NULL AND ((`id` > ?))")).
+		WithArgs(0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "name", "email"}).
+			AddRow(1, created, "a", "a@mail.ru").
+			AddRow(2, created, "b", "b@mail.ru").
+			AddRow(3, created, "c", "c@mail.ru").
+			AddRow(4, created, "a", "a@mail.ru"))
+	m.ExpectExec(fixedFullRe("UPDATE `users` SET `created_at` = ?, `updated_at` = ?, `deleted_at` = ?, "+
+		"`name` = ?, `email` = ? WHERE `users`.deleted_at IS NULL AND `users`.`id` = ?")).
+		WithArgs(created, sqlmock.AnyArg(), nil, "b2", "b@mail.ru", 2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	m.ExpectExec(fixedFullRe("INSERT INTO `users` (`created_at`,`updated_at`,`deleted_at`,`name`,`email`) "+
+		"VALUES (?,?,?,?,?)")).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), nil, "d", "d@mail.ru").
+		WillReturnResult(sqlmock.NewResult(4, 1))
+	for _, id := range []int{3, 4} { // duplicate of the first row is deleted too
+		m.ExpectExec(fixedFullRe("UPDATE `users` SET deleted_at=? WHERE `users`.deleted_at IS NULL AND `users`.`id` = ?")).
+			WithArgs(sqlmock.AnyArg(), id).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	countReq := "SELECT count(*) FROM `users` WHERE `users`.deleted_at IS NULL AND ((`id` > ?))"
+	m.ExpectQuery(fixedFullRe(countReq)).WithArgs(0).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	m.ExpectCommit()
+
+	desired := []test.User{
+		{Name: "a", Email: "a@mail.ru"},
+		{Name: "b2", Email: "b@mail.ru"},
+		{Name: "d", Email: "d@mail.ru"},
+	}
+	inserted, updated, deleted, err := test.NewUserQuerySet(db).IDGt(0).SyncSet(desired, test.UserDBSchema.Email)
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{1, 1, 2}, []int64{inserted, updated, deleted})
+	assert.Zero(t, desired[1].ID)
+
+	// desired row not matching queryset would be created by every sync
+	m.ExpectBegin()
+	m.ExpectQuery(fixedFullRe("SELECT * FROM `users` WHERE `users`.deleted_at IS NULL AND ((`name` = ?))")).
+		WithArgs("a").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "name", "email"}))
+	m.ExpectExec(fixedFullRe("INSERT INTO `users` (`created_at`,`updated_at`,`deleted_at`,`name`,`email`) "+
+		"VALUES (?,?,?,?,?)")).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), nil, "b2", "b@mail.ru").
+		WillReturnResult(sqlmock.NewResult(5, 1))
+	m.ExpectQuery(fixedFullRe("SELECT count(*) FROM `users` WHERE `users`.deleted_at IS NULL AND ((`name` = ?))")).
+		WithArgs("a").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	m.ExpectRollback()
+	_, _, _, err = test.NewUserQuerySet(db).NameEq("a").SyncSet(desired[1:2], test.UserDBSchema.Email)
+	assert.Equal(t, "1 of 1 desired User rows don't match queryset", err.Error())
+
+	_, _, _, err = test.NewUserQuerySet(db).SyncSet(desired)
+	assert.NotNil(t, err)
+}
+
+type testSearchClient struct {
+	ids []uint
+	err error
+}
+
+func (c testSearchClient) SearchIDs(field, query string) ([]uint, error) {
+	return c.ids, c.err
+}
+
+func testUsersSearchByName(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	expUsers := getTestUsers(2)
+	req := "SELECT * FROM `users` WHERE `users`.deleted_at IS NULL AND ((`email` != ?) AND (`id` IN (?,?)))"
+	m.ExpectQuery(fixedFullRe(req)).WithArgs("", expUsers[0].ID, expUsers[1].ID).
+		WillReturnRows(getRowsForUsers(expUsers))
+
+	client := testSearchClient{ids: []uint{expUsers[0].ID, expUsers[1].ID}}
+	qs, err := test.NewUserQuerySet(db).EmailNe("").NameSearch(client, "name")
+	assert.Nil(t, err)
+
+	var users []test.User
+	assert.Nil(t, qs.All(&users))
+	assert.Equal(t, expUsers, users)
+
+	_, err = test.NewUserQuerySet(db).NameSearch(testSearchClient{err: errors.New("search is down")}, "name")
+	assert.NotNil(t, err)
+}
+
+type testCacheStore map[string][]byte
+
+func (s testCacheStore) Get(key string) ([]byte, bool, error) {
+	v, ok := s[key]
+	return v, ok, nil
+}
+
+func (s testCacheStore) Set(key string, value []byte, ttl time.Duration) error {
+	s[key] = value
+	return nil
+}
+
+func (s testCacheStore) Delete(key string) error {
+	delete(s, key)
+	return nil
+}
+
+func testUserCache(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	expUsers := getTestUsers(2)
+	u := expUsers[1]
+	req := "SELECT * FROM `users` WHERE `users`.deleted_at IS NULL AND ((`id` = ?)) ORDER BY `users`.`id` ASC LIMIT 1"
+	m.ExpectQuery(fixedFullRe(req)).WithArgs(u.ID).
+		WillReturnRows(getRowsForUsers(expUsers[1:]))
+
+	store := testCacheStore{}
+	c := test.NewUserCache(store, time.Minute)
+	for i := 0; i < 2; i++ { // second fetch is from cache
+		cached, err := c.Fetch(db, u.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, u.Email, cached.Email)
+	}
+	assert.Len(t, store, 1)
+
+	req = "UPDATE `users` SET `name` = ? WHERE `users`.deleted_at IS NULL AND `users`.`id` = ?"
+	m.ExpectExec(fixedFullRe(req)).
+		WithArgs(u.Name, u.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.Nil(t, c.Update(db, &u, test.UserDBSchema.Name))
+	assert.Len(t, store, 0)
+}
+
+func testUserUpsert(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	u := getUser()
+	req := "INSERT INTO `users` (`created_at`,`updated_at`,`deleted_at`,`name`,`email`,`id`) VALUES (?,?,?,?,?,?) " +
+		"ON DUPLICATE KEY UPDATE `updated_at` = VALUES(`updated_at`),`deleted_at` = VALUES(`deleted_at`),`name` = VALUES(`name`)"
+	m.ExpectExec(fixedFullRe(req)).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), u.Name, u.Email, u.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.Nil(t, u.Upsert(db, test.UserDBSchema.Email))
+
+	// all columns are conflict columns: there is nothing to update
+	req = "INSERT INTO `users` (`created_at`,`updated_at`,`deleted_at`,`name`,`email`,`id`) VALUES (?,?,?,?,?,?) " +
+		"ON DUPLICATE KEY UPDATE `id` = `id`"
+	m.ExpectExec(fixedFullRe(req)).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), u.Name, u.Email, u.ID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.Nil(t, u.Upsert(db, test.UserDBSchema.Email, test.UserDBSchema.Name,
+		test.UserDBSchema.UpdatedAt, test.UserDBSchema.DeletedAt))
+}
+
+func testUserCreateIfNotExists(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	u := getUser()
+	req := "INSERT INTO `users` (`created_at`,`updated_at`,`deleted_at`,`name`,`email`,`id`) VALUES (?,?,?,?,?,?) " +
+		"ON DUPLICATE KEY UPDATE `id` = `id`"
+	m.ExpectExec(fixedFullRe(req)).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), u.Name, u.Email, u.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	m.ExpectExec(fixedFullRe(req)).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), u.Name, u.Email, u.ID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	created, err := u.CreateIfNotExists(db, test.UserDBSchema.Email)
+	assert.Nil(t, err)
+	assert.True(t, created)
+	created, err = u.CreateIfNotExists(db, test.UserDBSchema.Email)
+	assert.Nil(t, err)
+	assert.False(t, created)
+
+	_, err = u.CreateIfNotExists(db)
+	assert.NotNil(t, err)
+	_, err = (&test.User{}).CreateIfNotExists(db, test.UserDBSchema.ID) // autoincremented ID isn't inserted
+	assert.NotNil(t, err)
+}
+
+type testLimiter struct {
+	waits int
+}
+
+func (l *testLimiter) Wait(ctx context.Context) error {
+	l.waits++
+	return ctx.Err()
+}
+
+func testUsersThrottledDelete(t *testing.T, m sqlmock.Sqlmock, db *gorm.DB) {
+	users := getTestUsers(3)
+	countReq := "SELECT count(*) FROM `users` WHERE `users`.deleted_at IS NULL AND ((`name` != ?))"
+	m.ExpectQuery(fixedFullRe(countReq)).WithArgs("").
+		WillReturnRows(getRowWithFields([]driver.Value{len(users)}))
+	selectReq := "SELECT `id` FROM `users` WHERE `users`.deleted_at IS NULL AND ((`name` != ?) AND (`id` > ?)) ORDER BY `id` ASC LIMIT 2"
+	deleteReq := "UPDATE `users` SET deleted_at=? WHERE `users`.deleted_at IS NULL AND ((`id` IN (?,?)))"
+	m.ExpectQuery(fixedFullRe(selectReq)).WithArgs("", 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(users[0].ID).AddRow(users[1].ID))
+	m.ExpectExec(fixedFullRe(deleteReq)).WithArgs(sqlmock.AnyArg(), users[0].ID, users[1].ID).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	m.ExpectQuery(fixedFullRe(selectReq)).WithArgs("", users[1].ID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(users[2].ID))
+	deleteReq = strings.Replace(deleteReq, "(?,?)", "(?)", 1)
+	m.ExpectExec(fixedFullRe(deleteReq)).WithArgs(sqlmock.AnyArg(), users[2].ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	l := &testLimiter{}
+	var progress []test.UserBatchProgress
+	n, err := test.NewUserQuerySet(db).
+		NameNe("").
+		OrderDescByCreatedAt(). // must be replaced by order by primary key
+		Throttled(context.Background(), l).
+		WithProgress(func(p test.UserBatchProgress) {
+			progress = append(progress, p)
+		}).
+		Delete(2)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), n)
+	assert.Equal(t, 2, l.waits)
+	if assert.Len(t, progress, 2) {
+		assert.Equal(t, 2, progress[0].Processed)
+		assert.Equal(t, 3, progress[1].Total)
+		assert.Equal(t, time.Duration(0), progress[1].ETA)
+	}
+
+	_, err = test.NewUserQuerySet(db).Throttled(context.Background(), l).Delete(0)
+	assert.NotNil(t, err)
+}
+
+type fakeUserQuerier struct {
+	test.UserQuerier // panic on not stubbed methods
+	count            int
+}
+
+func (q fakeUserQuerier) Count() (int, error) {
+	return q.count, nil
+}
+
+func countUsers(q test.UserQuerier) (int, error) {
+	return q.Count()
+}
+
+func TestUserQuerierIsMockable(t *testing.T) {
+	n, err := countUsers(fakeUserQuerier{count: 3})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, n)
+}
+
+func TestFakeUserQuerySet(t *testing.T) {
+	users := getTestUsers(5)
+	users[3].Name = "Admin"
+	rows := append([]test.User{}, users...)
+	qs := test.NewFakeUserQuerySet(&rows)
+
+	var got []test.User
+	assert.Nil(t, qs.IDGt(1).OrderDescByID().Offset(1).Limit(2).All(&got))
+	assert.Equal(t, []test.User{users[3], users[2]}, got)
+
+	n, err := qs.NameILike("adm%").Count()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, n)
+
+	n, err = qs.NameEqFold("ADMIN").Count()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, n)
+
+	n, err = qs.NameStartsWith("Adm").NameEndsWith("min").NameContains("dmi").Count()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, n)
+
+	n, err = qs.NameMatches("^Adm").Count()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, n)
+
+	assert.Panics(t, func() { qs.NameMatches("(") }) // invalid pattern doesn't silently match nothing
+
+	n, err = qs.NameEqIfSet("Admin").EmailEqIfSet("").Count()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, n)
+
+	n, err = qs.NameLike("name_%").EmailIn(users[0].Email, users[4].Email).Count()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, n)
+
+	n, err = qs.Or(func(qs test.FakeUserQuerySet) test.FakeUserQuerySet {
+		return qs.NameEq("Admin")
+	}, func(qs test.FakeUserQuerySet) test.FakeUserQuerySet {
+		return qs.IDLt(2)
+	}).Count()
+	assert.Nil(t, err)
+	assert.Equal(t, 3, n)
+
+	n, err = qs.Not(func(qs test.FakeUserQuerySet) test.FakeUserQuerySet {
+		return qs.NameEq("Admin")
+	}).Count()
+	assert.Nil(t, err)
+	assert.Equal(t, 4, n)
+
+	rows[0].CreatedAt = time.Now().Add(-2 * time.Hour)
+	n, err = qs.CreatedAtWithin(time.Hour).Count()
+	assert.Nil(t, err)
+	assert.Equal(t, 4, n)
+	n, err = qs.CreatedAtBefore(time.Now().Add(-time.Hour)).Count()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, n)
+
+	var batches [][]test.User
+	assert.Nil(t, qs.IDGt(1).OrderDescByID().AllInBatches(2, func(batch []test.User) error {
+		batches = append(batches, batch)
+		return nil
+	}))
+	assert.Equal(t, [][]test.User{{rows[2], rows[3]}, {rows[4]}}, batches)
+	assert.NotNil(t, qs.AllInBatches(0, func(batch []test.User) error {
+		return nil
+	}))
+
+	ids, err := qs.IDGt(2).OrderDescByID().PluckID()
+	assert.Nil(t, err)
+	assert.Equal(t, []uint{rows[4].ID, rows[3].ID}, ids)
+
+	var u test.User
+	assert.Equal(t, gorm.ErrRecordNotFound, qs.EmailEq("unknown").One(&u))
+	_, err = qs.EmailEq("unknown").Last()
+	assert.Equal(t, gorm.ErrRecordNotFound, err)
+	u, err = qs.IDLt(3).Last()
+	assert.Nil(t, err)
+	assert.Equal(t, rows[2], u)
+	u, err = qs.IDLt(3).First()
+	assert.Nil(t, err)
+	assert.Equal(t, rows[0], u)
+	assert.Equal(t, test.ErrMultipleRecords, qs.IDLt(3).ExactlyOne(&u))
+	assert.Nil(t, qs.IDEq(rows[1].ID).ExactlyOne(&u))
+	assert.Equal(t, rows[1], u)
+
+	// deletion is soft like in gorm
+	assert.Nil(t, qs.IDLte(2).Delete())
+	assert.Len(t, rows, len(users))
+	n, err = qs.Count()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, n)
+	n, err = qs.DeletedOnly().Count()
+	assert.Nil(t, err)
+	assert.Equal(t, 3, n)
+
+	// deletion of soft deleted records is permanent
+	assert.Nil(t, qs.DeletedOnly().IDEq(0).Delete())
+	assert.Len(t, rows, len(users)-1)
+	n, err = qs.WithDeleted().Count()
+	assert.Nil(t, err)
+	assert.Equal(t, 4, n)
+}
+
+func TestToSearchDocumentFlattensRelations(t *testing.T) {
+	title := "title"
+	p := test.Post{
+		Blog:  &test.Blog{Name: "blog"},
+		User:  test.User{Email: "u@mail.ru"},
+		Title: &title,
+	}
+
+	doc := p.ToSearchDocument(test.PostDBSchema.Blog, test.PostDBSchema.User, test.PostDBSchema.Title)
+	assert.Equal(t, "blog", doc["blog.myname"])
+	assert.Equal(t, "u@mail.ru", doc["user.email"])
+	assert.Equal(t, &title, doc["title"])
+	assert.NotContains(t, doc, "id")
+
+	p.Blog = nil
+	assert.NotContains(t, p.ToSearchDocument(), "blog.myname")
+}
+
+func TestParseGenQsComment(t *testing.T) {
+	cases := []struct {
+		line string
+		opts structOptions
+		ok   bool
+	}{
+		{"// gen:qs", structOptions{}, true},
+		{"//gen : qs", structOptions{}, true},
+		{"// gen:qs cache tenant=OrgID", structOptions{"cache": "", "tenant": "OrgID"}, true},
+		{"// gen:qsx", nil, false},
+		{"// gen:qs:cache", nil, false},
+		{"// some doc", nil, false},
+	}
+
+	for _, c := range cases {
+		opts, ok := parseGenQsComment(c.line)
+		assert.Equal(t, c.ok, ok, c.line)
+		assert.Equal(t, c.opts, opts, c.line)
+	}
+}
+
+func TestGetQuerySetOptions(t *testing.T) {
+	doc := func(lines ...string) *ast.CommentGroup {
+		g := &ast.CommentGroup{}
+		for _, l := range lines {
+			g.List = append(g.List, &ast.Comment{Text: l})
+		}
+		return g
+	}
+
+	cases := []struct {
+		doc        *ast.CommentGroup
+		allStructs bool
+		opts       structOptions
+		ok         bool
+	}{
+		{doc("// gen:qs cache"), false, structOptions{"cache": ""}, true},
+		{doc("// gen:qs cache"), true, structOptions{"cache": ""}, true},
+		{doc("// T is a DTO"), false, nil, false},
+		{nil, true, structOptions{}, true},
+		{doc("// T is a DTO"), true, structOptions{}, true},
+		{doc("// T is a DTO", "// gen:qs skip"), true, nil, false},
+		{doc("// gen:qs skip"), false, nil, false},
+	}
+
+	for i, c := range cases {
+		opts, ok := getQuerySetOptions(c.doc, c.allStructs)
+		assert.Equal(t, c.ok, ok, "case %d", i)
+		assert.Equal(t, c.opts, opts, "case %d", i)
+	}
+}
+
+func TestParseGenIndexComment(t *testing.T) {
+	cases := []struct {
+		line, name, where string
+		ok                bool
+	}{
+		{"// gen:index active_email WHERE deleted_at IS NULL", "active_email", "deleted_at IS NULL", true},
+		{"//gen:index idx where  a > 1", "idx", "a > 1", true},
+		{"// gen:index idx", "", "", false},
+		{"// gen:qs", "", "", false},
+	}
+
+	for _, c := range cases {
+		name, where, ok := parseGenIndexComment(c.line)
+		assert.Equal(t, c.ok, ok, c.line)
+		assert.Equal(t, c.name, name, c.line)
+		assert.Equal(t, c.where, where, c.line)
+	}
+}
+
+func TestParseGenProcComment(t *testing.T) {
+	p, err := parseGenProcComment("// gen:proc TopUsers reports.top_users(minRating int, since time.Time)")
+	assert.Nil(t, err)
+	assert.Equal(t, &methods.Procedure{
+		Name:    "TopUsers",
+		SQLName: "reports.top_users",
+		Args: []methods.ProcedureArg{
+			{Name: "minRating", Type: "int"},
+			{Name: "since", Type: "time.Time"},
+		},
+	}, p)
+
+	p, err = parseGenProcComment("//gen:proc AllUsers all_users()")
+	assert.Nil(t, err)
+	assert.Equal(t, &methods.Procedure{Name: "AllUsers", SQLName: "all_users"}, p)
+
+	p, err = parseGenProcComment("// gen:qs")
+	assert.Nil(t, err)
+	assert.Nil(t, p)
+
+	for _, line := range []string{"// gen:proc TopUsers", "// gen:proc TopUsers top_users(int)",
+		"// gen:proc TopUsers top_users(db int)", "// gen:proc TopUsers top_users(ret int)",
+		"// gen:proc TopUsers top_users(err error)", "// gen:proc TopUsers top_users(type string)"} {
+		_, err = parseGenProcComment(line)
+		assert.NotNil(t, err, line)
+	}
+}
+
+func TestGetNaming(t *testing.T) {
+	s := parser.ParsedStruct{TypeName: "User"}
+	n, err := getNaming(s, structOptions{}, "")
+	assert.Nil(t, err)
+	assert.Equal(t, methods.Naming{QuerySet: "UserQuerySet", Constructor: "NewUserQuerySet"}, n)
+
+	n, err = getNaming(s, structOptions{"name": "Users"}, "Filter")
+	assert.Nil(t, err)
+	assert.Equal(t, methods.Naming{QuerySet: "Users", Constructor: "NewUsers", FilterPrefix: "Filter"}, n)
+	assert.Equal(t, "FilterNameEq", n.FilterName("Name", "Eq"))
+
+	n, err = getNaming(s, structOptions{"constructor": "QueryUsers", "prefix": ""}, "Filter")
+	assert.Nil(t, err)
+	assert.Equal(t, methods.Naming{QuerySet: "UserQuerySet", Constructor: "QueryUsers"}, n)
+
+	for _, opts := range []structOptions{{"name": "user-qs"}, {"constructor": "1New"}, {"prefix": "By-"}, {"name": "func"}} {
+		_, err = getNaming(s, opts, "")
+		assert.NotNil(t, err, opts)
+	}
+}
+
+func TestPrimaryKeyDefaultIsRequiredWithoutAutoIncrement(t *testing.T) {
+	for _, d := range []string{"spanner", "oracle"} {
+		outFile := filepath.Join(os.TempDir(), d+"_autogenerated_models.go")
+		err := GenerateQuerySetsWithConfig("test/models.go", outFile, Config{Dialect: d})
+		if assert.NotNil(t, err, d) {
+			assert.Contains(t, err.Error(), d+" dialect has no auto-increment")
+		}
+	}
+}
+
+func TestSequenceOption(t *testing.T) {
+	s := parser.ParsedStruct{TypeName: "User"}
+	pk := &field.Info{Name: "ID", IsNumeric: true}
+	for name, query := range map[string]string{
+		"oracle":   "SELECT users_id_seq.NEXTVAL FROM dual",
+		"postgres": "SELECT nextval('app.users_id_seq')",
+	} {
+		d, _ := dialect.Get(name)
+		seq := "users_id_seq"
+		if name == "postgres" {
+			seq = "app." + seq
+		}
+		q, err := getSequence(s, structOptions{"sequence": seq}, pk, d)
+		assert.Nil(t, err, name)
+		assert.Equal(t, query, q, name)
+	}
+
+	d, _ := dialect.Get("oracle")
+	q, err := getSequence(s, structOptions{}, pk, d)
+	assert.Nil(t, err)
+	assert.Empty(t, q)
+
+	for _, seq := range []string{"", "users_id_seq; DROP TABLE users", "1seq", "a.b.c"} {
+		_, err = getSequence(s, structOptions{"sequence": seq}, pk, d)
+		assert.NotNil(t, err, seq)
+	}
+	_, err = getSequence(s, structOptions{"sequence": "users_id_seq"}, &field.Info{Name: "ID", IsString: true}, d)
+	assert.NotNil(t, err)
+
+	d, _ = dialect.Get("mysql")
+	_, err = getSequence(s, structOptions{"sequence": "users_id_seq"}, pk, d)
+	assert.NotNil(t, err)
+}
+
+func TestOutPackageNeedsNoObjectMethods(t *testing.T) {
+	cfg := testConfig
+	cfg.OutPkg = filepath.Join(os.TempDir(), "queries")
+	err := GenerateQuerySetsWithConfig("test/models.go", "autogenerated_models.go", cfg)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "cache option of struct User isn't supported by out package")
+	}
+}
+
+func TestGenerationErrorsOfAllStructs(t *testing.T) {
+	pkgInfo, structs, err := parser.GetStructsInFile("test/models.go")
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	var msgs []string
+	for _, jobs := range []int{1, 4} {
+		_, err = getQuerySetConfigs(pkgInfo, structs, Config{Dialect: "spanner", Jobs: jobs})
+		errs, ok := err.(generationErrors)
+		if !assert.True(t, ok, "errors of all structs are returned") {
+			return
+		}
+		assert.True(t, len(errs) > 1)
+		msgs = append(msgs, err.Error())
+	}
+	assert.Equal(t, msgs[0], msgs[1], "order of errors doesn't depend on scheduling")
+
+	blog := structs["Blog"]
+	assert.NotZero(t, blog.Line)
+	assert.Contains(t, msgs[0], fmt.Sprintf("%s:%d: numeric primary key ID of struct Blog", blog.File, blog.Line))
+}
+
+func TestRegenerationIsZeroDiff(t *testing.T) {
+	outFile := filepath.Join(os.TempDir(), "zero_diff_autogenerated_models.go")
+	defer os.Remove(outFile)
+	for _, suffix := range []string{"_debug", "_nodebug"} {
+		defer os.Remove(debugVariantPath(outFile, suffix))
+	}
+
+	assert.Nil(t, GenerateQuerySetsWithConfig("test/models.go", outFile, testConfig))
+	generated, err := ioutil.ReadFile(outFile)
+	assert.Nil(t, err)
+	expected, err := ioutil.ReadFile("test/autogenerated_models.go")
+	assert.Nil(t, err)
+	assert.True(t, bytes.Equal(expected, generated), "output must be the same for the same models")
+
+	old := time.Now().Add(-time.Hour).Truncate(time.Second)
+	assert.Nil(t, os.Chtimes(outFile, old, old))
+	assert.Nil(t, GenerateQuerySetsWithConfig("test/models.go", outFile, testConfig))
+	info, err := os.Stat(outFile)
+	if assert.Nil(t, err) {
+		assert.True(t, info.ModTime().Equal(old), "unchanged file must not be rewritten")
+	}
+}
+
+func TestCheckGeneratedCode(t *testing.T) {
+	cfg := testConfig
+	cfg.Check = true
+	assert.Nil(t, GenerateQuerySetsWithConfig("test/models.go", "test/autogenerated_models.go", cfg))
+
+	cfg.FilterPrefix = "Filter"
+	err := GenerateQuerySetsWithConfig("test/models.go", "test/autogenerated_models.go", cfg)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "out file is out of date")
+		assert.Contains(t, err.Error(), "+++ test/autogenerated_models.go (generated)\n")
+		assert.Contains(t, err.Error(), "\n+func (qs UserQuerySet) FilterNameEq(name string) UserQuerySet {\n")
+		assert.Contains(t, err.Error(), "\n-func (qs UserQuerySet) NameEq(name string) UserQuerySet {\n")
+	}
+}
+
+func TestBuildConstraints(t *testing.T) {
+	c, err := getBuildConstraints("", "!prod")
+	assert.Nil(t, err)
+	assert.Equal(t, "//go:build !prod\n// +build !prod\n\n", c)
+
+	c, err = getBuildConstraints("!codeanalysis", "prod")
+	assert.Nil(t, err)
+	assert.Equal(t, "//go:build !codeanalysis && prod\n// +build !codeanalysis,prod\n\n", c)
+
+	c, err = getBuildConstraints("linux || darwin", "!prod")
+	assert.Nil(t, err)
+	assert.Equal(t, "//go:build (linux || darwin) && !prod\n// +build linux,!prod darwin,!prod\n\n", c)
+
+	_, err = getBuildConstraints("a &&")
+	assert.NotNil(t, err)
+}
+
+func TestGeneratedFileHeader(t *testing.T) {
+	outFile := filepath.Join(os.TempDir(), "header_autogenerated_models.go")
+	defer os.Remove(outFile)
+
+	cfg := Config{
+		Dialect:  "mysql",
+		BuildTag: "!codeanalysis",
+		Header:   "// Copyright 2026 Authors.\n\n// Code generated by go-queryset. DO NOT EDIT.\n",
+	}
+	assert.Nil(t, GenerateQuerySetsWithConfig("test/models.go", outFile, cfg))
+	code, err := ioutil.ReadFile(outFile)
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(string(code), "// Copyright 2026 Authors.\n\n"+
+		"// Code generated by go-queryset. DO NOT EDIT.\n\n"+
+		"//go:build !codeanalysis\n// +build !codeanalysis\n\npackage test\n"))
+
+	cfg.Header = "// Copyright 2026 Authors."
+	err = GenerateQuerySetsWithConfig("test/models.go", outFile, cfg)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "has no \"// Code generated ... DO NOT EDIT.\" line")
+	}
+}
+
+func TestGenerationCache(t *testing.T) {
+	outFile := filepath.Join(os.TempDir(), "cached_autogenerated_models.go")
+	defer os.Remove(outFile)
+	for _, suffix := range []string{"_debug", "_nodebug"} {
+		defer os.Remove(debugVariantPath(outFile, suffix))
+	}
+
+	assert.Nil(t, GenerateQuerySetsWithConfig("test/models.go", outFile, testConfig))
+	assert.True(t, newGenerationCache("test/models.go", outFile, testConfig).isUpToDate())
+
+	cfg := testConfig
+	cfg.FilterPrefix = "Filter"
+	assert.False(t, newGenerationCache("test/models.go", outFile, cfg).isUpToDate(), "config was changed")
+	cfg.Force = true
+	assert.Nil(t, newGenerationCache("test/models.go", outFile, cfg))
+
+	f, err := os.OpenFile(debugVariantPath(outFile, "_debug"), os.O_APPEND|os.O_WRONLY, 0)
+	if assert.Nil(t, err) {
+		_, err = f.WriteString("// edited\n")
+		assert.Nil(t, err)
+		assert.Nil(t, f.Close())
+	}
+	assert.False(t, newGenerationCache("test/models.go", outFile, testConfig).isUpToDate(), "out file was edited")
+}
+
+func TestGenerationCacheImportedFiles(t *testing.T) {
+	files, err := getImportedFiles("test/pkgimport")
+	assert.Nil(t, err)
+	imported := false
+	for _, f := range files {
+		imported = imported || strings.HasSuffix(f, filepath.FromSlash("pkgimport/forex/v1/types.go"))
+		assert.False(t, strings.HasPrefix(f, build.Default.GOROOT), "%s is in GOROOT", f)
+	}
+	assert.True(t, imported, "types of fields are declared in imported package")
+}
+
+func TestFilterStructsByFiles(t *testing.T) {
+	structs := parser.ParsedStructs{
+		"User":   {TypeName: "User", File: "models/user_model.go"},
+		"Post":   {TypeName: "Post", File: "models/post_model.go"},
+		"Legacy": {TypeName: "Legacy", File: "models/legacy.go"},
+	}
+
+	ret, err := filterStructsByFiles(structs, []string{"*_model.go"}, []string{"post_*"})
+	assert.Nil(t, err)
+	assert.Equal(t, parser.ParsedStructs{"User": structs["User"]}, ret)
+
+	ret, err = filterStructsByFiles(structs, nil, []string{"legacy.go"})
+	assert.Nil(t, err)
+	assert.Len(t, ret, 2)
+
+	_, err = filterStructsByFiles(structs, []string{"["}, nil)
+	assert.NotNil(t, err)
+}
+
+func TestUserTemplates(t *testing.T) {
+	dir, err := ioutil.TempDir("", "templates")
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	cfg := Config{Dialect: "mysql", TemplatesDir: dir}
+	outFile := filepath.Join(dir, "autogenerated_models.go")
+	err = GenerateQuerySetsWithConfig("test/models.go", outFile, cfg)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "can't parse user templates")
+	}
+
+	tmpl := `{{ define "struct" }}{{ if .HasOption "fake" }}
+// ForTenant filters by tenant
+func (qs {{ .Name }}) ForTenant(id uint) {{ .Name }} {
+	return qs.w(func(db *gorm.DB) *gorm.DB {
+		return db.Where("tenant_id = ?", id)
+	})
+}
+{{ end }}{{ end }}
+{{ define "package" }}
+var generatedStructs = []string{ {{- range .Configs }}"{{ .StructName }}", {{ end -}} }
+{{ end }}`
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "tenant.tmpl"), []byte(tmpl), 0600))
+	assert.Nil(t, GenerateQuerySetsWithConfig("test/models.go", outFile, cfg))
+
+	generated, err := ioutil.ReadFile(outFile)
+	assert.Nil(t, err)
+	assert.Contains(t, string(generated), "func (qs UserQuerySet) ForTenant(id uint) UserQuerySet {")
+	assert.NotContains(t, string(generated), "func (qs BlogQuerySet) ForTenant(")
+	assert.Contains(t, string(generated), `var generatedStructs = []string{"Blog", "CheckReservedKeywords",`)
+}
+
+var testConfig = Config{
+	Dialect:       "mysql",
+	DebugBuildTag: "!prod",
+	TenantField:   "TenantID",
+}
+
+func TestMain(m *testing.M) {
+	err := GenerateQuerySetsWithConfig("test/models.go", "test/autogenerated_models.go", testConfig)
+	if err != nil {
+		panic(err)
+	}
+
+	err = GenerateQuerySetsWithConfig("test/postgres/models.go", "test/postgres/autogenerated_models.go",
+		Config{Dialect: "postgres"})
 	if err != nil {
 		panic(err)
 	}
@@ -316,7 +2654,7 @@ func TestMain(m *testing.M) {
 
 func BenchmarkHello(b *testing.B) {
 	for i := 0; i < b.N; i++ {
-		err := GenerateQuerySets("test/models.go", "test/autogenerated_models.go")
+		err := GenerateQuerySetsWithConfig("test/models.go", "test/autogenerated_models.go", testConfig)
 		if err != nil {
 			b.Fatalf("can't generate querysets: %s", err)
 		}
@@ -0,0 +1,122 @@
+package queryset
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jirfag/go-queryset/parser"
+	"github.com/jirfag/go-queryset/queryset/dialect"
+	"github.com/jirfag/go-queryset/queryset/field"
+)
+
+// jobQueue is a job queue declared by "queue=ready:claimed" option: jobs in
+// ready status are claimed by workers, claimed job gets claimed status,
+// worker's id in LockedBy field and time of claim in LockedAt field.
+// Statuses are names of constants of Status field's type.
+type jobQueue struct {
+	Status, LockedBy, LockedAt field.Info
+
+	Ready   string // constant of status of jobs to claim
+	Claimed string // constant of status of claimed jobs
+
+	ReadyCond string // condition on status of jobs to claim
+	Order     string // order of jobs to claim: by primary key
+	ForUpdate string // clause locking selected jobs and skipping locked ones
+	Claim     string // format of UPDATE of claimed job with quoted table %[1]s
+	Heartbeat string // format of UPDATE of LockedAt of job claimed by worker
+	Reclaim   string // format of UPDATE returning stale claimed jobs to ready status
+}
+
+// queueFieldNames are names of fields required by job queue
+var queueFieldNames = []string{"Status", "LockedBy", "LockedAt"}
+
+// getJobQueue returns job queue of struct declared by "queue" option or nil
+// if there is no such option
+func getJobQueue(s parser.ParsedStruct, opts structOptions, fields []field.Info,
+	pk *field.Info, d dialect.Dialect) (*jobQueue, error) {
+
+	statuses, ok := opts["queue"]
+	if !ok {
+		return nil, nil
+	}
+
+	if d.ForUpdateSkipLocked() == "" {
+		return nil, fmt.Errorf("queue option of struct %s isn't supported by %s dialect: "+
+			"it has no SKIP LOCKED", s.TypeName, d.Name())
+	}
+	if pk == nil {
+		return nil, fmt.Errorf("struct %s has no primary key to claim jobs", s.TypeName)
+	}
+
+	byName := map[string]field.Info{}
+	for _, f := range fields {
+		byName[f.Name] = f
+	}
+	for _, name := range queueFieldNames {
+		if _, ok = byName[name]; !ok {
+			return nil, fmt.Errorf("job queue struct %s has no field %s, queue needs fields %s",
+				s.TypeName, name, strings.Join(queueFieldNames, ", "))
+		}
+	}
+
+	q := jobQueue{
+		Status:    byName["Status"],
+		LockedBy:  byName["LockedBy"],
+		LockedAt:  byName["LockedAt"],
+		ForUpdate: d.ForUpdateSkipLocked(),
+	}
+	if by := q.LockedBy; by.TypeName != "string" && !(by.IsPointer && by.GetPointed().TypeName == "string") {
+		return nil, fmt.Errorf("field LockedBy of job queue struct %s must be string or *string", s.TypeName)
+	}
+	if at := q.LockedAt; !at.IsTime && !(at.IsPointer && at.GetPointed().IsTime) {
+		return nil, fmt.Errorf("field LockedAt of job queue struct %s must be time.Time or *time.Time", s.TypeName)
+	}
+
+	parts := strings.Split(statuses, ":")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("invalid queue option %q of struct %s, expected queue=ready:claimed",
+			statuses, s.TypeName)
+	}
+	var err error
+	if q.Ready, err = getQueueStatus(s, q.Status, parts[0]); err != nil {
+		return nil, err
+	}
+	if q.Claimed, err = getQueueStatus(s, q.Status, parts[1]); err != nil {
+		return nil, err
+	}
+
+	q.ReadyCond = d.Quote(q.Status.DBName) + " = ?"
+	q.Order = d.Quote(pk.DBName)
+	status, lockedBy, lockedAt := d.Quote(q.Status.DBName), d.Quote(q.LockedBy.DBName), d.Quote(q.LockedAt.DBName)
+	q.Claim = fmt.Sprintf("UPDATE %%[1]s SET %s = ?, %s = ?, %s = ? WHERE %s = ?",
+		status, lockedBy, lockedAt, d.Quote(pk.DBName))
+	q.Heartbeat = fmt.Sprintf("UPDATE %%[1]s SET %s = ? WHERE %s = ? AND %s = ? AND %s = ?",
+		lockedAt, d.Quote(pk.DBName), status, lockedBy)
+
+	// not nullable LockedBy is reset to empty string, not nullable LockedAt is kept
+	reset := fmt.Sprintf("%s = ?, %s = ''", status, lockedBy)
+	if q.LockedBy.IsPointer {
+		reset = fmt.Sprintf("%s = ?, %s = NULL", status, lockedBy)
+	}
+	if q.LockedAt.IsPointer {
+		reset += fmt.Sprintf(", %s = NULL", lockedAt)
+	}
+	q.Reclaim = fmt.Sprintf("UPDATE %%[1]s SET %s WHERE %s = ? AND %s < ?", reset, status, lockedAt)
+	return &q, nil
+}
+
+// getQueueStatus returns constant of status of job queue by its name
+// without prefix of type name (e.g. Ready for JobStatusReady) or by name of
+// constant
+func getQueueStatus(s parser.ParsedStruct, status field.Info, name string) (string, error) {
+	var names []string
+	for _, v := range status.EnumValues {
+		if v.Name == name || v.Const == name {
+			return v.Const, nil
+		}
+		names = append(names, v.Name)
+	}
+
+	return "", fmt.Errorf("unknown status %q of job queue struct %s, expected one of constants of "+
+		"type %s: %s", name, s.TypeName, status.TypeName, strings.Join(names, ", "))
+}
@@ -1,17 +1,44 @@
 package queryset
 
 import (
+	"fmt"
+	"path/filepath"
 	"text/template"
-
-	"github.com/jirfag/go-queryset/queryset/methods"
 )
 
 var qsTmpl = template.Must(
-	template.New("generator").
-		Funcs(template.FuncMap{
-			"lcf": methods.LowercaseFirstRune,
-		}).
-		Parse(qsCode),
+	template.New("generator").Parse(qsCode + userHooksCode),
+)
+
+// userHooksCode defines empty hooks for user templates: template "struct"
+// is executed for config of every struct, template "package" is executed
+// once per package with all configs
+const userHooksCode = `{{ define "struct" }}{{ end }}{{ define "package" }}{{ end }}`
+
+// getQuerySetsTemplate returns template of querysets extended by user
+// templates (*.tmpl files) in dir: they redefine hooks "struct" and
+// "package", e.g. to generate methods of audit columns
+func getQuerySetsTemplate(dir string) (*template.Template, error) {
+	if dir == "" {
+		return qsTmpl, nil
+	}
+
+	t, err := qsTmpl.Clone()
+	if err != nil {
+		return nil, err
+	}
+	if t, err = t.ParseGlob(filepath.Join(dir, "*.tmpl")); err != nil {
+		return nil, fmt.Errorf("can't parse user templates in %s: %s", dir, err)
+	}
+	return t, nil
+}
+
+var debugTmpl = template.Must(
+	template.New("debug generator").Parse(debugCode),
+)
+
+var noDebugTmpl = template.Must(
+	template.New("no debug generator").Parse(noDebugCode),
 )
 
 const qsCode = `
@@ -20,35 +47,801 @@ const qsCode = `
 {{ range .Configs }}
   // ===== BEGIN of query set {{ .Name }}
 
+	{{ if .ModelPkg }}
+	// {{ .StructName }} is a model of {{ .Name }}
+	type {{ .StructName }} = {{ .ModelPkg }}.{{ .StructName }}
+	{{ end }}
+
 	// {{ .Name }} is an queryset type for {{ .StructName }}
   type {{ .Name }} struct {
 	  db *gorm.DB
+	  // db is built by applying ops, calls of GORM, to root, db of constructor:
+	  // branches of queryset never share conditions of GORM
+	  root *gorm.DB
+	  ops  []func(db *gorm.DB) *gorm.DB
+	  // columns are selected by Distinct methods, joined is set by Join methods
+	  // and preloads are names of associations preloaded by Preload methods:
+	  // GORM doesn't export them, ToSQL and CacheKey are built from them
+	  columns  string
+	  joined   bool
+	  preloads []string
   }
 
-  // New{{ .Name }} constructs new {{ .Name }}
-  func New{{ .Name }}(db *gorm.DB) {{ .Name }} {
-	  return {{ .Name }}{
-		  db: db.Model(&{{ .StructName }}{}),
-	  }
+	{{- if .Tenant }}
+	// {{ .Constructor }} constructs new {{ .Name }} of rows of tenant tenantID: querysets
+	// of {{ .StructName }} can't be constructed without tenant, so rows of other tenants don't leak
+	func {{ .Constructor }}(db *gorm.DB, tenantID {{ .Tenant.TypeName }}) {{ .Name }} {
+		qs := {{ .AllTenantsConstructor }}(db)
+		return qs.w(func(db *gorm.DB) *gorm.DB {
+			return db.Where("{{ .TenantCond }}", tenantID)
+		})
+	}
+
+	// {{ .AllTenantsConstructor }} constructs new {{ .Name }} of rows of all tenants.
+	// Conditions of db are shared with other users of db like by GORM.
+	func {{ .AllTenantsConstructor }}(db *gorm.DB) {{ .Name }} {
+		db = db.Model(&{{ .StructName }}{})
+		return {{ .Name }}{db: db, root: db}
+	}
+	{{- else }}
+  // {{ .Constructor }} constructs new {{ .Name }}. Conditions of db are
+  // shared with other users of db like by GORM.
+  func {{ .Constructor }}(db *gorm.DB) {{ .Name }} {
+	  db = db.Model(&{{ .StructName }}{})
+	  return {{ .Name }}{db: db, root: db}
   }
+	{{- end }}
 
-	func (qs {{ .Name }}) w(db *gorm.DB) {{ .Name }} {
-	  return New{{ .Name }}(db)
+	// Clone returns independent copy of queryset. Chain methods are copy-on-write
+	// too: conditions added to branches of queryset never affect each other.
+	func (qs {{ .Name }}) Clone() {{ .Name }} {
+		return qs
+	}
+
+	// {{ .Constructor }}Tx constructs new {{ .Name }} in transaction tx, e.g. begun by
+	// WithTransaction: queries of the queryset fail if tx isn't a transaction
+	func {{ .Constructor }}Tx(tx *gorm.DB{{ if .Tenant }}, tenantID {{ .Tenant.TypeName }}{{ end }}) {{ .Name }} {
+		qs := {{ .Constructor }}(tx{{ if .Tenant }}, tenantID{{ end }})
+		if _, ok := tx.CommonDB().(*sql.Tx); !ok {
+			return qs.w(withError(errors.New("db of {{ .Constructor }}Tx isn't a transaction")))
+		}
+		return qs
+	}
+
+	// w returns queryset with op applied to conditions of qs. Ops are replayed on root:
+	// GORM clones conditions shallowly, so appending conditions to db of one branch of
+	// queryset could overwrite conditions of another one.
+	func (qs {{ .Name }}) w(op func(db *gorm.DB) *gorm.DB) {{ .Name }} {
+		{{- if .HasOption "querylog" }}
+		method, apply := {{ .StructName }}QueryLogMethod(), op
+		op = func(db *gorm.DB) *gorm.DB {
+			db = apply(db)
+			if _, ok := db.Get("queryset:{{ .StructName }}:querylog"); ok {
+				db = db.Set("queryset:{{ .StructName }}:chain", append{{ .StructName }}QueryLogChain(db, method))
+			}
+			return db
+		}
+		{{- end }}
+		ops := append(qs.ops[:len(qs.ops):len(qs.ops)], op)
+		db := qs.root
+		for _, op := range ops {
+			db = op(db)
+		}
+		qs.db, qs.ops = db, ops
+		return qs
   }
 
+	// selectColumns returns queryset selecting columns instead of all columns
+	func (qs {{ .Name }}) selectColumns(columns string) {{ .Name }} {
+		qs = qs.w(func(db *gorm.DB) *gorm.DB {
+			return db.Select(columns)
+		})
+		qs.columns = columns
+		return qs
+	}
+
+	// preload returns queryset preloading association name
+	func (qs {{ .Name }}) preload(name string) {{ .Name }} {
+		qs = qs.w(func(db *gorm.DB) *gorm.DB {
+			return db.Preload(name)
+		})
+		qs.preloads = append(qs.preloads[:len(qs.preloads):len(qs.preloads)], name)
+		return qs
+	}
+
+	// rawSQL returns SQL built by format from quoted table name and conditions
+	// of queryset (WHERE, ORDER BY etc) and its vars. Bind vars of SQL are ?:
+	// it can be embedded into another query, which rebinds them. Bind vars
+	// are replaced from the last one: $1 and :1 are prefixes of $10 and :10.
+	func (qs {{ .Name }}) rawSQL(format string) (string, []interface{}) {
+		scope := qs.db.NewScope(&{{ .StructName }}{})
+		sql := fmt.Sprintf(format, scope.QuotedTableName(), scope.CombinedConditionSql())
+		for i := len(scope.SQLVars); i > 0; i-- {
+			sql = strings.Replace(sql, scope.Dialect().BindVar(i), "?", 1)
+		}
+		return sql, scope.SQLVars
+	}
+
+	// SubQuery returns subquery selecting column of queryset's rows: it's used
+	// by <Field>InSubquery filters of other querysets
+	func (qs {{ .Name }}) SubQuery(field {{ .StructName }}DBSchemaField) SubQuery {
+		column := qs.db.NewScope(&{{ .StructName }}{}).Quote(field.String())
+		sql, vars := qs.rawSQL("SELECT %[1]s." + column + " FROM %[1]s %[2]s")
+		return SubQuery{sql: sql, vars: vars}
+	}
+
+	// ToSQL returns SQL and args of select query of queryset as GORM would execute it,
+	// but doesn't execute it, e.g. to log it or to pass it to EXPLAIN. Bind vars of SQL
+	// are bind vars of dialect of db. Columns selected by custom methods calling Select
+	// of GORM aren't tracked: all columns are in SQL.
+	func (qs {{ .Name }}) ToSQL() (string, []interface{}) {
+		scope := qs.db.NewScope(&{{ .StructName }}{})
+		columns := qs.columns
+		if columns == "" {
+			columns = "*"
+			if qs.joined { // like GORM: columns of joined tables aren't selected
+				columns = scope.QuotedTableName() + ".*"
+			}
+		}
+
+		scope.Raw(fmt.Sprintf("SELECT %s FROM %s %s", columns, scope.QuotedTableName(), scope.CombinedConditionSql()))
+		return scope.SQL, scope.SQLVars
+	}
+
+	{{ if .Explain }}
+	// Explain returns plan of select query of queryset chosen by DB, e.g. to check
+	// usage of indexes. Query isn't executed. Rows of plan are separated by newlines,
+	// columns of rows by tabs.
+	func (qs {{ .Name }}) Explain(ctx context.Context) (string, error) {
+		query, args := qs.ToSQL()
+		var lines []string
+		err := call{{ .StructName }}Breaker(qs.db, func() error {
+			rows, err := queryContext(ctx, qs.db, fmt.Sprintf({{ printf "%q" .Explain }}, query), args)
+			if err != nil {
+				return err
+			}
+			defer rows.Close()
+
+			columns, err := rows.Columns()
+			if err != nil {
+				return err
+			}
+			for rows.Next() {
+				values, dests := make([]sql.NullString, len(columns)), make([]interface{}, len(columns))
+				for i := range values {
+					dests[i] = &values[i]
+				}
+				if err := rows.Scan(dests...); err != nil {
+					return err
+				}
+
+				line := make([]string, len(values))
+				for i, v := range values {
+					line[i] = v.String
+				}
+				lines = append(lines, strings.Join(line, "\t"))
+			}
+			return rows.Err()
+		})
+		return strings.Join(lines, "\n"), err
+	}
+	{{ end }}
+
+	// CacheKey returns key of query of queryset: its selected columns, conditions
+	// (WHERE, ORDER BY etc) with vars and preloaded associations. Columns selected
+	// by custom Select of GORM aren't included into the key.
+	func (qs {{ .Name }}) CacheKey() string {
+		sql, vars := qs.rawSQL("%[1]s %[2]s")
+		return fmt.Sprintf("%q %s %v %q", qs.columns, sql, vars, qs.preloads)
+	}
+
+	// WithContext returns queryset, which statements pass ctx to hook set by SetQueryHook
+	func (qs {{ .Name }}) WithContext(ctx context.Context) {{ .Name }} {
+		return qs.w(func(db *gorm.DB) *gorm.DB {
+			return db.Set("queryset:ctx", ctx)
+		})
+	}
+
+	{{ $s := .StructName }}
+	// filter{{ .StructName }}Columns are columns of {{ .StructName }} filtered by ApplyFilters
+	// and ordered by OrderByParam
+	var filter{{ .StructName }}Columns = map[{{ .StructName }}DBSchemaField]filterColumn{
+		{{- range .FilterColumns }}
+		{{ $s }}DBSchema.{{ .Field }}: {
+			quoted:   {{ printf "%q" .Quoted }},
+			typ:      reflect.TypeOf((*{{ .TypeName }})(nil)).Elem(),
+			nullable: {{ .Nullable }},
+		},
+		{{- end }}
+	}
+
+	// ApplyFilters returns queryset filtered by runtime specification of filters, e.g. by
+	// query params of generic list endpoint. Filters are applied like filter methods:
+	// values are passed as bind vars. Error is returned for fields without filters,
+	// unknown operations and values of wrong types.
+	func (qs {{ .Name }}) ApplyFilters(filters map[{{ .StructName }}DBSchemaField]FilterSpec) ({{ .Name }}, error) {
+		names := make([]string, 0, len(filters))
+		for f := range filters {
+			names = append(names, string(f))
+		}
+		sort.Strings(names) // SQL doesn't depend on map order
+
+		for _, name := range names {
+			f := {{ .StructName }}DBSchemaField(name)
+			column, ok := filter{{ .StructName }}Columns[f]
+			if !ok {
+				return qs, fmt.Errorf("{{ .StructName }} can't be filtered by %q", f)
+			}
+			cond, args, err := column.cond(filters[f])
+			if err != nil {
+				return qs, fmt.Errorf("invalid filter of {{ .StructName }} by %s: %s", f, err)
+			}
+			qs = qs.w(func(db *gorm.DB) *gorm.DB {
+				return db.Where(cond, args...)
+			})
+		}
+		return qs, nil
+	}
+
+	// OrderByParam returns queryset ordered by API-style sort param: comma-separated
+	// db names of fields, prefixed by - for descending order, e.g. "-created_at,name".
+	// Error is returned for unknown fields, they are never interpolated into SQL.
+	func (qs {{ .Name }}) OrderByParam(param string) ({{ .Name }}, error) {
+		if strings.TrimSpace(param) == "" {
+			return qs, nil
+		}
+
+		for _, name := range strings.Split(param, ",") {
+			name = strings.TrimSpace(name)
+			order := "ASC"
+			if strings.HasPrefix(name, "-") {
+				name, order = name[1:], "DESC"
+			}
+			column, ok := filter{{ .StructName }}Columns[{{ .StructName }}DBSchemaField(name)]
+			if !ok {
+				return qs, fmt.Errorf("{{ .StructName }} can't be ordered by %q", name)
+			}
+			qs = qs.w(func(db *gorm.DB) *gorm.DB {
+				return db.Order(column.quoted + " " + order)
+			})
+		}
+		return qs, nil
+	}
+
+	// {{ .Name }}Union is a UNION of {{ .Name }} querysets: it has only read finishers,
+	// preloads and selected columns of querysets aren't used
+	type {{ .Name }}Union struct {
+		db   *gorm.DB
+		sql  string
+		vars []interface{}
+	}
+
+	// Union returns union of distinct rows of querysets qs and other. Querysets
+	// keep their own order and limits, e.g. to union top rows by two criteria.
+	func (qs {{ .Name }}) Union(other {{ .Name }}) {{ .Name }}Union {
+		return {{ .Name }}Union{db: qs.db}.add("", qs).add("UNION", other)
+	}
+
+	// UnionAll returns union of all rows of querysets qs and other, duplicates aren't removed
+	func (qs {{ .Name }}) UnionAll(other {{ .Name }}) {{ .Name }}Union {
+		return {{ .Name }}Union{db: qs.db}.add("", qs).add("UNION ALL", other)
+	}
+
+	// Union returns union of distinct rows of u and queryset other
+	func (u {{ .Name }}Union) Union(other {{ .Name }}) {{ .Name }}Union {
+		return u.add("UNION", other)
+	}
+
+	// UnionAll returns union of all rows of u and queryset other
+	func (u {{ .Name }}Union) UnionAll(other {{ .Name }}) {{ .Name }}Union {
+		return u.add("UNION ALL", other)
+	}
+
+	func (u {{ .Name }}Union) add(op string, qs {{ .Name }}) {{ .Name }}Union {
+		sql, vars := qs.rawSQL("SELECT * FROM %[1]s %[2]s")
+		sql = strings.TrimSpace(sql)
+		{{- if .OrderedSubquery }}
+		if _, paged := qs.db.Get("{{ .Name }}:paged"); !paged && strings.Contains(sql, " ORDER BY ") {
+			sql += {{ printf "%q" .OrderedSubquery }} // ordered operand isn't limited
+		}
+		{{- end }}
+		sql = fmt.Sprintf({{ printf "%q" .UnionSelect }}, sql)
+		if op != "" {
+			sql = u.sql + " " + op + " " + sql
+		}
+		return {{ .Name }}Union{
+			db:   u.db,
+			sql:  sql,
+			vars: append(append([]interface{}{}, u.vars...), vars...),
+		}
+	}
+
+	// ToSQL returns SQL and args of union, bind vars of SQL are ?
+	func (u {{ .Name }}Union) ToSQL() (string, []interface{}) {
+		return u.sql, u.vars
+	}
+
+	// All selects all rows of union: limit of FailIfMoreThan or MaxRows option
+	// of the first queryset is checked while rows are scanned
+	func (u {{ .Name }}Union) All(ret *[]{{ .StructName }}) error {
+		*ret = nil
+		var loaded []{{ .StructName }}
+		max, tooMany := {{ .Name }}{db: u.db}.maxRows(true), false
+		err := call{{ .StructName }}Breaker(u.db, func() error {
+			rows, err := u.db.New().Raw(u.sql, u.vars...).Rows()
+			if err != nil {
+				return err
+			}
+			defer rows.Close()
+
+			for rows.Next() {
+				if max > 0 && len(loaded) == max {
+					tooMany = true // it isn't a failure of DB for breaker
+					return nil
+				}
+
+				var o {{ .StructName }}
+				if err = u.db.ScanRows(rows, &o); err != nil {
+					return err
+				}
+				loaded = append(loaded, o)
+			}
+			return rows.Err()
+		})
+		if err != nil {
+			return err
+		}
+		if tooMany {
+			return {{ .StructName }}TooManyRowsError{Max: max}
+		}
+
+		*ret = loaded
+		return nil
+	}
+
+	// Count returns number of rows of union: it's checked against limit of
+	// FailIfMoreThan of the first queryset
+	func (u {{ .Name }}Union) Count() (int, error) {
+		var count int
+		sql := "SELECT count(*) FROM (" + u.sql + ") " + u.db.NewScope(&{{ .StructName }}{}).Quote("union_rows")
+		err := call{{ .StructName }}Breaker(u.db, func() error {
+			return u.db.New().Raw(sql, u.vars...).Row().Scan(&count)
+		})
+		if err != nil {
+			return 0, err
+		}
+		if err := ({{ .Name }}{db: u.db}).checkRowsNum(count, false); err != nil {
+			return 0, err
+		}
+		return count, nil
+	}
+
+	{{ if .AsOfSystemTime }}
+	// {{ .Name }}AsOf reads results of {{ .Name }} from snapshot of table: it has only
+	// read finishers, preloads and selected columns of queryset aren't used
+	type {{ .Name }}AsOf struct {
+		qs     {{ .Name }}
+		clause string
+	}
+
+	// AsOfSystemTime returns reader of queryset results from consistent snapshot of table
+	// at time t, e.g. for analytics: snapshot reads don't block writes and aren't blocked
+	// by them. t must be within garbage collection window of database.
+	func (qs {{ .Name }}) AsOfSystemTime(t time.Time) {{ .Name }}AsOf {
+		return {{ .Name }}AsOf{
+			qs:     qs,
+			clause: fmt.Sprintf({{ printf "%q" .AsOfSystemTime }}, t.UTC().Format({{ printf "%q" .TimestampLayout }})),
+		}
+	}
+
+	func (s {{ .Name }}AsOf) rawSQL(columns string) (string, []interface{}) {
+		return s.qs.rawSQL("SELECT " + columns + " FROM %[1]s " + s.clause + " %[2]s")
+	}
+
+	// All is a snapshot read of {{ .Name }}.All
+	func (s {{ .Name }}AsOf) All(ret *[]{{ .StructName }}) error {
+		sql, vars := s.rawSQL("*")
+		if err := s.qs.db.New().Raw(sql, vars...).Scan(ret).Error; err != nil {
+			return err
+		}
+		if err := s.qs.checkRowsNum(len(*ret), true); err != nil {
+			*ret = nil
+			return err
+		}
+		return nil
+	}
+
+	// One is a snapshot read of {{ .Name }}.One
+	func (s {{ .Name }}AsOf) One(ret *{{ .StructName }}) error {
+		if s.qs.maxRows(false) > 0 {
+			if _, err := s.Count(); err != nil {
+				return err
+			}
+		}
+
+		s.qs = s.qs.w(func(db *gorm.DB) *gorm.DB {
+			return db.Limit(1)
+		})
+		sql, vars := s.rawSQL("*")
+		return s.qs.db.New().Raw(sql, vars...).Scan(ret).Error
+	}
+
+	// Count is a snapshot read of {{ .Name }}.Count
+	func (s {{ .Name }}AsOf) Count() (int, error) {
+		var count int
+		sql, vars := s.rawSQL("count(*)")
+		err := call{{ .StructName }}Breaker(s.qs.db, func() error {
+			return s.qs.db.New().Raw(sql, vars...).Row().Scan(&count)
+		})
+		if err != nil {
+			return 0, err
+		}
+		if err := s.qs.checkRowsNum(count, false); err != nil {
+			return 0, err
+		}
+		return count, nil
+	}
+	{{ end }}
+
+	// {{ .StructName }}QueryMemo memoizes results of {{ .Name }} finishers All, One and Count
+	type {{ .StructName }}QueryMemo struct {
+		mu sync.Mutex
+		results map[string]interface{}
+	}
+
+	type memo{{ .StructName }}Key struct{}
+
+	// With{{ .StructName }}QueryMemo returns ctx with new memo of {{ .Name }} results,
+	// e.g. create it per request in middleware
+	func With{{ .StructName }}QueryMemo(ctx context.Context) context.Context {
+		return context.WithValue(ctx, memo{{ .StructName }}Key{}, &{{ .StructName }}QueryMemo{
+			results: map[string]interface{}{},
+		})
+	}
+
+	// Memoized returns queryset, which finishers All, One and Count return results memoized
+	// in ctx created by With{{ .StructName }}QueryMemo: identical calls (by CacheKey) query
+	// DB only once. Queryset isn't memoized if ctx has no memo.
+	func (qs {{ .Name }}) Memoized(ctx context.Context) {{ .Name }} {
+		memo, ok := ctx.Value(memo{{ .StructName }}Key{}).(*{{ .StructName }}QueryMemo)
+		if !ok {
+			return qs
+		}
+		return qs.w(func(db *gorm.DB) *gorm.DB {
+			return db.Set("{{ .Name }}:memo", memo)
+		})
+	}
+
+	// memoize calls load to fill ret by finisher and memoizes ret if queryset is Memoized
+	func (qs {{ .Name }}) memoize(finisher string, ret interface{}, load func() error) error {
+		v, ok := qs.db.Get("{{ .Name }}:memo")
+		if !ok {
+			return load()
+		}
+
+		memo := v.(*{{ .StructName }}QueryMemo)
+		key := finisher + " " + qs.CacheKey()
+		memo.mu.Lock()
+		result, ok := memo.results[key]
+		memo.mu.Unlock()
+		if ok {
+			switch ret := ret.(type) {
+			case *[]{{ .StructName }}:
+				*ret = append([]{{ .StructName }}(nil), result.([]{{ .StructName }})...)
+			case *{{ .StructName }}:
+				*ret = result.({{ .StructName }})
+			case *int:
+				*ret = result.(int)
+			}
+			return nil
+		}
+
+		if err := load(); err != nil {
+			return err
+		}
+
+		switch ret := ret.(type) {
+		case *[]{{ .StructName }}:
+			result = append([]{{ .StructName }}(nil), (*ret)...)
+		case *{{ .StructName }}:
+			result = *ret
+		case *int:
+			result = *ret
+		}
+		memo.mu.Lock()
+		memo.results[key] = result
+		memo.mu.Unlock()
+		return nil
+	}
+
+	// {{ .StructName }}TooManyRowsError is returned by read finishers of {{ .Name }} limited
+	// by FailIfMoreThan if more rows matched
+	type {{ .StructName }}TooManyRowsError struct {
+		Max int
+	}
+
+	func (e {{ .StructName }}TooManyRowsError) Error() string {
+		return fmt.Sprintf("more than %d {{ .StructName }} rows matched", e.Max)
+	}
+
+	// FailIfMoreThan returns queryset, which read finishers return {{ .StructName }}TooManyRowsError
+	// instead of loading more than n rows, e.g. if filter was accidentally dropped. Query
+	// gets LIMIT n+1: it replaces Limit of queryset. Finishers loading fewer rows than
+	// matched (One, Iterate, CountDistinct{Field}, etc.) count matched rows by extra query.
+	func (qs {{ .Name }}) FailIfMoreThan(n int) {{ .Name }} {
+		return qs.Limit(n + 1).w(func(db *gorm.DB) *gorm.DB {
+			return db.Set("{{ .Name }}:max_rows", n)
+		})
+	}
+
+	// maxRows returns limit of FailIfMoreThan or, for finishers loading rows, of
+	// MaxRows option: number of rows isn't limited if it isn't positive
+	func (qs {{ .Name }}) maxRows(loading bool) int {
+		if v, ok := qs.db.Get("{{ .Name }}:max_rows"); ok {
+			return v.(int)
+		}
+		if loading {
+			return load{{ .StructName }}Options().MaxRows
+		}
+		return 0
+	}
+
+	// checkRowsNum returns error if num rows exceed limit of FailIfMoreThan
+	// or, if rows were loaded, of MaxRows option
+	func (qs {{ .Name }}) checkRowsNum(num int, loaded bool) error {
+		if max := qs.maxRows(loaded); max > 0 && num > max {
+			return {{ .StructName }}TooManyRowsError{Max: max}
+		}
+		return nil
+	}
+
+	// checkMatchedNum returns error if more rows match queryset than limit of
+	// FailIfMoreThan: it's checked by finishers, which don't load all matched
+	// rows, and costs count query only if limit is set
+	func (qs {{ .Name }}) checkMatchedNum() error {
+		if qs.maxRows(false) <= 0 {
+			return nil
+		}
+
+		var num int
+		err := call{{ .StructName }}Breaker(qs.db, func() error {
+			return qs.db.Limit(-1).Offset(-1).Count(&num).Error
+		})
+		if err != nil {
+			return err
+		}
+		return qs.checkRowsNum(num, false)
+	}
+
+	// {{ .StructName }}Options are runtime options of generated code of {{ .StructName }},
+	// zero values keep defaults
+	type {{ .StructName }}Options struct {
+		// MaxRows makes All and Pluck fail with {{ .StructName }}TooManyRowsError if more rows
+		// are loaded, FailIfMoreThan overrides it. Unlike FailIfMoreThan it doesn't limit query
+		// and isn't checked by finishers, which don't load all rows.
+		MaxRows int
+		{{- if .HasOption "cache" }}
+
+		// CacheTTL overrides ttl of {{ .StructName }} caches for rows cached after Configure{{ .StructName }}
+		CacheTTL time.Duration
+		{{- end }}
+		{{- if .HasOption "hedged" }}
+
+		// HedgeDelay overrides delay of hedged reads of {{ .StructName }}
+		HedgeDelay time.Duration
+		{{- end }}
+		{{- if .HasOption "querylog" }}
+
+		// SlowQuery makes query log of {{ .StructName }} record only statements lasting longer
+		SlowQuery time.Duration
+		{{- end }}
+		{{- if .HasOption "breaker" }}
+
+		// BreakerThreshold is a number of consecutive failures opening circuit of breakers
+		// made by New{{ .StructName }}Breaker, 5 by default
+		BreakerThreshold int
+
+		// BreakerCooldown is a time circuit of breakers made by New{{ .StructName }}Breaker stays
+		// open, 10 seconds by default
+		BreakerCooldown time.Duration
+		{{- end }}
+	}
+
+	var options{{ .StructName }} atomic.Value
+
+	// Configure{{ .StructName }} sets runtime options of {{ .StructName }} replacing previous ones:
+	// it's safe to call it concurrently with queries, e.g. on reload of config of service
+	func Configure{{ .StructName }}(opts {{ .StructName }}Options) {
+		options{{ .StructName }}.Store(opts)
+	}
+
+	func load{{ .StructName }}Options() {{ .StructName }}Options {
+		opts, _ := options{{ .StructName }}.Load().({{ .StructName }}Options)
+		return opts
+	}
+
+	var scopes{{ .StructName }} = struct {
+		sync.RWMutex
+		m map[string]func(qs {{ .Name }}) {{ .Name }}
+	}{
+		m: map[string]func(qs {{ .Name }}) {{ .Name }}{},
+	}
+
+	// Register{{ .StructName }}Scope registers scope of {{ .Name }} by name for Scoped,
+	// e.g. in init next to scope function. Scope registered by the same name is replaced.
+	func Register{{ .StructName }}Scope(name string, scope func(qs {{ .Name }}) {{ .Name }}) {
+		scopes{{ .StructName }}.Lock()
+		defer scopes{{ .StructName }}.Unlock()
+		scopes{{ .StructName }}.m[name] = scope
+	}
+
+	// {{ .StructName }}ScopeNames returns sorted names of registered scopes of {{ .Name }}
+	func {{ .StructName }}ScopeNames() []string {
+		scopes{{ .StructName }}.RLock()
+		defer scopes{{ .StructName }}.RUnlock()
+
+		var names []string
+		for name := range scopes{{ .StructName }}.m {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return names
+	}
+
+	// Scoped applies scopes registered by Register{{ .StructName }}Scope by names in order,
+	// e.g. names from request of admin: queries fail if scope isn't registered
+	func (qs {{ .Name }}) Scoped(names ...string) {{ .Name }} {
+		for _, name := range names {
+			scopes{{ .StructName }}.RLock()
+			scope, ok := scopes{{ .StructName }}.m[name]
+			scopes{{ .StructName }}.RUnlock()
+			if !ok {
+				return qs.w(withError(fmt.Errorf("unknown {{ .StructName }} scope %q", name)))
+			}
+			qs = scope(qs)
+		}
+		return qs
+	}
+
+	{{ if .HasOption "stats" }}
+	// {{ .StructName }}Stats is a snapshot of statistics of {{ .StructName }} rows returned by Stats
+	type {{ .StructName }}Stats struct {
+		{{- range .StatsFields }}
+		{{ .Name }} {{ .TypeName }}
+		{{- end }}
+	}
+	{{ end }}
+
+	{{ if .HasOption "locale" }}
+	// {{ .StructName }}Locale is a locale of ordering and full-text search of {{ .Name }}:
+	// Collation is a collation of ordering by string fields (e.g. de-DE-x-icu), SearchConfig
+	// is a text search configuration of full-text search (e.g. german), it's used only by
+	// postgres. Empty settings are defaults of DB.
+	type {{ .StructName }}Locale struct {
+		Collation    string
+		SearchConfig string
+	}
+
+	type locale{{ .StructName }}Key struct{}
+
+	var locale{{ .StructName }}CollationRe = regexp.MustCompile("^[\\w.@-]+$")
+
+	// With{{ .StructName }}Locale returns ctx carrying locale of {{ .Name }}
+	func With{{ .StructName }}Locale(ctx context.Context, l {{ .StructName }}Locale) context.Context {
+		return context.WithValue(ctx, locale{{ .StructName }}Key{}, l)
+	}
+
+	// Localized returns queryset, which ordering by string fields and full-text search
+	// use locale of ctx set by With{{ .StructName }}Locale: call it before them.
+	// Queryset isn't localized if ctx has no locale.
+	func (qs {{ .Name }}) Localized(ctx context.Context) {{ .Name }} {
+		l, ok := ctx.Value(locale{{ .StructName }}Key{}).({{ .StructName }}Locale)
+		if !ok {
+			return qs
+		}
+
+		if l.Collation != "" && !locale{{ .StructName }}CollationRe.MatchString(l.Collation) {
+			return qs.w(withError(fmt.Errorf("invalid collation %q of {{ .StructName }} locale", l.Collation)))
+		}
+		return qs.w(func(db *gorm.DB) *gorm.DB {
+			return db.Set("{{ .Name }}:locale", l)
+		})
+	}
+
+	func (qs {{ .Name }}) locale() {{ .StructName }}Locale {
+		v, _ := qs.db.Get("{{ .Name }}:locale")
+		l, _ := v.({{ .StructName }}Locale)
+		return l
+	}
+
+	// collate returns quoted column in collation of locale of queryset
+	func (qs {{ .Name }}) collate(column string) string {
+		if c := qs.locale().Collation; c != "" {
+			return fmt.Sprintf({{ printf "%q" .Collate }}, column, c)
+		}
+		return column
+	}
+	{{ end }}
+
 	{{ range .Methods }}
 		{{ .GetDoc .GetMethodName }}
-		func ({{ .GetReceiverDeclaration }}) {{ .GetMethodName }}({{ .GetArgsDeclaration }})
+		func {{ with .GetReceiverDeclaration }}({{ . }}) {{ end }}{{ .GetMethodName }}({{ .GetArgsDeclaration }})
 		{{- .GetReturnValuesDeclaration }} {
       {{ .GetBody }}
 		}
 	{{ end }}
 
+	// {{ .StructName }}Querier is an interface of {{ .Name }}: depend on it
+	// to mock {{ .Name }} in tests
+	type {{ .StructName }}Querier interface {
+		{{- range .QuerySetMethods }}
+			{{ .GetMethodName }}({{ .GetArgsDeclaration }}) {{ .GetReturnValuesDeclaration }}
+		{{- end }}
+	}
+
+	var _ {{ .StructName }}Querier = {{ .Name }}{}
+
   // ===== END of query set {{ .Name }}
 
+	{{ if and .PrimaryKey .SearchBackedFields }}
+	// {{ .StructName }}SearchClient is a client of external search engine (Elasticsearch,
+	// Meilisearch etc): it returns primary keys of {{ .StructName }} records with field matching query
+	type {{ .StructName }}SearchClient interface {
+		SearchIDs(field, query string) ([]{{ .PrimaryKey.TypeName }}, error)
+	}
+	{{ end }}
+
+	{{ if .PrimaryKey }}
+	// {{ .StructName }}Limiter limits rate of batch mutations of {{ .StructName }}:
+	// *rate.Limiter from golang.org/x/time/rate satisfies it
+	type {{ .StructName }}Limiter interface {
+		Wait(ctx context.Context) error
+	}
+
+	// {{ .StructName }}Throttled runs batch mutations of {{ .StructName }} records waiting
+	// for limiter before every batch
+	type {{ .StructName }}Throttled struct {
+		ctx context.Context
+		qs {{ .Name }}
+		limiter {{ .StructName }}Limiter
+		progress []{{ .StructName }}ProgressFunc
+	}
+	{{ end }}
+
+	// {{ .StructName }}BatchProgress is a progress of batch operation on {{ .StructName }} records
+	type {{ .StructName }}BatchProgress struct {
+		Processed int           // number of processed records
+		Total     int           // total number of records: it's 0 if unknown
+		Elapsed   time.Duration // time since start of operation
+		ETA       time.Duration // estimated time to finish: it's 0 if unknown
+	}
+
+	// {{ .StructName }}ProgressFunc is called after every batch of batch operation
+	type {{ .StructName }}ProgressFunc func(p {{ .StructName }}BatchProgress)
+
+	func report{{ .StructName }}BatchProgress(fns []{{ .StructName }}ProgressFunc, processed, total int, started time.Time) {
+		if len(fns) == 0 {
+			return
+		}
+
+		p := {{ .StructName }}BatchProgress{
+			Processed: processed,
+			Total:     total,
+			Elapsed:   time.Since(started),
+		}
+		if processed != 0 && total > processed {
+			p.ETA = time.Duration(float64(p.Elapsed) / float64(processed) * float64(total-processed))
+		}
+
+		for _, fn := range fns {
+			fn(p)
+		}
+	}
+
 	// ===== BEGIN of {{ .StructName }} modifiers
 
-	{{ $ft := printf "%s%s" .StructName "DBSchemaField" | lcf }}
+	{{ $ft := printf "%s%s" .StructName "DBSchemaField" }}
+	// {{ $ft }} is a name of {{ .StructName }} field in DB
 	type {{ $ft }} string
 
 	func (f {{ $ft }}) String() string {
@@ -66,8 +859,54 @@ const qsCode = `
 		{{- end }}
 	}
 
+	{{ if not (or .ModelPkg (.HasOption "readonly")) }}
+	{{- if .HasOption "notify" }}
+	// Update updates {{ .StructName }} fields by primary key and notifies
+	// {{ .StructName }}NotifyChannel about it in the same transaction
+	{{- else if .HasOption "isolation" }}
+	// Update updates {{ .StructName }} fields by primary key in transaction
+	// with {{ .StructName }}IsolationLevel unless db is already a transaction
+	{{- else }}
 	// Update updates {{ .StructName }} fields by primary key
+	{{- end }}
+	{{- if .HasOption "strict" }}
+	// ErrNoRowsAffected is returned if no rows were updated.
+	{{- end }}
 	func (o *{{ .StructName }}) Update(db *gorm.DB, fields ...{{ $ft }}) error {
+		{{- if .HasOption "strict" }}
+		n, err := o.UpdateNum(db, fields...)
+		if err == nil && n == 0 {
+			return ErrNoRowsAffected
+		}
+		{{- else }}
+		_, err := o.UpdateNum(db, fields...)
+		{{- end }}
+		return err
+	}
+
+	// UpdateNum is Update returning number of updated rows
+	func (o *{{ .StructName }}) UpdateNum(db *gorm.DB, fields ...{{ $ft }}) (int64, error) {
+		{{- if .HasChecks }}
+		if err := o.validate(fields...); err != nil {
+			return 0, err
+		}
+		{{ end }}
+	{{- if or (.HasOption "notify") (.HasOption "isolation") }}
+		var n int64
+		{{- if .HasOption "notify" }}
+		err := o.notify(db, "update", func(tx *gorm.DB) error {
+		{{- else }}
+		err := o.inTx(db, func(tx *gorm.DB) error {
+		{{- end }}
+			var err error
+			n, err = o.update(tx, fields...)
+			return err
+		})
+		return n, err
+	}
+
+	func (o *{{ .StructName }}) update(db *gorm.DB, fields ...{{ $ft }}) (int64, error) {
+	{{ end -}}
 		dbNameToFieldName := map[string]interface{}{
 			{{- range .Fields }}
 				"{{ .DBName }}": o.{{ .Name }},
@@ -78,19 +917,74 @@ const qsCode = `
 			fs := f.String()
 			u[fs] = dbNameToFieldName[fs]
 		}
-		if err := db.Model(o).Updates(u).Error; err != nil {
+		{{- if .Version }}
+
+		// GORM sets updated fields of o: version is restored if update fails
+		version := o.{{ .Version.Name }}
+		u["{{ .Version.DBName }}"] = version + 1
+		res := db.Model(o).Set(sortedUpdateName, true).Where("{{ .VersionCond }}", version).Updates(u)
+		if err := res.Error; err != nil {
+			o.{{ .Version.Name }} = version
 			if err == gorm.ErrRecordNotFound {
-				return err
+				return 0, err
 			}
 
-			return fmt.Errorf("can't update {{ .StructName }} %v fields %v: %s",
+			return 0, fmt.Errorf("can't update {{ .StructName }} %v fields %v: %s",
 				o, fields, err)
 		}
+		if res.RowsAffected == 0 {
+			o.{{ .Version.Name }} = version
+			return 0, ErrStaleObject
+		}
+		{{- else }}
+		res := db.Model(o).Set(sortedUpdateName, true).Updates(u)
+		if err := res.Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return 0, err
+			}
 
-		return nil
-	}
+			return 0, fmt.Errorf("can't update {{ .StructName }} %v fields %v: %s",
+				o, fields, err)
+		}
+		{{- end }}
 
-	// {{ .StructName }}Updater is an {{ .StructName }} updates manager
+		return res.RowsAffected, nil
+	}
+	{{- if .PrimaryKey }}
+	{{ $schema := printf "%s%s" .StructName "DBSchema" }}
+	// Save creates {{ .StructName }} by Create if its primary key is zero, otherwise it updates
+	// all fields of {{ .StructName }} by primary key by Update
+	{{- if .Version }}: they are updated only if its {{ .Version.Name }}
+	// wasn't changed since it was loaded, ErrStaleObject is returned otherwise.
+	// {{ .Version.Name }} is incremented.
+	{{- end }}
+	func (o *{{ .StructName }}) Save(db *gorm.DB) error {
+		var zero {{ .PrimaryKey.TypeName }}
+		if o.{{ .PrimaryKey.Name }} == zero {
+			return o.Create(db)
+		}
+		return o.Update(db
+			{{- range .Fields }}{{ if not .IsPrimaryKey }}, {{ $schema }}.{{ .Name }}{{ end }}{{ end }})
+	}
+	{{- end }}
+	{{- if .Sequence }}
+
+	// nextID sets primary key of {{ .StructName }} to next value of its sequence if it's zero:
+	// it's called before inserts
+	func (o *{{ .StructName }}) nextID(db *gorm.DB) error {
+		if o.{{ .PrimaryKey.Name }} != 0 {
+			return nil
+		}
+		if err := db.New().Raw({{ printf "%q" .Sequence }}).Row().Scan(&o.{{ .PrimaryKey.Name }}); err != nil {
+			return fmt.Errorf("can't get next value of sequence of {{ .StructName }}: %s", err)
+		}
+		return nil
+	}
+	{{- end }}
+	{{- end }}
+
+	{{ if not (.HasOption "readonly") }}
+	// {{ .StructName }}Updater is an {{ .StructName }} updates manager
 	type {{ .StructName }}Updater struct {
 		fields map[string]interface{}
 		db *gorm.DB
@@ -100,12 +994,2034 @@ const qsCode = `
 	func New{{ .StructName }}Updater(db *gorm.DB) {{ .StructName }}Updater {
 		return {{ .StructName }}Updater{
 			fields: map[string]interface{}{},
-			db: db.Model(&{{ .StructName }}{}),
+			db: db.Model(&{{ .StructName }}{}).Set(sortedUpdateName, true),
 		}
 	}
+	{{ end }}
+
+	{{ if .HasChecks }}
+	// {{ .StructName }}CheckError is a violation of check constraint of {{ .StructName }} field
+	type {{ .StructName }}CheckError struct {
+		Field {{ $ft }}
+		Check string // violated condition of check constraint
+	}
+
+	func (e {{ .StructName }}CheckError) Error() string {
+		return fmt.Sprintf("{{ .StructName }} field %s violates check %q", e.Field, e.Check)
+	}
+
+	{{- if not .ModelPkg }}
+
+	// Validate checks constraints of all {{ .StructName }} fields: it returns
+	// {{ .StructName }}CheckError on the first violation. Create and Update call it.
+	func (o *{{ .StructName }}) Validate() error {
+		return o.validate()
+	}
+	{{- end }}
+	{{ end }}
 
 	// ===== END of {{ .StructName }} modifiers
-{{ end }}
 
-// ===== END of all query sets
+	{{ if .HasOption "fake" }}
+	{{ $fqs := printf "Fake%s" .Name }}
+	// ===== BEGIN of {{ .StructName }} fake queryset
+
+	// {{ $fqs }} is an in-memory fake of {{ .Name }} for unit tests. It supports
+	// the same filters, ordering, limit and offset, but relations aren't preloaded.
+	type {{ $fqs }} struct {
+		rows    *[]{{ .StructName }}
+		filters []func(o *{{ .StructName }}) bool
+		orders  []func(a, b *{{ .StructName }}) int
+		limit   int
+		offset  int
+		maxRows int // limit of FailIfMoreThan, it's -1 if there is no limit
+		unscoped bool
+	}
+
+	{{- if .HasOption "readonly" }}
+	// New{{ $fqs }} creates fake queryset over rows
+	{{- else if .IsSoftDeleted }}
+	// New{{ $fqs }} creates fake queryset over rows: Delete sets DeletedAt of records
+	// like soft delete of GORM, records are removed from rows only by Delete of
+	// queryset with WithDeleted
+	{{- else }}
+	// New{{ $fqs }} creates fake queryset over rows: Delete removes records from rows
+	{{- end }}
+	func New{{ $fqs }}(rows *[]{{ .StructName }}) {{ $fqs }} {
+		return {{ $fqs }}{
+			rows:    rows,
+			limit:   -1,
+			maxRows: -1,
+		}
+	}
+
+	func (qs {{ $fqs }}) filter(fn func(o *{{ .StructName }}) bool) {{ $fqs }} {
+		qs.filters = append(qs.filters[:len(qs.filters):len(qs.filters)], fn)
+		return qs
+	}
+
+	func (qs {{ $fqs }}) order(fn func(a, b *{{ .StructName }}) int) {{ $fqs }} {
+		qs.orders = append(qs.orders[:len(qs.orders):len(qs.orders)], fn)
+		return qs
+	}
+
+	func (qs {{ $fqs }}) matches(o *{{ .StructName }}) bool {
+		{{- if .IsSoftDeleted }}
+		if !qs.unscoped && o.DeletedAt != nil {
+			return false
+		}
+		{{- end }}
+		return qs.matchesFilters(o)
+	}
+
+	func (qs {{ $fqs }}) matchesFilters(o *{{ .StructName }}) bool {
+		for _, fn := range qs.filters {
+			if !fn(o) {
+				return false
+			}
+		}
+		return true
+	}
+
+	// Or is a fake of {{ .Name }}.Or
+	func (qs {{ $fqs }}) Or(branches ...func(qs {{ $fqs }}) {{ $fqs }}) {{ $fqs }} {
+		if len(branches) == 0 {
+			return qs
+		}
+
+		return qs.filter(func(o *{{ .StructName }}) bool {
+			for _, branch := range branches {
+				if branch({{ $fqs }}{}).matchesFilters(o) {
+					return true
+				}
+			}
+			return false
+		})
+	}
+
+	// Not is a fake of {{ .Name }}.Not
+	func (qs {{ $fqs }}) Not(branch func(qs {{ $fqs }}) {{ $fqs }}) {{ $fqs }} {
+		return qs.filter(func(o *{{ .StructName }}) bool {
+			return !branch({{ $fqs }}{}).matchesFilters(o)
+		})
+	}
+
+	func (qs {{ $fqs }}) less(a, b *{{ .StructName }}) bool {
+		for _, fn := range qs.orders {
+			if c := fn(a, b); c != 0 {
+				return c < 0
+			}
+		}
+		return false
+	}
+
+	// indexes returns indexes of matched rows in order of queryset
+	func (qs {{ $fqs }}) indexes() []int {
+		rows := *qs.rows
+		var ret []int
+		for i := range rows {
+			if !qs.matches(&rows[i]) {
+				continue
+			}
+
+			// stable insertion sort: fakes are for small data sets
+			j := len(ret)
+			ret = append(ret, i)
+			for ; j > 0 && qs.less(&rows[i], &rows[ret[j-1]]); j-- {
+				ret[j] = ret[j-1]
+			}
+			ret[j] = i
+		}
+
+		if qs.offset >= len(ret) {
+			return nil
+		}
+		ret = ret[qs.offset:]
+		if qs.limit >= 0 && qs.limit < len(ret) {
+			ret = ret[:qs.limit]
+		}
+		return ret
+	}
+
+	// Limit is a fake of {{ .Name }}.Limit
+	func (qs {{ $fqs }}) Limit(limit int) {{ $fqs }} {
+		qs.limit = limit
+		return qs
+	}
+
+	// Offset is a fake of {{ .Name }}.Offset
+	func (qs {{ $fqs }}) Offset(offset int) {{ $fqs }} {
+		qs.offset = offset
+		return qs
+	}
+
+	// Clone is a fake of {{ .Name }}.Clone
+	func (qs {{ $fqs }}) Clone() {{ $fqs }} {
+		return qs
+	}
+
+	// FailIfMoreThan is a fake of {{ .Name }}.FailIfMoreThan
+	func (qs {{ $fqs }}) FailIfMoreThan(n int) {{ $fqs }} {
+		qs.limit, qs.maxRows = n+1, n
+		return qs
+	}
+
+	func (qs {{ $fqs }}) checkRowsNum(num int, loaded bool) error {
+		max := qs.maxRows
+		if max < 0 && loaded {
+			max = load{{ .StructName }}Options().MaxRows
+		}
+		if max <= 0 || num <= max {
+			return nil
+		}
+		return {{ .StructName }}TooManyRowsError{Max: max}
+	}
+
+	func (qs {{ $fqs }}) checkMatchedNum() error {
+		if qs.maxRows <= 0 {
+			return nil
+		}
+		qs.limit, qs.offset = -1, 0
+		return qs.checkRowsNum(len(qs.indexes()), false)
+	}
+
+	// All is a fake of {{ .Name }}.All
+	func (qs {{ $fqs }}) All(ret *[]{{ .StructName }}) error {
+		*ret = nil
+		indexes := qs.indexes()
+		if err := qs.checkRowsNum(len(indexes), true); err != nil {
+			return err
+		}
+		for _, i := range indexes {
+			*ret = append(*ret, (*qs.rows)[i])
+		}
+		return nil
+	}
+
+	// Iterate is a fake of {{ .Name }}.Iterate
+	func (qs {{ $fqs }}) Iterate(fn func(o {{ .StructName }}) error) error {
+		if err := qs.checkMatchedNum(); err != nil {
+			return err
+		}
+		for _, i := range qs.indexes() {
+			if err := fn((*qs.rows)[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	{{ if and .PrimaryKey .PrimaryKey.IsNumeric }}
+	// AllInBatches is a fake of {{ .Name }}.AllInBatches
+	func (qs {{ $fqs }}) AllInBatches(batchSize int, fn func(batch []{{ .StructName }}) error) error {
+		if batchSize < 1 {
+			return fmt.Errorf("invalid batch size %d", batchSize)
+		}
+		if err := qs.checkMatchedNum(); err != nil {
+			return err
+		}
+
+		qs.orders, qs.limit, qs.offset, qs.maxRows = nil, -1, 0, 0
+		var rows []{{ .StructName }}
+		if err := qs.OrderAscBy{{ .PrimaryKey.Name }}().All(&rows); err != nil {
+			return err
+		}
+
+		for len(rows) != 0 {
+			n := batchSize
+			if n > len(rows) {
+				n = len(rows)
+			}
+			if err := fn(rows[:n:n]); err != nil {
+				return err
+			}
+			rows = rows[n:]
+		}
+		return nil
+	}
+	{{ end }}
+
+	// One is a fake of {{ .Name }}.One
+	func (qs {{ $fqs }}) One(ret *{{ .StructName }}) error {
+		if err := qs.checkMatchedNum(); err != nil {
+			return err
+		}
+
+		indexes := qs.Limit(1).indexes()
+		if len(indexes) == 0 {
+			return {{ .NotFoundError }}
+		}
+
+		*ret = (*qs.rows)[indexes[0]]
+		return nil
+	}
+
+	// ExactlyOne is a fake of {{ .Name }}.ExactlyOne
+	func (qs {{ $fqs }}) ExactlyOne(ret *{{ .StructName }}) error {
+		if err := qs.checkMatchedNum(); err != nil {
+			return err
+		}
+
+		indexes := qs.Limit(2).indexes()
+		switch len(indexes) {
+		case 0:
+			return {{ .NotFoundError }}
+		case 1:
+			*ret = (*qs.rows)[indexes[0]]
+			return nil
+		}
+		return ErrMultipleRecords
+	}
+
+	// First is a fake of {{ .Name }}.First
+	func (qs {{ $fqs }}) First() ({{ .StructName }}, error) {
+		var ret {{ .StructName }}
+		err := qs.One(&ret)
+		return ret, err
+	}
+
+	// Last is a fake of {{ .Name }}.Last
+	func (qs {{ $fqs }}) Last() ({{ .StructName }}, error) {
+		if err := qs.checkMatchedNum(); err != nil {
+			return {{ .StructName }}{}, err
+		}
+
+		indexes := qs.indexes()
+		if len(indexes) == 0 {
+			return {{ .StructName }}{}, {{ .NotFoundError }}
+		}
+
+		return (*qs.rows)[indexes[len(indexes)-1]], nil
+	}
+
+	// Count is a fake of {{ .Name }}.Count
+	func (qs {{ $fqs }}) Count() (int, error) {
+		count := len(qs.indexes())
+		if err := qs.checkRowsNum(count, false); err != nil {
+			return 0, err
+		}
+		return count, nil
+	}
+
+	{{ if not (.HasOption "readonly") }}
+	// Delete is a fake of {{ .Name }}.Delete
+	func (qs {{ $fqs }}) Delete() error {
+		{{- if .HasOption "strict" }}
+		n, err := qs.DeleteNum()
+		if err == nil && n == 0 {
+			return ErrNoRowsAffected
+		}
+		{{- else }}
+		_, err := qs.DeleteNum()
+		{{- end }}
+		return err
+	}
+
+	// DeleteNum is a fake of {{ .Name }}.DeleteNum
+	func (qs {{ $fqs }}) DeleteNum() (int64, error) {
+		{{- if .IsSoftDeleted }}
+		if !qs.unscoped {
+			return qs.SoftDeleteNum()
+		}
+		{{ end }}
+		deleted := map[int]bool{}
+		for _, i := range qs.indexes() {
+			deleted[i] = true
+		}
+
+		var rows []{{ .StructName }}
+		for i := range *qs.rows {
+			if !deleted[i] {
+				rows = append(rows, (*qs.rows)[i])
+			}
+		}
+		*qs.rows = rows
+		return int64(len(deleted)), nil
+	}
+	{{ end }}
+
+	{{ if .IsSoftDeleted }}
+	// WithDeleted is a fake of {{ .Name }}.WithDeleted
+	func (qs {{ $fqs }}) WithDeleted() {{ $fqs }} {
+		qs.unscoped = true
+		return qs
+	}
+
+	// DeletedOnly is a fake of {{ .Name }}.DeletedOnly
+	func (qs {{ $fqs }}) DeletedOnly() {{ $fqs }} {
+		qs.unscoped = true
+		return qs.filter(func(o *{{ .StructName }}) bool {
+			return o.DeletedAt != nil
+		})
+	}
+
+	{{ if not (.HasOption "readonly") }}
+	// SoftDelete is a fake of {{ .Name }}.SoftDelete
+	func (qs {{ $fqs }}) SoftDelete() error {
+		{{- if .HasOption "strict" }}
+		n, err := qs.SoftDeleteNum()
+		if err == nil && n == 0 {
+			return ErrNoRowsAffected
+		}
+		{{- else }}
+		_, err := qs.SoftDeleteNum()
+		{{- end }}
+		return err
+	}
+
+	// SoftDeleteNum is a fake of {{ .Name }}.SoftDeleteNum
+	func (qs {{ $fqs }}) SoftDeleteNum() (int64, error) {
+		now := time.Now()
+		indexes := qs.indexes()
+		for _, i := range indexes {
+			(*qs.rows)[i].DeletedAt = &now
+		}
+		return int64(len(indexes)), nil
+	}
+	{{ end }}
+	{{ end }}
+
+	// fake{{ .StructName }}Like matches s with SQL LIKE pattern: % matches
+	// any string and _ matches any character
+	func fake{{ .StructName }}Like(s, pattern string, fold bool) bool {
+		if fold {
+			s, pattern = strings.ToLower(s), strings.ToLower(pattern)
+		}
+
+		sr, pr := []rune(s), []rune(pattern)
+		// matched[j] is true if sr[:i] matches pr[:j]
+		matched := make([]bool, len(pr)+1)
+		matched[0] = true
+		for j := 1; j <= len(pr) && pr[j-1] == '%'; j++ {
+			matched[j] = true
+		}
+		for i := 1; i <= len(sr); i++ {
+			prev := matched[0]
+			matched[0] = false
+			for j := 1; j <= len(pr); j++ {
+				cur := matched[j]
+				switch pr[j-1] {
+				case '%':
+					matched[j] = matched[j-1] || cur
+				case '_':
+					matched[j] = prev
+				default:
+					matched[j] = prev && sr[i-1] == pr[j-1]
+				}
+				prev = cur
+			}
+		}
+		return matched[len(pr)]
+	}
+
+	// {{ .StructName }}Finisher is a part of {{ .StructName }}Querier implemented by
+	// {{ $fqs }} too: code loading rows can depend on it and be tested with fake
+	type {{ .StructName }}Finisher interface {
+		{{- range .FakeFinisherMethods }}
+			{{ .GetMethodName }}({{ .GetArgsDeclaration }}) {{ .GetReturnValuesDeclaration }}
+		{{- end }}
+	}
+
+	var (
+		_ {{ .StructName }}Finisher = {{ .Name }}{}
+		_ {{ .StructName }}Finisher = {{ $fqs }}{}
+	)
+
+	// ===== END of {{ .StructName }} fake queryset
+	{{ end }}
+
+	{{ if .HasOption "breaker" }}
+	// ===== BEGIN of {{ .StructName }} circuit breaker
+
+	// {{ .StructName }}Breaker is a circuit breaker of DB calls of {{ .StructName }}, e.g. adapter of
+	// sony/gobreaker: it stops calls while DB is browning out instead of piling them up
+	type {{ .StructName }}Breaker interface {
+		// Allow returns error if circuit is open: call isn't made and fails with this error
+		Allow() error
+		// Success and Failure record result of allowed call
+		Success()
+		Failure(err error)
+	}
+
+	// Register{{ .StructName }}Breaker passes DB calls of {{ .StructName }} through breaker b: statements
+	// of {{ .StructName }} table fail with error of b.Allow without touching db while circuit
+	// is open, gorm.ErrRecordNotFound isn't a failure. Register it once per db before
+	// constructing querysets: callbacks are registered in callbacks of db, which GORM
+	// clones per db opened by gorm.Open, so other dbs aren't affected. Row queries
+	// (Count, Pluck etc) and raw statements (upserts, batch inserts) don't run GORM
+	// callbacks, they find b in settings of db.
+	func Register{{ .StructName }}Breaker(db *gorm.DB, b {{ .StructName }}Breaker) {
+		db.InstantSet("queryset:{{ .StructName }}:breaker", b)
+		table := db.NewScope(&{{ .StructName }}{}).TableName()
+		allow := func(scope *gorm.Scope) {
+			if scope.HasError() || scope.TableName() != table {
+				return
+			}
+
+			if err := b.Allow(); err != nil {
+				scope.Err(err)
+				return
+			}
+			scope.InstanceSet("queryset:{{ .StructName }}:allowed", true)
+		}
+		record := func(scope *gorm.Scope) {
+			if _, ok := scope.InstanceGet("queryset:{{ .StructName }}:allowed"); ok {
+				record{{ .StructName }}BreakerResult(b, scope.DB().Error)
+			}
+		}
+
+		const allowName, recordName = "queryset:{{ .StructName }}_breaker_allow", "queryset:{{ .StructName }}_breaker_record"
+		db.Callback().Create().Before("gorm:begin_transaction").Register(allowName, allow)
+		db.Callback().Create().After("gorm:commit_or_rollback_transaction").Register(recordName, record)
+		db.Callback().Update().Before("gorm:begin_transaction").Register(allowName, allow)
+		db.Callback().Update().After("gorm:commit_or_rollback_transaction").Register(recordName, record)
+		db.Callback().Delete().Before("gorm:begin_transaction").Register(allowName, allow)
+		db.Callback().Delete().After("gorm:commit_or_rollback_transaction").Register(recordName, record)
+		db.Callback().Query().Before("gorm:query").Register(allowName, allow)
+		db.Callback().Query().After("gorm:after_query").Register(recordName, record)
+	}
+
+	// Err{{ .StructName }}CircuitOpen is returned by breakers made by New{{ .StructName }}Breaker
+	// while their circuit is open
+	var Err{{ .StructName }}CircuitOpen = errors.New("circuit breaker of {{ .StructName }} is open")
+
+	// default{{ .StructName }}Breaker opens circuit after consecutive failures
+	type default{{ .StructName }}Breaker struct {
+		mu        sync.Mutex
+		failures  int
+		openUntil time.Time
+	}
+
+	// New{{ .StructName }}Breaker returns breaker opening circuit after BreakerThreshold consecutive
+	// failures for BreakerCooldown: they are options set by Configure{{ .StructName }} and they
+	// are read on every failure, so they can be tuned at runtime
+	func New{{ .StructName }}Breaker() {{ .StructName }}Breaker {
+		return &default{{ .StructName }}Breaker{}
+	}
+
+	func (b *default{{ .StructName }}Breaker) Allow() error {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if time.Now().Before(b.openUntil) {
+			return Err{{ .StructName }}CircuitOpen
+		}
+		return nil
+	}
+
+	func (b *default{{ .StructName }}Breaker) Success() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		b.failures = 0
+	}
+
+	func (b *default{{ .StructName }}Breaker) Failure(err error) {
+		opts := load{{ .StructName }}Options()
+		threshold, cooldown := opts.BreakerThreshold, opts.BreakerCooldown
+		if threshold <= 0 {
+			threshold = 5
+		}
+		if cooldown <= 0 {
+			cooldown = 10 * time.Second
+		}
+
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if b.failures++; b.failures >= threshold {
+			b.failures = 0
+			b.openUntil = time.Now().Add(cooldown)
+		}
+	}
+
+	func record{{ .StructName }}BreakerResult(b {{ .StructName }}Breaker, err error) {
+		if err == nil || err == gorm.ErrRecordNotFound {
+			b.Success()
+		} else {
+			b.Failure(err)
+		}
+	}
+
+	// call{{ .StructName }}Breaker makes call, which doesn't run GORM callbacks, through breaker
+	// registered in db by Register{{ .StructName }}Breaker
+	func call{{ .StructName }}Breaker(db *gorm.DB, call func() error) error {
+		v, ok := db.Get("queryset:{{ .StructName }}:breaker")
+		if !ok {
+			return call()
+		}
+
+		b := v.({{ .StructName }}Breaker)
+		if err := b.Allow(); err != nil {
+			return err
+		}
+
+		err := call()
+		record{{ .StructName }}BreakerResult(b, err)
+		return err
+	}
+
+	// ===== END of {{ .StructName }} circuit breaker
+	{{ else }}
+	// call{{ .StructName }}Breaker makes call: {{ .StructName }} has no breaker option
+	func call{{ .StructName }}Breaker(db *gorm.DB, call func() error) error {
+		return call()
+	}
+	{{ end }}
+
+	{{ if .HasOption "querylog" }}
+	// ===== BEGIN of {{ .StructName }} query log
+
+	// {{ .StructName }}QueryLogRecord is a record of statement of {{ .StructName }} table written
+	// by Register{{ .StructName }}QueryLog. Vars of statement aren't recorded: SQL has bind vars.
+	type {{ .StructName }}QueryLogRecord struct {
+		Model    string        ` + "`" + `json:"model"` + "`" + `
+		Chain    []string      ` + "`" + `json:"chain,omitempty"` + "`" + ` // methods of queryset called before statement
+		SQL      string        ` + "`" + `json:"sql"` + "`" + `
+		Duration time.Duration ` + "`" + `json:"duration"` + "`" + ` // in nanoseconds
+		Rows     int64         ` + "`" + `json:"rows"` + "`" + ` // selected or affected rows
+		Error    string        ` + "`" + `json:"error,omitempty"` + "`" + `
+	}
+
+	// Register{{ .StructName }}QueryLog appends record of every statement of {{ .StructName }} table
+	// to w as a line of JSON (NDJSON) for offline analysis of real queries. Register it once
+	// per db before constructing querysets. Row queries (Count, Pluck etc) and raw statements
+	// (upserts, batch inserts) don't run GORM callbacks, so they aren't recorded. Errors
+	// of writing to w are ignored.
+	func Register{{ .StructName }}QueryLog(db *gorm.DB, w io.Writer) {
+		db.InstantSet("queryset:{{ .StructName }}:querylog", true)
+		table := db.NewScope(&{{ .StructName }}{}).TableName()
+		start := func(scope *gorm.Scope) {
+			if scope.TableName() == table {
+				scope.InstanceSet("queryset:{{ .StructName }}:querylog_start", time.Now())
+			}
+		}
+
+		var mu sync.Mutex
+		write := func(scope *gorm.Scope) {
+			v, ok := scope.InstanceGet("queryset:{{ .StructName }}:querylog_start")
+			if !ok {
+				return
+			}
+
+			r := {{ .StructName }}QueryLogRecord{
+				Model:    "{{ .StructName }}",
+				SQL:      scope.SQL,
+				Duration: time.Since(v.(time.Time)),
+				Rows:     scope.DB().RowsAffected,
+			}
+			if r.Duration < load{{ .StructName }}Options().SlowQuery {
+				return
+			}
+			if chain, ok := scope.Get("queryset:{{ .StructName }}:chain"); ok {
+				r.Chain = chain.([]string)
+			}
+			if err := scope.DB().Error; err != nil {
+				r.Error = err.Error()
+			}
+
+			line, err := json.Marshal(r)
+			if err != nil {
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			w.Write(append(line, '\n'))
+		}
+
+		const startName, writeName = "queryset:{{ .StructName }}_querylog_start", "queryset:{{ .StructName }}_querylog_write"
+		db.Callback().Create().Before("gorm:begin_transaction").Register(startName, start)
+		db.Callback().Create().After("gorm:commit_or_rollback_transaction").Register(writeName, write)
+		db.Callback().Update().Before("gorm:begin_transaction").Register(startName, start)
+		db.Callback().Update().After("gorm:commit_or_rollback_transaction").Register(writeName, write)
+		db.Callback().Delete().Before("gorm:begin_transaction").Register(startName, start)
+		db.Callback().Delete().After("gorm:commit_or_rollback_transaction").Register(writeName, write)
+		db.Callback().Query().Before("gorm:query").Register(startName, start)
+		db.Callback().Query().After("gorm:after_query").Register(writeName, write)
+	}
+
+	// append{{ .StructName }}QueryLogChain returns chain of queryset methods of db with method
+	func append{{ .StructName }}QueryLogChain(db *gorm.DB, method string) []string {
+		var chain []string
+		if v, ok := db.Get("queryset:{{ .StructName }}:chain"); ok {
+			chain = append(chain, v.([]string)...)
+		}
+		if method == "" {
+			return chain
+		}
+		return append(chain, method)
+	}
+
+	// {{ .StructName }}QueryLogMethod returns name of queryset method, which called w
+	func {{ .StructName }}QueryLogMethod() string {
+		pcs := make([]uintptr, 1)
+		if runtime.Callers(3, pcs) == 0 {
+			return ""
+		}
+		frame, _ := runtime.CallersFrames(pcs).Next()
+		parts := strings.Split(frame.Function, ".")
+		i := len(parts) - 1
+		for i > 0 && strings.HasPrefix(parts[i], "func") { // closure in method
+			i--
+		}
+		return parts[i]
+	}
+
+	// ===== END of {{ .StructName }} query log
+	{{ end }}
+
+	{{ if .HasOption "cache" }}
+	{{ $pk := .PrimaryKey }}
+	// ===== BEGIN of {{ .StructName }} cache
+
+	// {{ .StructName }}CacheStore is a key-value storage for {{ .StructName }}Cache, e.g. Redis client wrapper
+	type {{ .StructName }}CacheStore interface {
+		// Get returns value by key and false if there is no such key
+		Get(key string) ([]byte, bool, error)
+		Set(key string, value []byte, ttl time.Duration) error
+		Delete(key string) error
+	}
+
+	// {{ .StructName }}Cache caches {{ .StructName }} rows by primary key
+	type {{ .StructName }}Cache struct {
+		store {{ .StructName }}CacheStore
+		ttl time.Duration
+		{{- if .Tenant }}
+		tenantID {{ .Tenant.TypeName }}
+		{{- end }}
+	}
+
+	// New{{ .StructName }}Cache creates new {{ .StructName }} cache, rows are cached for ttl
+	{{- if .Tenant }}: only
+	// rows of tenant tenantID are cached and fetched{{ end }}
+	func New{{ .StructName }}Cache(store {{ .StructName }}CacheStore, ttl time.Duration
+		{{- if .Tenant }}, tenantID {{ .Tenant.TypeName }}{{ end }}) {{ .StructName }}Cache {
+		return {{ .StructName }}Cache{
+			store: store,
+			ttl: ttl,
+			{{- if .Tenant }}
+			tenantID: tenantID,
+			{{- end }}
+		}
+	}
+
+	func (c {{ .StructName }}Cache) key(pk {{ $pk.TypeName }}) string {
+		{{- if .Tenant }}
+		return fmt.Sprintf("{{ .StructName }}:%v:%v", c.tenantID, pk)
+		{{- else }}
+		return fmt.Sprintf("{{ .StructName }}:%v", pk)
+		{{- end }}
+	}
+
+	{{- if .Tenant }}
+
+	// checkTenant returns error if o isn't a row of tenant of cache
+	func (c {{ .StructName }}Cache) checkTenant(o *{{ .StructName }}) error {
+		if o.{{ .Tenant.Name }} != c.tenantID {
+			return fmt.Errorf("{{ .StructName }} %v isn't a row of tenant %v of cache", o.{{ $pk.Name }}, c.tenantID)
+		}
+		return nil
+	}
+	{{- end }}
+
+	// Get returns cached {{ .StructName }} by primary key and false if it isn't cached
+	func (c {{ .StructName }}Cache) Get(pk {{ $pk.TypeName }}) (*{{ .StructName }}, bool, error) {
+		data, ok, err := c.store.Get(c.key(pk))
+		if err != nil || !ok {
+			return nil, false, err
+		}
+
+		var o {{ .StructName }}
+		if err = json.Unmarshal(data, &o); err != nil {
+			return nil, false, fmt.Errorf("can't unmarshal cached {{ .StructName }} %v: %s", pk, err)
+		}
+
+		return &o, true, nil
+	}
+
+	// Set caches {{ .StructName }} by it's primary key
+	func (c {{ .StructName }}Cache) Set(o *{{ .StructName }}) error {
+		{{- if .Tenant }}
+		if err := c.checkTenant(o); err != nil {
+			return err
+		}
+		{{- end }}
+		data, err := json.Marshal(o)
+		if err != nil {
+			return fmt.Errorf("can't marshal {{ .StructName }} %v: %s", o.{{ $pk.Name }}, err)
+		}
+
+		ttl := c.ttl
+		if opts := load{{ .StructName }}Options(); opts.CacheTTL != 0 {
+			ttl = opts.CacheTTL
+		}
+		return c.store.Set(c.key(o.{{ $pk.Name }}), data, ttl)
+	}
+
+	// Invalidate removes cached {{ .StructName }} by primary key
+	func (c {{ .StructName }}Cache) Invalidate(pk {{ $pk.TypeName }}) error {
+		return c.store.Delete(c.key(pk))
+	}
+
+	// Fetch returns cached {{ .StructName }} by primary key or loads it from db and caches it
+	func (c {{ .StructName }}Cache) Fetch(db *gorm.DB, pk {{ $pk.TypeName }}) (*{{ .StructName }}, error) {
+		if o, ok, err := c.Get(pk); err != nil || ok {
+			return o, err
+		}
+
+		var o {{ .StructName }}
+		if err := {{ .Constructor }}(db{{ if .Tenant }}, c.tenantID{{ end }}).{{ .FilterName $pk.Name "Eq" }}(pk).One(&o); err != nil {
+			return nil, err
+		}
+
+		return &o, c.Set(&o)
+	}
+
+	// Update updates {{ .StructName }} fields by primary key and invalidates it's cache
+	func (c {{ .StructName }}Cache) Update(db *gorm.DB, o *{{ .StructName }}, fields ...{{ $ft }}) error {
+		{{- if .Tenant }}
+		if err := c.checkTenant(o); err != nil {
+			return err
+		}
+		{{- end }}
+		if err := o.Update(db, fields...); err != nil {
+			return err
+		}
+
+		return c.Invalidate(o.{{ $pk.Name }})
+	}
+
+	// Delete deletes {{ .StructName }} by primary key and invalidates it's cache
+	func (c {{ .StructName }}Cache) Delete(db *gorm.DB, o *{{ .StructName }}) error {
+		{{- if .Tenant }}
+		if err := c.checkTenant(o); err != nil {
+			return err
+		}
+		{{- end }}
+		if err := o.Delete(db); err != nil {
+			return err
+		}
+
+		return c.Invalidate(o.{{ $pk.Name }})
+	}
+
+	// ===== END of {{ .StructName }} cache
+	{{ end }}
+
+	{{ if .HasOption "hedged" }}
+	// ===== BEGIN of {{ .StructName }} hedged reads
+
+	// {{ .StructName }}Hedged runs read finishers on primary db and hedges them by
+	// replica: replica is queried if primary hasn't answered in delay or has failed
+	type {{ .StructName }}Hedged struct {
+		primary, replica *gorm.DB
+		delay time.Duration
+		{{- if .Tenant }}
+		tenantID {{ .Tenant.TypeName }}
+		{{- end }}
+	}
+
+	// New{{ .StructName }}Hedged creates runner of reads hedged by replica after delay
+	{{- if .Tenant }}, querysets
+	// are built for rows of tenant tenantID{{ end }}
+	func New{{ .StructName }}Hedged(primary, replica *gorm.DB, delay time.Duration
+		{{- if .Tenant }}, tenantID {{ .Tenant.TypeName }}{{ end }}) {{ .StructName }}Hedged {
+		return {{ .StructName }}Hedged{
+			primary: primary,
+			replica: replica,
+			delay: delay,
+			{{- if .Tenant }}
+			tenantID: tenantID,
+			{{- end }}
+		}
+	}
+
+	type {{ .StructName }}HedgedResult struct {
+		v interface{}
+		err error
+	}
+
+	// run calls read on primary and on replica and returns the first answer:
+	// result or gorm.ErrRecordNotFound. If both have failed, the first error is
+	// returned. Slower attempt isn't canceled: it finishes in background.
+	func (h {{ .StructName }}Hedged) run(read func(db *gorm.DB) (interface{}, error)) (interface{}, error) {
+		results := make(chan {{ .StructName }}HedgedResult, 2)
+		attempt := func(db *gorm.DB) {
+			v, err := read(db)
+			results <- {{ .StructName }}HedgedResult{v: v, err: err}
+		}
+
+		delay := h.delay
+		if opts := load{{ .StructName }}Options(); opts.HedgeDelay != 0 {
+			delay = opts.HedgeDelay
+		}
+
+		go attempt(h.primary)
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+
+		pending, hedged := 1, false
+		var firstErr error
+		for pending != 0 {
+			select {
+			case r := <-results:
+				pending--
+				if r.err == nil || r.err == gorm.ErrRecordNotFound {
+					return r.v, r.err
+				}
+				if firstErr == nil {
+					firstErr = r.err
+				}
+			case <-timer.C:
+			}
+
+			if !hedged { // after delay or failure of primary
+				hedged = true
+				pending++
+				go attempt(h.replica)
+			}
+		}
+
+		return nil, firstErr
+	}
+
+	// All selects records of queryset built by build on primary or replica
+	func (h {{ .StructName }}Hedged) All(build func(qs {{ .Name }}) {{ .Name }}, ret *[]{{ .StructName }}) error {
+		v, err := h.run(func(db *gorm.DB) (interface{}, error) {
+			var rows []{{ .StructName }}
+			err := build({{ .Constructor }}(db{{ if .Tenant }}, h.tenantID{{ end }})).All(&rows)
+			return rows, err
+		})
+		if err != nil {
+			return err
+		}
+
+		*ret = v.([]{{ .StructName }})
+		return nil
+	}
+
+	// One selects record of queryset built by build on primary or replica
+	func (h {{ .StructName }}Hedged) One(build func(qs {{ .Name }}) {{ .Name }}, ret *{{ .StructName }}) error {
+		v, err := h.run(func(db *gorm.DB) (interface{}, error) {
+			var o {{ .StructName }}
+			err := build({{ .Constructor }}(db{{ if .Tenant }}, h.tenantID{{ end }})).One(&o)
+			return o, err
+		})
+		if err != nil {
+			return err
+		}
+
+		*ret = v.({{ .StructName }})
+		return nil
+	}
+
+	// Count counts records of queryset built by build on primary or replica
+	func (h {{ .StructName }}Hedged) Count(build func(qs {{ .Name }}) {{ .Name }}) (int, error) {
+		v, err := h.run(func(db *gorm.DB) (interface{}, error) {
+			return build({{ .Constructor }}(db{{ if .Tenant }}, h.tenantID{{ end }})).Count()
+		})
+		if err != nil {
+			return 0, err
+		}
+
+		return v.(int), nil
+	}
+
+	// ===== END of {{ .StructName }} hedged reads
+	{{ end }}
+
+	{{ if and .PrimaryKey (not .ModelPkg) (not (.HasOption "readonly")) }}
+	{{ $pk := .PrimaryKey }}
+	{{ $ft := printf "%s%s" .StructName "DBSchemaField" }}
+	{{ $schema := printf "%s%s" .StructName "DBSchema" }}
+	// ===== BEGIN of {{ .StructName }} sync
+
+	// SyncSet makes {{ .StructName }} rows matching queryset equal to desired rows in one
+	// transaction: rows are matched by values of keyFields, missing rows are created,
+	// changed rows are updated and rows absent in desired are deleted, as well as rows
+	// duplicating key of another current row. Primary key
+	{{- if .HasCreatedAt }} and
+	// CreatedAt{{ end }} of updated rows are kept, desired isn't modified. Relations aren't synced.
+	// Desired rows must match queryset: otherwise they would be created again by the next
+	// sync, so transaction is rolled back.
+	func (qs {{ .Name }}) SyncSet(desired []{{ .StructName }}, keyFields ...{{ $ft }}) (inserted, updated, deleted int64, err error) {
+		if len(keyFields) == 0 {
+			return 0, 0, 0, fmt.Errorf("no key fields to sync {{ .StructName }}")
+		}
+
+		{{- if .SyncedFields }}
+		compared := []{{ $ft }}{
+			{{- range .SyncedFields }}
+			{{ $schema }}.{{ .Name }},
+			{{- end }}
+		}
+		{{- end }}
+		fingerprint := func(o *{{ .StructName }}, fields ...{{ $ft }}) (string, error) {
+			data, err := json.Marshal(o.ToSearchDocument(fields...))
+			if err != nil {
+				return "", fmt.Errorf("can't marshal {{ .StructName }} fields: %s", err)
+			}
+			return string(data), nil
+		}
+
+		err = WithTransaction(qs.db, func(tx *gorm.DB) error {
+			var current []{{ .StructName }}
+			if err := tx.Find(&current).Error; err != nil {
+				return fmt.Errorf("can't get current {{ .StructName }} rows: %s", err)
+			}
+
+			currentKeys := make([]string, len(current))
+			byKey := map[string]*{{ .StructName }}{}
+			for i := range current {
+				k, err := fingerprint(&current[i], keyFields...)
+				if err != nil {
+					return err
+				}
+				currentKeys[i] = k
+				if byKey[k] == nil {
+					byKey[k] = &current[i]
+				}
+			}
+
+			db := tx.New()
+			synced := map[string]bool{}
+			for i := range desired {
+				o := desired[i]
+				k, err := fingerprint(&o, keyFields...)
+				if err != nil {
+					return err
+				}
+				if synced[k] {
+					return fmt.Errorf("duplicate key %s of desired {{ .StructName }}", k)
+				}
+				synced[k] = true
+
+				{{- if .HasChecks }}
+				if err := o.validate(); err != nil {
+					return err
+				}
+				{{- end }}
+
+				cur := byKey[k]
+				if cur == nil {
+					if err := db.Create(&o).Error; err != nil {
+						return fmt.Errorf("can't create {{ .StructName }} %s: %s", k, err)
+					}
+					inserted++
+					continue
+				}
+				{{- if .SyncedFields }}
+
+				was, err := fingerprint(cur, compared...)
+				if err != nil {
+					return err
+				}
+				now, err := fingerprint(&o, compared...)
+				if err != nil {
+					return err
+				}
+				if was == now {
+					continue
+				}
+
+				o.{{ $pk.Name }} = cur.{{ $pk.Name }}
+				{{- if .HasCreatedAt }}
+				o.CreatedAt = cur.CreatedAt
+				{{- end }}
+				if err := db.Save(&o).Error; err != nil {
+					return fmt.Errorf("can't update {{ .StructName }} %s: %s", k, err)
+				}
+				updated++
+				{{- end }}
+			}
+
+			for i, k := range currentKeys {
+				if synced[k] && byKey[k] == &current[i] {
+					continue
+				}
+				if err := db.Delete(&current[i]).Error; err != nil {
+					return fmt.Errorf("can't delete {{ .StructName }} %s: %s", k, err)
+				}
+				deleted++
+			}
+
+			var matched int
+			if err := tx.Limit(-1).Offset(-1).Count(&matched).Error; err != nil {
+				return fmt.Errorf("can't count synced {{ .StructName }} rows: %s", err)
+			}
+			if matched < len(desired) {
+				return fmt.Errorf("%d of %d desired {{ .StructName }} rows don't match queryset",
+					len(desired)-matched, len(desired))
+			}
+			return nil
+		})
+		if err != nil {
+			return 0, 0, 0, err
+		}
+		return inserted, updated, deleted, nil
+	}
+
+	// ===== END of {{ .StructName }} sync
+	{{ end }}
+
+	{{ if .Queue }}
+	{{ $q := .Queue }}
+	// ===== BEGIN of {{ .StructName }} job queue
+
+	// ClaimNext claims the first by primary key {{ .StructName }} in {{ $q.Ready }} status
+	// matching queryset for worker workerID: it gets {{ $q.Claimed }} status, {{ $q.LockedBy.Name }}
+	// and {{ $q.LockedAt.Name }} are set. Rows locked by concurrent claims are skipped, so workers
+	// don't wait for each other and don't claim the same row. gorm.ErrRecordNotFound is
+	// returned if there is nothing to claim.
+	func (qs {{ .Name }}) ClaimNext(workerID string) (*{{ .StructName }}, error) {
+		var ret {{ .StructName }}
+		err := WithTransaction(qs.db, func(tx *gorm.DB) error {
+			err := tx.Where({{ printf "%q" $q.ReadyCond }}, {{ $q.Ready }}).Order({{ printf "%q" $q.Order }}).Limit(1).
+				Set("gorm:query_option", {{ printf "%q" $q.ForUpdate }}).Find(&ret).Error
+			if err != nil {
+				return err
+			}
+
+			now := time.Now()
+			ret.{{ $q.Status.Name }} = {{ $q.Claimed }}
+			ret.{{ $q.LockedBy.Name }} = {{ if $q.LockedBy.IsPointer }}&{{ end }}workerID
+			ret.{{ $q.LockedAt.Name }} = {{ if $q.LockedAt.IsPointer }}&{{ end }}now
+			claim := fmt.Sprintf({{ printf "%q" $q.Claim }}, tx.NewScope(&ret).QuotedTableName())
+			return execWithHook(tx.New(), claim, {{ $q.Claimed }}, workerID, now, ret.{{ .PrimaryKey.Name }}).Error
+		})
+		if err != nil {
+			return nil, err
+		}
+		return &ret, nil
+	}
+
+	// Heartbeat{{ .StructName }} sets {{ $q.LockedAt.Name }} of {{ .StructName }} with primary key {{ .PrimaryKeyArgName }}
+	// to now if it's still claimed by worker workerID: call it periodically while the job runs
+	// to prevent its reclaim by ReclaimStale{{ .StructName }}. It returns false if the claim was lost:
+	// the worker should stop the job.
+	func Heartbeat{{ .StructName }}(db *gorm.DB, {{ .PrimaryKeyArgName }} {{ .PrimaryKey.TypeName }}, workerID string) (bool, error) {
+		heartbeat := fmt.Sprintf({{ printf "%q" $q.Heartbeat }}, db.NewScope(&{{ .StructName }}{}).QuotedTableName())
+		res := execWithHook(db.New(), heartbeat, time.Now(), {{ .PrimaryKeyArgName }}, {{ $q.Claimed }}, workerID)
+		return res.RowsAffected != 0, res.Error
+	}
+
+	// ReclaimStale{{ .StructName }} returns claimed {{ .StructName }} rows to {{ $q.Ready }} status if their
+	// {{ $q.LockedAt.Name }} (time of claim or of the last heartbeat) is older than olderThan, e.g. their
+	// workers crashed: they are claimed again by ClaimNext. It returns number of reclaimed rows.
+	func ReclaimStale{{ .StructName }}(db *gorm.DB, olderThan time.Duration) (int64, error) {
+		reclaim := fmt.Sprintf({{ printf "%q" $q.Reclaim }}, db.NewScope(&{{ .StructName }}{}).QuotedTableName())
+		res := execWithHook(db.New(), reclaim, {{ $q.Ready }}, {{ $q.Claimed }}, time.Now().Add(-olderThan))
+		return res.RowsAffected, res.Error
+	}
+
+	// ===== END of {{ .StructName }} job queue
+	{{ end }}
+
+	{{ if .Geo }}
+	{{ $g := .Geo }}
+	// ===== BEGIN of {{ .StructName }} geo queries
+
+	// WithinBoundingBox filters rows with {{ $g.Lat.Name }} and {{ $g.Lng.Name }} in box, e.g. in map view.
+	// Box crosses antimeridian if minLng > maxLng.
+	func (qs {{ .Name }}) WithinBoundingBox(minLat, minLng, maxLat, maxLng float64) {{ .Name }} {
+		if minLng > maxLng {
+			return qs.w(func(db *gorm.DB) *gorm.DB {
+				return db.Where({{ printf "%q" $g.WithinWrapped }}, minLat, maxLat, minLng, maxLng)
+			})
+		}
+		return qs.w(func(db *gorm.DB) *gorm.DB {
+			return db.Where({{ printf "%q" $g.Within }}, minLat, maxLat, minLng, maxLng)
+		})
+	}
+
+	// {{ .StructName }}WithDistance is {{ .StructName }} with distance in meters from point of OrderByDistanceFrom
+	type {{ .StructName }}WithDistance struct {
+		{{ .StructName }}
+		Distance float64
+	}
+
+	// {{ .Name }}ByDistance reads results of {{ .Name }} ordered by distance from point
+	type {{ .Name }}ByDistance struct {
+		qs       {{ .Name }}
+		lat, lng float64
+	}
+
+	// OrderByDistanceFrom returns reader of queryset results ordered by great-circle distance
+	// from point (lat, lng): nearest rows are the first. Order of queryset is replaced,
+	// combine it with Limit and WithinBoundingBox for map views.
+	func (qs {{ .Name }}) OrderByDistanceFrom(lat, lng float64) {{ .Name }}ByDistance {
+		return {{ .Name }}ByDistance{qs: qs, lat: lat, lng: lng}
+	}
+
+	// All returns results of queryset with distances
+	func (s {{ .Name }}ByDistance) All() ([]{{ .StructName }}WithDistance, error) {
+		var ret []{{ .StructName }}WithDistance
+		columns := fmt.Sprintf({{ printf "%q" $g.Columns }}, s.qs.db.NewScope(&{{ .StructName }}{}).QuotedTableName())
+		err := s.qs.db.Select(columns, s.lat, s.lat, s.lng).Order({{ printf "%q" $g.Order }}, true).Scan(&ret).Error
+		if err != nil {
+			return nil, err
+		}
+		if err = s.qs.checkRowsNum(len(ret), true); err != nil {
+			return nil, err
+		}
+		return ret, nil
+	}
+
+	// ===== END of {{ .StructName }} geo queries
+	{{ end }}
+
+	{{ if .HasOption "readonly" }}
+	{{ $v := printf "%s%s" .StructName "View" }}
+	// ===== BEGIN of {{ .StructName }} read-only views
+
+	// {{ $v }} is a read-only snapshot of {{ .StructName }} fetched for display: fields are
+	// available by getters only, so view can't be modified or saved. Pointers, slices
+	// and maps returned by getters share memory with snapshot.
+	type {{ $v }} struct {
+		o {{ .StructName }}
+	}
+
+	// View returns read-only snapshot of o
+	func (o *{{ .StructName }}) View() {{ $v }} {
+		return {{ $v }}{o: *o}
+	}
+	{{ range .Fields }}
+	// {{ .Name }} returns {{ .Name }} of snapshot
+	func (v {{ $v }}) {{ .Name }}() {{ .TypeName }} {
+		return v.o.{{ .Name }}
+	}
+	{{ end }}
+
+	// AllViews is All returning read-only views of {{ .StructName }}
+	func (qs {{ .Name }}) AllViews() ([]{{ $v }}, error) {
+		var ret []{{ .StructName }}
+		if err := qs.All(&ret); err != nil {
+			return nil, err
+		}
+
+		views := make([]{{ $v }}, 0, len(ret))
+		for i := range ret {
+			views = append(views, ret[i].View())
+		}
+		return views, nil
+	}
+
+	// OneView is One returning read-only view of {{ .StructName }}
+	func (qs {{ .Name }}) OneView() ({{ $v }}, error) {
+		var ret {{ .StructName }}
+		err := qs.One(&ret)
+		return ret.View(), err
+	}
+
+	// ===== END of {{ .StructName }} read-only views
+	{{ end }}
+
+	{{ if .Errors }}
+	{{ $e := .Errors }}
+	// ===== BEGIN of {{ .StructName }} errors
+
+	// Err{{ .StructName }}NotFound is returned by finishers of one {{ .StructName }} if nothing was fetched
+	var Err{{ .StructName }}NotFound = errors.New("{{ .StructName }} not found")
+	{{ if $e.DuplicateKeyRe }}
+	// {{ .StructName }}DuplicateError is returned by Create of {{ .StructName }} if row violates unique index
+	type {{ .StructName }}DuplicateError struct {
+		Index  string // name of unique index or constraint
+		Column string // comma-separated columns of index
+		Err    error  // error of driver
+	}
+
+	func (e {{ .StructName }}DuplicateError) Error() string {
+		return fmt.Sprintf("duplicate {{ .StructName }} by %s: %s", e.Column, e.Err)
+	}
+
+	var (
+		duplicate{{ .StructName }}Re      = regexp.MustCompile({{ printf "%q" $e.DuplicateKeyRe }})
+		duplicate{{ .StructName }}Columns = map[string]string{
+			{{- range $e.Indexes }}
+			"{{ .Name }}": "{{ .Columns }}",
+			{{- end }}
+		}
+	)
+	{{ end }}
+	// Translate{{ .StructName }}Error translates error of query of {{ .StructName }} into typed error:
+	// gorm.ErrRecordNotFound into Err{{ .StructName }}NotFound
+	{{- if $e.DuplicateKeyRe }}, errors of driver about violations of unique
+	// indexes into {{ .StructName }}DuplicateError{{ end }}. Other errors are returned as is.
+	func Translate{{ .StructName }}Error(err error) error {
+		if err == gorm.ErrRecordNotFound {
+			return Err{{ .StructName }}NotFound
+		}
+		{{- if $e.DuplicateKeyRe }}
+		if err == nil {
+			return nil
+		}
+
+		m := duplicate{{ .StructName }}Re.FindStringSubmatch(err.Error())
+		if m == nil {
+			return err
+		}
+		column, ok := duplicate{{ .StructName }}Columns[m[1]]
+		if !ok {
+			column = m[1] // column of sqlite or of not declared index
+		}
+		return {{ .StructName }}DuplicateError{Index: m[1], Column: column, Err: err}
+		{{- else }}
+		return err
+		{{- end }}
+	}
+
+	// ===== END of {{ .StructName }} errors
+	{{ end }}
+
+	{{ if .HasOption "mirror" }}
+	{{ $pk := .PrimaryKey }}
+	{{ $ft := printf "%s%s" .StructName "DBSchemaField" }}
+	// ===== BEGIN of {{ .StructName }} reconciler
+
+	// {{ .StructName }}Divergence is a difference of {{ .StructName }} row in source and target DBs
+	type {{ .StructName }}Divergence struct {
+		PK {{ $pk.TypeName }}
+		Source *{{ .StructName }} // it's nil if row is missing in source
+		Target *{{ .StructName }} // it's nil if row is missing in target
+	}
+
+	// {{ .StructName }}ReconcileStats is a result of {{ .StructName }} reconciliation
+	type {{ .StructName }}ReconcileStats struct {
+		Compared int // rows present in both DBs
+		Missing int // rows missing in target
+		Extra int // rows missing in source
+		Different int // rows with different checksums
+		Fixed int // divergences fixed in target
+	}
+
+	// {{ .StructName }}Reconciler compares {{ .StructName }} rows (soft deleted too) of mirrored
+	// source and target DBs, e.g. service DB and warehouse: it pages both DBs
+	// ordered by primary key and compares checksums of rows
+	type {{ .StructName }}Reconciler struct {
+		source, target *gorm.DB
+		batchSize int
+		fields []{{ $ft }}
+		fix bool
+	}
+
+	// New{{ .StructName }}Reconciler creates reconciler of source and target DBs paging them by batchSize rows
+	func New{{ .StructName }}Reconciler(source, target *gorm.DB, batchSize int) {{ .StructName }}Reconciler {
+		return {{ .StructName }}Reconciler{
+			source: source,
+			target: target,
+			batchSize: batchSize,
+		}
+	}
+
+	// Compare returns reconciler comparing only fields, e.g. to skip columns
+	// maintained by target DB. All fields are compared by default.
+	func (r {{ .StructName }}Reconciler) Compare(fields ...{{ $ft }}) {{ .StructName }}Reconciler {
+		r.fields = fields
+		return r
+	}
+
+	// WithFix returns reconciler fixing target: missing rows are created by
+	// Create{{ .StructName }}Batch, different rows are saved and extra rows are deleted
+	func (r {{ .StructName }}Reconciler) WithFix() {{ .StructName }}Reconciler {
+		r.fix = true
+		return r
+	}
+
+	func (r {{ .StructName }}Reconciler) checksum(o *{{ .StructName }}) (uint64, error) {
+		data, err := json.Marshal(o.ToSearchDocument(r.fields...))
+		if err != nil {
+			return 0, fmt.Errorf("can't marshal {{ .StructName }} %v: %s", o.{{ $pk.Name }}, err)
+		}
+
+		h := fnv.New64a()
+		h.Write(data)
+		return h.Sum64(), nil
+	}
+
+	// pager returns func returning next row of db ordered by primary key, it returns nil after the last row
+	func (r {{ .StructName }}Reconciler) pager(db *gorm.DB) func() (*{{ .StructName }}, error) {
+		var rows []{{ .StructName }}
+		var last {{ $pk.TypeName }}
+		started, done := false, false
+		return func() (*{{ .StructName }}, error) {
+			if len(rows) == 0 && !done {
+				qs := {{ .InternalConstructor }}(db.Unscoped())
+				if started {
+					qs = qs.{{ .FilterName $pk.Name "Gt" }}(last)
+				}
+				if err := qs.OrderAscBy{{ $pk.Name }}().Limit(r.batchSize).All(&rows); err != nil {
+					return nil, fmt.Errorf("can't get page of {{ .StructName }}: %s", err)
+				}
+
+				started, done = true, len(rows) < r.batchSize
+				if len(rows) != 0 {
+					last = rows[len(rows)-1].{{ $pk.Name }}
+				}
+			}
+
+			if len(rows) == 0 {
+				return nil, nil
+			}
+			o := &rows[0]
+			rows = rows[1:]
+			return o, nil
+		}
+	}
+
+	// fixDivergence fixes divergent row in target, missing rows are collected
+	// to be created by batches
+	func (r {{ .StructName }}Reconciler) fixDivergence(d {{ .StructName }}Divergence, missing *[]{{ .StructName }}) error {
+		switch {
+		case d.Target == nil:
+			*missing = append(*missing, *d.Source)
+			if len(*missing) < r.batchSize {
+				return nil
+			}
+			return r.createMissing(missing)
+		case d.Source == nil:
+			return r.target.Unscoped().Delete(d.Target).Error
+		default:
+			o := *d.Source
+			return r.target.Unscoped().Set("gorm:update_column", true).Save(&o).Error
+		}
+	}
+
+	func (r {{ .StructName }}Reconciler) createMissing(missing *[]{{ .StructName }}) error {
+		if len(*missing) == 0 {
+			return nil
+		}
+
+		err := Create{{ .StructName }}Batch(r.target, *missing, r.batchSize)
+		*missing = (*missing)[:0]
+		return err
+	}
+
+	// Run compares all rows of source and target and calls report (if it isn't nil)
+	// for every divergence. If reconciler was created WithFix, divergences are fixed
+	// in target after report.
+	func (r {{ .StructName }}Reconciler) Run(report func(d {{ .StructName }}Divergence) error) ({{ .StructName }}ReconcileStats, error) {
+		var stats {{ .StructName }}ReconcileStats
+		if r.batchSize <= 0 {
+			return stats, fmt.Errorf("invalid batch size %d", r.batchSize)
+		}
+
+		nextSource, nextTarget := r.pager(r.source), r.pager(r.target)
+		s, err := nextSource()
+		if err != nil {
+			return stats, err
+		}
+		t, err := nextTarget()
+		if err != nil {
+			return stats, err
+		}
+
+		var missing []{{ .StructName }}
+		for s != nil || t != nil {
+			var d *{{ .StructName }}Divergence
+			switch {
+			case t == nil || (s != nil && s.{{ $pk.Name }} < t.{{ $pk.Name }}):
+				stats.Missing++
+				d = &{{ .StructName }}Divergence{PK: s.{{ $pk.Name }}, Source: s}
+				s, err = nextSource()
+			case s == nil || t.{{ $pk.Name }} < s.{{ $pk.Name }}:
+				stats.Extra++
+				d = &{{ .StructName }}Divergence{PK: t.{{ $pk.Name }}, Target: t}
+				t, err = nextTarget()
+			default:
+				stats.Compared++
+				var sum, targetSum uint64
+				if sum, err = r.checksum(s); err != nil {
+					return stats, err
+				}
+				if targetSum, err = r.checksum(t); err != nil {
+					return stats, err
+				}
+				if sum != targetSum {
+					stats.Different++
+					d = &{{ .StructName }}Divergence{PK: s.{{ $pk.Name }}, Source: s, Target: t}
+				}
+
+				if s, err = nextSource(); err != nil {
+					return stats, err
+				}
+				t, err = nextTarget()
+			}
+			if err != nil {
+				return stats, err
+			}
+
+			if d == nil {
+				continue
+			}
+			if report != nil {
+				if err := report(*d); err != nil {
+					return stats, err
+				}
+			}
+			if r.fix {
+				if err := r.fixDivergence(*d, &missing); err != nil {
+					return stats, fmt.Errorf("can't fix {{ .StructName }} %v: %s", d.PK, err)
+				}
+				stats.Fixed++
+			}
+		}
+
+		if err := r.createMissing(&missing); err != nil {
+			return stats, fmt.Errorf("can't create missing {{ .StructName }}: %s", err)
+		}
+		return stats, nil
+	}
+
+	// ===== END of {{ .StructName }} reconciler
+	{{ end }}
+
+	{{ if .HasOption "isolation" }}
+	// ===== BEGIN of {{ .StructName }} transactions
+
+	// {{ .StructName }}IsolationLevel is an isolation level of transactions
+	// of {{ .StructName }} mutations
+	const {{ .StructName }}IsolationLevel = "{{ .IsolationLevel }}"
+
+	// Begin{{ .StructName }}Tx begins transaction with {{ .StructName }}IsolationLevel:
+	// use it for transactions mutating {{ .StructName }} records
+	func Begin{{ .StructName }}Tx(db *gorm.DB) *gorm.DB {
+		tx := db.Begin()
+		if tx.Error != nil {
+			return tx
+		}
+
+		if err := tx.Exec("{{ .SetIsolation }}").Error; err != nil {
+			tx.Rollback()
+			tx.AddError(err)
+		}
+		return tx
+	}
+
+	{{ if not (.HasOption "notify") }}
+	// inTx runs mutation fn in transaction begun by Begin{{ .StructName }}Tx
+	// unless db is already a transaction
+	func (o *{{ .StructName }}) inTx(db *gorm.DB, fn func(tx *gorm.DB) error) error {
+		if _, ok := db.CommonDB().(*sql.Tx); ok {
+			return fn(db)
+		}
+
+		tx := Begin{{ .StructName }}Tx(db)
+		if tx.Error != nil {
+			return tx.Error
+		}
+
+		if err := fn(tx); err != nil {
+			tx.Rollback()
+			return err
+		}
+		return tx.Commit().Error
+	}
+	{{ end }}
+
+	// ===== END of {{ .StructName }} transactions
+	{{ end }}
+
+	{{ if .HasOption "notify" }}
+	// ===== BEGIN of {{ .StructName }} notifications
+
+	// {{ .StructName }}NotifyChannel is a postgres channel for {{ .StructName }} mutations notifications
+	const {{ .StructName }}NotifyChannel = "{{ .NotifyChannel }}"
+
+	// {{ .StructName }}Event is a notification about {{ .StructName }} mutation
+	type {{ .StructName }}Event struct {
+		Model string
+		Op string // create, update or delete
+		PK {{ .PrimaryKey.TypeName }}
+	}
+
+	// notify runs mutation fn and notifies {{ .StructName }}NotifyChannel about it
+	// in the same transaction: notification is delivered only after commit
+	func (o *{{ .StructName }}) notify(db *gorm.DB, op string, fn func(tx *gorm.DB) error) error {
+		tx := db
+		_, inTx := db.CommonDB().(*sql.Tx)
+		if !inTx {
+			if tx = {{ if .HasOption "isolation" }}Begin{{ .StructName }}Tx(db){{ else }}db.Begin(){{ end }}; tx.Error != nil {
+				return tx.Error
+			}
+		}
+
+		err := fn(tx)
+		if err == nil {
+			var payload []byte
+			payload, err = json.Marshal({{ .StructName }}Event{Model: "{{ .StructName }}", Op: op, PK: o.{{ .PrimaryKey.Name }}})
+			if err == nil {
+				err = execWithHook(tx, "SELECT pg_notify(?, ?)", {{ .StructName }}NotifyChannel, string(payload)).Error
+			}
+		}
+
+		if inTx {
+			return err
+		}
+
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		return tx.Commit().Error
+	}
+
+	// Decode{{ .StructName }}Event decodes payload of notification from {{ .StructName }}NotifyChannel
+	func Decode{{ .StructName }}Event(payload string) ({{ .StructName }}Event, error) {
+		var e {{ .StructName }}Event
+		if err := json.Unmarshal([]byte(payload), &e); err != nil {
+			return e, fmt.Errorf("can't decode {{ .StructName }} event %q: %s", payload, err)
+		}
+
+		return e, nil
+	}
+
+	// Handle{{ .StructName }}Events decodes payloads of notifications from {{ .StructName }}NotifyChannel
+	// (e.g. received by pq.Listener) and passes events to fn until payloads is closed
+	func Handle{{ .StructName }}Events(payloads <-chan string, fn func({{ .StructName }}Event) error) error {
+		for payload := range payloads {
+			e, err := Decode{{ .StructName }}Event(payload)
+			if err != nil {
+				return err
+			}
+
+			if err = fn(e); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	// ===== END of {{ .StructName }} notifications
+	{{ end }}
+
+	{{ template "struct" . }}
+{{ end }}
+
+{{ if .PackageFuncs }}
+// SubQuery is a query of queryset selecting one column, e.g. by Select<Field>:
+// it's used by <Field>InSubquery filters of another queryset, so both
+// querysets are executed by one statement
+type SubQuery struct {
+	sql  string
+	vars []interface{}
+}
+
+// Expr returns subquery as GORM expression for raw conditions,
+// e.g. Where("id IN (?)", sub.Expr())
+func (s SubQuery) Expr() interface{} {
+	return gorm.Expr(s.sql, s.vars...)
+}
+
+// FilterSpec is a filter of ApplyFilters: Op is one of eq, ne, lt, lte, gt, gte,
+// like, in, notin, isnull and notnull. Value must have type of field (pointed
+// type for nullable fields), it's a non-empty slice of such values for in and
+// notin and it's ignored for isnull and notnull.
+type FilterSpec struct {
+	Op    string
+	Value interface{}
+}
+
+// filterOps are formats of conditions of operations of FilterSpec on quoted column
+var filterOps = map[string]string{
+	"eq":      "%s = ?",
+	"ne":      "%s != ?",
+	"lt":      "%s < ?",
+	"lte":     "%s <= ?",
+	"gt":      "%s > ?",
+	"gte":     "%s >= ?",
+	"like":    "%s LIKE ?",
+	"in":      "%s IN (?)",
+	"notin":   "%s NOT IN (?)",
+	"isnull":  "%s IS NULL",
+	"notnull": "%s IS NOT NULL",
+}
+
+// filterColumn is a quoted column filtered by ApplyFilters with type of values
+type filterColumn struct {
+	quoted   string
+	typ      reflect.Type
+	nullable bool // isnull and notnull are applied only to nullable columns
+}
+
+// cond returns condition of filter spec on column and its bind vars
+func (c filterColumn) cond(spec FilterSpec) (string, []interface{}, error) {
+	format, ok := filterOps[spec.Op]
+	if !ok {
+		return "", nil, fmt.Errorf("unknown operation %q", spec.Op)
+	}
+	cond := fmt.Sprintf(format, c.quoted)
+
+	switch spec.Op {
+	case "isnull", "notnull":
+		if !c.nullable {
+			return "", nil, fmt.Errorf("%s can't be applied to not nullable column", spec.Op)
+		}
+		return cond, nil, nil
+	case "in", "notin":
+		v := reflect.ValueOf(spec.Value)
+		if v.Kind() != reflect.Slice || v.Type().Elem() != c.typ || v.Len() == 0 {
+			return "", nil, fmt.Errorf("value of %s must be non-empty []%s, not %T", spec.Op, c.typ, spec.Value)
+		}
+	case "like":
+		if c.typ.Kind() != reflect.String || reflect.TypeOf(spec.Value) != c.typ {
+			return "", nil, fmt.Errorf("value of like must be string pattern, not %T", spec.Value)
+		}
+	default:
+		if reflect.TypeOf(spec.Value) != c.typ {
+			return "", nil, fmt.Errorf("value of %s must be %s, not %T", spec.Op, c.typ, spec.Value)
+		}
+	}
+	return cond, []interface{}{spec.Value}, nil
+}
+
+// likeEscaper escapes wildcards of patterns of StartsWith, EndsWith and Contains
+// filters by ! escape character
+var likeEscaper = strings.NewReplacer({{ .LikeEscapes }})
+
+// sortedUpdateName namespaces callback and setting of sorted updates by import
+// path of package
+var sortedUpdateName = reflect.TypeOf(QueryHook(nil)).PkgPath() + ":sorted_update"
+
+func init() {
+	gorm.DefaultCallback.Update().Before("gorm:update").Register(sortedUpdateName, updateSorted)
+}
+
+// updateSorted sets columns of updates of querysets and objects in order of
+// their names: GORM sets them in random order of map iteration, so the same
+// update would have different SQL every time. Updated columns are taken from
+// GORM, so its gorm:update has nothing to do and other callbacks run as usual.
+func updateSorted(scope *gorm.Scope) {
+	if _, ok := scope.Get(sortedUpdateName); !ok || scope.HasError() {
+		return
+	}
+	attrs, ok := scope.InstanceGet("gorm:update_attrs")
+	if !ok {
+		return
+	}
+
+	values := attrs.(map[string]interface{})
+	columns := make([]string, 0, len(values))
+	for column := range values {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+
+	sets := make([]string, 0, len(columns))
+	for _, column := range columns {
+		sets = append(sets, fmt.Sprintf("%s = %s", scope.Quote(column), scope.AddToVars(values[column])))
+	}
+	sql := fmt.Sprintf("UPDATE %s SET %s", scope.QuotedTableName(), strings.Join(sets, ", "))
+	if cond := scope.CombinedConditionSql(); cond != "" {
+		sql += " " + cond
+	}
+	if option, ok := scope.Get("gorm:update_option"); ok {
+		sql += " " + fmt.Sprint(option)
+	}
+	if len(sets) != 0 {
+		scope.Raw(sql).Exec()
+	}
+	scope.InstanceSet("gorm:update_attrs", map[string]interface{}{})
+}
+
+// ErrMultipleRecords is returned by ExactlyOne if more than one record matches
+var ErrMultipleRecords = errors.New("more than one record matches")
+
+// ErrStaleObject is returned by Update and Save of structs with version field
+// if the row was updated or deleted since the object was loaded: reload it
+// and retry
+var ErrStaleObject = errors.New("object was modified concurrently")
+
+// ErrNoRowsAffected is returned by Update, Delete and SoftDelete of structs
+// with "strict" option if no rows were affected: their Num variants (e.g.
+// DeleteNum) return number of affected rows instead
+var ErrNoRowsAffected = errors.New("no rows affected")
+
+// QueryHook is called after statement with its SQL with bind vars, args, duration
+// and error, e.g. to end span of tracing or to log slow queries. ctx is set by
+// WithContext of queryset or is context.Background().
+type QueryHook func(ctx context.Context, sql string, args []interface{}, took time.Duration, err error)
+
+// queryHookName namespaces names of hook callbacks and settings by import path of
+// package: querysets of several packages can hook one db
+var queryHookName = reflect.TypeOf(QueryHook(nil)).PkgPath() + ":queryset_hook"
+
+// SetQueryHook calls hook after every statement of db: only db is hooked, not gorm
+// globally. Set it once per db before constructing querysets. Row queries (Count,
+// Pluck, Row, Rows etc) run no GORM callbacks after them, so they are hooked by GORM
+// log of their scope: it isn't written for hooked db and errors of row queries are
+// returned by finishers only. Statements controlling transactions aren't hooked.
+func SetQueryHook(db *gorm.DB, hook QueryHook) {
+	db.InstantSet(queryHookName, hook)
+
+	start := func(scope *gorm.Scope) {
+		scope.InstanceSet(queryHookName+"_start", time.Now())
+	}
+	call := func(scope *gorm.Scope) {
+		v, ok := scope.InstanceGet(queryHookName + "_start")
+		if !ok {
+			return
+		}
+		hook(queryHookContext(scope.DB()), scope.SQL, scope.SQLVars, time.Since(v.(time.Time)), scope.DB().Error)
+	}
+	rowQuery := func(scope *gorm.Scope) {
+		// scope has its own clone of db: log of other statements isn't changed
+		scope.DB().SetLogger(queryHookLogger{scope: scope, hook: hook})
+		scope.DB().LogMode(true)
+	}
+
+	startName, callName := queryHookName+"_start", queryHookName+"_call"
+	db.Callback().Create().Before("gorm:begin_transaction").Register(startName, start)
+	db.Callback().Create().After("gorm:commit_or_rollback_transaction").Register(callName, call)
+	db.Callback().Update().Before("gorm:begin_transaction").Register(startName, start)
+	db.Callback().Update().After("gorm:commit_or_rollback_transaction").Register(callName, call)
+	db.Callback().Delete().Before("gorm:begin_transaction").Register(startName, start)
+	db.Callback().Delete().After("gorm:commit_or_rollback_transaction").Register(callName, call)
+	db.Callback().Query().Before("gorm:query").Register(startName, start)
+	db.Callback().Query().After("gorm:after_query").Register(callName, call)
+	db.Callback().RowQuery().Register(callName, rowQuery)
+}
+
+// queryHookLogger calls hook by GORM log of executed row query of scope
+type queryHookLogger struct {
+	scope *gorm.Scope
+	hook  QueryHook
+}
+
+func (l queryHookLogger) Print(v ...interface{}) {
+	if len(v) != 5 || v[0] != "sql" {
+		return
+	}
+
+	took, _ := v[2].(time.Duration)
+	l.hook(queryHookContext(l.scope.DB()), l.scope.SQL, l.scope.SQLVars, took, l.scope.DB().Error)
+}
+
+// queryHookContext returns context of queryset set by WithContext
+func queryHookContext(db *gorm.DB) context.Context {
+	if ctx, ok := db.Get("queryset:ctx"); ok {
+		return ctx.(context.Context)
+	}
+	return context.Background()
+}
+
+// execWithHook executes raw statement: GORM runs no callbacks for it, so hook
+// set by SetQueryHook is called here
+func execWithHook(db *gorm.DB, query string, args ...interface{}) *gorm.DB {
+	hook, ok := db.Get(queryHookName)
+	if !ok {
+		return db.Exec(query, args...)
+	}
+
+	start := time.Now()
+	res := db.Exec(query, args...)
+	hook.(QueryHook)(queryHookContext(db), query, args, time.Since(start), res.Error)
+	return res
+}
+
+// withError returns op of queryset adding err to db
+func withError(err error) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.Set("queryset:error", err) // clone: db of other ops isn't changed
+		db.AddError(err)
+		return db
+	}
+}
+
+// queryContext queries db with cancellation by ctx if db supports it (Go 1.8+)
+func queryContext(ctx context.Context, db *gorm.DB, query string, args []interface{}) (*sql.Rows, error) {
+	if c, ok := db.CommonDB().(interface {
+		QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	}); ok {
+		return c.QueryContext(ctx, query, args...)
+	}
+	return db.CommonDB().Query(query, args...)
+}
+
+// WithTransaction runs fn in transaction: it's committed if fn returns nil and
+// it's rolled back if fn returns error or panics. If db is already a transaction,
+// fn runs in it. Construct querysets in fn by New{StructName}QuerySetTx(tx).
+func WithTransaction(db *gorm.DB, fn func(tx *gorm.DB) error) error {
+	if _, ok := db.CommonDB().(*sql.Tx); ok {
+		return fn(db)
+	}
+
+	tx := db.Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	committed := false
+	defer func() {
+		if !committed {
+			tx.Rollback()
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	committed = true
+	return tx.Commit().Error
+}
+
+{{ if .Constraints.Defer }}
+// WithDeferredConstraints runs fn in transaction tx with deferrable constraints deferred:
+// they are checked after fn instead of after every statement, e.g. to swap unique values
+// of rows. Only constraints declared as DEFERRABLE are deferred. If fn returns error,
+// constraints stay deferred till the end of tx. tx must be a transaction, e.g. begun by
+// WithTransaction.
+func WithDeferredConstraints(tx *gorm.DB, fn func(tx *gorm.DB) error) error {
+	if _, ok := tx.CommonDB().(*sql.Tx); !ok {
+		return errors.New("db of WithDeferredConstraints isn't a transaction")
+	}
+
+	if err := tx.Exec("{{ .Constraints.Defer }}").Error; err != nil {
+		return err
+	}
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	// violations of deferred constraints are reported here, not by COMMIT
+	return tx.Exec("{{ .Constraints.Immediate }}").Error
+}
+{{ end }}
+
+{{ if .TwoPhase.Prepare }}
+// PrepareTransaction runs fn in transaction and prepares it for two-phase commit
+// with global identifier gid instead of committing: it's rolled back if fn returns
+// error or panics. Prepared transaction survives disconnects and crashes, finish
+// it by CommitPrepared or RollbackPrepared from any session.
+func PrepareTransaction(db *gorm.DB, gid string, fn func(tx *gorm.DB) error) error {
+	if _, ok := db.CommonDB().(*sql.Tx); ok {
+		return errors.New("can't prepare nested transaction")
+	}
+
+	tx := db.Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	prepared := false
+	defer func() {
+		if !prepared {
+			tx.Rollback()
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	if err := tx.Exec(fmt.Sprintf("{{ .TwoPhase.Prepare }}", quoteTransactionGID(gid))).Error; err != nil {
+		return err
+	}
+
+	prepared = true
+	// session isn't in transaction after preparation: COMMIT only releases tx
+	return tx.Commit().Error
+}
+
+// CommitPrepared commits transaction prepared by PrepareTransaction with global identifier gid
+func CommitPrepared(db *gorm.DB, gid string) error {
+	return db.Exec(fmt.Sprintf("{{ .TwoPhase.Commit }}", quoteTransactionGID(gid))).Error
+}
+
+// RollbackPrepared rolls back transaction prepared by PrepareTransaction with global identifier gid
+func RollbackPrepared(db *gorm.DB, gid string) error {
+	return db.Exec(fmt.Sprintf("{{ .TwoPhase.Rollback }}", quoteTransactionGID(gid))).Error
+}
+
+// quoteTransactionGID quotes gid as string literal: statements of two-phase
+// commit don't accept bind vars
+func quoteTransactionGID(gid string) string {
+	return "'" + strings.Replace(gid, "'", "''", -1) + "'"
+}
+{{ end }}
+{{ end }}
+
+{{ if .PackageFuncs }}
+{{ template "package" . }}
+{{ end }}
+
+// ===== END of all query sets
+`
+
+const debugCode = `
+// ===== BEGIN of debug methods of all query sets: they are built with {{ .DebugTag }} tag
+
+{{ range .Configs }}
+	// Debug returns queryset, which logs its queries. It's a no-op in builds
+	// with {{ $.NoDebugTag }} tag.
+	func (qs {{ .Name }}) Debug() {{ .Name }} {
+		return qs.w(func(db *gorm.DB) *gorm.DB {
+			return db.Debug()
+		})
+	}
+
+	// DryRun returns SQL and args of select query of queryset like ToSQL. It isn't
+	// generated into builds with {{ $.NoDebugTag }} tag: use it only in tests and tools.
+	func (qs {{ .Name }}) DryRun() (string, []interface{}) {
+		return qs.ToSQL()
+	}
+
+	// Register{{ .StructName }}NPlusOneDetector registers callback of db, which detects
+	// N+1 queries problem: report is called when the same select query of {{ .StructName }}
+	// table (with any args) is executed threshold times, e.g. in a loop over parent records.
+	// Call returned reset at the start of every unit of work (request, job etc).
+	// Register it once per db. It's a no-op in builds with {{ $.NoDebugTag }} tag.
+	func Register{{ .StructName }}NPlusOneDetector(db *gorm.DB, threshold int,
+		report func(sql string, n int)) (reset func()) {
+
+		var mu sync.Mutex
+		counts := map[string]int{}
+		table := db.NewScope(&{{ .StructName }}{}).TableName()
+		db.Callback().Query().After("gorm:query").Register("queryset:{{ .StructName }}_n_plus_one",
+			func(scope *gorm.Scope) {
+				if scope.HasError() || scope.TableName() != table {
+					return
+				}
+
+				mu.Lock()
+				counts[scope.SQL]++
+				n := counts[scope.SQL]
+				mu.Unlock()
+
+				if n == threshold {
+					report(scope.SQL, n)
+				}
+			})
+
+		return func() {
+			mu.Lock()
+			counts = map[string]int{}
+			mu.Unlock()
+		}
+	}
+{{ end }}
+
+// ===== END of debug methods of all query sets
+`
+
+const noDebugCode = `
+// ===== BEGIN of no-op debug methods of all query sets: they are built with {{ .NoDebugTag }} tag
+
+{{ range .Configs }}
+	// Debug returns queryset as is: queries are logged only in builds
+	// with {{ $.DebugTag }} tag.
+	func (qs {{ .Name }}) Debug() {{ .Name }} {
+		return qs
+	}
+
+	// Register{{ .StructName }}NPlusOneDetector does nothing: N+1 queries problem
+	// is detected only in builds with {{ $.DebugTag }} tag.
+	func Register{{ .StructName }}NPlusOneDetector(db *gorm.DB, threshold int,
+		report func(sql string, n int)) (reset func()) {
+
+		return func() {}
+	}
+{{ end }}
+
+// ===== END of no-op debug methods of all query sets
 `
@@ -0,0 +1,13 @@
+package cockroachdb
+
+import "github.com/jinzhu/gorm"
+
+//go:generate go run ../../../cmd/goqueryset/goqueryset.go -in models.go -dialect cockroachdb
+
+// Payment is a model for testing of CockroachDB-specific generated code
+// gen:qs breaker
+type Payment struct {
+	gorm.Model
+
+	Amount int
+}
@@ -1,24 +1,28 @@
 package test
 
 import (
+	"database/sql"
+	"time"
+
 	"github.com/jinzhu/gorm"
 	"github.com/jirfag/go-queryset/queryset/tmp"
 )
 
-//go:generate go run ../../cmd/goqueryset/goqueryset.go -in models.go
+//go:generate go run ../../cmd/goqueryset/goqueryset.go -in models.go -dialect mysql -debug-tag !prod -tenant-field TenantID
 
 // User is a usual user
-// gen:qs
+// gen:qs cache fake hedged querylog ifset breaker
+// gen:proc TopUsers top_users(minRating int, since time.Time)
 type User struct {
 	gorm.Model
 
-	//Posts []Post
-	Name  string
-	Email string
+	Posts []Post
+	Name  string `queryset:"search"`
+	Email string `gorm:"unique_index"`
 }
 
 // Blog is a blog
-// gen:qs
+// gen:qs mirror
 type Blog struct {
 	gorm.Model
 
@@ -26,15 +30,23 @@ type Blog struct {
 }
 
 // Post is an article
-// gen:qs
+// gen:qs fake
 type Post struct {
 	gorm.Model
 
 	Blog   *Blog // may be no blog
+	BlogID *uint
 	User   User
-	Title  *string
+	UserID uint
+	Title  *string `queryset:"fulltext"`
+	Draft  bool    `queryset:"nofilter"`
+	Meta   string  `gorm:"type:json"`
 	Str    tmp.StringDef
 	Unused int `gorm:"-"`
+
+	Subtitle    sql.NullString `queryset:"fulltext"`
+	Views       sql.NullInt64  `gorm:"check:views >= 0"`
+	PublishedAt *time.Time     // sql.NullTime needs Go 1.13
 }
 
 // String is just for testing purposes
@@ -58,3 +70,81 @@ type CheckReservedKeywords struct {
 	Type   string
 	Struct int
 }
+
+// EventKind is a kind of user's event
+type EventKind string
+
+// Kinds of user's events
+const (
+	EventKindLogin  EventKind = "login"
+	EventKindLogout EventKind = "logout"
+)
+
+// EventSource is a name of service emitted event
+type EventSource = string
+
+// Event is a user's event stored in sharded database (TiDB, Vitess)
+// gen:qs sharded fake stats
+type Event struct {
+	gorm.Model
+
+	User     User
+	UserID   uint
+	Kind     EventKind `queryset:"cas"`
+	PrevKind *EventKind
+	Source   EventSource
+}
+
+// Comment is a comment of post, its queryset is named by team conventions
+// and its mutations fail if they affect no rows
+// gen:qs name=Comments constructor=QueryComments prefix=Filter fake strict
+type Comment struct {
+	gorm.Model
+
+	Post   Post
+	PostID uint
+	Text   string
+}
+
+// Place is a point of interest on map, it's fetched only for display
+// gen:qs readonly one=take
+type Place struct {
+	gorm.Model
+
+	Name string
+	Lat  float64 `queryset:"lat"`
+	Lng  float64 `queryset:"lng"`
+}
+
+// Invoice is an invoice of tenant of SaaS, tenants mustn't see invoices of
+// each other
+// gen:qs hedged errors cache
+type Invoice struct {
+	gorm.Model
+
+	TenantID uint   `gorm:"unique_index:tenant_number"`
+	Number   string `gorm:"unique_index:tenant_number" queryset:"cas"`
+	Amount   int
+	Version  int // version of optimistic locking
+}
+
+// JobStatus is a status of background job
+type JobStatus int
+
+// Statuses of background jobs
+const (
+	JobStatusPending JobStatus = iota
+	JobStatusRunning
+	JobStatusDone
+)
+
+// Job is a background job claimed by workers
+// gen:qs queue=Pending:Running
+type Job struct {
+	gorm.Model
+
+	Status   JobStatus
+	LockedBy *string
+	LockedAt *time.Time
+	Priority uint `queryset:"weight"`
+}
@@ -0,0 +1,46 @@
+package outpkg
+
+import "github.com/jinzhu/gorm"
+
+//go:generate go run ../../../cmd/goqueryset/goqueryset.go -in models.go -out-pkg queries -dialect postgres -debug-tag !prod -build-tag go1.9
+
+// Status is a status of user
+type Status string
+
+// Statuses of user: unexported ones are inaccessible in package of querysets
+const (
+	StatusNew    Status = "new"
+	StatusActive Status = "active"
+	statusHidden Status = "hidden"
+)
+
+// level isn't exported: querysets of out package have no methods of its fields
+type level int
+
+// User is a user with querysets in queries package
+// gen:qs fake errors
+type User struct {
+	gorm.Model
+
+	Name   string
+	Email  string `gorm:"unique_index"`
+	Status Status
+	Level  level
+	Posts  []Post
+}
+
+// Post is an article of user
+// gen:qs
+type Post struct {
+	gorm.Model
+
+	User   User
+	UserID uint
+	Title  string
+	Views  int `gorm:"check:views >= 0"`
+}
+
+// IsHidden returns true if user is hidden
+func (u User) IsHidden() bool {
+	return u.Status == statusHidden || u.Level < 0
+}
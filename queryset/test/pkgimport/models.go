@@ -1,6 +1,6 @@
 package models
 
-//go:generate goqueryset -in models.go
+//go:generate goqueryset -in .
 
 import (
 	forex "github.com/jirfag/go-queryset/queryset/test/pkgimport/forex/v1"
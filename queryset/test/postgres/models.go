@@ -0,0 +1,38 @@
+package postgres
+
+import (
+	"encoding/json"
+
+	"github.com/jinzhu/gorm"
+)
+
+//go:generate go run ../../../cmd/goqueryset/goqueryset.go -in models.go -dialect postgres
+
+// Order is a model for testing of postgres-specific generated code
+// gen:qs notify
+// gen:index active_number WHERE deleted_at IS NULL
+type Order struct {
+	gorm.Model
+
+	Number string `gorm:"unique_index:active_number;check:char_length(number) > 0"`
+	Items  []OrderItem
+}
+
+// OrderItem is an item of order
+// gen:qs isolation=serializable locale
+type OrderItem struct {
+	gorm.Model
+
+	OrderID uint
+	SKU     string          `queryset:"fulltext"`
+	Attrs   json.RawMessage `gorm:"type:jsonb"`
+}
+
+// Shipment is a shipment of order: its ID is taken from sequence shared with
+// tables of other carriers
+// gen:qs sequence=shipments_id_seq
+type Shipment struct {
+	gorm.Model
+
+	OrderID uint
+}
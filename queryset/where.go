@@ -0,0 +1,136 @@
+package queryset
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	whereStringRe = regexp.MustCompile(`'(?:[^']|'')*'`)
+	whereIdentRe  = regexp.MustCompile("[`\"\\[]?([A-Za-z_][\\w$]*)[`\"\\]]?(\\s*[.(])?")
+	whereSelectRe = regexp.MustCompile(`(?i)\bSELECT\b`)
+)
+
+// whereKeywords are SQL keywords and literals of conditions: they aren't
+// columns
+var whereKeywords = map[string]bool{}
+
+func init() {
+	for _, w := range strings.Fields(`AND OR NOT NULL IS IN LIKE ILIKE BETWEEN TRUE FALSE
+		EXISTS CASE WHEN THEN ELSE END AS ESCAPE INTERVAL DISTINCT FROM ANY ALL SOME
+		COLLATE CURRENT_DATE CURRENT_TIME CURRENT_TIMESTAMP UNKNOWN SIMILAR TO
+		DAY HOUR MINUTE SECOND MONTH YEAR WEEK`) {
+		whereKeywords[w] = true
+	}
+}
+
+// whereConditionColumns returns columns referenced by raw SQL condition:
+// identifiers except keywords, functions, qualified names (they can be
+// columns of joined tables) and identifiers in string literals. Nothing
+// is returned for conditions with subqueries.
+func whereConditionColumns(cond string) []string {
+	cond = whereStringRe.ReplaceAllString(cond, "''")
+	if whereSelectRe.MatchString(cond) {
+		return nil // columns of subqueries belong to other tables
+	}
+
+	var ret []string
+	qualified := false
+	for _, m := range whereIdentRe.FindAllStringSubmatchIndex(cond, -1) {
+		name, next := cond[m[2]:m[3]], ""
+		if m[4] != -1 {
+			next = strings.TrimSpace(cond[m[4]:m[5]])
+		}
+
+		isColumn := !qualified && next == "" && !whereKeywords[strings.ToUpper(name)] &&
+			(m[0] == 0 || !isWhereIdentRune(rune(cond[m[0]-1])) && cond[m[0]-1] != ':') // ::type
+		qualified = next == "."
+		if isColumn {
+			ret = append(ret, name)
+		}
+	}
+	return ret
+}
+
+// isWhereIdentRune returns true if r continues identifier or number: e.g. e
+// in 1e5 isn't a column
+func isWhereIdentRune(r rune) bool {
+	return r == '_' || r == '$' || r == '.' || r >= '0' && r <= '9' ||
+		r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z'
+}
+
+// whereCallStruct returns name of struct of queryset, which method Where is
+// called by call: queryset must be constructed in the same chain of calls,
+// e.g. NewUserQuerySet(db).NameEq(name).Where(...)
+func whereCallStruct(call *ast.CallExpr, constructors map[string]string) (string, bool) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Where" {
+		return "", false
+	}
+
+	x := sel.X
+	for {
+		c, ok := x.(*ast.CallExpr)
+		if !ok {
+			return "", false
+		}
+
+		switch fun := c.Fun.(type) {
+		case *ast.Ident:
+			s, ok := constructors[fun.Name]
+			return s, ok
+		case *ast.SelectorExpr:
+			x = fun.X
+		default:
+			return "", false
+		}
+	}
+}
+
+// checkWhereConditions checks, that raw conditions passed as string literals
+// to Where of querysets in files reference only columns of their structs
+func checkWhereConditions(files []*ast.File, configs querySetStructConfigSlice) error {
+	constructors := map[string]string{}
+	columns := map[string]map[string]bool{}
+	for _, c := range configs {
+		constructors[c.Constructor] = c.StructName
+		columns[c.StructName] = map[string]bool{}
+		for _, f := range c.Fields {
+			if !f.IsStruct && !(f.IsPointer && f.GetPointed().IsStruct) {
+				columns[c.StructName][f.DBName] = true
+			}
+		}
+	}
+
+	var err error
+	for _, file := range files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || err != nil || len(call.Args) == 0 {
+				return err == nil
+			}
+
+			structName, ok := whereCallStruct(call, constructors)
+			lit, isLit := call.Args[0].(*ast.BasicLit)
+			if !ok || !isLit || lit.Kind != token.STRING {
+				return true
+			}
+
+			cond, _ := strconv.Unquote(lit.Value)
+			for _, column := range whereConditionColumns(cond) {
+				if !columns[structName][column] {
+					err = fmt.Errorf("condition %q of %s Where references unknown column %s",
+						cond, structName, column)
+					return false
+				}
+			}
+			return true
+		})
+	}
+
+	return err
+}
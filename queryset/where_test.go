@@ -0,0 +1,53 @@
+package queryset
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/jirfag/go-queryset/queryset/field"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWhereConditionColumns(t *testing.T) {
+	cases := []struct {
+		cond    string
+		columns []string
+	}{
+		{"rating > ? AND name IS NOT NULL", []string{"rating", "name"}},
+		{"`user_id` = ? OR \"email\" LIKE 'a b%'", []string{"user_id", "email"}},
+		{"LOWER(name) = lower(?) AND users.id IN (?)", []string{"name"}},
+		{"created_at > NOW() - INTERVAL 1 DAY", []string{"created_at"}},
+		{"attrs->>'size' = $1 AND rating::text <> '1e5'", []string{"attrs", "rating"}},
+		{"id IN (SELECT user_id FROM posts)", nil},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.columns, whereConditionColumns(c.cond), c.cond)
+	}
+}
+
+func TestCheckWhereConditions(t *testing.T) {
+	configs := querySetStructConfigSlice{
+		{
+			StructName: "User",
+			Fields: []field.Info{
+				{BaseInfo: field.BaseInfo{Name: "ID", DBName: "id"}},
+				{BaseInfo: field.BaseInfo{Name: "Name", DBName: "name"}},
+			},
+		},
+	}
+	configs[0].Constructor = "NewUserQuerySet"
+
+	parse := func(body string) []*ast.File {
+		f, err := parser.ParseFile(token.NewFileSet(), "users.go", "package test\nfunc f() {\n"+body+"\n}", 0)
+		assert.Nil(t, err)
+		return []*ast.File{f}
+	}
+
+	assert.Nil(t, checkWhereConditions(parse(`NewUserQuerySet(db).IDGt(1).Where("name = ?", "a").All(&users)`),
+		configs))
+	assert.Nil(t, checkWhereConditions(parse(`qs.Where("nme = ?", "a")`), configs)) // unknown queryset
+	err := checkWhereConditions(parse(`NewUserQuerySet(db).Where("id > ? AND nme = ?", 1, "a")`), configs)
+	assert.EqualError(t, err, `condition "id > ? AND nme = ?" of User Where references unknown column nme`)
+}